@@ -24,6 +24,39 @@ type Client struct {
 	formats   strfmt.Registry
 }
 
+/*
+DeleteKvstoreLocks forcefully releases a lock held in the kvstore
+
+Releases the lock on the specified path regardless of its owner. An
+audit entry recording the released lock is written to the kvstore.
+
+
+*/
+func (a *Client) DeleteKvstoreLocks(params *DeleteKvstoreLocksParams) (*DeleteKvstoreLocksOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewDeleteKvstoreLocksParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "DeleteKvstoreLocks",
+		Method:             "DELETE",
+		PathPattern:        "/kvstore/locks",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &DeleteKvstoreLocksReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*DeleteKvstoreLocksOK), nil
+
+}
+
 /*
 GetConfig gets configuration of cilium daemon
 
@@ -116,6 +149,118 @@ func (a *Client) GetHealthz(params *GetHealthzParams) (*GetHealthzOK, error) {
 
 }
 
+/*
+GetKvstoreLocks lists locks held in the kvstore
+*/
+func (a *Client) GetKvstoreLocks(params *GetKvstoreLocksParams) (*GetKvstoreLocksOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetKvstoreLocksParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetKvstoreLocks",
+		Method:             "GET",
+		PathPattern:        "/kvstore/locks",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetKvstoreLocksReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetKvstoreLocksOK), nil
+
+}
+
+/*
+GetMap lists all pinned b p f maps
+*/
+func (a *Client) GetMap(params *GetMapParams) (*GetMapOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetMapParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetMap",
+		Method:             "GET",
+		PathPattern:        "/map",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetMapReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetMapOK), nil
+
+}
+
+/*
+GetMapName retrieves contents of b p f map
+*/
+func (a *Client) GetMapName(params *GetMapNameParams) (*GetMapNameOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetMapNameParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetMapName",
+		Method:             "GET",
+		PathPattern:        "/map/{name}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetMapNameReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetMapNameOK), nil
+
+}
+
+/*
+GetMapNameEntries retrieves the decoded entries of a b p f map
+*/
+func (a *Client) GetMapNameEntries(params *GetMapNameEntriesParams) (*GetMapNameEntriesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetMapNameEntriesParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetMapNameEntries",
+		Method:             "GET",
+		PathPattern:        "/map/{name}/entries",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetMapNameEntriesReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetMapNameEntriesOK), nil
+
+}
+
 /*
 PatchConfig modifies daemon configuration
 
@@ -246,6 +246,38 @@ func (a *Client) GetEndpointIDLog(params *GetEndpointIDLogParams) (*GetEndpointI
 
 }
 
+/*
+GetEndpointIDPolicyStats retrieves the policy hit counters of this endpoint
+
+Retrieves the number of packets and bytes which have been allowed
+by each entry of the policy of the endpoint.
+
+*/
+func (a *Client) GetEndpointIDPolicyStats(params *GetEndpointIDPolicyStatsParams) (*GetEndpointIDPolicyStatsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetEndpointIDPolicyStatsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetEndpointIDPolicyStats",
+		Method:             "GET",
+		PathPattern:        "/endpoint/{id}/policy-stats",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetEndpointIDPolicyStatsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetEndpointIDPolicyStatsOK), nil
+
+}
+
 /*
 PatchEndpointID modifies existing endpoint
 
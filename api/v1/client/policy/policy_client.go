@@ -52,6 +52,34 @@ func (a *Client) DeletePolicy(params *DeletePolicyParams) (*DeletePolicyOK, erro
 
 }
 
+/*
+DeletePolicyEgressGatewayName deletes an egress gateway policy
+*/
+func (a *Client) DeletePolicyEgressGatewayName(params *DeletePolicyEgressGatewayNameParams) (*DeletePolicyEgressGatewayNameOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewDeletePolicyEgressGatewayNameParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "DeletePolicyEgressGatewayName",
+		Method:             "DELETE",
+		PathPattern:        "/policy/egress-gateway/{name}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &DeletePolicyEgressGatewayNameReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*DeletePolicyEgressGatewayNameOK), nil
+
+}
+
 /*
 GetIdentity retrieves a list of identities that have metadata matching the provided parameters
 
@@ -142,6 +170,34 @@ func (a *Client) GetPolicy(params *GetPolicyParams) (*GetPolicyOK, error) {
 
 }
 
+/*
+GetPolicyEgressGateway retrieves all egress gateway policies
+*/
+func (a *Client) GetPolicyEgressGateway(params *GetPolicyEgressGatewayParams) (*GetPolicyEgressGatewayOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetPolicyEgressGatewayParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetPolicyEgressGateway",
+		Method:             "GET",
+		PathPattern:        "/policy/egress-gateway",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetPolicyEgressGatewayReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetPolicyEgressGatewayOK), nil
+
+}
+
 /*
 GetPolicyResolve resolves policy for an identity context
 */
@@ -198,6 +254,40 @@ func (a *Client) PutPolicy(params *PutPolicyParams) (*PutPolicyOK, error) {
 
 }
 
+/*
+PutPolicyEgressGatewayName creates or update an egress gateway policy
+*/
+func (a *Client) PutPolicyEgressGatewayName(params *PutPolicyEgressGatewayNameParams) (*PutPolicyEgressGatewayNameOK, *PutPolicyEgressGatewayNameCreated, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPutPolicyEgressGatewayNameParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "PutPolicyEgressGatewayName",
+		Method:             "PUT",
+		PathPattern:        "/policy/egress-gateway/{name}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PutPolicyEgressGatewayNameReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	switch value := result.(type) {
+	case *PutPolicyEgressGatewayNameOK:
+		return value, nil, nil
+	case *PutPolicyEgressGatewayNameCreated:
+		return nil, value, nil
+	}
+	return nil, nil, nil
+
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
@@ -21,7 +21,7 @@ import (
 // NewGetServiceParams creates a new GetServiceParams object
 // with the default values initialized.
 func NewGetServiceParams() *GetServiceParams {
-
+	var ()
 	return &GetServiceParams{
 
 		timeout: cr.DefaultTimeout,
@@ -31,7 +31,7 @@ func NewGetServiceParams() *GetServiceParams {
 // NewGetServiceParamsWithTimeout creates a new GetServiceParams object
 // with the default values initialized, and the ability to set a timeout on a request
 func NewGetServiceParamsWithTimeout(timeout time.Duration) *GetServiceParams {
-
+	var ()
 	return &GetServiceParams{
 
 		timeout: timeout,
@@ -41,7 +41,7 @@ func NewGetServiceParamsWithTimeout(timeout time.Duration) *GetServiceParams {
 // NewGetServiceParamsWithContext creates a new GetServiceParams object
 // with the default values initialized, and the ability to set a context for a request
 func NewGetServiceParamsWithContext(ctx context.Context) *GetServiceParams {
-
+	var ()
 	return &GetServiceParams{
 
 		Context: ctx,
@@ -51,7 +51,7 @@ func NewGetServiceParamsWithContext(ctx context.Context) *GetServiceParams {
 // NewGetServiceParamsWithHTTPClient creates a new GetServiceParams object
 // with the default values initialized, and the ability to set a custom HTTPClient for a request
 func NewGetServiceParamsWithHTTPClient(client *http.Client) *GetServiceParams {
-
+	var ()
 	return &GetServiceParams{
 		HTTPClient: client,
 	}
@@ -61,6 +61,18 @@ func NewGetServiceParamsWithHTTPClient(client *http.Client) *GetServiceParams {
 for the get service operation typically these are written to a http.Request
 */
 type GetServiceParams struct {
+
+	/*Name
+	  Name of the service
+
+	*/
+	Name *string
+	/*Namespace
+	  Namespace of the service
+
+	*/
+	Namespace *string
+
 	timeout    time.Duration
 	Context    context.Context
 	HTTPClient *http.Client
@@ -99,6 +111,28 @@ func (o *GetServiceParams) SetHTTPClient(client *http.Client) {
 	o.HTTPClient = client
 }
 
+// WithName adds the name to the get service params
+func (o *GetServiceParams) WithName(name *string) *GetServiceParams {
+	o.SetName(name)
+	return o
+}
+
+// SetName adds the name to the get service params
+func (o *GetServiceParams) SetName(name *string) {
+	o.Name = name
+}
+
+// WithNamespace adds the namespace to the get service params
+func (o *GetServiceParams) WithNamespace(namespace *string) *GetServiceParams {
+	o.SetNamespace(namespace)
+	return o
+}
+
+// SetNamespace adds the namespace to the get service params
+func (o *GetServiceParams) SetNamespace(namespace *string) {
+	o.Namespace = namespace
+}
+
 // WriteToRequest writes these params to a swagger request
 func (o *GetServiceParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
 
@@ -107,6 +141,38 @@ func (o *GetServiceParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Re
 	}
 	var res []error
 
+	if o.Name != nil {
+
+		// query param name
+		var qrName string
+		if o.Name != nil {
+			qrName = *o.Name
+		}
+		qName := qrName
+		if qName != "" {
+			if err := r.SetQueryParam("name", qName); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Namespace != nil {
+
+		// query param namespace
+		var qrNamespace string
+		if o.Namespace != nil {
+			qrNamespace = *o.Namespace
+		}
+		qNamespace := qrNamespace
+		if qNamespace != "" {
+			if err := r.SetQueryParam("namespace", qNamespace); err != nil {
+				return err
+			}
+		}
+
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -72,6 +72,9 @@ type Endpoint struct {
 	// Required: true
 	PolicyEnabled *string `json:"policy-enabled"`
 
+	// Fill level of the policy map of the endpoint
+	PolicyMap *EndpointPolicyMap `json:"policy-map,omitempty"`
+
 	// The policy revision this endpoint is running on
 	PolicyRevision int64 `json:"policy-revision,omitempty"`
 
@@ -117,6 +120,8 @@ type Endpoint struct {
 
 /* polymorph Endpoint policy-enabled false */
 
+/* polymorph Endpoint policy-map false */
+
 /* polymorph Endpoint policy-revision false */
 
 /* polymorph Endpoint state false */
@@ -157,6 +162,11 @@ func (m *Endpoint) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validatePolicyMap(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateState(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -308,6 +318,25 @@ func (m *Endpoint) validatePolicyEnabled(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Endpoint) validatePolicyMap(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.PolicyMap) { // not required
+		return nil
+	}
+
+	if m.PolicyMap != nil {
+
+		if err := m.PolicyMap.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("policy-map")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *Endpoint) validateState(formats strfmt.Registry) error {
 
 	if err := m.State.Validate(formats); err != nil {
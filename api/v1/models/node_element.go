@@ -19,6 +19,11 @@ import (
 
 type NodeElement struct {
 
+	// SPI of the key encrypting the traffic to the node, 0 if the
+	// traffic is not encrypted
+	//
+	EncryptionKey int64 `json:"encryption-key,omitempty"`
+
 	// Address used for probing cluster connectivity
 	HealthEndpointAddress *NodeAddressing `json:"health-endpoint-address,omitempty"`
 
@@ -32,6 +37,8 @@ type NodeElement struct {
 	SecondaryAddresses []*NodeAddressingElement `json:"secondary-addresses"`
 }
 
+/* polymorph NodeElement encryption-key false */
+
 /* polymorph NodeElement health-endpoint-address false */
 
 /* polymorph NodeElement name false */
@@ -6,6 +6,7 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"encoding/json"
 	"strconv"
 
 	strfmt "github.com/go-openapi/strfmt"
@@ -30,8 +31,20 @@ type Service struct {
 	// Required: true
 	FrontendAddress *FrontendAddress `json:"frontend-address"`
 
+	// Active health check of the backends
+	HealthCheck *ServiceHealthCheck `json:"health-check,omitempty"`
+
 	// Unique identification
 	ID int64 `json:"id,omitempty"`
+
+	// User defined metadata of the service
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Name of the service, shared by all its frontends
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the service name
+	Namespace string `json:"namespace,omitempty"`
 }
 
 /* polymorph Service backend-addresses false */
@@ -40,8 +53,16 @@ type Service struct {
 
 /* polymorph Service frontend-address false */
 
+/* polymorph Service health-check false */
+
 /* polymorph Service id false */
 
+/* polymorph Service labels false */
+
+/* polymorph Service name false */
+
+/* polymorph Service namespace false */
+
 // Validate validates this service
 func (m *Service) Validate(formats strfmt.Registry) error {
 	var res []error
@@ -61,6 +82,11 @@ func (m *Service) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateHealthCheck(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -132,6 +158,25 @@ func (m *Service) validateFrontendAddress(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Service) validateHealthCheck(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.HealthCheck) { // not required
+		return nil
+	}
+
+	if m.HealthCheck != nil {
+
+		if err := m.HealthCheck.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("health-check")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *Service) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -160,22 +205,257 @@ type ServiceFlags struct {
 
 	// Perform direct server return
 	DirectServerReturn bool `json:"direct-server-return,omitempty"`
+
+	// Algorithm used to select a backend
+	LbAlgorithm string `json:"lb-algorithm,omitempty"`
+
+	// Session affinity of clients to backends
+	SessionAffinity string `json:"session-affinity,omitempty"`
+
+	// Session affinity timeout in seconds
+	SessionAffinityTimeout int64 `json:"session-affinity-timeout,omitempty"`
+
+	// Locality of the backends preferred for new connections
+	Topology string `json:"topology,omitempty"`
+
+	// Type of the frontend
+	Type string `json:"type,omitempty"`
 }
 
 /* polymorph ServiceFlags active-frontend false */
 
 /* polymorph ServiceFlags direct-server-return false */
 
+/* polymorph ServiceFlags lb-algorithm false */
+
+/* polymorph ServiceFlags session-affinity false */
+
+/* polymorph ServiceFlags session-affinity-timeout false */
+
+/* polymorph ServiceFlags topology false */
+
+/* polymorph ServiceFlags type false */
+
 // Validate validates this service flags
 func (m *ServiceFlags) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateLbAlgorithm(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateSessionAffinity(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateSessionAffinityTimeout(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateTopology(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateType(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+var serviceFlagsTypeLbAlgorithmPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["hash","maglev"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		serviceFlagsTypeLbAlgorithmPropEnum = append(serviceFlagsTypeLbAlgorithmPropEnum, v)
+	}
+}
+
+const (
+	// ServiceFlagsLbAlgorithmHash captures enum value "hash"
+	ServiceFlagsLbAlgorithmHash string = "hash"
+	// ServiceFlagsLbAlgorithmMaglev captures enum value "maglev"
+	ServiceFlagsLbAlgorithmMaglev string = "maglev"
+)
+
+// prop value enum
+func (m *ServiceFlags) validateLbAlgorithmEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, serviceFlagsTypeLbAlgorithmPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *ServiceFlags) validateLbAlgorithm(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.LbAlgorithm) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateLbAlgorithmEnum("flags"+"."+"lb-algorithm", "body", m.LbAlgorithm); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var serviceFlagsTypeSessionAffinityPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["None","ClientIP"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		serviceFlagsTypeSessionAffinityPropEnum = append(serviceFlagsTypeSessionAffinityPropEnum, v)
+	}
+}
+
+const (
+	// ServiceFlagsSessionAffinityNone captures enum value "None"
+	ServiceFlagsSessionAffinityNone string = "None"
+	// ServiceFlagsSessionAffinityClientIP captures enum value "ClientIP"
+	ServiceFlagsSessionAffinityClientIP string = "ClientIP"
+)
+
+// prop value enum
+func (m *ServiceFlags) validateSessionAffinityEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, serviceFlagsTypeSessionAffinityPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *ServiceFlags) validateSessionAffinity(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.SessionAffinity) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateSessionAffinityEnum("flags"+"."+"session-affinity", "body", m.SessionAffinity); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ServiceFlags) validateSessionAffinityTimeout(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.SessionAffinityTimeout) { // not required
+		return nil
+	}
+
+	if err := validate.MinimumInt("flags"+"."+"session-affinity-timeout", "body", int64(m.SessionAffinityTimeout), 0, false); err != nil {
+		return err
+	}
+
+	if err := validate.MaximumInt("flags"+"."+"session-affinity-timeout", "body", int64(m.SessionAffinityTimeout), 86400, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var serviceFlagsTypeTopologyPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["none","node","zone"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		serviceFlagsTypeTopologyPropEnum = append(serviceFlagsTypeTopologyPropEnum, v)
+	}
+}
+
+const (
+	// ServiceFlagsTopologyNone captures enum value "none"
+	ServiceFlagsTopologyNone string = "none"
+	// ServiceFlagsTopologyNode captures enum value "node"
+	ServiceFlagsTopologyNode string = "node"
+	// ServiceFlagsTopologyZone captures enum value "zone"
+	ServiceFlagsTopologyZone string = "zone"
+)
+
+// prop value enum
+func (m *ServiceFlags) validateTopologyEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, serviceFlagsTypeTopologyPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *ServiceFlags) validateTopology(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Topology) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateTopologyEnum("flags"+"."+"topology", "body", m.Topology); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var serviceFlagsTypeTypePropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["ClusterIP","NodePort","ExternalIPs"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		serviceFlagsTypeTypePropEnum = append(serviceFlagsTypeTypePropEnum, v)
+	}
+}
+
+const (
+	// ServiceFlagsTypeClusterIP captures enum value "ClusterIP"
+	ServiceFlagsTypeClusterIP string = "ClusterIP"
+	// ServiceFlagsTypeNodePort captures enum value "NodePort"
+	ServiceFlagsTypeNodePort string = "NodePort"
+	// ServiceFlagsTypeExternalIPs captures enum value "ExternalIPs"
+	ServiceFlagsTypeExternalIPs string = "ExternalIPs"
+)
+
+// prop value enum
+func (m *ServiceFlags) validateTypeEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, serviceFlagsTypeTypePropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *ServiceFlags) validateType(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Type) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateTypeEnum("flags"+"."+"type", "body", m.Type); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *ServiceFlags) MarshalBinary() ([]byte, error) {
 	if m == nil {
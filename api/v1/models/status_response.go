@@ -23,12 +23,18 @@ type StatusResponse struct {
 	// Status of cluster
 	Cluster *ClusterStatus `json:"cluster,omitempty"`
 
+	// Status of the connection tracking tables
+	Conntrack *Status `json:"conntrack,omitempty"`
+
 	// Status of local container runtime
 	ContainerRuntime *Status `json:"container-runtime,omitempty"`
 
 	// Status of all endpoint controllers
 	Controllers ControllerStatuses `json:"controllers"`
 
+	// Status of the encryption of traffic between nodes
+	Encryption *EncryptionStatus `json:"encryption,omitempty"`
+
 	// Status of IP address management
 	IPAM *IPAMStatus `json:"ipam,omitempty"`
 
@@ -40,16 +46,23 @@ type StatusResponse struct {
 
 	// Status of the node monitor
 	NodeMonitor *MonitorStatus `json:"nodeMonitor,omitempty"`
+
+	// Status of the service file synchronization
+	ServiceFiles *ServiceFilesStatus `json:"service-files,omitempty"`
 }
 
 /* polymorph StatusResponse cilium false */
 
 /* polymorph StatusResponse cluster false */
 
+/* polymorph StatusResponse conntrack false */
+
 /* polymorph StatusResponse container-runtime false */
 
 /* polymorph StatusResponse controllers false */
 
+/* polymorph StatusResponse encryption false */
+
 /* polymorph StatusResponse ipam false */
 
 /* polymorph StatusResponse kubernetes false */
@@ -58,6 +71,8 @@ type StatusResponse struct {
 
 /* polymorph StatusResponse nodeMonitor false */
 
+/* polymorph StatusResponse service-files false */
+
 // Validate validates this status response
 func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 	var res []error
@@ -72,11 +87,21 @@ func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateConntrack(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateContainerRuntime(formats); err != nil {
 		// prop
 		res = append(res, err)
 	}
 
+	if err := m.validateEncryption(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateIPAM(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -97,6 +122,11 @@ func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateServiceFiles(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -141,6 +171,25 @@ func (m *StatusResponse) validateCluster(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *StatusResponse) validateConntrack(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Conntrack) { // not required
+		return nil
+	}
+
+	if m.Conntrack != nil {
+
+		if err := m.Conntrack.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("conntrack")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *StatusResponse) validateContainerRuntime(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.ContainerRuntime) { // not required
@@ -160,6 +209,25 @@ func (m *StatusResponse) validateContainerRuntime(formats strfmt.Registry) error
 	return nil
 }
 
+func (m *StatusResponse) validateEncryption(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Encryption) { // not required
+		return nil
+	}
+
+	if m.Encryption != nil {
+
+		if err := m.Encryption.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("encryption")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *StatusResponse) validateIPAM(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.IPAM) { // not required
@@ -236,6 +304,25 @@ func (m *StatusResponse) validateNodeMonitor(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *StatusResponse) validateServiceFiles(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ServiceFiles) { // not required
+		return nil
+	}
+
+	if m.ServiceFiles != nil {
+
+		if err := m.ServiceFiles.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("service-files")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *StatusResponse) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -475,6 +475,45 @@ func init() {
         }
       }
     },
+    "/endpoint/{id}/policy-stats": {
+      "get": {
+        "description": "Retrieves the number of packets and bytes which have been allowed\nby each entry of the policy of the endpoint.\n",
+        "tags": [
+          "endpoint"
+        ],
+        "summary": "Retrieves the policy hit counters of this endpoint.",
+        "parameters": [
+          {
+            "$ref": "#/parameters/endpoint-id"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/EndpointPolicyStats"
+            }
+          },
+          "400": {
+            "description": "Invalid endpoint ID format for specified type",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Invalid"
+          },
+          "404": {
+            "description": "Endpoint not found"
+          },
+          "500": {
+            "description": "Policy map of the endpoint could not be read",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
     "/identity": {
       "get": {
         "description": "Retrieves a list of identities that have metadata matching the provided parameters, or all identities if no parameters are provided.\n",
@@ -657,6 +696,140 @@ func init() {
         }
       }
     },
+    "/kvstore/locks": {
+      "get": {
+        "tags": [
+          "daemon"
+        ],
+        "summary": "List locks held in the kvstore",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/KVStoreLock"
+              }
+            }
+          },
+          "500": {
+            "description": "Locks could not be listed",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      },
+      "delete": {
+        "description": "Releases the lock on the specified path regardless of its owner. An\naudit entry recording the released lock is written to the kvstore.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Forcefully release a lock held in the kvstore",
+        "parameters": [
+          {
+            "$ref": "#/parameters/kvstore-lock-path"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/KVStoreLock"
+            }
+          },
+          "404": {
+            "description": "No lock is held on the path"
+          },
+          "500": {
+            "description": "Lock could not be released",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
+    "/map": {
+      "get": {
+        "tags": [
+          "daemon"
+        ],
+        "summary": "List all pinned BPF maps",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/BPFMapList"
+            }
+          },
+          "500": {
+            "description": "Pinned BPF maps could not be listed",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
+    "/map/{name}": {
+      "get": {
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Retrieve contents of BPF map",
+        "parameters": [
+          {
+            "$ref": "#/parameters/map-name"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/BPFMap"
+            }
+          },
+          "404": {
+            "description": "Map not found"
+          }
+        }
+      }
+    },
+    "/map/{name}/entries": {
+      "get": {
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Retrieve the decoded entries of a BPF map",
+        "parameters": [
+          {
+            "$ref": "#/parameters/map-name"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/BPFMap"
+            }
+          },
+          "404": {
+            "description": "Map not found"
+          },
+          "500": {
+            "description": "BPF map could not be dumped",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
     "/policy": {
       "get": {
         "description": "Returns the entire policy tree with all children.\n",
@@ -766,6 +939,89 @@ func init() {
         }
       }
     },
+    "/policy/egress-gateway": {
+      "get": {
+        "tags": [
+          "policy"
+        ],
+        "summary": "Retrieve all egress gateway policies",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/EgressGatewayPolicy"
+              }
+            }
+          }
+        }
+      }
+    },
+    "/policy/egress-gateway/{name}": {
+      "put": {
+        "tags": [
+          "policy"
+        ],
+        "summary": "Create or update an egress gateway policy",
+        "parameters": [
+          {
+            "$ref": "#/parameters/egress-gateway-policy-name"
+          },
+          {
+            "$ref": "#/parameters/egress-gateway-policy"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Updated"
+          },
+          "201": {
+            "description": "Created"
+          },
+          "400": {
+            "description": "Invalid egress gateway policy",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Invalid"
+          },
+          "500": {
+            "description": "Error while applying egress gateway policy",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      },
+      "delete": {
+        "tags": [
+          "policy"
+        ],
+        "summary": "Delete an egress gateway policy",
+        "parameters": [
+          {
+            "$ref": "#/parameters/egress-gateway-policy-name"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success"
+          },
+          "404": {
+            "description": "Egress gateway policy not found"
+          },
+          "500": {
+            "description": "Egress gateway policy deletion failed",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
     "/policy/resolve": {
       "get": {
         "tags": [
@@ -876,6 +1132,14 @@ func init() {
           "service"
         ],
         "summary": "Retrieve list of all services",
+        "parameters": [
+          {
+            "$ref": "#/parameters/service-name"
+          },
+          {
+            "$ref": "#/parameters/service-namespace"
+          }
+        ],
         "responses": {
           "200": {
             "description": "Success",
@@ -988,6 +1252,98 @@ func init() {
       "description": "IP address",
       "type": "string"
     },
+    "BPFMap": {
+      "description": "BPF map definition and content",
+      "type": "object",
+      "properties": {
+        "cache": {
+          "description": "Contents of cache",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/BPFMapEntry"
+          }
+        },
+        "description": {
+          "description": "Documentation of BPF map",
+          "type": "string"
+        },
+        "entries": {
+          "description": "Decoded entries of BPF map, only present in dumps",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/BPFMapEntry"
+          }
+        },
+        "key-size": {
+          "description": "Size of the keys of BPF map in bytes",
+          "type": "integer"
+        },
+        "max-entries": {
+          "description": "Maximum number of entries of BPF map",
+          "type": "integer"
+        },
+        "name": {
+          "description": "Name of BPF map",
+          "type": "string"
+        },
+        "num-entries": {
+          "description": "Number of entries in BPF map",
+          "type": "integer"
+        },
+        "path": {
+          "description": "Path to BPF map",
+          "type": "string"
+        },
+        "type": {
+          "description": "Type of BPF map",
+          "type": "string"
+        },
+        "value-size": {
+          "description": "Size of the values of BPF map in bytes",
+          "type": "integer"
+        }
+      }
+    },
+    "BPFMapEntry": {
+      "description": "BPF map cache entry",
+      "type": "object",
+      "properties": {
+        "desired-action": {
+          "description": "Desired action to be performed",
+          "type": "string",
+          "enum": [
+            "ok",
+            "insert",
+            "delete"
+          ]
+        },
+        "key": {
+          "description": "Key of map entry",
+          "type": "string"
+        },
+        "last-error": {
+          "description": "Last error seen while performing desired action",
+          "type": "string"
+        },
+        "value": {
+          "description": "Value of map entry",
+          "type": "string"
+        }
+      }
+    },
+    "BPFMapList": {
+      "description": "List of BPF Maps",
+      "type": "object",
+      "properties": {
+        "maps": {
+          "description": "Array of open BPF map lists",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/BPFMap"
+          }
+        }
+      }
+    },
     "BackendAddress": {
       "description": "Service backend address",
       "type": "object",
@@ -995,19 +1351,66 @@ func init() {
         "ip"
       ],
       "properties": {
+        "draining": {
+          "description": "Backend was removed from the service and only serves its established connections until the drain timeout",
+          "type": "boolean"
+        },
+        "health": {
+          "description": "Health of the backend as determined by the health check of the service",
+          "type": "string",
+          "enum": [
+            "unknown",
+            "healthy",
+            "unhealthy"
+          ]
+        },
         "ip": {
           "description": "Layer 3 address",
           "type": "string"
         },
+        "name": {
+          "description": "Name of the backend",
+          "type": "string"
+        },
+        "node-name": {
+          "description": "Name of the node the backend is running on",
+          "type": "string"
+        },
         "port": {
           "description": "Layer 4 port number",
           "type": "integer",
           "format": "uint16"
         },
+        "statistics": {
+          "description": "Traffic sent to the backend by the local node",
+          "$ref": "#/definitions/BackendStatistics"
+        },
         "weight": {
           "description": "Weight for Round Robin",
           "type": "integer",
           "format": "uint16"
+        },
+        "zone": {
+          "description": "Zone the backend is running in",
+          "type": "string"
+        }
+      }
+    },
+    "BackendStatistics": {
+      "description": "Load-balancing statistics of a service backend",
+      "type": "object",
+      "properties": {
+        "bytes": {
+          "description": "Number of bytes sent to the backend",
+          "type": "integer"
+        },
+        "connections": {
+          "description": "Number of connections opened to the backend",
+          "type": "integer"
+        },
+        "packets": {
+          "description": "Number of packets sent to the backend",
+          "type": "integer"
         }
       }
     },
@@ -1234,6 +1637,53 @@ func init() {
         }
       }
     },
+    "EgressGatewayPolicy": {
+      "description": "Egress gateway policy redirecting the traffic of the selected endpoints to external destinations through a gateway node",
+      "type": "object",
+      "properties": {
+        "destination-cidrs": {
+          "description": "IPv4 prefixes of the external destinations",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "egress-ip": {
+          "description": "Source IP of the traffic leaving the gateway node, must be assigned to an interface of the gateway node",
+          "type": "string"
+        },
+        "endpoint-selector": {
+          "description": "Selector of the endpoints in the JSON format of the policy language",
+          "type": "string"
+        },
+        "gateway-node": {
+          "description": "Name of the gateway node",
+          "type": "string"
+        },
+        "name": {
+          "description": "Name of the policy",
+          "type": "string"
+        }
+      }
+    },
+    "EncryptionStatus": {
+      "description": "Status of the encryption of traffic between nodes",
+      "type": "object",
+      "properties": {
+        "mode": {
+          "description": "Mechanism used to encrypt the traffic",
+          "type": "string",
+          "enum": [
+            "Disabled",
+            "IPsec"
+          ]
+        },
+        "msg": {
+          "description": "Human readable status/error/warning message",
+          "type": "string"
+        }
+      }
+    },
     "Endpoint": {
       "description": "Endpoint",
       "type": "object",
@@ -1315,6 +1765,10 @@ func init() {
             "both"
           ]
         },
+        "policy-map": {
+          "description": "Fill level of the policy map of the endpoint",
+          "$ref": "#/definitions/EndpointPolicyMap"
+        },
         "policy-revision": {
           "description": "The policy revision this endpoint is running on",
           "type": "integer"
@@ -1461,6 +1915,37 @@ func init() {
         }
       }
     },
+    "EndpointPolicyMap": {
+      "description": "Fill level of the policy map of an endpoint",
+      "type": "object",
+      "properties": {
+        "entries": {
+          "description": "Number of entries required by the policy of the endpoint",
+          "type": "integer"
+        },
+        "max-entries": {
+          "description": "Maximum number of entries of the policy map",
+          "type": "integer"
+        }
+      }
+    },
+    "EndpointPolicyStats": {
+      "description": "Policy hit counters of an endpoint",
+      "type": "object",
+      "properties": {
+        "entries": {
+          "description": "Hit counters of the entries of the policy map",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/PolicyStatsEntry"
+          }
+        },
+        "id": {
+          "description": "ID of the endpoint",
+          "type": "integer"
+        }
+      }
+    },
     "EndpointState": {
       "description": "State of endpoint",
       "type": "string",
@@ -1635,6 +2120,36 @@ func init() {
         }
       }
     },
+    "KVStoreLock": {
+      "description": "Owner information of a lock held in the kvstore",
+      "type": "object",
+      "properties": {
+        "acquired-at": {
+          "description": "Time the lock has been acquired",
+          "type": "string"
+        },
+        "key": {
+          "description": "Backend specific key representing the lock",
+          "type": "string"
+        },
+        "node": {
+          "description": "Name of the node holding the lock",
+          "type": "string"
+        },
+        "operation": {
+          "description": "Operation for which the lock is held",
+          "type": "string"
+        },
+        "path": {
+          "description": "Path which has been locked",
+          "type": "string"
+        },
+        "pid": {
+          "description": "Process ID of the process holding the lock",
+          "type": "integer"
+        }
+      }
+    },
     "KVstoreConfiguration": {
       "description": "Configuration used for the kvstore",
       "properties": {
@@ -1776,6 +2291,10 @@ func init() {
     "NodeElement": {
       "description": "Known node in the cluster",
       "properties": {
+        "encryption-key": {
+          "description": "SPI of the key encrypting the traffic to the node, 0 if the\ntraffic is not encrypted\n",
+          "type": "integer"
+        },
         "health-endpoint-address": {
           "description": "Address used for probing cluster connectivity",
           "$ref": "#/definitions/NodeAddressing"
@@ -1829,6 +2348,36 @@ func init() {
         }
       }
     },
+    "PolicyStatsEntry": {
+      "description": "Number of packets and bytes allowed by an entry of the policy of an\nendpoint\n",
+      "type": "object",
+      "properties": {
+        "bytes": {
+          "description": "Number of bytes allowed by the entry",
+          "type": "integer"
+        },
+        "identity": {
+          "description": "Security identity of the peer",
+          "type": "integer"
+        },
+        "labels": {
+          "description": "Labels of the security identity of the peer",
+          "$ref": "#/definitions/Labels"
+        },
+        "packets": {
+          "description": "Number of packets allowed by the entry",
+          "type": "integer"
+        },
+        "port": {
+          "description": "Destination port, or 0 if the entry applies to all ports",
+          "type": "integer"
+        },
+        "protocol": {
+          "description": "Protocol of the destination port",
+          "type": "string"
+        }
+      }
+    },
     "PolicyTraceResult": {
       "description": "Response to a policy resolution process",
       "type": "object",
@@ -1886,6 +2435,46 @@ func init() {
             "direct-server-return": {
               "description": "Perform direct server return",
               "type": "boolean"
+            },
+            "lb-algorithm": {
+              "description": "Algorithm used to select a backend",
+              "type": "string",
+              "enum": [
+                "hash",
+                "maglev"
+              ]
+            },
+            "session-affinity": {
+              "description": "Session affinity of clients to backends",
+              "type": "string",
+              "enum": [
+                "None",
+                "ClientIP"
+              ]
+            },
+            "session-affinity-timeout": {
+              "description": "Session affinity timeout in seconds",
+              "type": "integer",
+              "maximum": 86400,
+              "minimum": 0
+            },
+            "topology": {
+              "description": "Locality of the backends preferred for new connections",
+              "type": "string",
+              "enum": [
+                "none",
+                "node",
+                "zone"
+              ]
+            },
+            "type": {
+              "description": "Type of the frontend",
+              "type": "string",
+              "enum": [
+                "ClusterIP",
+                "NodePort",
+                "ExternalIPs"
+              ]
             }
           }
         },
@@ -1893,9 +2482,114 @@ func init() {
           "description": "Frontend address",
           "$ref": "#/definitions/FrontendAddress"
         },
+        "health-check": {
+          "description": "Active health check of the backends",
+          "$ref": "#/definitions/ServiceHealthCheck"
+        },
         "id": {
           "description": "Unique identification",
           "type": "integer"
+        },
+        "labels": {
+          "description": "User defined metadata of the service",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
+        "name": {
+          "description": "Name of the service, shared by all its frontends",
+          "type": "string"
+        },
+        "namespace": {
+          "description": "Namespace of the service name",
+          "type": "string"
+        }
+      }
+    },
+    "ServiceFileError": {
+      "description": "Service file which could not be synchronized",
+      "type": "object",
+      "properties": {
+        "error": {
+          "description": "Reason the file could not be synchronized",
+          "type": "string"
+        },
+        "file": {
+          "description": "Name of the file",
+          "type": "string"
+        }
+      }
+    },
+    "ServiceFilesStatus": {
+      "description": "Status of the service file synchronization",
+      "type": "object",
+      "properties": {
+        "directory": {
+          "description": "Directory the service files are read from",
+          "type": "string"
+        },
+        "invalid-files": {
+          "description": "Service files which could not be synchronized",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/ServiceFileError"
+          }
+        },
+        "msg": {
+          "description": "Human readable status/error/warning message",
+          "type": "string"
+        },
+        "services": {
+          "description": "Number of services synchronized from service files",
+          "type": "integer"
+        },
+        "state": {
+          "description": "State the component is in",
+          "type": "string",
+          "enum": [
+            "Ok",
+            "Warning",
+            "Disabled"
+          ]
+        }
+      }
+    },
+    "ServiceHealthCheck": {
+      "description": "Active health check of the backends of a service",
+      "type": "object",
+      "properties": {
+        "healthy-threshold": {
+          "description": "Number of consecutive successful checks for a backend to become healthy",
+          "type": "integer",
+          "minimum": 1
+        },
+        "interval": {
+          "description": "Interval between checks in seconds",
+          "type": "integer",
+          "minimum": 1
+        },
+        "path": {
+          "description": "Path requested by HTTP checks",
+          "type": "string"
+        },
+        "timeout": {
+          "description": "Timeout of a check in seconds",
+          "type": "integer",
+          "minimum": 1
+        },
+        "type": {
+          "description": "Type of check",
+          "type": "string",
+          "enum": [
+            "tcp",
+            "http"
+          ]
+        },
+        "unhealthy-threshold": {
+          "description": "Number of consecutive failed checks for a backend to become unhealthy",
+          "type": "integer",
+          "minimum": 1
         }
       }
     },
@@ -1931,6 +2625,10 @@ func init() {
           "description": "Status of cluster",
           "$ref": "#/definitions/ClusterStatus"
         },
+        "conntrack": {
+          "description": "Status of the connection tracking tables",
+          "$ref": "#/definitions/Status"
+        },
         "container-runtime": {
           "description": "Status of local container runtime",
           "$ref": "#/definitions/Status"
@@ -1939,6 +2637,10 @@ func init() {
           "description": "Status of all endpoint controllers",
           "$ref": "#/definitions/ControllerStatuses"
         },
+        "encryption": {
+          "description": "Status of the encryption of traffic between nodes",
+          "$ref": "#/definitions/EncryptionStatus"
+        },
         "ipam": {
           "description": "Status of IP address management",
           "$ref": "#/definitions/IPAMStatus"
@@ -1954,6 +2656,10 @@ func init() {
         "nodeMonitor": {
           "description": "Status of the node monitor",
           "$ref": "#/definitions/MonitorStatus"
+        },
+        "service-files": {
+          "description": "Status of the service file synchronization",
+          "$ref": "#/definitions/ServiceFilesStatus"
         }
       }
     }
@@ -1968,6 +2674,22 @@ func init() {
         "$ref": "#/definitions/CIDRList"
       }
     },
+    "egress-gateway-policy": {
+      "description": "Egress gateway policy",
+      "name": "policy",
+      "in": "body",
+      "required": true,
+      "schema": {
+        "$ref": "#/definitions/EgressGatewayPolicy"
+      }
+    },
+    "egress-gateway-policy-name": {
+      "type": "string",
+      "description": "Name of the egress gateway policy",
+      "name": "name",
+      "in": "path",
+      "required": true
+    },
     "endpoint-change-request": {
       "name": "endpoint",
       "in": "body",
@@ -2014,6 +2736,13 @@ func init() {
       "in": "path",
       "required": true
     },
+    "kvstore-lock-path": {
+      "type": "string",
+      "description": "Path of the lock",
+      "name": "path",
+      "in": "query",
+      "required": true
+    },
     "labels": {
       "description": "List of labels\n",
       "name": "labels",
@@ -2023,6 +2752,13 @@ func init() {
         "$ref": "#/definitions/Labels"
       }
     },
+    "map-name": {
+      "type": "string",
+      "description": "Name of map",
+      "name": "name",
+      "in": "path",
+      "required": true
+    },
     "pod-name": {
       "type": "string",
       "description": "K8s pod name\n",
@@ -2062,6 +2798,18 @@ func init() {
       "name": "id",
       "in": "path",
       "required": true
+    },
+    "service-name": {
+      "type": "string",
+      "description": "Name of the service",
+      "name": "name",
+      "in": "query"
+    },
+    "service-namespace": {
+      "type": "string",
+      "description": "Namespace of the service",
+      "name": "namespace",
+      "in": "query"
     }
   },
   "x-schemes": [
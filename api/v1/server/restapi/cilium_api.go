@@ -48,9 +48,15 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		IPAMDeleteIPAMIPHandler: ipam.DeleteIPAMIPHandlerFunc(func(params ipam.DeleteIPAMIPParams) middleware.Responder {
 			return middleware.NotImplemented("operation IPAMDeleteIPAMIP has not yet been implemented")
 		}),
+		DaemonDeleteKvstoreLocksHandler: daemon.DeleteKvstoreLocksHandlerFunc(func(params daemon.DeleteKvstoreLocksParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonDeleteKvstoreLocks has not yet been implemented")
+		}),
 		PolicyDeletePolicyHandler: policy.DeletePolicyHandlerFunc(func(params policy.DeletePolicyParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyDeletePolicy has not yet been implemented")
 		}),
+		PolicyDeletePolicyEgressGatewayNameHandler: policy.DeletePolicyEgressGatewayNameHandlerFunc(func(params policy.DeletePolicyEgressGatewayNameParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyDeletePolicyEgressGatewayName has not yet been implemented")
+		}),
 		PrefilterDeletePrefilterHandler: prefilter.DeletePrefilterHandlerFunc(func(params prefilter.DeletePrefilterParams) middleware.Responder {
 			return middleware.NotImplemented("operation PrefilterDeletePrefilter has not yet been implemented")
 		}),
@@ -81,6 +87,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		EndpointGetEndpointIDLogHandler: endpoint.GetEndpointIDLogHandlerFunc(func(params endpoint.GetEndpointIDLogParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointGetEndpointIDLog has not yet been implemented")
 		}),
+		EndpointGetEndpointIDPolicyStatsHandler: endpoint.GetEndpointIDPolicyStatsHandlerFunc(func(params endpoint.GetEndpointIDPolicyStatsParams) middleware.Responder {
+			return middleware.NotImplemented("operation EndpointGetEndpointIDPolicyStats has not yet been implemented")
+		}),
 		DaemonGetHealthzHandler: daemon.GetHealthzHandlerFunc(func(params daemon.GetHealthzParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetHealthz has not yet been implemented")
 		}),
@@ -90,9 +99,24 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		PolicyGetIdentityIDHandler: policy.GetIdentityIDHandlerFunc(func(params policy.GetIdentityIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyGetIdentityID has not yet been implemented")
 		}),
+		DaemonGetKvstoreLocksHandler: daemon.GetKvstoreLocksHandlerFunc(func(params daemon.GetKvstoreLocksParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetKvstoreLocks has not yet been implemented")
+		}),
+		DaemonGetMapHandler: daemon.GetMapHandlerFunc(func(params daemon.GetMapParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetMap has not yet been implemented")
+		}),
+		DaemonGetMapNameHandler: daemon.GetMapNameHandlerFunc(func(params daemon.GetMapNameParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetMapName has not yet been implemented")
+		}),
+		DaemonGetMapNameEntriesHandler: daemon.GetMapNameEntriesHandlerFunc(func(params daemon.GetMapNameEntriesParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetMapNameEntries has not yet been implemented")
+		}),
 		PolicyGetPolicyHandler: policy.GetPolicyHandlerFunc(func(params policy.GetPolicyParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyGetPolicy has not yet been implemented")
 		}),
+		PolicyGetPolicyEgressGatewayHandler: policy.GetPolicyEgressGatewayHandlerFunc(func(params policy.GetPolicyEgressGatewayParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyGetPolicyEgressGateway has not yet been implemented")
+		}),
 		PolicyGetPolicyResolveHandler: policy.GetPolicyResolveHandlerFunc(func(params policy.GetPolicyResolveParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyGetPolicyResolve has not yet been implemented")
 		}),
@@ -129,6 +153,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		PolicyPutPolicyHandler: policy.PutPolicyHandlerFunc(func(params policy.PutPolicyParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyPutPolicy has not yet been implemented")
 		}),
+		PolicyPutPolicyEgressGatewayNameHandler: policy.PutPolicyEgressGatewayNameHandlerFunc(func(params policy.PutPolicyEgressGatewayNameParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyPutPolicyEgressGatewayName has not yet been implemented")
+		}),
 		PrefilterPutPrefilterHandler: prefilter.PutPrefilterHandlerFunc(func(params prefilter.PutPrefilterParams) middleware.Responder {
 			return middleware.NotImplemented("operation PrefilterPutPrefilter has not yet been implemented")
 		}),
@@ -168,8 +195,12 @@ type CiliumAPI struct {
 	EndpointDeleteEndpointIDHandler endpoint.DeleteEndpointIDHandler
 	// IPAMDeleteIPAMIPHandler sets the operation handler for the delete IP a m IP operation
 	IPAMDeleteIPAMIPHandler ipam.DeleteIPAMIPHandler
+	// DaemonDeleteKvstoreLocksHandler sets the operation handler for the delete kvstore locks operation
+	DaemonDeleteKvstoreLocksHandler daemon.DeleteKvstoreLocksHandler
 	// PolicyDeletePolicyHandler sets the operation handler for the delete policy operation
 	PolicyDeletePolicyHandler policy.DeletePolicyHandler
+	// PolicyDeletePolicyEgressGatewayNameHandler sets the operation handler for the delete policy egress gateway name operation
+	PolicyDeletePolicyEgressGatewayNameHandler policy.DeletePolicyEgressGatewayNameHandler
 	// PrefilterDeletePrefilterHandler sets the operation handler for the delete prefilter operation
 	PrefilterDeletePrefilterHandler prefilter.DeletePrefilterHandler
 	// ServiceDeleteServiceIDHandler sets the operation handler for the delete service ID operation
@@ -190,14 +221,26 @@ type CiliumAPI struct {
 	EndpointGetEndpointIDLabelsHandler endpoint.GetEndpointIDLabelsHandler
 	// EndpointGetEndpointIDLogHandler sets the operation handler for the get endpoint ID log operation
 	EndpointGetEndpointIDLogHandler endpoint.GetEndpointIDLogHandler
+	// EndpointGetEndpointIDPolicyStatsHandler sets the operation handler for the get endpoint ID policy stats operation
+	EndpointGetEndpointIDPolicyStatsHandler endpoint.GetEndpointIDPolicyStatsHandler
 	// DaemonGetHealthzHandler sets the operation handler for the get healthz operation
 	DaemonGetHealthzHandler daemon.GetHealthzHandler
 	// PolicyGetIdentityHandler sets the operation handler for the get identity operation
 	PolicyGetIdentityHandler policy.GetIdentityHandler
 	// PolicyGetIdentityIDHandler sets the operation handler for the get identity ID operation
 	PolicyGetIdentityIDHandler policy.GetIdentityIDHandler
+	// DaemonGetKvstoreLocksHandler sets the operation handler for the get kvstore locks operation
+	DaemonGetKvstoreLocksHandler daemon.GetKvstoreLocksHandler
+	// DaemonGetMapHandler sets the operation handler for the get map operation
+	DaemonGetMapHandler daemon.GetMapHandler
+	// DaemonGetMapNameHandler sets the operation handler for the get map name operation
+	DaemonGetMapNameHandler daemon.GetMapNameHandler
+	// DaemonGetMapNameEntriesHandler sets the operation handler for the get map name entries operation
+	DaemonGetMapNameEntriesHandler daemon.GetMapNameEntriesHandler
 	// PolicyGetPolicyHandler sets the operation handler for the get policy operation
 	PolicyGetPolicyHandler policy.GetPolicyHandler
+	// PolicyGetPolicyEgressGatewayHandler sets the operation handler for the get policy egress gateway operation
+	PolicyGetPolicyEgressGatewayHandler policy.GetPolicyEgressGatewayHandler
 	// PolicyGetPolicyResolveHandler sets the operation handler for the get policy resolve operation
 	PolicyGetPolicyResolveHandler policy.GetPolicyResolveHandler
 	// PrefilterGetPrefilterHandler sets the operation handler for the get prefilter operation
@@ -222,6 +265,8 @@ type CiliumAPI struct {
 	EndpointPutEndpointIDLabelsHandler endpoint.PutEndpointIDLabelsHandler
 	// PolicyPutPolicyHandler sets the operation handler for the put policy operation
 	PolicyPutPolicyHandler policy.PutPolicyHandler
+	// PolicyPutPolicyEgressGatewayNameHandler sets the operation handler for the put policy egress gateway name operation
+	PolicyPutPolicyEgressGatewayNameHandler policy.PutPolicyEgressGatewayNameHandler
 	// PrefilterPutPrefilterHandler sets the operation handler for the put prefilter operation
 	PrefilterPutPrefilterHandler prefilter.PutPrefilterHandler
 	// ServicePutServiceIDHandler sets the operation handler for the put service ID operation
@@ -297,10 +342,18 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "ipam.DeleteIPAMIPHandler")
 	}
 
+	if o.DaemonDeleteKvstoreLocksHandler == nil {
+		unregistered = append(unregistered, "daemon.DeleteKvstoreLocksHandler")
+	}
+
 	if o.PolicyDeletePolicyHandler == nil {
 		unregistered = append(unregistered, "policy.DeletePolicyHandler")
 	}
 
+	if o.PolicyDeletePolicyEgressGatewayNameHandler == nil {
+		unregistered = append(unregistered, "policy.DeletePolicyEgressGatewayNameHandler")
+	}
+
 	if o.PrefilterDeletePrefilterHandler == nil {
 		unregistered = append(unregistered, "prefilter.DeletePrefilterHandler")
 	}
@@ -341,6 +394,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "endpoint.GetEndpointIDLogHandler")
 	}
 
+	if o.EndpointGetEndpointIDPolicyStatsHandler == nil {
+		unregistered = append(unregistered, "endpoint.GetEndpointIDPolicyStatsHandler")
+	}
+
 	if o.DaemonGetHealthzHandler == nil {
 		unregistered = append(unregistered, "daemon.GetHealthzHandler")
 	}
@@ -353,10 +410,30 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "policy.GetIdentityIDHandler")
 	}
 
+	if o.DaemonGetKvstoreLocksHandler == nil {
+		unregistered = append(unregistered, "daemon.GetKvstoreLocksHandler")
+	}
+
+	if o.DaemonGetMapHandler == nil {
+		unregistered = append(unregistered, "daemon.GetMapHandler")
+	}
+
+	if o.DaemonGetMapNameHandler == nil {
+		unregistered = append(unregistered, "daemon.GetMapNameHandler")
+	}
+
+	if o.DaemonGetMapNameEntriesHandler == nil {
+		unregistered = append(unregistered, "daemon.GetMapNameEntriesHandler")
+	}
+
 	if o.PolicyGetPolicyHandler == nil {
 		unregistered = append(unregistered, "policy.GetPolicyHandler")
 	}
 
+	if o.PolicyGetPolicyEgressGatewayHandler == nil {
+		unregistered = append(unregistered, "policy.GetPolicyEgressGatewayHandler")
+	}
+
 	if o.PolicyGetPolicyResolveHandler == nil {
 		unregistered = append(unregistered, "policy.GetPolicyResolveHandler")
 	}
@@ -405,6 +482,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "policy.PutPolicyHandler")
 	}
 
+	if o.PolicyPutPolicyEgressGatewayNameHandler == nil {
+		unregistered = append(unregistered, "policy.PutPolicyEgressGatewayNameHandler")
+	}
+
 	if o.PrefilterPutPrefilterHandler == nil {
 		unregistered = append(unregistered, "prefilter.PutPrefilterHandler")
 	}
@@ -513,11 +594,21 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["DELETE"]["/ipam/{ip}"] = ipam.NewDeleteIPAMIP(o.context, o.IPAMDeleteIPAMIPHandler)
 
+	if o.handlers["DELETE"] == nil {
+		o.handlers["DELETE"] = make(map[string]http.Handler)
+	}
+	o.handlers["DELETE"]["/kvstore/locks"] = daemon.NewDeleteKvstoreLocks(o.context, o.DaemonDeleteKvstoreLocksHandler)
+
 	if o.handlers["DELETE"] == nil {
 		o.handlers["DELETE"] = make(map[string]http.Handler)
 	}
 	o.handlers["DELETE"]["/policy"] = policy.NewDeletePolicy(o.context, o.PolicyDeletePolicyHandler)
 
+	if o.handlers["DELETE"] == nil {
+		o.handlers["DELETE"] = make(map[string]http.Handler)
+	}
+	o.handlers["DELETE"]["/policy/egress-gateway/{name}"] = policy.NewDeletePolicyEgressGatewayName(o.context, o.PolicyDeletePolicyEgressGatewayNameHandler)
+
 	if o.handlers["DELETE"] == nil {
 		o.handlers["DELETE"] = make(map[string]http.Handler)
 	}
@@ -568,6 +659,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/endpoint/{id}/log"] = endpoint.NewGetEndpointIDLog(o.context, o.EndpointGetEndpointIDLogHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/endpoint/{id}/policy-stats"] = endpoint.NewGetEndpointIDPolicyStats(o.context, o.EndpointGetEndpointIDPolicyStatsHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -583,11 +679,36 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/identity/{id}"] = policy.NewGetIdentityID(o.context, o.PolicyGetIdentityIDHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/kvstore/locks"] = daemon.NewGetKvstoreLocks(o.context, o.DaemonGetKvstoreLocksHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/map"] = daemon.NewGetMap(o.context, o.DaemonGetMapHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/map/{name}"] = daemon.NewGetMapName(o.context, o.DaemonGetMapNameHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/map/{name}/entries"] = daemon.NewGetMapNameEntries(o.context, o.DaemonGetMapNameEntriesHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/policy"] = policy.NewGetPolicy(o.context, o.PolicyGetPolicyHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/policy/egress-gateway"] = policy.NewGetPolicyEgressGateway(o.context, o.PolicyGetPolicyEgressGatewayHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -648,6 +769,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["PUT"]["/policy"] = policy.NewPutPolicy(o.context, o.PolicyPutPolicyHandler)
 
+	if o.handlers["PUT"] == nil {
+		o.handlers["PUT"] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/policy/egress-gateway/{name}"] = policy.NewPutPolicyEgressGatewayName(o.context, o.PolicyPutPolicyEgressGatewayNameHandler)
+
 	if o.handlers["PUT"] == nil {
 		o.handlers["PUT"] = make(map[string]http.Handler)
 	}
@@ -9,7 +9,10 @@ import (
 	"net/http"
 
 	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
+
+	strfmt "github.com/go-openapi/strfmt"
 )
 
 // NewGetServiceParams creates a new GetServiceParams object
@@ -27,6 +30,15 @@ type GetServiceParams struct {
 
 	// HTTP Request Object
 	HTTPRequest *http.Request
+
+	/*Name of the service
+	  In: query
+	*/
+	Name *string
+	/*Namespace of the service
+	  In: query
+	*/
+	Namespace *string
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -35,8 +47,48 @@ func (o *GetServiceParams) BindRequest(r *http.Request, route *middleware.Matche
 	var res []error
 	o.HTTPRequest = r
 
+	qs := runtime.Values(r.URL.Query())
+
+	qName, qhkName, _ := qs.GetOK("name")
+	if err := o.bindName(qName, qhkName, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qNamespace, qhkNamespace, _ := qs.GetOK("namespace")
+	if err := o.bindNamespace(qNamespace, qhkNamespace, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
+
+func (o *GetServiceParams) bindName(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Name = &raw
+
+	return nil
+}
+
+func (o *GetServiceParams) bindNamespace(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Namespace = &raw
+
+	return nil
+}
@@ -13,7 +13,12 @@ import (
 
 // GetServiceURL generates an URL for the get service operation
 type GetServiceURL struct {
+	Name      *string
+	Namespace *string
+
 	_basePath string
+	// avoid unkeyed usage
+	_ struct{}
 }
 
 // WithBasePath sets the base path for this url builder, only required when it's different from the
@@ -43,6 +48,26 @@ func (o *GetServiceURL) Build() (*url.URL, error) {
 	}
 	result.Path = golangswaggerpaths.Join(_basePath, _path)
 
+	qs := make(url.Values)
+
+	var name string
+	if o.Name != nil {
+		name = *o.Name
+	}
+	if name != "" {
+		qs.Set("name", name)
+	}
+
+	var namespace string
+	if o.Namespace != nil {
+		namespace = *o.Namespace
+	}
+	if namespace != "" {
+		qs.Set("namespace", namespace)
+	}
+
+	result.RawQuery = qs.Encode()
+
 	return &result, nil
 }
 
@@ -47,8 +47,7 @@
 #include "lib/csum.h"
 #include "lib/conntrack.h"
 #include "lib/encap.h"
-
-#define POLICY_ID ((LXC_ID << 16) | SECLABEL)
+#include "lib/egress_gw.h"
 
 struct bpf_elf_map __section_maps CT_MAP6 = {
 #ifdef HAVE_LRU_MAP_TYPE
@@ -125,6 +124,7 @@ static inline int ipv6_l3_from_lxc(struct __sk_buff *skb,
 	struct lb6_key key = {};
 	struct ct_state ct_state_new = {};
 	struct ct_state ct_state = {};
+	struct lb6_stats_key stats_key = {};
 	void *data, *data_end;
 	union v6addr *daddr, orig_dip;
 	uint16_t dstID = WORLD_ID;
@@ -159,8 +159,8 @@ static inline int ipv6_l3_from_lxc(struct __sk_buff *skb,
 	 * address.
 	 */
 	if ((svc = lb6_lookup_service(skb, &key)) != NULL) {
-		ret = lb6_local(skb, l3_off, l4_off, &csum_off, &key, tuple, svc,
-				&ct_state_new);
+		ret = lb6_local(skb, &CT_MAP6, l3_off, l4_off, &csum_off, &key, tuple, svc,
+				&ct_state_new, &stats_key);
 		if (IS_ERR(ret))
 			return ret;
 	}
@@ -190,6 +190,10 @@ skip_service_lookup:
 	if (ret < 0)
 		return ret;
 
+#ifdef ENABLE_LB_STATS
+	lb6_update_stats(skb, &stats_key, ret == CT_NEW);
+#endif
+
 	forwarding_reason = ret;
 
 	switch (ret) {
@@ -443,6 +447,7 @@ static inline int handle_ipv4_from_lxc(struct __sk_buff *skb)
 	struct lb4_key key = {};
 	struct ct_state ct_state_new = {};
 	struct ct_state ct_state = {};
+	struct lb4_stats_key stats_key = {};
 	__be32 orig_dip;
 	uint16_t dstID = WORLD_ID;
 
@@ -475,8 +480,9 @@ static inline int handle_ipv4_from_lxc(struct __sk_buff *skb)
 	ct_state_new.orig_dport = key.dport;
 #ifdef ENABLE_IPV4
 	if ((svc = lb4_lookup_service(skb, &key)) != NULL) {
-		ret = lb4_local(skb, l3_off, l4_off, &csum_off,
-				&key, &tuple, svc, &ct_state_new, ip4->saddr);
+		ret = lb4_local(skb, &CT_MAP4, l3_off, l4_off, &csum_off,
+				&key, &tuple, svc, &ct_state_new, ip4->saddr,
+				&stats_key);
 		if (IS_ERR(ret))
 			return ret;
 	}
@@ -506,6 +512,10 @@ skip_service_lookup:
 	if (ret < 0)
 		return ret;
 
+#ifdef ENABLE_LB_STATS
+	lb4_update_stats(skb, &stats_key, ret == CT_NEW);
+#endif
+
 	forwarding_reason = ret;
 
 	switch (ret) {
@@ -631,6 +641,22 @@ skip_service_lookup:
 		if (likely(lpm4_egress_lookup(orig_dip)))
 			policy_mark_skip(skb);
 
+		/* Send connections of the endpoint which match an egress
+		 * gateway policy to the gateway node. Replies must take the
+		 * path of the original connection. Packets subject to egress
+		 * policy enforcement are not redirected as they would bypass
+		 * it. */
+		if (forwarding_reason != CT_REPLY && forwarding_reason != CT_RELATED) {
+#ifdef POLICY_EGRESS
+			if (is_policy_skip(skb))
+#endif
+			{
+				ret = egress_gw_redirect_v4(skb, ip4->saddr, orig_dip, SECLABEL);
+				if (ret != TC_ACT_OK)
+					return ret;
+			}
+		}
+
 		goto pass_to_stack;
 	}
 
@@ -765,7 +791,7 @@ struct bpf_elf_map __section_maps POLICY_MAP = {
 	.size_key	= sizeof(struct policy_key),
 	.size_value	= sizeof(struct policy_entry),
 	.pinning	= PIN_GLOBAL_NS,
-	.max_elem	= 1024,
+	.max_elem	= ENDPOINT_POLICY_MAP_SIZE,
 };
 
 static inline int __inline__ ipv6_policy(struct __sk_buff *skb, int ifindex, __u32 src_label,
@@ -47,6 +47,12 @@
 #include "lib/drop.h"
 #include "lib/encap.h"
 
+#if defined ENABLE_MASQUERADE && !defined FROM_HOST
+/* Masquerading is only performed on the native device */
+#include "lib/nat.h"
+#define NETDEV_MASQUERADE
+#endif
+
 static inline __u32 derive_sec_ctx(struct __sk_buff *skb, const union v6addr *node_ip,
 				   struct ipv6hdr *ip6)
 {
@@ -56,7 +62,14 @@ static inline __u32 derive_sec_ctx(struct __sk_buff *skb, const union v6addr *no
 	if (ipv6_match_prefix_64((union v6addr *) &ip6->saddr, node_ip)) {
 		/* Read initial 4 bytes of header and then extract flowlabel */
 		__u32 *tmp = (__u32 *) ip6;
-		return bpf_ntohl(*tmp & IPV6_FLOWLABEL_MASK);
+		__u32 identity = bpf_ntohl(*tmp & IPV6_FLOWLABEL_MASK);
+
+		/* The flowlabel only carries the cluster local part of the
+		 * identity of local endpoints, restore the cluster bits. */
+		if (identity >= MIN_ALLOCATED_ID)
+			identity |= LOCAL_CLUSTER_ID_BITS;
+
+		return identity;
 	}
 
 	return WORLD_ID;
@@ -401,8 +414,17 @@ static inline int handle_ipv4(struct __sk_buff *skb, __u32 proxy_identity)
 __section_tail(CILIUM_MAP_CALLS, CILIUM_CALL_IPV4) int tail_handle_ipv4(struct __sk_buff *skb)
 {
 	__u32 proxy_identity = skb->cb[CB_SRC_IDENTITY];
-	int ret = handle_ipv4(skb, proxy_identity);
+	int ret;
 
+#ifdef NETDEV_MASQUERADE
+	/* Translate replies to masqueraded packets back to the endpoint
+	 * before looking up the destination */
+	ret = snat_v4_ingress(skb);
+	if (IS_ERR(ret))
+		return send_drop_notify_error(skb, ret, TC_ACT_SHOT);
+#endif
+
+	ret = handle_ipv4(skb, proxy_identity);
 	if (IS_ERR(ret))
 		return send_drop_notify_error(skb, ret, TC_ACT_SHOT);
 
@@ -468,12 +490,35 @@ int from_netdev(struct __sk_buff *skb)
 	return ret;
 }
 
+#if defined NETDEV_MASQUERADE && defined ENABLE_IPV4
+__section("to-netdev")
+int to_netdev(struct __sk_buff *skb)
+{
+	int ret = TC_ACT_OK;
+
+	switch (skb->protocol) {
+	case bpf_htons(ETH_P_IP):
+		ret = snat_v4_egress(skb);
+		if (IS_ERR(ret))
+			return send_drop_notify_error(skb, ret, TC_ACT_SHOT);
+		ret = TC_ACT_OK;
+		break;
+
+	default:
+		/* Pass all other traffic unmodified */
+		break;
+	}
+
+	return ret;
+}
+#endif
+
 struct bpf_elf_map __section_maps POLICY_MAP = {
 	.type		= BPF_MAP_TYPE_HASH,
 	.size_key	= sizeof(__u32),
 	.size_value	= sizeof(struct policy_entry),
 	.pinning	= PIN_GLOBAL_NS,
-	.max_elem	= 1024,
+	.max_elem	= ENDPOINT_POLICY_MAP_SIZE,
 };
 
 __section_tail(CILIUM_MAP_RES_POLICY, SECLABEL) int handle_policy(struct __sk_buff *skb)
@@ -34,6 +34,7 @@
 #include "lib/geneve.h"
 #include "lib/drop.h"
 #include "lib/policy.h"
+#include "lib/egress_gw.h"
 
 static inline int handle_ipv6(struct __sk_buff *skb)
 {
@@ -127,10 +128,17 @@ static inline int handle_ipv4(struct __sk_buff *skb)
 			goto to_host;
 
 		return ipv4_local_delivery(skb, ETH_HLEN, l4_off, key.tunnel_id, ip4, ep);
-	} else {
-		return DROP_NON_LOCAL;
 	}
 
+	/* Packets of remote endpoints for which the local node is the egress
+	 * gateway are passed to the stack which translates the source address
+	 * to the egress IP and forwards them. */
+	if ((ip4->daddr & IPV4_CLUSTER_MASK) != IPV4_CLUSTER_RANGE &&
+	    egress_gw_is_local_v4(ip4->daddr))
+		goto to_host;
+
+	return DROP_NON_LOCAL;
+
 to_host:
 #ifdef HOST_IFINDEX
 	if (1) {
@@ -204,7 +212,7 @@ struct bpf_elf_map __section_maps POLICY_MAP = {
 	.size_key	= sizeof(__u32),
 	.size_value	= sizeof(struct policy_entry),
 	.pinning	= PIN_GLOBAL_NS,
-	.max_elem	= 1024,
+	.max_elem	= ENDPOINT_POLICY_MAP_SIZE,
 };
 
 __section_tail(CILIUM_MAP_RES_POLICY, SECLABEL) int handle_policy(struct __sk_buff *skb)
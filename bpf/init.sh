@@ -23,6 +23,8 @@ MODE=$5
 NATIVE_DEV=$6
 XDP_DEV=$7
 XDP_MODE=$8
+# Only set if MODE = "direct"
+MASQ=$9
 
 HOST_ID="host"
 WORLD_ID="world"
@@ -221,6 +223,30 @@ function bpf_load()
 	tc filter add dev $DEV $WHERE prio 1 handle 1 bpf da obj $OUT sec $SEC
 }
 
+# Attaches the masquerading program to the egress of a device without
+# recreating the qdisc so that the program loaded on ingress by bpf_load is
+# preserved
+function bpf_load_masq()
+{
+	DEV=$1
+	OPTS=$2
+	IN=$3
+	OUT=$4
+	SEC=$5
+	CALLS_MAP=$6
+
+	NODE_MAC=$(ip link show $DEV | grep ether | awk '{print $2}')
+	NODE_MAC="{.addr=$(mac2array $NODE_MAC)}"
+
+	OPTS="${OPTS} -DNODE_MAC=${NODE_MAC} -DCALLS_MAP=${CALLS_MAP}"
+	bpf_compile $IN $OUT obj "$OPTS"
+
+	tc qdisc replace dev $DEV clsact
+	tc filter del dev $DEV egress 2> /dev/null || true
+	rm "/sys/fs/bpf/tc/globals/$CALLS_MAP" 2> /dev/null || true
+	tc filter add dev $DEV egress prio 1 handle 1 bpf da obj $OUT sec $SEC
+}
+
 HOST_DEV1="cilium_host"
 HOST_DEV2="cilium_net"
 
@@ -315,6 +341,11 @@ if [ "$MODE" = "direct" ]; then
 		OPTS="-DSECLABEL=${ID} -DPOLICY_MAP=cilium_policy_reserved_${ID}"
 		bpf_load $NATIVE_DEV "$OPTS" "ingress" bpf_netdev.c bpf_netdev.o from-netdev $CALLS_MAP
 
+		if [ "$MASQ" = "true" ]; then
+			CALLS_MAP=cilium_calls_netdev_egress_${ID}
+			bpf_load_masq $NATIVE_DEV "$OPTS" bpf_netdev.c bpf_netdev_egress.o to-netdev $CALLS_MAP
+		fi
+
 		echo "$NATIVE_DEV" > $RUNDIR/device.state
 	fi
 elif [ "$MODE" = "lb" ]; then
@@ -168,12 +168,13 @@ struct endpoint_key {
 /* Value of endpoint map */
 struct endpoint_info {
 	__u32		ifindex;
-	__u16		sec_label;
+	__u16		unused; /* formerly 16 bit sec_label */
 	__u16           lxc_id;
 	__u32		flags;
 	mac_t		mac;
 	mac_t		node_mac;
-	__u32		pad[4];
+	__u32		sec_label;
+	__u32		pad[3];
 	struct portmap  portmap[PORTMAP_MAX];
 };
 
@@ -247,6 +248,7 @@ enum {
 #define DROP_NO_SERVICE		-158
 #define DROP_POLICY_L4		-159
 #define DROP_NO_TUNNEL_ENDPOINT -160
+#define DROP_NAT_NO_MAPPING	-161
 
 
 /* Magic skb->mark markers which identify packets originating from the proxy
@@ -343,6 +345,15 @@ struct ct_entry {
 	__u32 src_sec_id;
 };
 
+/* Backend is not selected for new connections */
+#define LB_SLAVE_DRAINING	1
+/* Backend is excluded from the selection for new connections, e.g. because
+ * it is failing its health check
+ */
+#define LB_SLAVE_EXCLUDED	2
+/* Backends with any of these flags are skipped for new connections */
+#define LB_SLAVE_SKIP		(LB_SLAVE_DRAINING | LB_SLAVE_EXCLUDED)
+
 struct lb6_key {
         union v6addr address;
         __be16 dport;		/* L4 port filter, if unset, all ports apply */
@@ -352,7 +363,10 @@ struct lb6_key {
 struct lb6_service {
 	union v6addr target;
 	__be16 port;
-	__u16 count;
+	union {
+		__u16 count;	/* Number of backends (master) */
+		__u16 flags;	/* LB_SLAVE_* flags (backend) */
+	};
 	__u16 rev_nat_index;
 	__u16 weight;
 } __attribute__((packed));
@@ -371,7 +385,10 @@ struct lb4_key {
 struct lb4_service {
 	__be32 target;
 	__be16 port;
-	__u16 count;
+	union {
+		__u16 count;	/* Number of backends (master) */
+		__u16 flags;	/* LB_SLAVE_* flags (backend) */
+	};
 	__u16 rev_nat_index;
 	__u16 weight;
 } __attribute__((packed));
@@ -387,6 +404,59 @@ struct lb_sequence {
 	__u16 idx[LB_RR_MAX_SEQ];
 };
 
+struct lb4_affinity_key {
+	__be32 client_ip;
+	__u16 rev_nat_id;
+	__u16 pad;
+} __attribute__((packed));
+
+struct lb6_affinity_key {
+	union v6addr client_ip;
+	__u16 rev_nat_id;
+	__u16 pad;
+} __attribute__((packed));
+
+struct lb_affinity_val {
+	__u32 last_used;
+	__u16 slave;
+	__u16 pad;
+};
+
+struct lb_affinity_match_key {
+	__u16 rev_nat_id;
+	__u16 pad;
+};
+
+struct lb_affinity_match {
+	__u32 timeout;
+};
+
+struct lb4_stats_key {
+	__be32 address;
+	__be16 port;
+	__u16 rev_nat_index;
+} __attribute__((packed));
+
+struct lb6_stats_key {
+	union v6addr address;
+	__be16 port;
+	__u16 rev_nat_index;
+} __attribute__((packed));
+
+struct lb_stats {
+	__u64 packets;
+	__u64 bytes;
+	__u64 connections;
+};
+
+#ifdef LB_MAGLEV_TABLE_SIZE
+// LB_MAGLEV_TABLE_SIZE generated by daemon in node_config.h
+struct lb_maglev {
+	__u16 count;
+	__u16 idx[LB_MAGLEV_TABLE_SIZE];
+};
+#endif
+
 struct ct_state {
 	__u16 rev_nat_index;
 	__u16 loopback:1,
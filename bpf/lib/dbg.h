@@ -95,6 +95,9 @@ enum {
 				 * arg2: dst sec-id
 				 * arg3: (dport << 16) | protocol
 				 */
+	DBG_EGRESS_GW,		/* arg1: gateway node IP
+				 * arg2: egress IP
+				 */
 };
 
 /* Capture types */
@@ -0,0 +1,141 @@
+/*
+ *  Copyright (C) 2018 Authors of Cilium
+ *
+ *  This program is free software; you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation; either version 2 of the License, or
+ *  (at your option) any later version.
+ *
+ *  This program is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with this program; if not, write to the Free Software
+ *  Foundation, Inc., 51 Franklin St, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+#ifndef __LIB_EGRESS_GW__
+#define __LIB_EGRESS_GW__
+
+#include "common.h"
+#include "dbg.h"
+#include "encap.h"
+
+/* The LPM maps are created by the agent which refuses to enable the egress
+ * gateway if the kernel does not support them. */
+#if defined ENABLE_EGRESS_GATEWAY && defined ENABLE_IPV4 && defined ENCAP_IFINDEX
+
+#ifndef EGRESS_GW_MAP_SIZE
+#define EGRESS_GW_MAP_SIZE	16384
+#endif
+
+/* Egress gateway policies of local endpoints, indexed by the endpoint IP and
+ * the destination CIDR. The prefix length covers the full source address,
+ * i.e. it is 32 + the length of the destination CIDR. Must match
+ * egressmap.Key4 in pkg/maps/egressmap */
+struct egress_key4 {
+	struct bpf_lpm_trie_key lpm_key;
+	__be32	saddr;
+	__be32	daddr;
+};
+
+/* Must match egressmap.Info4 in pkg/maps/egressmap */
+struct egress_info4 {
+	__be32	egress_ip;
+	/* Node IP of the gateway node, 0 if the local node is the gateway */
+	__be32	gateway_ip;
+};
+
+/* Destination CIDRs of the egress gateway policies for which the local node
+ * is the gateway. Must match egressmap.GatewayKey4 in pkg/maps/egressmap */
+struct egress_gw_key4 {
+	struct bpf_lpm_trie_key lpm_key;
+	__be32	daddr;
+};
+
+struct egress_gw_info4 {
+	__be32	egress_ip;
+};
+
+struct bpf_elf_map __section_maps cilium_egress_v4 = {
+	.type		= BPF_MAP_TYPE_LPM_TRIE,
+	.size_key	= sizeof(struct egress_key4),
+	.size_value	= sizeof(struct egress_info4),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= EGRESS_GW_MAP_SIZE,
+	.flags		= BPF_F_NO_PREALLOC,
+};
+
+struct bpf_elf_map __section_maps cilium_egress_gw_v4 = {
+	.type		= BPF_MAP_TYPE_LPM_TRIE,
+	.size_key	= sizeof(struct egress_gw_key4),
+	.size_value	= sizeof(struct egress_gw_info4),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= EGRESS_GW_MAP_SIZE,
+	.flags		= BPF_F_NO_PREALLOC,
+};
+
+/**
+ * Redirect a packet of a local endpoint to the gateway node of a matching
+ * egress gateway policy
+ * @arg skb	packet
+ * @arg saddr	source address of the packet
+ * @arg daddr	destination address of the packet
+ * @arg seclabel	security identity of the endpoint
+ *
+ * The packet is encapsulated and sent to the gateway node which translates
+ * the source address to the egress IP of the policy.
+ *
+ * Returns TC_ACT_OK if no policy with a remote gateway node applies and the
+ * packet must take the regular path, otherwise the result of the redirect
+ */
+static inline int __inline__ egress_gw_redirect_v4(struct __sk_buff *skb, __be32 saddr,
+						   __be32 daddr, __u32 seclabel)
+{
+	struct egress_key4 key = {
+		.lpm_key = { 64, {} },
+		.saddr = saddr,
+		.daddr = daddr,
+	};
+	struct egress_info4 *info;
+
+	info = map_lookup_elem(&cilium_egress_v4, &key);
+	if (!info || !info->gateway_ip)
+		return TC_ACT_OK;
+
+	cilium_dbg(skb, DBG_EGRESS_GW, info->gateway_ip, info->egress_ip);
+
+	return encap_and_redirect_with_nodeid(skb, bpf_ntohl(info->gateway_ip), seclabel);
+}
+
+/**
+ * Check whether the local node is the gateway for packets to daddr which
+ * were received from a remote node
+ */
+static inline bool __inline__ egress_gw_is_local_v4(__be32 daddr)
+{
+	struct egress_gw_key4 key = {
+		.lpm_key = { 32, {} },
+		.daddr = daddr,
+	};
+
+	return map_lookup_elem(&cilium_egress_gw_v4, &key) != NULL;
+}
+
+#else
+
+static inline int __inline__ egress_gw_redirect_v4(struct __sk_buff *skb, __be32 saddr,
+						   __be32 daddr, __u32 seclabel)
+{
+	return TC_ACT_OK;
+}
+
+static inline bool __inline__ egress_gw_is_local_v4(__be32 daddr)
+{
+	return false;
+}
+
+#endif /* ENABLE_EGRESS_GATEWAY && ENABLE_IPV4 && ENCAP_IFINDEX */
+
+#endif /* __LIB_EGRESS_GW__ */
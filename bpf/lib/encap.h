@@ -27,19 +27,12 @@
 
 #ifdef ENCAP_IFINDEX
 
-static inline int __encap_and_redirect(struct __sk_buff *skb, struct endpoint_key *k,
-				       __u32 seclabel, uint8_t *buf, int sz)
+static inline int __encap_and_redirect_with_nodeid(struct __sk_buff *skb, __u32 node_id,
+						   __u32 seclabel, uint8_t *buf, int sz)
 {
-	struct endpoint_key *tunnel;
 	struct bpf_tunnel_key key = {};
-	__u32 node_id;
 	int ret;
 
-	if ((tunnel = map_lookup_elem(&tunnel_endpoint_map, k)) == NULL) {
-		return DROP_NO_TUNNEL_ENDPOINT;
-	}
-
-	node_id = bpf_htonl(tunnel->ip4);
 	key.tunnel_id = seclabel;
 	key.remote_ipv4 = node_id;
 
@@ -69,15 +62,30 @@ static inline int __encap_and_redirect(struct __sk_buff *skb, struct endpoint_ke
 	return redirect(ENCAP_IFINDEX, 0);
 }
 
-static inline int __inline__ encap_and_redirect(struct __sk_buff *skb, struct endpoint_key *key,
-						__u32 seclabel)
+/* Encapsulate the packet to the node with the IP node_id (host byte
+ * order, as expected by skb_set_tunnel_key()) and redirect it to the tunnel
+ * device. */
+static inline int __inline__ encap_and_redirect_with_nodeid(struct __sk_buff *skb, __u32 node_id,
+							    __u32 seclabel)
 {
 #ifdef GENEVE_OPTS
 	uint8_t buf[] = GENEVE_OPTS;
 #else
 	uint8_t buf[] = {};
 #endif
-	return __encap_and_redirect(skb, key, seclabel, buf, sizeof(buf));
+	return __encap_and_redirect_with_nodeid(skb, node_id, seclabel, buf, sizeof(buf));
+}
+
+static inline int __inline__ encap_and_redirect(struct __sk_buff *skb, struct endpoint_key *key,
+						__u32 seclabel)
+{
+	struct endpoint_key *tunnel;
+
+	if ((tunnel = map_lookup_elem(&tunnel_endpoint_map, key)) == NULL) {
+		return DROP_NO_TUNNEL_ENDPOINT;
+	}
+
+	return encap_and_redirect_with_nodeid(skb, bpf_htonl(tunnel->ip4), seclabel);
 }
 
 
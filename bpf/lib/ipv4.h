@@ -47,4 +47,10 @@ static inline int ipv4_hdrlen(struct iphdr *ip4)
 	return ip4->ihl * 4;
 }
 
+static inline bool ipv4_is_fragment(struct iphdr *ip4)
+{
+	/* More fragments flag or non-zero fragment offset */
+	return ip4->frag_off & bpf_htons(0x3FFF);
+}
+
 #endif /* __LIB_IPV4__ */
@@ -36,6 +36,11 @@
 #define CILIUM_LB_MAP_MAX_ENTRIES	65536
 #define CILIUM_LB_MAP_MAX_FE		256
 
+/* Number of following backends tried when a new connection was assigned to
+ * a draining backend.
+ */
+#define LB_DRAIN_RETRIES		4
+
 struct bpf_elf_map __section_maps cilium_lb6_reverse_nat = {
 	.type		= BPF_MAP_TYPE_HASH,
 	.size_key	= sizeof(__u16),
@@ -60,6 +65,16 @@ struct bpf_elf_map __section_maps cilium_lb6_rr_seq = {
 	.max_elem       = CILIUM_LB_MAP_MAX_FE,
 };
 
+#ifdef ENABLE_MAGLEV
+struct bpf_elf_map __section_maps cilium_lb6_maglev = {
+	.type           = BPF_MAP_TYPE_HASH,
+	.size_key       = sizeof(struct lb6_key),
+	.size_value     = sizeof(struct lb_maglev),
+	.pinning        = PIN_GLOBAL_NS,
+	.max_elem       = CILIUM_LB_MAP_MAX_FE,
+};
+#endif
+
 struct bpf_elf_map __section_maps cilium_lb4_reverse_nat = {
 	.type		= BPF_MAP_TYPE_HASH,
 	.size_key	= sizeof(__u16),
@@ -83,6 +98,58 @@ struct bpf_elf_map __section_maps cilium_lb4_rr_seq = {
 	.pinning        = PIN_GLOBAL_NS,
 	.max_elem       = CILIUM_LB_MAP_MAX_FE,
 };
+
+#ifdef ENABLE_MAGLEV
+struct bpf_elf_map __section_maps cilium_lb4_maglev = {
+	.type           = BPF_MAP_TYPE_HASH,
+	.size_key       = sizeof(struct lb4_key),
+	.size_value     = sizeof(struct lb_maglev),
+	.pinning        = PIN_GLOBAL_NS,
+	.max_elem       = CILIUM_LB_MAP_MAX_FE,
+};
+#endif
+struct bpf_elf_map __section_maps cilium_lb6_affinity = {
+	.type           = BPF_MAP_TYPE_HASH,
+	.size_key       = sizeof(struct lb6_affinity_key),
+	.size_value     = sizeof(struct lb_affinity_val),
+	.pinning        = PIN_GLOBAL_NS,
+	.max_elem       = CILIUM_LB_MAP_MAX_ENTRIES,
+};
+
+struct bpf_elf_map __section_maps cilium_lb4_affinity = {
+	.type           = BPF_MAP_TYPE_HASH,
+	.size_key       = sizeof(struct lb4_affinity_key),
+	.size_value     = sizeof(struct lb_affinity_val),
+	.pinning        = PIN_GLOBAL_NS,
+	.max_elem       = CILIUM_LB_MAP_MAX_ENTRIES,
+};
+
+struct bpf_elf_map __section_maps cilium_lb_affinity_match = {
+	.type           = BPF_MAP_TYPE_HASH,
+	.size_key       = sizeof(struct lb_affinity_match_key),
+	.size_value     = sizeof(struct lb_affinity_match),
+	.pinning        = PIN_GLOBAL_NS,
+	.max_elem       = CILIUM_LB_MAP_MAX_ENTRIES,
+};
+
+#ifdef ENABLE_LB_STATS
+struct bpf_elf_map __section_maps cilium_lb6_stats = {
+	.type           = BPF_MAP_TYPE_HASH,
+	.size_key       = sizeof(struct lb6_stats_key),
+	.size_value     = sizeof(struct lb_stats),
+	.pinning        = PIN_GLOBAL_NS,
+	.max_elem       = CILIUM_LB_MAP_MAX_ENTRIES,
+};
+
+struct bpf_elf_map __section_maps cilium_lb4_stats = {
+	.type           = BPF_MAP_TYPE_HASH,
+	.size_key       = sizeof(struct lb4_stats_key),
+	.size_value     = sizeof(struct lb_stats),
+	.pinning        = PIN_GLOBAL_NS,
+	.max_elem       = CILIUM_LB_MAP_MAX_ENTRIES,
+};
+#endif
+
 #define REV_NAT_F_TUPLE_SADDR 1
 #ifdef LB_DEBUG
 #define cilium_dbg_lb cilium_trace
@@ -90,6 +157,54 @@ struct bpf_elf_map __section_maps cilium_lb4_rr_seq = {
 #define cilium_dbg_lb(a, b, c, d)
 #endif
 
+#ifdef ENABLE_LB_STATS
+/* Accounts the packet in skb to the backend the statistics entry of key is
+ * for. new_conn is set if the packet opens a new connection to the backend.
+ */
+static inline void lb_update_stats(struct __sk_buff *skb, void *map,
+				   void *key, bool new_conn)
+{
+	struct lb_stats *stats, new_stats = {};
+
+	stats = map_lookup_elem(map, key);
+	if (!stats) {
+		/* Ignore failures, the entry was either created by another
+		 * CPU or the map is full.
+		 */
+		map_update_elem(map, key, &new_stats, BPF_NOEXIST);
+		stats = map_lookup_elem(map, key);
+		if (!stats)
+			return;
+	}
+
+	__sync_fetch_and_add(&stats->packets, 1);
+	__sync_fetch_and_add(&stats->bytes, skb->len);
+	if (new_conn)
+		__sync_fetch_and_add(&stats->connections, 1);
+}
+
+/* Accounts the packet in skb to the backend of the IPv6 statistics entry of
+ * key. Called by the caller of lb6_local() once it has looked up the
+ * connection tracking entry of the translated packet, new_conn is set if the
+ * lookup returned CT_NEW. Keys with a zero rev_nat_index were not filled in by
+ * lb6_local() and are ignored.
+ */
+static inline void lb6_update_stats(struct __sk_buff *skb,
+				    struct lb6_stats_key *key, bool new_conn)
+{
+	if (key->rev_nat_index)
+		lb_update_stats(skb, &cilium_lb6_stats, key, new_conn);
+}
+
+/* IPv4 variant of lb6_update_stats() for keys filled in by lb4_local() */
+static inline void lb4_update_stats(struct __sk_buff *skb,
+				    struct lb4_stats_key *key, bool new_conn)
+{
+	if (key->rev_nat_index)
+		lb_update_stats(skb, &cilium_lb4_stats, key, new_conn);
+}
+#endif /* ENABLE_LB_STATS */
+
 #ifdef HAVE_MAP_VAL_ADJ
 static inline int lb_next_rr(struct __sk_buff *skb,
 			     struct lb_sequence *seq,
@@ -108,6 +223,81 @@ static inline int lb_next_rr(struct __sk_buff *skb,
 }
 #endif
 
+#if defined ENABLE_MAGLEV && defined HAVE_MAP_VAL_ADJ
+static inline int lb_next_maglev(struct __sk_buff *skb,
+				 struct lb_maglev *tbl,
+				 __u32 hash)
+{
+	int slave = 0;
+	__u32 offset = hash % LB_MAGLEV_TABLE_SIZE;
+
+	if (offset < LB_MAGLEV_TABLE_SIZE) {
+		/* Slave 0 is reserved for the master slot */
+		slave = tbl->idx[offset] + 1;
+		cilium_dbg_lb(skb, DBG_RR_SLAVE_SEL, hash, slave);
+	}
+
+	return slave;
+}
+#endif
+
+/* Returns the timeout of the ClientIP session affinity of the service with
+ * the given reverse NAT index or 0 if the service has no session affinity.
+ */
+static inline __u32 lb_affinity_timeout(__u16 rev_nat_index)
+{
+	struct lb_affinity_match_key key = {
+		.rev_nat_id = rev_nat_index,
+	};
+	struct lb_affinity_match *match;
+
+	match = map_lookup_elem(&cilium_lb_affinity_match, &key);
+	if (match)
+		return match->timeout;
+
+	return 0;
+}
+
+/* Upper bound in seconds of the step in which the last use of a session
+ * affinity entry is refreshed.
+ */
+#define LB_AFFINITY_UPDATE_STEP	10
+
+/* Returns the slave the client was sent to within the affinity timeout or
+ * the given slave otherwise. The returned slave is remembered for the client.
+ *
+ * To avoid a map update for every packet, the entry is only rewritten if the
+ * slave changes or if its last use is older than a step of a quarter of the
+ * timeout, bounded by LB_AFFINITY_UPDATE_STEP. A session may thus expire up
+ * to one step early.
+ */
+static inline __u16 lb_affinity_select(void *map, void *key, __u32 timeout,
+				       __u16 count, __u16 slave)
+{
+	struct lb_affinity_val *val, new_val = {};
+	__u32 now = bpf_ktime_get_sec();
+	__u32 step = timeout / 4;
+
+	if (step > LB_AFFINITY_UPDATE_STEP)
+		step = LB_AFFINITY_UPDATE_STEP;
+	else if (step == 0)
+		step = 1;
+
+	val = map_lookup_elem(map, key);
+	if (val && val->last_used + timeout >= now &&
+	    val->slave > 0 && val->slave <= count) {
+		slave = val->slave;
+		if (now - val->last_used < step)
+			return slave;
+	}
+
+	new_val.last_used = now;
+	new_val.slave = slave;
+	map_update_elem(map, key, &new_val, 0);
+
+	return slave;
+}
+
 static inline __u32 lb_enforce_rehash(struct __sk_buff *skb)
 {
 #ifdef HAVE_SET_HASH_INVALID
@@ -147,6 +337,22 @@ static inline int lb6_select_slave(struct __sk_buff *skb,
 	}
 #endif
 
+#if defined ENABLE_MAGLEV && defined HAVE_MAP_VAL_ADJ
+	/* Services using Maglev consistent hashing have a lookup table which
+	 * only moves about 1/N of the flows when a backend is added or removed.
+	 * The table is indexed with a variable offset which increases the
+	 * verifier complexity in the same way as the weighted selection above,
+	 * so it is only compiled in if enabled with --enable-lb-maglev.
+	 */
+	if (slave == 0) {
+		struct lb_maglev *tbl;
+
+		tbl = map_lookup_elem(&cilium_lb6_maglev, key);
+		if (tbl && tbl->count != 0)
+			slave = lb_next_maglev(skb, tbl, hash);
+	}
+#endif
+
 	if (slave == 0) {
 		/* Slave 0 is reserved for the master slot */
 		slave = (hash % count) + 1;
@@ -181,6 +387,22 @@ static inline int lb4_select_slave(struct __sk_buff *skb,
 	}
 #endif
 
+#if defined ENABLE_MAGLEV && defined HAVE_MAP_VAL_ADJ
+	/* Services using Maglev consistent hashing have a lookup table which
+	 * only moves about 1/N of the flows when a backend is added or removed.
+	 * The table is indexed with a variable offset which increases the
+	 * verifier complexity in the same way as the weighted selection above,
+	 * so it is only compiled in if enabled with --enable-lb-maglev.
+	 */
+	if (slave == 0) {
+		struct lb_maglev *tbl;
+
+		tbl = map_lookup_elem(&cilium_lb4_maglev, key);
+		if (tbl && tbl->count != 0)
+			slave = lb_next_maglev(skb, tbl, hash);
+	}
+#endif
+
 	if (slave == 0) {
 		/* Slave 0 is reserved for the master slot */
 		slave = (hash % count) + 1;
@@ -396,6 +618,65 @@ static inline struct lb6_service *lb6_lookup_slave(struct __sk_buff *skb,
 	return NULL;
 }
 
+/* Returns true if the connection of the packet was established to the
+ * backend slave, i.e. its forward entry exists in the conntrack map.
+ */
+static inline bool lb6_slave_established(struct __sk_buff *skb, void *ct_map,
+					 int l4_off, struct ipv6_ct_tuple *tuple,
+					 struct lb6_key *key,
+					 struct lb6_service *slave)
+{
+	/* flags are TUPLE_F_OUT */
+	struct ipv6_ct_tuple ct_key = {
+		.nexthdr = tuple->nexthdr,
+	};
+
+	if (tuple->nexthdr != IPPROTO_TCP && tuple->nexthdr != IPPROTO_UDP)
+		return false;
+
+	/* Source port offsets for UDP and TCP are the same */
+	if (skb_load_bytes(skb, l4_off, &ct_key.sport, sizeof(ct_key.sport)) < 0)
+		return false;
+
+	ipv6_addr_copy(&ct_key.daddr, &tuple->saddr);
+	ipv6_addr_copy(&ct_key.saddr, &slave->target);
+	ct_key.dport = slave->port ? slave->port : key->dport;
+
+	return map_lookup_elem(ct_map, &ct_key) != NULL;
+}
+
+/* Returns the backend to use for the packet instead of the selected svc.
+ * Connections already established to a draining or excluded backend keep
+ * using it, new ones are moved to one of the next backends which is neither
+ * draining nor excluded.
+ */
+static inline struct lb6_service *lb6_skip_draining(struct __sk_buff *skb,
+						    void *ct_map, int l4_off,
+						    struct ipv6_ct_tuple *tuple,
+						    struct lb6_key *key,
+						    struct lb6_service *svc,
+						    __u16 count)
+{
+	struct lb6_service *next;
+	__u16 slave = key->slave;
+	int i;
+
+	if (!(svc->flags & LB_SLAVE_SKIP) ||
+	    lb6_slave_established(skb, ct_map, l4_off, tuple, key, svc))
+		return svc;
+
+#pragma unroll
+	for (i = 0; i < LB_DRAIN_RETRIES; i++) {
+		next = lb6_lookup_slave(skb, key, (slave + i) % count + 1);
+		if (next && !(next->flags & LB_SLAVE_SKIP))
+			return next;
+	}
+
+	/* No other backend found, stay on the draining one */
+	key->slave = slave;
+	return svc;
+}
+
 static inline int __inline__ lb6_xlate(struct __sk_buff *skb, union v6addr *new_dst, __u8 nexthdr,
 				       int l3_off, int l4_off, struct csum_offset *csum_off,
 				       struct lb6_key *key, struct lb6_service *svc)
@@ -424,18 +705,46 @@ static inline int __inline__ lb6_xlate(struct __sk_buff *skb, union v6addr *new_
 	return TC_ACT_OK;
 }
 
-static inline int __inline__ lb6_local(struct __sk_buff *skb, int l3_off, int l4_off,
+static inline int __inline__ lb6_local(struct __sk_buff *skb, void *ct_map,
+				       int l3_off, int l4_off,
 				       struct csum_offset *csum_off, struct lb6_key *key,
 				       struct ipv6_ct_tuple *tuple, struct lb6_service *svc,
-				       struct ct_state *state)
+				       struct ct_state *state,
+				       struct lb6_stats_key *stats_key)
 {
-	__u16 slave;
+	__u16 slave, count = svc->count;
 	union v6addr *addr;
+	__u32 timeout;
 
-	slave = lb6_select_slave(skb, key, svc->count, svc->weight);
+	slave = lb6_select_slave(skb, key, count, svc->weight);
 	if (!(svc = lb6_lookup_slave(skb, key, slave)))
 		return DROP_NO_SERVICE;
 
+	timeout = lb_affinity_timeout(svc->rev_nat_index);
+	if (timeout) {
+		struct lb6_affinity_key aff_key = {
+			.rev_nat_id = svc->rev_nat_index,
+		};
+		__u16 aff_slave;
+
+		ipv6_addr_copy(&aff_key.client_ip, &tuple->saddr);
+		aff_slave = lb_affinity_select(&cilium_lb6_affinity, &aff_key,
+					       timeout, count, slave);
+		if (aff_slave != slave &&
+		    !(svc = lb6_lookup_slave(skb, key, aff_slave)))
+			return DROP_NO_SERVICE;
+	}
+
+	svc = lb6_skip_draining(skb, ct_map, l4_off, tuple, key, svc, count);
+
+#ifdef ENABLE_LB_STATS
+	/* The statistics are updated by the caller after the connection
+	 * tracking lookup, see lb6_update_stats() */
+	ipv6_addr_copy(&stats_key->address, &svc->target);
+	stats_key->port = svc->port ? svc->port : key->dport;
+	stats_key->rev_nat_index = svc->rev_nat_index;
+#endif
+
 	ipv6_addr_copy(&tuple->daddr, &svc->target);
 	addr = &tuple->daddr;
 
@@ -614,6 +923,65 @@ static inline struct lb4_service *lb4_lookup_slave(struct __sk_buff *skb,
 	return NULL;
 }
 
+/* Returns true if the connection of the packet was established to the
+ * backend slave, i.e. its forward entry exists in the conntrack map.
+ */
+static inline bool lb4_slave_established(struct __sk_buff *skb, void *ct_map,
+					 int l4_off, struct ipv4_ct_tuple *tuple,
+					 struct lb4_key *key,
+					 struct lb4_service *slave)
+{
+	/* flags are TUPLE_F_OUT */
+	struct ipv4_ct_tuple ct_key = {
+		.nexthdr = tuple->nexthdr,
+	};
+
+	if (tuple->nexthdr != IPPROTO_TCP && tuple->nexthdr != IPPROTO_UDP)
+		return false;
+
+	/* Source port offsets for UDP and TCP are the same */
+	if (skb_load_bytes(skb, l4_off, &ct_key.sport, sizeof(ct_key.sport)) < 0)
+		return false;
+
+	ct_key.daddr = tuple->saddr;
+	ct_key.saddr = slave->target;
+	ct_key.dport = slave->port ? slave->port : key->dport;
+
+	return map_lookup_elem(ct_map, &ct_key) != NULL;
+}
+
+/* Returns the backend to use for the packet instead of the selected svc.
+ * Connections already established to a draining or excluded backend keep
+ * using it, new ones are moved to one of the next backends which is neither
+ * draining nor excluded.
+ */
+static inline struct lb4_service *lb4_skip_draining(struct __sk_buff *skb,
+						    void *ct_map, int l4_off,
+						    struct ipv4_ct_tuple *tuple,
+						    struct lb4_key *key,
+						    struct lb4_service *svc,
+						    __u16 count)
+{
+	struct lb4_service *next;
+	__u16 slave = key->slave;
+	int i;
+
+	if (!(svc->flags & LB_SLAVE_SKIP) ||
+	    lb4_slave_established(skb, ct_map, l4_off, tuple, key, svc))
+		return svc;
+
+#pragma unroll
+	for (i = 0; i < LB_DRAIN_RETRIES; i++) {
+		next = lb4_lookup_slave(skb, key, (slave + i) % count + 1);
+		if (next && !(next->flags & LB_SLAVE_SKIP))
+			return next;
+	}
+
+	/* No other backend found, stay on the draining one */
+	key->slave = slave;
+	return svc;
+}
+
 static inline int __inline__
 lb4_xlate(struct __sk_buff *skb, __be32 *new_daddr, __be32 *new_saddr,
 	  __be32 *old_saddr, __u8 nexthdr, int l3_off, int l4_off,
@@ -661,18 +1029,46 @@ lb4_xlate(struct __sk_buff *skb, __be32 *new_daddr, __be32 *new_saddr,
 }
 
 #ifdef ENABLE_IPV4
-static inline int __inline__ lb4_local(struct __sk_buff *skb, int l3_off, int l4_off,
+static inline int __inline__ lb4_local(struct __sk_buff *skb, void *ct_map,
+				       int l3_off, int l4_off,
 				       struct csum_offset *csum_off, struct lb4_key *key,
 				       struct ipv4_ct_tuple *tuple, struct lb4_service *svc,
-				       struct ct_state *state, __be32 saddr)
+				       struct ct_state *state, __be32 saddr,
+				       struct lb4_stats_key *stats_key)
 {
 	__be32 new_saddr = 0, new_daddr;
-	__u16 slave;
+	__u16 slave, count = svc->count;
+	__u32 timeout;
 
-	slave = lb4_select_slave(skb, key, svc->count, svc->weight);
+	slave = lb4_select_slave(skb, key, count, svc->weight);
 	if (!(svc = lb4_lookup_slave(skb, key, slave)))
 		return DROP_NO_SERVICE;
 
+	timeout = lb_affinity_timeout(svc->rev_nat_index);
+	if (timeout) {
+		struct lb4_affinity_key aff_key = {
+			.client_ip = saddr,
+			.rev_nat_id = svc->rev_nat_index,
+		};
+		__u16 aff_slave;
+
+		aff_slave = lb_affinity_select(&cilium_lb4_affinity, &aff_key,
+					       timeout, count, slave);
+		if (aff_slave != slave &&
+		    !(svc = lb4_lookup_slave(skb, key, aff_slave)))
+			return DROP_NO_SERVICE;
+	}
+
+	svc = lb4_skip_draining(skb, ct_map, l4_off, tuple, key, svc, count);
+
+#ifdef ENABLE_LB_STATS
+	/* The statistics are updated by the caller after the connection
+	 * tracking lookup, see lb4_update_stats() */
+	stats_key->address = svc->target;
+	stats_key->port = svc->port ? svc->port : key->dport;
+	stats_key->rev_nat_index = svc->rev_nat_index;
+#endif
+
 	state->rev_nat_index = svc->rev_nat_index;
 	state->addr = new_daddr = svc->target;
 
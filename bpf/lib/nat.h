@@ -0,0 +1,396 @@
+/*
+ *  Copyright (C) 2018 Authors of Cilium
+ *
+ *  This program is free software; you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation; either version 2 of the License, or
+ *  (at your option) any later version.
+ *
+ *  This program is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with this program; if not, write to the Free Software
+ *  Foundation, Inc., 51 Franklin St, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+#ifndef __LIB_NAT__
+#define __LIB_NAT__
+
+#include <linux/ip.h>
+#include <linux/icmp.h>
+#include <linux/tcp.h>
+#include <linux/udp.h>
+
+#include "common.h"
+#include "utils.h"
+#include "ipv4.h"
+#include "csum.h"
+#include "l4.h"
+#include "dbg.h"
+
+#if defined ENABLE_MASQUERADE && defined ENABLE_IPV4
+
+#ifndef SNAT_MAPPING_IPV4_SIZE
+#define SNAT_MAPPING_IPV4_SIZE	65536
+#endif
+
+/* Port range of the translated source ports, must not overlap with the
+ * ephemeral port range of the local stack. */
+#ifndef SNAT_MAPPING_MIN_PORT
+#define SNAT_MAPPING_MIN_PORT	1024
+#endif
+
+#ifndef SNAT_MAPPING_MAX_PORT
+#define SNAT_MAPPING_MAX_PORT	32767
+#endif
+
+/* Number of ports tried before giving up to find a free port */
+#define SNAT_COLLISION_RETRIES	16
+
+/* Lifetime of a mapping in seconds after it has seen a packet, must match
+ * nat.DefaultLifetime in pkg/maps/nat */
+#define SNAT_LIFETIME		360
+
+/* Must match nat.DirEgress and nat.DirIngress in pkg/maps/nat */
+enum {
+	NAT_DIR_EGRESS,
+	NAT_DIR_INGRESS,
+};
+
+/* Tuple of a packet as seen on the native device. Egress mappings are
+ * indexed by the tuple of packets leaving an endpoint, ingress mappings by
+ * the tuple of the replies to the node IP. */
+struct ipv4_nat_tuple {
+	__be32	daddr;
+	__be32	saddr;
+	__be16	dport;
+	__be16	sport;
+	__u8	nexthdr;
+	__u8	flags;
+	__u16	pad;
+};
+
+struct ipv4_nat_entry {
+	__u32	lifetime;
+	/* Source address and port of egress packets, destination address and
+	 * port of ingress packets after the translation */
+	__be32	to_addr;
+	__be16	to_port;
+	__u16	pad;
+};
+
+struct snat_v4_exclude {
+	__be32	net;
+	__be32	mask;
+};
+
+struct bpf_elf_map __section_maps cilium_snat_v4_external = {
+	.type		= BPF_MAP_TYPE_HASH,
+	.size_key	= sizeof(struct ipv4_nat_tuple),
+	.size_value	= sizeof(struct ipv4_nat_entry),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= SNAT_MAPPING_IPV4_SIZE,
+};
+
+#define ICMP_ID_OFF (offsetof(struct icmphdr, un.echo.id))
+
+static inline void __inline__ snat_v4_update_timeout(struct ipv4_nat_entry *entry)
+{
+	entry->lifetime = bpf_ktime_get_sec() + SNAT_LIFETIME;
+}
+
+static inline __u16 __inline__ snat_v4_random_port(void)
+{
+	return SNAT_MAPPING_MIN_PORT +
+	       get_prandom_u32() % (SNAT_MAPPING_MAX_PORT - SNAT_MAPPING_MIN_PORT + 1);
+}
+
+/**
+ * Check whether a packet leaving the node must be masqueraded
+ * @arg saddr	source address
+ * @arg daddr	destination address
+ *
+ * Only packets of local endpoints are masqueraded. Packets to the cluster
+ * prefix and to the excluded prefixes leave the node unmodified.
+ */
+static inline bool __inline__ snat_v4_needed(__be32 saddr, __be32 daddr)
+{
+	if ((saddr & IPV4_MASK) != (IPV4_GATEWAY & IPV4_MASK))
+		return false;
+
+	if ((daddr & IPV4_CLUSTER_MASK) == IPV4_CLUSTER_RANGE)
+		return false;
+
+#ifdef SNAT_IPV4_EXCLUDE_MAPPINGS
+	{
+		struct snat_v4_exclude exclude[] = { SNAT_IPV4_EXCLUDE_MAPPINGS };
+		const int size = (sizeof(exclude) / sizeof(exclude[0]));
+		int i;
+
+#pragma unroll
+		for (i = 0; i < size; i++) {
+			if ((daddr & exclude[i].mask) == exclude[i].net)
+				return false;
+		}
+	}
+#endif
+
+	return true;
+}
+
+/**
+ * Load the NAT tuple of a packet
+ * @arg skb	packet
+ * @arg ip4	IPv4 header of the packet
+ * @arg l4_off	offset to L4 header
+ * @arg dir	NAT_DIR_EGRESS or NAT_DIR_INGRESS
+ * @arg tuple	tuple to fill in
+ *
+ * ICMP echo requests and replies are mapped by their identifier, which is
+ * stored as both source and destination port.
+ *
+ * Returns 0 if the packet can be translated, 1 if the packet is not subject
+ * to translation, or a negative DROP_* reason
+ */
+static inline int __inline__ snat_v4_load_tuple(struct __sk_buff *skb, struct iphdr *ip4,
+						int l4_off, int dir,
+						struct ipv4_nat_tuple *tuple)
+{
+	struct icmphdr icmp;
+
+	tuple->nexthdr = ip4->protocol;
+	tuple->daddr = ip4->daddr;
+	tuple->saddr = ip4->saddr;
+	tuple->flags = dir;
+
+	switch (tuple->nexthdr) {
+	case IPPROTO_TCP:
+	case IPPROTO_UDP:
+		/* Port offsets for UDP and TCP are the same */
+		if (l4_load_port(skb, l4_off + TCP_SPORT_OFF, &tuple->sport) < 0 ||
+		    l4_load_port(skb, l4_off + TCP_DPORT_OFF, &tuple->dport) < 0)
+			return DROP_INVALID;
+		break;
+
+	case IPPROTO_ICMP:
+		if (skb_load_bytes(skb, l4_off, &icmp, sizeof(icmp)) < 0)
+			return DROP_INVALID;
+		if (icmp.type != (dir == NAT_DIR_EGRESS ? ICMP_ECHO : ICMP_ECHOREPLY))
+			return 1;
+		tuple->sport = icmp.un.echo.id;
+		tuple->dport = icmp.un.echo.id;
+		break;
+
+	default:
+		return 1;
+	}
+
+	return 0;
+}
+
+/**
+ * Rewrite the address and port of a packet and fix up the checksums
+ * @arg skb		packet
+ * @arg l4_off		offset to L4 header
+ * @arg nexthdr		L4 protocol
+ * @arg dir		NAT_DIR_EGRESS to rewrite the source, NAT_DIR_INGRESS to
+ *			rewrite the destination
+ * @arg old_addr	current address
+ * @arg new_addr	translated address
+ * @arg old_port	current port
+ * @arg new_port	translated port
+ *
+ * NOTE: Calling this function will invalidate any pkt context offset
+ * validation for direct packet access.
+ *
+ * Return 0 on success or a negative DROP_* reason
+ */
+static inline int __inline__ snat_v4_rewrite(struct __sk_buff *skb, int l4_off, __u8 nexthdr,
+					     int dir, __be32 old_addr, __be32 new_addr,
+					     __be16 old_port, __be16 new_port)
+{
+	int addr_off = dir == NAT_DIR_EGRESS ? offsetof(struct iphdr, saddr) :
+					       offsetof(struct iphdr, daddr);
+	int port_off = dir == NAT_DIR_EGRESS ? TCP_SPORT_OFF : TCP_DPORT_OFF;
+	struct csum_offset csum = {};
+	int ret;
+
+	csum_l4_offset_and_flags(nexthdr, &csum);
+	if (nexthdr == IPPROTO_ICMP) {
+		/* The ICMP checksum does not cover a pseudo header, only the
+		 * identifier has to be accounted for. */
+		csum.offset = offsetof(struct icmphdr, checksum);
+		port_off = ICMP_ID_OFF;
+	}
+
+	if (old_port != new_port) {
+		ret = l4_modify_port(skb, l4_off, port_off, &csum, new_port, old_port);
+		if (IS_ERR(ret))
+			return ret;
+	}
+
+	if (skb_store_bytes(skb, ETH_HLEN + addr_off, &new_addr, 4, 0) < 0)
+		return DROP_WRITE_ERROR;
+
+	if (l3_csum_replace(skb, ETH_HLEN + offsetof(struct iphdr, check), old_addr, new_addr, 4) < 0)
+		return DROP_CSUM_L3;
+
+	if (nexthdr != IPPROTO_ICMP &&
+	    csum_l4_replace(skb, l4_off, &csum, old_addr, new_addr, 4 | BPF_F_PSEUDO_HDR) < 0)
+		return DROP_CSUM_L4;
+
+	return 0;
+}
+
+/**
+ * Create the egress and ingress mapping of a new flow
+ * @arg tuple	egress tuple of the flow
+ * @arg state	egress mapping to fill in
+ *
+ * The original source port is preserved if it is within the port range and
+ * not in use for the same destination. Otherwise random ports are tried.
+ * The ingress mapping is created first to reserve the port.
+ *
+ * Return 0 on success or DROP_NAT_NO_MAPPING
+ */
+static inline int __inline__ snat_v4_new_mapping(struct ipv4_nat_tuple *tuple,
+						 struct ipv4_nat_entry *state)
+{
+	struct ipv4_nat_tuple rtuple = {
+		.daddr = SNAT_IPV4_EXTERNAL,
+		.saddr = tuple->daddr,
+		.sport = tuple->dport,
+		.nexthdr = tuple->nexthdr,
+		.flags = NAT_DIR_INGRESS,
+	};
+	struct ipv4_nat_entry rstate = {
+		.to_addr = tuple->saddr,
+		.to_port = tuple->sport,
+	};
+	__u16 port = bpf_ntohs(tuple->sport);
+	int i;
+
+	snat_v4_update_timeout(&rstate);
+	snat_v4_update_timeout(state);
+	state->to_addr = SNAT_IPV4_EXTERNAL;
+
+	if (port < SNAT_MAPPING_MIN_PORT || port > SNAT_MAPPING_MAX_PORT)
+		port = snat_v4_random_port();
+
+#pragma unroll
+	for (i = 0; i < SNAT_COLLISION_RETRIES; i++) {
+		rtuple.dport = bpf_htons(port);
+		if (tuple->nexthdr == IPPROTO_ICMP)
+			rtuple.sport = rtuple.dport;
+
+		if (map_update_elem(&cilium_snat_v4_external, &rtuple, &rstate, BPF_NOEXIST) == 0) {
+			state->to_port = rtuple.dport;
+			if (map_update_elem(&cilium_snat_v4_external, tuple, state, 0) < 0) {
+				map_delete_elem(&cilium_snat_v4_external, &rtuple);
+				return DROP_NAT_NO_MAPPING;
+			}
+			return 0;
+		}
+
+		port = snat_v4_random_port();
+	}
+
+	return DROP_NAT_NO_MAPPING;
+}
+
+/**
+ * Masquerade a packet leaving the node to the node IP
+ * @arg skb	packet
+ *
+ * Return 0 if the packet was translated or does not need to be, or a
+ * negative DROP_* reason
+ */
+static inline int __inline__ snat_v4_egress(struct __sk_buff *skb)
+{
+	struct ipv4_nat_tuple tuple = {};
+	struct ipv4_nat_entry *state, new_state = {};
+	void *data, *data_end;
+	struct iphdr *ip4;
+	__be32 to_addr;
+	__be16 to_port;
+	int l4_off, ret;
+
+	if (!revalidate_data(skb, &data, &data_end, &ip4))
+		return DROP_INVALID;
+
+	if (!snat_v4_needed(ip4->saddr, ip4->daddr))
+		return 0;
+
+	if (ipv4_is_fragment(ip4))
+		return DROP_FRAG_NOSUPPORT;
+
+	l4_off = ETH_HLEN + ipv4_hdrlen(ip4);
+	ret = snat_v4_load_tuple(skb, ip4, l4_off, NAT_DIR_EGRESS, &tuple);
+	if (ret != 0)
+		return ret < 0 ? ret : 0;
+
+	if ((state = map_lookup_elem(&cilium_snat_v4_external, &tuple)) != NULL) {
+		snat_v4_update_timeout(state);
+	} else {
+		ret = snat_v4_new_mapping(&tuple, &new_state);
+		if (IS_ERR(ret))
+			return ret;
+		state = &new_state;
+	}
+
+	to_addr = state->to_addr;
+	to_port = state->to_port;
+
+	return snat_v4_rewrite(skb, l4_off, tuple.nexthdr, NAT_DIR_EGRESS,
+			       tuple.saddr, to_addr, tuple.sport, to_port);
+}
+
+/**
+ * Translate replies to masqueraded packets back to the endpoint
+ * @arg skb	packet
+ *
+ * Packets to the node IP without a mapping are left untouched and passed to
+ * the local stack.
+ *
+ * NOTE: Calling this function will invalidate any pkt context offset
+ * validation for direct packet access.
+ *
+ * Return 0 if the packet was translated or does not need to be, or a
+ * negative DROP_* reason
+ */
+static inline int __inline__ snat_v4_ingress(struct __sk_buff *skb)
+{
+	struct ipv4_nat_tuple tuple = {};
+	struct ipv4_nat_entry *state;
+	void *data, *data_end;
+	struct iphdr *ip4;
+	__be32 to_addr;
+	__be16 to_port;
+	int l4_off, ret;
+
+	if (!revalidate_data(skb, &data, &data_end, &ip4))
+		return DROP_INVALID;
+
+	if (ip4->daddr != SNAT_IPV4_EXTERNAL || ipv4_is_fragment(ip4))
+		return 0;
+
+	l4_off = ETH_HLEN + ipv4_hdrlen(ip4);
+	ret = snat_v4_load_tuple(skb, ip4, l4_off, NAT_DIR_INGRESS, &tuple);
+	if (ret != 0)
+		return ret < 0 ? ret : 0;
+
+	if ((state = map_lookup_elem(&cilium_snat_v4_external, &tuple)) == NULL)
+		return 0;
+
+	snat_v4_update_timeout(state);
+	to_addr = state->to_addr;
+	to_port = state->to_port;
+
+	return snat_v4_rewrite(skb, l4_off, tuple.nexthdr, NAT_DIR_INGRESS,
+			       tuple.daddr, to_addr, tuple.dport, to_port);
+}
+
+#endif /* ENABLE_MASQUERADE && ENABLE_IPV4 */
+#endif /* __LIB_NAT__ */
@@ -34,6 +34,8 @@
 #define HOST_ID 1
 #define WORLD_ID 2
 #define CLUSTER_ID 3
+#define MIN_ALLOCATED_ID 256
+#define LOCAL_CLUSTER_ID_BITS 0x10000
 #define HOST_IFINDEX_MAC { .addr = { 0xce, 0x72, 0xa7, 0x03, 0x88, 0x56 } }
 #define NAT46_PREFIX { .addr = { 0xbe, 0xef, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0xa, 0x0, 0x0, 0x0, 0x0, 0x0 } }
 #define IPV4_MASK 0xffff
@@ -49,5 +51,14 @@
 #define NODE_MAC { .addr = { 0xde, 0xad, 0xbe, 0xef, 0xc0, 0xde } }
 #define ENABLE_IPV4
 #define LB_RR_MAX_SEQ 31
+#define LB_MAGLEV_TABLE_SIZE 1021
+#define ENABLE_MAGLEV 1
+#define ENABLE_LB_STATS 1
 #define TUNNEL_ENDPOINT_MAP_SIZE 65536
 #define ENDPOINTS_MAP_SIZE 65536
+#define ENDPOINT_POLICY_MAP_SIZE 1024
+#define SNAT_IPV4_EXTERNAL 0x0b22a8c0
+#define SNAT_MAPPING_IPV4_SIZE 65536
+#define SNAT_MAPPING_MIN_PORT 1024
+#define SNAT_MAPPING_MAX_PORT 32767
+#define SNAT_IPV4_EXCLUDE_MAPPINGS { .net = 0xa8c0, .mask = 0xffff }
@@ -15,6 +15,12 @@
 package cmd
 
 import (
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/u8proto"
+
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +30,70 @@ var bpfCtCmd = &cobra.Command{
 	Short: "Connection tracking tables",
 }
 
+// ctFilterFlags holds the values of the flags selecting CT entries, shared by
+// "cilium bpf ct list" and "cilium bpf ct flush"
+var ctFilterFlags struct {
+	srcIP   string
+	dstIP   string
+	srcPort uint16
+	dstPort uint16
+	proto   string
+	flags   []string
+	minIdle time.Duration
+	maxIdle time.Duration
+}
+
 func init() {
 	bpfCmd.AddCommand(bpfCtCmd)
 }
+
+// addCtFilterFlags adds the flags selecting CT entries to cmd
+func addCtFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&ctFilterFlags.srcIP, "src-ip", "", "Only select entries with the given source IP")
+	cmd.Flags().StringVar(&ctFilterFlags.dstIP, "dst-ip", "", "Only select entries with the given destination IP")
+	cmd.Flags().Uint16Var(&ctFilterFlags.srcPort, "src-port", 0, "Only select entries with the given source port")
+	cmd.Flags().Uint16Var(&ctFilterFlags.dstPort, "dst-port", 0, "Only select entries with the given destination port")
+	cmd.Flags().StringVar(&ctFilterFlags.proto, "proto", "", "Only select entries with the given protocol (tcp, udp, icmp, icmpv6)")
+	cmd.Flags().StringSliceVar(&ctFilterFlags.flags, "flags", nil,
+		"Only select entries with all of the given flags (in, out, related, rx-closing, tx-closing, nat46, lb-loopback)")
+	cmd.Flags().DurationVar(&ctFilterFlags.minIdle, "min-idle", 0, "Only select entries which did not see a packet for at least the given duration")
+	cmd.Flags().DurationVar(&ctFilterFlags.maxIdle, "max-idle", 0, "Only select entries which saw a packet within the given duration")
+}
+
+// parseCtFilter returns the filter selecting CT entries given on the command
+// line
+func parseCtFilter() *ctmap.ListFilter {
+	filter := &ctmap.ListFilter{
+		SrcPort: ctFilterFlags.srcPort,
+		DstPort: ctFilterFlags.dstPort,
+		Flags:   ctFilterFlags.flags,
+		MinIdle: ctFilterFlags.minIdle,
+		MaxIdle: ctFilterFlags.maxIdle,
+	}
+
+	if ctFilterFlags.srcIP != "" {
+		if filter.SrcIP = net.ParseIP(ctFilterFlags.srcIP); filter.SrcIP == nil {
+			Fatalf("Invalid source IP %s", ctFilterFlags.srcIP)
+		}
+	}
+
+	if ctFilterFlags.dstIP != "" {
+		if filter.DstIP = net.ParseIP(ctFilterFlags.dstIP); filter.DstIP == nil {
+			Fatalf("Invalid destination IP %s", ctFilterFlags.dstIP)
+		}
+	}
+
+	if ctFilterFlags.proto != "" {
+		proto, err := u8proto.ParseProtocol(ctFilterFlags.proto)
+		if err != nil {
+			Fatalf("Invalid protocol: %s", err)
+		}
+		filter.Proto = proto
+	}
+
+	if err := filter.ValidateFlags(); err != nil {
+		Fatalf("Invalid flags: %s", err)
+	}
+
+	return filter
+}
@@ -27,26 +27,29 @@ import (
 
 // bpfCtFlushCmd represents the bpf_ct_flush command
 var bpfCtFlushCmd = &cobra.Command{
-	Use:    "flush",
-	Short:  "Flush all connection tracking entries",
+	Use:    "flush ( <endpoint identifier> | global )",
+	Short:  "Flush connection tracking entries",
+	Long:   "Flush all connection tracking entries or only the ones selected by the given filters",
 	PreRun: requireEndpointIDorGlobal,
 	Run: func(cmd *cobra.Command, args []string) {
 		common.RequireRootPrivilege("cilium bpf ct flush")
+		filter := parseCtFilter()
 		if args[0] == "global" {
-			flushCtProto(ctmap.MapName6Global, "")
-			flushCtProto(ctmap.MapName4Global, "")
+			flushCtProto(ctmap.MapName6Global, "", filter)
+			flushCtProto(ctmap.MapName4Global, "", filter)
 		} else {
-			flushCtProto(ctmap.MapName6, args[0])
-			flushCtProto(ctmap.MapName4, args[0])
+			flushCtProto(ctmap.MapName6, args[0], filter)
+			flushCtProto(ctmap.MapName4, args[0], filter)
 		}
 	},
 }
 
 func init() {
 	bpfCtCmd.AddCommand(bpfCtFlushCmd)
+	addCtFilterFlags(bpfCtFlushCmd)
 }
 
-func flushCtProto(mapType, eID string) {
+func flushCtProto(mapType, eID string, filter *ctmap.ListFilter) {
 	file := bpf.MapPath(mapType + eID)
 	m, err := bpf.OpenMap(file)
 	if err != nil {
@@ -57,6 +60,11 @@ func flushCtProto(mapType, eID string) {
 		}
 	}
 	defer m.Close()
-	entries := ctmap.Flush(m, mapType)
+	var entries int
+	if filter.IsEmpty() {
+		entries = ctmap.Flush(m, mapType)
+	} else {
+		entries = ctmap.FlushFiltered(m, mapType, filter)
+	}
 	fmt.Println("Flushed", entries, "entries from", mapType)
 }
@@ -27,27 +27,41 @@ import (
 
 // bpfCtListCmd represents the bpf_ct_list command
 var bpfCtListCmd = &cobra.Command{
-	Use:    "list",
+	Use:    "list ( <endpoint identifier> | global )",
 	Short:  "List connection tracking entries",
 	PreRun: requireEndpointIDorGlobal,
 	Run: func(cmd *cobra.Command, args []string) {
 		common.RequireRootPrivilege("cilium bpf ct list")
+		filter := parseCtFilter()
+		entries := []*ctmap.Entry{}
 		if args[0] == "global" {
-			dumpCtProto(ctmap.MapName6Global, "")
-			dumpCtProto(ctmap.MapName4Global, "")
+			entries = append(entries, dumpCtProto(ctmap.MapName6Global, "", filter)...)
+			entries = append(entries, dumpCtProto(ctmap.MapName4Global, "", filter)...)
 		} else {
-			dumpCtProto(ctmap.MapName6, args[0])
-			dumpCtProto(ctmap.MapName4, args[0])
+			entries = append(entries, dumpCtProto(ctmap.MapName6, args[0], filter)...)
+			entries = append(entries, dumpCtProto(ctmap.MapName4, args[0], filter)...)
+		}
+
+		if len(dumpOutput) > 0 {
+			if err := OutputPrinter(entries); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+
+		for _, entry := range entries {
+			fmt.Println(entry)
 		}
 	},
 }
 
 func init() {
 	bpfCtCmd.AddCommand(bpfCtListCmd)
+	addCtFilterFlags(bpfCtListCmd)
+	AddMultipleOutput(bpfCtListCmd)
 }
 
-func dumpCtProto(mapType, eID string) {
-
+func dumpCtProto(mapType, eID string, filter *ctmap.ListFilter) []*ctmap.Entry {
 	file := bpf.MapPath(mapType + eID)
 	m, err := bpf.OpenMap(file)
 	if err != nil {
@@ -58,9 +72,9 @@ func dumpCtProto(mapType, eID string) {
 		}
 	}
 	defer m.Close()
-	out, err := ctmap.ToString(m, mapType)
+	entries, err := ctmap.ListEntries(m, mapType, filter)
 	if err != nil {
 		Fatalf("Error while dumping BPF Map: %s", err)
 	}
-	fmt.Println(out)
+	return entries
 }
@@ -30,9 +30,13 @@ const (
 	idTitle             = "ID"
 	serviceAddressTitle = "SERVICE ADDRESS"
 	backendAddressTitle = "BACKEND ADDRESS"
+	clientAddressTitle  = "CLIENT ADDRESS (SERVICE ID)"
 )
 
-var listRevNAT bool
+var (
+	listRevNAT   bool
+	listAffinity bool
+)
 var serviceList = map[string][]string{}
 
 // bpfCtListCmd represents the bpf_ct_list command
@@ -47,6 +51,10 @@ var bpfLBListCmd = &cobra.Command{
 			firstTitle = idTitle
 			lbmap.RevNat4Map.Dump(lbmap.RevNat4DumpParser, dumpRevNAT)
 			lbmap.RevNat6Map.Dump(lbmap.RevNat6DumpParser, dumpRevNAT)
+		} else if listAffinity {
+			firstTitle = clientAddressTitle
+			dumpAffinity(lbmap.Affinity4Map)
+			dumpAffinity(lbmap.Affinity6Map)
 		} else {
 			firstTitle = serviceAddressTitle
 			lbmap.Service4Map.Dump(lbmap.Service4DumpParser, dumpService)
@@ -81,6 +89,7 @@ var bpfLBListCmd = &cobra.Command{
 func init() {
 	bpfLBCmd.AddCommand(bpfLBListCmd)
 	bpfLBListCmd.Flags().BoolVarP(&listRevNAT, "revnat", "", false, "List reverse NAT entries")
+	bpfLBListCmd.Flags().BoolVarP(&listAffinity, "affinity", "", false, "List session affinity entries")
 	AddMultipleOutput(bpfLBListCmd)
 }
 
@@ -97,3 +106,22 @@ func dumpRevNAT(key bpf.MapKey, value bpf.MapValue) {
 	revNatV := value.(lbmap.RevNatValue)
 	serviceList[revNatK.String()] = append(serviceList[revNatK.String()], revNatV.String())
 }
+
+func dumpAffinity(m *bpf.Map) {
+	entries, err := lbmap.DumpAffinity(m)
+	if err != nil {
+		// The map does not exist if the address family is disabled
+		return
+	}
+
+	var now uint32
+	if t, err := bpf.GetMtime(); err == nil {
+		now = uint32(t / 1000000000)
+	}
+
+	for _, entry := range entries {
+		key := entry.Key.String()
+		value := fmt.Sprintf("backend %d, last used %ds ago", entry.Value.Slave, now-entry.Value.LastUsed)
+		serviceList[key] = append(serviceList[key], value)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/maps/nat"
+
+	"github.com/spf13/cobra"
+)
+
+// bpfNatListCmd represents the bpf_nat_list command
+var bpfNatListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List masquerading mappings",
+	Run: func(cmd *cobra.Command, args []string) {
+		common.RequireRootPrivilege("cilium bpf nat list")
+
+		file := bpf.MapPath(nat.MapName4)
+		m, err := bpf.OpenMap(file)
+		if err != nil {
+			if err == os.ErrNotExist {
+				Fatalf("Unable to open %s: %s: is --enable-bpf-masquerade set?", file, err)
+			}
+			Fatalf("Unable to open %s: %s", file, err)
+		}
+		defer m.Close()
+
+		entries, err := nat.ListEntries(m)
+		if err != nil {
+			Fatalf("Error while dumping BPF Map: %s", err)
+		}
+
+		if len(dumpOutput) > 0 {
+			if err := OutputPrinter(entries); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+
+		for _, entry := range entries {
+			fmt.Println(entry)
+		}
+	},
+}
+
+func init() {
+	bpfNatCmd.AddCommand(bpfNatListCmd)
+	AddMultipleOutput(bpfNatListCmd)
+}
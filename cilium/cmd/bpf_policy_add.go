@@ -54,7 +54,7 @@ func updatePolicyKey(cmd *cobra.Command, args []string, add bool) {
 	}
 
 	file := bpf.MapPath(policymap.MapName + lbl)
-	policyMap, _, err := policymap.OpenMap(file)
+	policyMap, err := policymap.OpenGlobalMap(file)
 	if err != nil {
 		Fatalf("Cannot open policymap '%s' : %s", file, err)
 	}
@@ -64,13 +64,12 @@ func listMap(cmd *cobra.Command, args []string) {
 	}
 
 	file := bpf.MapPath(policymap.MapName + lbl)
-	fd, err := bpf.ObjGet(file)
+	m, err := policymap.OpenGlobalMap(file)
 	if err != nil {
 		Fatalf("%s\n", err)
 	}
-	defer bpf.ObjClose(fd)
+	defer m.Close()
 
-	m := policymap.PolicyMap{Fd: fd}
 	statsMap, err := m.DumpToSlice()
 	if err != nil {
 		Fatalf("Error while opening bpf Map: %s\n", err)
@@ -19,6 +19,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"text/tabwriter"
 
 	endpointApi "github.com/cilium/cilium/api/v1/client/endpoint"
 	"github.com/cilium/cilium/api/v1/models"
@@ -26,7 +28,10 @@ import (
 	"github.com/spf13/viper"
 )
 
-var lbls []string
+var (
+	lbls             []string
+	printPolicyStats bool
+)
 
 // endpointGetCmd represents the endpoint_get command
 var endpointGetCmd = &cobra.Command{
@@ -58,6 +63,11 @@ var endpointGetCmd = &cobra.Command{
 			endpointInst = append(endpointInst, result)
 		}
 
+		if printPolicyStats {
+			getEndpointPolicyStats(endpointInst)
+			return
+		}
+
 		if len(dumpOutput) > 0 {
 			if err := OutputPrinter(endpointInst); err != nil {
 				os.Exit(1)
@@ -92,5 +102,45 @@ var endpointGetCmd = &cobra.Command{
 func init() {
 	endpointCmd.AddCommand(endpointGetCmd)
 	endpointGetCmd.Flags().StringSliceVarP(&lbls, "labels", "l", []string{}, "list of labels")
+	endpointGetCmd.Flags().BoolVar(&printPolicyStats, "policy-stats", false, "Display the packets and bytes allowed by each policy entry")
 	AddMultipleOutput(endpointGetCmd)
 }
+
+func getEndpointPolicyStats(endpoints []*models.Endpoint) {
+	stats := make([]*models.EndpointPolicyStats, 0, len(endpoints))
+	for _, ep := range endpoints {
+		eID := strconv.FormatInt(ep.ID, 10)
+		result, err := client.EndpointPolicyStatsGet(eID)
+		if err != nil {
+			Fatalf("Cannot get policy stats of endpoint %s: %s\n", eID, err)
+		}
+		stats = append(stats, result)
+	}
+
+	if len(dumpOutput) > 0 {
+		if err := OutputPrinter(stats); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "ENDPOINT\tIDENTITY\tLABELS (source:key[=value])\tPORT/PROTO\tBYTES\tPACKETS\t\n")
+	for _, s := range stats {
+		for _, e := range s.Entries {
+			port := models.PortProtocolANY
+			if e.Port != 0 {
+				port = fmt.Sprintf("%d/%s", e.Port, e.Protocol)
+			}
+			lbls := []string(e.Labels)
+			if len(lbls) == 0 {
+				lbls = []string{""}
+			}
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%d\t%d\t\n", s.ID, e.Identity, lbls[0], port, e.Bytes, e.Packets)
+			for _, lbl := range lbls[1:] {
+				fmt.Fprintf(w, "\t\t%s\t\t\t\t\n", lbl)
+			}
+		}
+	}
+	w.Flush()
+}
@@ -67,8 +67,18 @@ func listEndpoint(w *tabwriter.Writer, ep *models.Endpoint, id string, label str
 		isIngressPolicyEnabled = PolicyDisabled
 		isEgressPolicyEnabled = PolicyEnabled
 	}
-	fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
-		ep.ID, isIngressPolicyEnabled, isEgressPolicyEnabled, id, label, ep.Addressing.IPV6, ep.Addressing.IPV4, ep.State)
+	fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+		ep.ID, isIngressPolicyEnabled, isEgressPolicyEnabled, id, label, ep.Addressing.IPV6, ep.Addressing.IPV4, ep.State,
+		policyMapPressure(ep))
+}
+
+// policyMapPressure returns the number of entries required by the policy of
+// the endpoint in percent of the size of its policy map.
+func policyMapPressure(ep *models.Endpoint) string {
+	if ep.PolicyMap == nil || ep.PolicyMap.MaxEntries == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", ep.PolicyMap.Entries*100/ep.PolicyMap.MaxEntries)
 }
 
 func listEndpoints() {
@@ -93,12 +103,14 @@ func printEndpointList(w *tabwriter.Writer, eps []*models.Endpoint) {
 		policyIngressTitle = "POLICY (ingress)"
 		policyEgressTitle  = "POLICY (egress)"
 		enforcementTitle   = "ENFORCEMENT"
+		policyMapTitle     = "POLICY MAP"
+		pressureTitle      = "PRESSURE"
 	)
 
 	if !noHeaders {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
-			endpointTitle, policyIngressTitle, policyEgressTitle, labelsIDTitle, labelsDesTitle, ipv6Title, ipv4Title, statusTitle)
-		fmt.Fprintf(w, "\t%s\t%s\t\t\t\t\t\n", enforcementTitle, enforcementTitle)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			endpointTitle, policyIngressTitle, policyEgressTitle, labelsIDTitle, labelsDesTitle, ipv6Title, ipv4Title, statusTitle, policyMapTitle)
+		fmt.Fprintf(w, "\t%s\t%s\t\t\t\t\t\t%s\t\n", enforcementTitle, enforcementTitle, pressureTitle)
 	}
 
 	if len(dumpOutput) > 0 {
@@ -126,7 +138,7 @@ func printEndpointList(w *tabwriter.Writer, eps []*models.Endpoint) {
 					listEndpoint(w, ep, id, lbl)
 					first = false
 				} else {
-					fmt.Fprintf(w, "\t\t\t\t%s\t\t\t\t\n", lbl)
+					fmt.Fprintf(w, "\t\t\t\t%s\t\t\t\t\t\n", lbl)
 				}
 			}
 		}
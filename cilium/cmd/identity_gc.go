@@ -0,0 +1,97 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/cilium/cilium/pkg/policy"
+
+	"github.com/spf13/cobra"
+)
+
+var identityGCDryRun bool
+
+// identityGCCmd represents the identity gc command
+var identityGCCmd = &cobra.Command{
+	Use:     "gc",
+	Short:   "Release unused identities in the kvstore",
+	Example: "cilium identity gc --dry-run",
+	Run: func(cmd *cobra.Command, args []string) {
+		runIdentityGC()
+	},
+}
+
+func init() {
+	identityCmd.AddCommand(identityGCCmd)
+	identityGCCmd.Flags().BoolVar(&identityGCDryRun, "dry-run", false,
+		"Only report identities which would be released")
+	AddMultipleOutput(identityGCCmd)
+}
+
+func runIdentityGC() {
+	setupKvstore()
+
+	result, err := policy.RunIdentityGC(identityGCDryRun)
+	if err != nil {
+		Fatalf("Unable to run identity garbage collector: %s", err)
+	}
+
+	sort.Slice(result.Released, func(i, j int) bool {
+		return result.Released[i].ID < result.Released[j].ID
+	})
+
+	if len(dumpOutput) > 0 {
+		if err := OutputPrinter(result); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "Allocated:\t%d\n", result.Allocated)
+	fmt.Fprintf(w, "In use:\t%d\n", result.InUse)
+	fmt.Fprintf(w, "Quarantined:\t%d\n", result.Quarantined)
+	fmt.Fprintf(w, "Quarantine period:\t%s\n", result.QuarantinePeriod)
+	if identityGCDryRun {
+		fmt.Fprintf(w, "To be released:\t%d\n", len(result.Released))
+	} else {
+		fmt.Fprintf(w, "Released:\t%d\n", len(result.Released))
+	}
+	w.Flush()
+
+	if len(result.Released) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Fprintln(w, "ID\tLabels\t")
+	for _, identity := range result.Released {
+		lbls := identity.Labels.GetModel()
+		sort.Strings(lbls)
+		if len(lbls) == 0 {
+			fmt.Fprintf(w, "%d\t\t\n", identity.ID)
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%s\t\n", identity.ID, lbls[0])
+		for _, lbl := range lbls[1:] {
+			fmt.Fprintf(w, "\t%s\t\n", lbl)
+		}
+	}
+	w.Flush()
+}
@@ -0,0 +1,98 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	"github.com/spf13/cobra"
+)
+
+// kvstoreLocksCmd represents the kvstore locks command
+var kvstoreLocksCmd = &cobra.Command{
+	Use:     "locks",
+	Short:   "List locks held in the kvstore",
+	Example: "cilium kvstore locks",
+	Run: func(cmd *cobra.Command, args []string) {
+		listLocks()
+	},
+}
+
+// kvstoreLocksReleaseCmd represents the kvstore locks release command
+var kvstoreLocksReleaseCmd = &cobra.Command{
+	Use:     "release <path>",
+	Short:   "Forcefully release a lock held in the kvstore",
+	Example: "cilium kvstore locks release cilium/state/identities/v1/locks/foo",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			Fatalf("Please specify the path of the lock to release")
+		}
+
+		info, err := client.KVStoreLockRelease(args[0])
+		if err != nil {
+			Fatalf("Unable to release lock: %s", err)
+		}
+
+		fmt.Printf("Released lock %s held by %s (PID %d) for %s\n",
+			info.Path, info.Node, info.Pid, lockAge(info))
+	},
+}
+
+func init() {
+	kvstoreCmd.AddCommand(kvstoreLocksCmd)
+	kvstoreLocksCmd.AddCommand(kvstoreLocksReleaseCmd)
+	AddMultipleOutput(kvstoreLocksCmd)
+}
+
+// lockAge returns for how long the lock has been held, rounded to seconds
+func lockAge(l *models.KVStoreLock) time.Duration {
+	acquired, err := time.Parse(time.RFC3339Nano, l.AcquiredAt)
+	if err != nil {
+		return 0
+	}
+	return time.Since(acquired).Round(time.Second)
+}
+
+func listLocks() {
+	locks, err := client.KVStoreLocks()
+	if err != nil {
+		Fatalf("Unable to list locks: %s", err)
+	}
+
+	// Show the oldest locks first
+	sort.Slice(locks, func(i, j int) bool {
+		return lockAge(locks[i]) > lockAge(locks[j])
+	})
+
+	if len(dumpOutput) > 0 {
+		if err := OutputPrinter(locks); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "PATH\tNODE\tPID\tOPERATION\tAGE\t")
+	for _, l := range locks {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t\n", l.Path, l.Node, l.Pid, l.Operation, lockAge(l))
+	}
+	w.Flush()
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// mapDumpCmd represents the map_dump command
+var mapDumpCmd = &cobra.Command{
+	Use:     "dump <name>",
+	Short:   "Display decoded entries of given BPF map",
+	Example: "cilium map dump cilium_lxc",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			Fatalf("map name must be specified")
+		}
+
+		m, err := client.MapDump(args[0])
+		if err != nil {
+			Fatalf("Error while dumping map %s: %s", args[0], err)
+		}
+
+		if len(dumpOutput) > 0 {
+			if err := OutputPrinter(m); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+
+		if len(m.Entries) == 0 {
+			fmt.Printf("Map is empty\n")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+		fmt.Fprintf(w, "Key\tValue\n")
+		for _, e := range m.Entries {
+			fmt.Fprintf(w, "%s\t%s\n", e.Key, e.Value)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	mapCmd.AddCommand(mapDumpCmd)
+	AddMultipleOutput(mapDumpCmd)
+}
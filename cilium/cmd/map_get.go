@@ -0,0 +1,80 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// mapGetCmd represents the map_get command
+var mapGetCmd = &cobra.Command{
+	Use:     "get <name>",
+	Short:   "Display attributes and cached content of given BPF map",
+	Example: "cilium map get cilium_lxc",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			Fatalf("map name must be specified")
+		}
+
+		m, err := client.MapGet(args[0])
+		if err != nil {
+			Fatalf("Error while retrieving map %s: %s", args[0], err)
+		}
+
+		if len(dumpOutput) > 0 {
+			if err := OutputPrinter(m); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+		fmt.Fprintf(w, "Name:\t%s\n", m.Name)
+		fmt.Fprintf(w, "Path:\t%s\n", m.Path)
+		fmt.Fprintf(w, "Description:\t%s\n", m.Description)
+		fmt.Fprintf(w, "Type:\t%s\n", m.Type)
+		fmt.Fprintf(w, "Key size:\t%d\n", m.KeySize)
+		fmt.Fprintf(w, "Value size:\t%d\n", m.ValueSize)
+		fmt.Fprintf(w, "Entries:\t%d/%d (%s)\n", m.NumEntries, m.MaxEntries, mapFillLevel(m))
+		w.Flush()
+
+		// Maps without a userspace cache have no cache contents
+		if m.Cache == nil {
+			return
+		}
+
+		fmt.Printf("\n")
+		if len(m.Cache) == 0 {
+			fmt.Printf("Cache is empty\n")
+			return
+		}
+
+		w = tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+		fmt.Fprintf(w, "Key\tValue\tState\tError\n")
+		for _, e := range m.Cache {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Key, e.Value, e.DesiredAction, e.LastError)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	mapCmd.AddCommand(mapGetCmd)
+	AddMultipleOutput(mapGetCmd)
+}
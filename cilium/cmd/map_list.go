@@ -0,0 +1,73 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	"github.com/spf13/cobra"
+)
+
+// mapListCmd represents the map_list command
+var mapListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List all pinned BPF maps",
+	Example: "cilium map list",
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.MapList()
+		if err != nil {
+			Fatalf("Error while retrieving map list: %s", err)
+		}
+
+		if len(dumpOutput) > 0 {
+			if err := OutputPrinter(resp); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+		fmt.Fprintf(w, "Name\tType\tNum entries\tMax entries\tFill\tNum errors\n")
+		for _, m := range resp.Maps {
+			errors := 0
+			for _, e := range m.Cache {
+				if e.LastError != "" {
+					errors++
+				}
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%d\n", m.Name, m.Type,
+				m.NumEntries, m.MaxEntries, mapFillLevel(m), errors)
+		}
+		w.Flush()
+	},
+}
+
+// mapFillLevel returns the number of entries of the map in percent of its
+// maximum number of entries
+func mapFillLevel(m *models.BPFMap) string {
+	if m.MaxEntries == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", m.NumEntries*100/m.MaxEntries)
+}
+
+func init() {
+	mapCmd.AddCommand(mapListCmd)
+	AddMultipleOutput(mapListCmd)
+}
@@ -0,0 +1,40 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var policyEgressGatewayDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete an egress gateway policy",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 || args[0] == "" {
+			Usagef(cmd, "Missing egress gateway policy name")
+		}
+
+		if err := client.EgressGatewayPolicyDelete(args[0]); err != nil {
+			Fatalf("Cannot delete egress gateway policy: %s", err)
+		}
+		fmt.Printf("Deleted egress gateway policy %s\n", args[0])
+	},
+}
+
+func init() {
+	policyEgressGatewayCmd.AddCommand(policyEgressGatewayDeleteCmd)
+}
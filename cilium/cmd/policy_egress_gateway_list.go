@@ -0,0 +1,59 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var policyEgressGatewayListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List egress gateway policies",
+	Run: func(cmd *cobra.Command, args []string) {
+		listEgressGatewayPolicies()
+	},
+}
+
+func init() {
+	policyEgressGatewayCmd.AddCommand(policyEgressGatewayListCmd)
+	AddMultipleOutput(policyEgressGatewayListCmd)
+}
+
+func listEgressGatewayPolicies() {
+	list, err := client.EgressGatewayPolicyList()
+	if err != nil {
+		Fatalf("Cannot get egress gateway policies: %s", err)
+	}
+
+	if len(dumpOutput) > 0 {
+		if err := OutputPrinter(list); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "Name\tGateway node\tEgress IP\tDestinations\tEndpoint selector\t")
+	for _, p := range list {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", p.Name, p.GatewayNode, p.EgressIP,
+			strings.Join(p.DestinationCidrs, ","), p.EndpointSelector)
+	}
+	w.Flush()
+}
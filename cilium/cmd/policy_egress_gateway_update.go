@@ -0,0 +1,76 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	egressEndpointLabels   []string
+	egressDestinationCIDRs []string
+	egressGatewayNode      string
+	egressIP               string
+)
+
+var policyEgressGatewayUpdateCmd = &cobra.Command{
+	Use:   "update <name>",
+	Short: "Create or update an egress gateway policy",
+	Example: "cilium policy egress-gateway update partner-a --endpoint-labels k8s:app=backend " +
+		"--destination-cidr 203.0.113.0/24 --gateway-node node1 --egress-ip 192.0.2.10",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 || args[0] == "" {
+			Usagef(cmd, "Missing egress gateway policy name")
+		}
+		updateEgressGatewayPolicy(args[0])
+	},
+}
+
+func init() {
+	policyEgressGatewayCmd.AddCommand(policyEgressGatewayUpdateCmd)
+	flags := policyEgressGatewayUpdateCmd.Flags()
+	flags.StringSliceVar(&egressEndpointLabels, "endpoint-labels", []string{}, "Labels selecting the endpoints of the policy")
+	flags.StringSliceVar(&egressDestinationCIDRs, "destination-cidr", []string{}, "IPv4 prefixes of the external destinations")
+	flags.StringVar(&egressGatewayNode, "gateway-node", "", "Name of the node forwarding the traffic")
+	flags.StringVar(&egressIP, "egress-ip", "", "Source IP of the traffic leaving the gateway node")
+}
+
+func updateEgressGatewayPolicy(name string) {
+	selector := api.NewESFromLabels(labels.ParseSelectLabelArray(egressEndpointLabels...)...)
+	p := &models.EgressGatewayPolicy{
+		Name:             name,
+		EndpointSelector: selector.String(),
+		DestinationCidrs: egressDestinationCIDRs,
+		GatewayNode:      egressGatewayNode,
+		EgressIP:         egressIP,
+	}
+
+	created, err := client.EgressGatewayPolicyPut(name, p)
+	if err != nil {
+		Fatalf("Cannot create or update egress gateway policy: %s", err)
+	}
+
+	if created {
+		fmt.Printf("Created egress gateway policy %s\n", name)
+	} else {
+		fmt.Printf("Updated egress gateway policy %s\n", name)
+	}
+}
@@ -17,23 +17,29 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common/types"
 
 	"github.com/spf13/cobra"
 )
 
+var showServiceStats bool
+
 // serviceGetCmd represents the service_get command
 var serviceGetCmd = &cobra.Command{
-	Use:    "get <service id>",
+	Use:    "get <service id | [namespace/]name>",
 	Short:  "Display service information",
 	PreRun: requireServiceID,
 	Run: func(cmd *cobra.Command, args []string) {
 		svcIDstr := args[0]
 		id, err := strconv.ParseInt(svcIDstr, 0, 64)
 		if err != nil {
-			Fatalf("Unable to parse service ID: %s", svcIDstr)
+			getServicesByName(svcIDstr)
+			return
 		}
 
 		svc, err := client.GetServiceID(id)
@@ -41,15 +47,6 @@ var serviceGetCmd = &cobra.Command{
 			Fatalf("Cannot get service '%v': %s\n", id, err)
 		}
 
-		slice := []string{}
-		for _, be := range svc.BackendAddresses {
-			if bea, err := types.NewL3n4AddrFromBackendModel(be); err != nil {
-				slice = append(slice, fmt.Sprintf("invalid backend: %+v", be))
-			} else {
-				slice = append(slice, bea.String())
-			}
-		}
-
 		if len(dumpOutput) > 0 {
 			if err := OutputPrinter(svc); err != nil {
 				os.Exit(1)
@@ -57,19 +54,129 @@ var serviceGetCmd = &cobra.Command{
 			return
 		}
 
-		if fea, err := types.NewL3n4AddrFromModel(svc.FrontendAddress); err != nil {
-			fmt.Fprintf(os.Stderr, "invalid frontend model: %s", err)
-		} else {
-			fmt.Printf("%s =>\n", fea.String())
-		}
-
-		for i, be := range slice {
-			fmt.Printf("\t\t%d => %s (%d)\n", i+1, be, svc.ID)
-		}
+		printService(svc)
 	},
 }
 
 func init() {
 	serviceCmd.AddCommand(serviceGetCmd)
+	serviceGetCmd.Flags().BoolVarP(&showServiceStats, "stats", "", false, "Show load-balancing statistics of the backends (requires --enable-lb-stats)")
 	AddMultipleOutput(serviceGetCmd)
 }
+
+// getServicesByName displays all frontends of the service with the given
+// name in the "[namespace/]name" format.
+func getServicesByName(fullName string) {
+	namespace, name := "", fullName
+	if i := strings.Index(fullName, "/"); i >= 0 {
+		namespace, name = fullName[:i], fullName[i+1:]
+	}
+
+	svcs, err := client.GetServicesByName(namespace, name)
+	if err != nil {
+		Fatalf("Cannot get service '%s': %s\n", fullName, err)
+	}
+	if len(svcs) == 0 {
+		Fatalf("Service '%s' not found\n", fullName)
+	}
+
+	if len(dumpOutput) > 0 {
+		if err := OutputPrinter(svcs); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	sort.Slice(svcs, func(i, j int) bool { return svcs[i].ID < svcs[j].ID })
+	for _, svc := range svcs {
+		printService(svc)
+	}
+}
+
+func printService(svc *models.Service) {
+	slice := []string{}
+	draining := 0
+	total := models.BackendStatistics{}
+	for _, be := range svc.BackendAddresses {
+		bea, err := types.NewL3n4AddrFromBackendModel(be)
+		if err != nil {
+			slice = append(slice, fmt.Sprintf("invalid backend: %+v", be))
+			continue
+		}
+		str := bea.String()
+		if be.Name != "" {
+			str = fmt.Sprintf("%s %s", be.Name, str)
+		}
+		if be.Weight != 0 {
+			str = fmt.Sprintf("%s (W: %d)", str, be.Weight)
+		}
+		if be.NodeName != "" || be.Zone != "" {
+			str = fmt.Sprintf("%s on %s", str, strings.Trim(be.NodeName+"/"+be.Zone, "/"))
+		}
+		if be.Health != "" {
+			str = fmt.Sprintf("%s [%s]", str, be.Health)
+		}
+		if be.Draining {
+			str = fmt.Sprintf("%s [draining]", str)
+			draining++
+		}
+		if showServiceStats {
+			stats := be.Statistics
+			if stats == nil {
+				stats = &models.BackendStatistics{}
+			}
+			str = fmt.Sprintf("%s (packets: %d, bytes: %d, connections: %d)",
+				str, stats.Packets, stats.Bytes, stats.Connections)
+			total.Packets += stats.Packets
+			total.Bytes += stats.Bytes
+			total.Connections += stats.Connections
+		}
+		slice = append(slice, str)
+	}
+
+	if fea, err := types.NewL3n4AddrFromModel(svc.FrontendAddress); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid frontend model: %s", err)
+	} else {
+		fmt.Printf("%s =>\n", fea.String())
+	}
+
+	for i, be := range slice {
+		fmt.Printf("\t\t%d => %s (%d)\n", i+1, be, svc.ID)
+	}
+
+	if svc.Name != "" {
+		name := types.LBSVCName{Namespace: svc.Namespace, Name: svc.Name}
+		fmt.Printf("Name: %s\n", name.String())
+	}
+
+	if len(svc.Labels) > 0 {
+		labels := make([]string, 0, len(svc.Labels))
+		for k, v := range svc.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+		fmt.Printf("Labels: %s\n", strings.Join(labels, ", "))
+	}
+
+	if svc.Flags != nil && svc.Flags.Topology != "" && svc.Flags.Topology != models.ServiceFlagsTopologyNone {
+		fmt.Printf("Topology: %s\n", svc.Flags.Topology)
+	}
+
+	if draining > 0 {
+		fmt.Printf("Draining backends: %d\n", draining)
+	}
+
+	if showServiceStats {
+		fmt.Printf("Statistics: packets: %d, bytes: %d, connections: %d\n",
+			total.Packets, total.Bytes, total.Connections)
+	}
+
+	if hc := svc.HealthCheck; hc != nil {
+		fmt.Printf("Health check: %s every %ds, timeout %ds, healthy after %d, unhealthy after %d",
+			hc.Type, hc.Interval, hc.Timeout, hc.HealthyThreshold, hc.UnhealthyThreshold)
+		if hc.Type == models.ServiceHealthCheckTypeHTTP {
+			fmt.Printf(", path %s", hc.Path)
+		}
+		fmt.Printf("\n")
+	}
+}
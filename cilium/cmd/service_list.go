@@ -50,11 +50,14 @@ func listServices(cmd *cobra.Command, args []string) {
 }
 
 func printServiceList(w *tabwriter.Writer, list []*models.Service) {
-	fmt.Fprintln(w, "ID\tFrontend\tBackend\t")
+	fmt.Fprintln(w, "ID\tName\tFrontend\tType\tAffinity\tBackend\t")
 
 	type ServiceOutput struct {
 		ID               int64
+		Name             string
 		FrontendAddress  string
+		Type             string
+		SessionAffinity  string
 		BackendAddresses []string
 	}
 	svcs := []ServiceOutput{}
@@ -73,18 +76,44 @@ func printServiceList(w *tabwriter.Writer, list []*models.Service) {
 				fmt.Fprintf(os.Stderr, "error parsing backend %+v", be)
 				continue
 			}
-			var str string
+			str := beA.String()
+			if be.Name != "" {
+				str = fmt.Sprintf("%s %s", be.Name, str)
+			}
 			if be.Weight != 0 {
-				str = fmt.Sprintf("%d => %s (W: %d)", i+1, beA.String(), be.Weight)
+				str = fmt.Sprintf("%d => %s (W: %d)", i+1, str, be.Weight)
 			} else {
-				str = fmt.Sprintf("%d => %s", i+1, beA.String())
+				str = fmt.Sprintf("%d => %s", i+1, str)
+			}
+			if be.Health != "" {
+				str = fmt.Sprintf("%s [%s]", str, be.Health)
+			}
+			if be.Draining {
+				str = fmt.Sprintf("%s [draining]", str)
 			}
 			backendAddresses = append(backendAddresses, str)
 		}
 
+		// Services without a type are only reachable on their frontend
+		// address like a cluster IP.
+		svcType := models.ServiceFlagsTypeClusterIP
+		if svc.Flags != nil && svc.Flags.Type != "" {
+			svcType = svc.Flags.Type
+		}
+
+		affinity := ""
+		if svc.Flags != nil && svc.Flags.SessionAffinity == models.ServiceFlagsSessionAffinityClientIP {
+			affinity = fmt.Sprintf("ClientIP (%ds)", svc.Flags.SessionAffinityTimeout)
+		}
+
+		name := types.LBSVCName{Namespace: svc.Namespace, Name: svc.Name}
+
 		SvcOutput := ServiceOutput{
 			ID:               svc.ID,
+			Name:             name.String(),
 			FrontendAddress:  feA.String(),
+			Type:             svcType,
+			SessionAffinity:  affinity,
 			BackendAddresses: backendAddresses,
 		}
 		svcs = append(svcs, SvcOutput)
@@ -105,19 +134,19 @@ func printServiceList(w *tabwriter.Writer, list []*models.Service) {
 		var str string
 
 		if len(service.BackendAddresses) == 0 {
-			str = fmt.Sprintf("%d\t%s\t\t",
-				service.ID, service.FrontendAddress)
+			str = fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t\t",
+				service.ID, service.Name, service.FrontendAddress, service.Type, service.SessionAffinity)
 			fmt.Fprintln(w, str)
 			continue
 		}
 
-		str = fmt.Sprintf("%d\t%s\t%s\t",
-			service.ID, service.FrontendAddress,
+		str = fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%s\t",
+			service.ID, service.Name, service.FrontendAddress, service.Type, service.SessionAffinity,
 			service.BackendAddresses[0])
 		fmt.Fprintln(w, str)
 
 		for _, bkaddr := range service.BackendAddresses[1:] {
-			str := fmt.Sprintf("\t\t%s\t", bkaddr)
+			str := fmt.Sprintf("\t\t\t\t\t%s\t", bkaddr)
 			fmt.Fprintln(w, str)
 		}
 	}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common/types"
@@ -29,10 +30,26 @@ import (
 )
 
 var (
-	addRev   bool
-	idU      uint64
-	frontend string
-	backends []string
+	addRev      bool
+	idU         uint64
+	frontend    string
+	backends    []string
+	lbAlgorithm string
+	topology    string
+
+	serviceName      string
+	serviceNamespace string
+	serviceLabels    []string
+
+	sessionAffinity        bool
+	sessionAffinityTimeout uint32
+
+	healthCheck                   string
+	healthCheckInterval           uint32
+	healthCheckTimeout            uint32
+	healthCheckPath               string
+	healthCheckHealthyThreshold   uint32
+	healthCheckUnhealthyThreshold uint32
 )
 
 // serviceUpdateCmd represents the service_update command
@@ -49,7 +66,20 @@ func init() {
 	serviceUpdateCmd.Flags().BoolVarP(&addRev, "rev", "", true, "Add reverse translation")
 	serviceUpdateCmd.Flags().Uint64VarP(&idU, "id", "", 0, "Identifier")
 	serviceUpdateCmd.Flags().StringVarP(&frontend, "frontend", "", "", "Frontend address")
-	serviceUpdateCmd.Flags().StringSliceVarP(&backends, "backends", "", []string{}, "Backend address or addresses followed by optional weight (<IP:Port>[/weight])")
+	serviceUpdateCmd.Flags().StringSliceVarP(&backends, "backends", "", []string{}, "Backend address or addresses with optional name, weight and node ([name=]<IP:Port>[/weight][@node])")
+	serviceUpdateCmd.Flags().StringVarP(&serviceName, "name", "", "", "Name of the service")
+	serviceUpdateCmd.Flags().StringVarP(&serviceNamespace, "namespace", "", "", "Namespace of the service name")
+	serviceUpdateCmd.Flags().StringSliceVarP(&serviceLabels, "labels", "", []string{}, "Labels of the service (key=value)")
+	serviceUpdateCmd.Flags().StringVarP(&lbAlgorithm, "lb-algorithm", "", string(types.LBAlgorithmHash), "Backend selection algorithm (hash, maglev)")
+	serviceUpdateCmd.Flags().StringVarP(&topology, "topology", "", string(types.LBTopologyNone), "Prefer backends on the local node or in the local zone (none, node, zone)")
+	serviceUpdateCmd.Flags().BoolVarP(&sessionAffinity, "session-affinity", "", false, "Send all connections of a client IP to the same backend")
+	serviceUpdateCmd.Flags().Uint32VarP(&sessionAffinityTimeout, "session-affinity-timeout", "", types.DefaultSessionAffinityTimeout, "Session affinity timeout in seconds")
+	serviceUpdateCmd.Flags().StringVarP(&healthCheck, "health-check", "", "", "Actively check the health of backends (tcp, http)")
+	serviceUpdateCmd.Flags().Uint32VarP(&healthCheckInterval, "health-check-interval", "", uint32(types.DefaultHealthCheckInterval/time.Second), "Interval between health checks in seconds")
+	serviceUpdateCmd.Flags().Uint32VarP(&healthCheckTimeout, "health-check-timeout", "", uint32(types.DefaultHealthCheckTimeout/time.Second), "Timeout of a health check in seconds")
+	serviceUpdateCmd.Flags().StringVarP(&healthCheckPath, "health-check-path", "", types.DefaultHealthCheckPath, "Path requested by HTTP health checks")
+	serviceUpdateCmd.Flags().Uint32VarP(&healthCheckHealthyThreshold, "health-check-healthy-threshold", "", types.DefaultHealthCheckHealthyThreshold, "Number of successful health checks for a backend to become healthy")
+	serviceUpdateCmd.Flags().Uint32VarP(&healthCheckUnhealthyThreshold, "health-check-unhealthy-threshold", "", types.DefaultHealthCheckUnhealthyThreshold, "Number of failed health checks for a backend to become unhealthy")
 }
 
 func parseFrontendAddress(address string) (*models.FrontendAddress, net.IP) {
@@ -70,15 +100,63 @@ func updateService(cmd *cobra.Command, args []string) {
 	id := int64(idU)
 	fa, faIP := parseFrontendAddress(frontend)
 
+	algorithm, err := types.NewLBAlgorithm(lbAlgorithm)
+	if err != nil {
+		Fatalf("Invalid load-balancing algorithm: %s\n", err)
+	}
+
+	svcTopology, err := types.NewLBTopology(topology)
+	if err != nil {
+		Fatalf("Invalid topology: %s\n", err)
+	}
+
 	svc := &models.Service{
 		ID:               id,
+		Name:             serviceName,
+		Namespace:        serviceNamespace,
 		FrontendAddress:  fa,
 		BackendAddresses: []*models.BackendAddress{},
 		Flags: &models.ServiceFlags{
 			DirectServerReturn: addRev,
+			LbAlgorithm:        string(algorithm),
+			Topology:           string(svcTopology),
 		},
 	}
 
+	if serviceNamespace != "" && serviceName == "" {
+		Fatalf("Namespace given without a service name\n")
+	}
+
+	for _, label := range serviceLabels {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			Fatalf("Incorrect label specification %s\n", label)
+		}
+		if svc.Labels == nil {
+			svc.Labels = map[string]string{}
+		}
+		svc.Labels[kv[0]] = kv[1]
+	}
+
+	if sessionAffinity {
+		svc.Flags.SessionAffinity = models.ServiceFlagsSessionAffinityClientIP
+		svc.Flags.SessionAffinityTimeout = int64(sessionAffinityTimeout)
+	}
+
+	if healthCheck != "" {
+		svc.HealthCheck = &models.ServiceHealthCheck{
+			Type:               healthCheck,
+			Interval:           int64(healthCheckInterval),
+			Timeout:            int64(healthCheckTimeout),
+			Path:               healthCheckPath,
+			HealthyThreshold:   int64(healthCheckHealthyThreshold),
+			UnhealthyThreshold: int64(healthCheckUnhealthyThreshold),
+		}
+		if _, err := types.NewLBHealthCheckFromModel(svc.HealthCheck); err != nil {
+			Fatalf("Invalid health check: %s\n", err)
+		}
+	}
+
 	if len(backends) == 0 {
 		fmt.Printf("Reading backend list from stdin...\n")
 
@@ -89,7 +167,15 @@ func updateService(cmd *cobra.Command, args []string) {
 	}
 
 	for _, backend := range backends {
-		tmp := strings.Split(backend, "/")
+		name, nodeName := "", ""
+		spec := backend
+		if i := strings.Index(spec, "="); i >= 0 {
+			name, spec = spec[:i], spec[i+1:]
+		}
+		if i := strings.LastIndex(spec, "@"); i >= 0 {
+			spec, nodeName = spec[:i], spec[i+1:]
+		}
+		tmp := strings.Split(spec, "/")
 		if len(tmp) > 2 {
 			Fatalf("Incorrect backend specification %s\n", backend)
 		}
@@ -120,6 +206,8 @@ func updateService(cmd *cobra.Command, args []string) {
 			Fatalf("L4 backend found (%v) with L3 frontend", beAddr)
 		}
 
+		be.Name = name
+		be.NodeName = nodeName
 		ba := be.GetBackendModel()
 		svc.BackendAddresses = append(svc.BackendAddresses, ba)
 	}
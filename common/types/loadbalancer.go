@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/lock"
@@ -47,21 +48,359 @@ type FEPortName string
 // ServiceID is the service's ID.
 type ServiceID uint16
 
+// DefaultSessionAffinityTimeout is the default timeout in seconds of the
+// ClientIP session affinity of a service, matching Kubernetes.
+const DefaultSessionAffinityTimeout = 10800
+
+// LBAlgorithm is the algorithm used by the datapath to select a backend of a
+// service.
+type LBAlgorithm string
+
+const (
+	// LBAlgorithmHash selects the backend by the packet hash modulo the
+	// number of backends. Adding or removing a backend redistributes most
+	// of the existing flows.
+	LBAlgorithmHash = LBAlgorithm(models.ServiceFlagsLbAlgorithmHash)
+
+	// LBAlgorithmMaglev selects the backend with a Maglev consistent
+	// hashing lookup table. Adding or removing a backend only moves about
+	// 1/N of the existing flows.
+	LBAlgorithmMaglev = LBAlgorithm(models.ServiceFlagsLbAlgorithmMaglev)
+)
+
+// NewLBAlgorithm returns the LBAlgorithm matching the given name. An empty
+// name selects LBAlgorithmHash.
+func NewLBAlgorithm(name string) (LBAlgorithm, error) {
+	switch LBAlgorithm(name) {
+	case "", LBAlgorithmHash:
+		return LBAlgorithmHash, nil
+	case LBAlgorithmMaglev:
+		return LBAlgorithmMaglev, nil
+	}
+	return "", fmt.Errorf("unknown load-balancing algorithm %q", name)
+}
+
+// LBTopology is the locality of the backends of a service which are
+// preferred for new connections.
+type LBTopology string
+
+const (
+	// LBTopologyNone selects among all backends of the service.
+	LBTopologyNone = LBTopology(models.ServiceFlagsTopologyNone)
+
+	// LBTopologyNode prefers the backends running on the local node, then
+	// the backends running in the zone of the local node.
+	LBTopologyNode = LBTopology(models.ServiceFlagsTopologyNode)
+
+	// LBTopologyZone prefers the backends running in the zone of the local
+	// node.
+	LBTopologyZone = LBTopology(models.ServiceFlagsTopologyZone)
+)
+
+// NewLBTopology returns the LBTopology matching the given name. An empty name
+// selects LBTopologyNone.
+func NewLBTopology(name string) (LBTopology, error) {
+	switch LBTopology(name) {
+	case "", LBTopologyNone:
+		return LBTopologyNone, nil
+	case LBTopologyNode:
+		return LBTopologyNode, nil
+	case LBTopologyZone:
+		return LBTopologyZone, nil
+	}
+	return "", fmt.Errorf("unknown topology %q", name)
+}
+
+// LBSVCType is the type of the frontend of a service.
+type LBSVCType string
+
+const (
+	// LBSVCTypeClusterIP is a frontend on the cluster IP of a service.
+	LBSVCTypeClusterIP = LBSVCType(models.ServiceFlagsTypeClusterIP)
+
+	// LBSVCTypeNodePort is a frontend on the node port of a service on one
+	// of the addresses of the local node.
+	LBSVCTypeNodePort = LBSVCType(models.ServiceFlagsTypeNodePort)
+
+	// LBSVCTypeExternalIPs is a frontend on one of the external IPs of a
+	// service.
+	LBSVCTypeExternalIPs = LBSVCType(models.ServiceFlagsTypeExternalIPs)
+)
+
+// NewLBSVCType returns the LBSVCType matching the given name. An empty name
+// selects LBSVCTypeClusterIP.
+func NewLBSVCType(name string) (LBSVCType, error) {
+	switch LBSVCType(name) {
+	case "", LBSVCTypeClusterIP:
+		return LBSVCTypeClusterIP, nil
+	case LBSVCTypeNodePort:
+		return LBSVCTypeNodePort, nil
+	case LBSVCTypeExternalIPs:
+		return LBSVCTypeExternalIPs, nil
+	}
+	return "", fmt.Errorf("unknown frontend type %q", name)
+}
+
+// LBHealthCheckType is the type of the active health check of the backends
+// of a service.
+type LBHealthCheckType string
+
+const (
+	// LBHealthCheckTCP checks if a TCP connection to the backend can be
+	// established.
+	LBHealthCheckTCP = LBHealthCheckType(models.ServiceHealthCheckTypeTCP)
+
+	// LBHealthCheckHTTP checks if the backend responds to an HTTP GET
+	// request with a 2xx or 3xx status code.
+	LBHealthCheckHTTP = LBHealthCheckType(models.ServiceHealthCheckTypeHTTP)
+)
+
+const (
+	// DefaultHealthCheckInterval is the default interval between two
+	// health checks of a backend.
+	DefaultHealthCheckInterval = 10 * time.Second
+
+	// DefaultHealthCheckTimeout is the default timeout of a health check.
+	DefaultHealthCheckTimeout = 2 * time.Second
+
+	// DefaultHealthCheckHealthyThreshold is the default number of
+	// consecutive successful checks for a backend to become healthy.
+	DefaultHealthCheckHealthyThreshold = 2
+
+	// DefaultHealthCheckUnhealthyThreshold is the default number of
+	// consecutive failed checks for a backend to become unhealthy.
+	DefaultHealthCheckUnhealthyThreshold = 3
+
+	// DefaultHealthCheckPath is the default path requested by HTTP checks.
+	DefaultHealthCheckPath = "/"
+)
+
+// LBHealthCheck is the active health check of the backends of a service.
+type LBHealthCheck struct {
+	Type               LBHealthCheckType
+	Interval           time.Duration
+	Timeout            time.Duration
+	HealthyThreshold   int
+	UnhealthyThreshold int
+
+	// Path is only used by LBHealthCheckHTTP
+	Path string
+}
+
+// NewLBHealthCheckFromModel returns the health check of the given model with
+// defaults filled in for all unset fields. Returns nil if hc is nil.
+func NewLBHealthCheckFromModel(hc *models.ServiceHealthCheck) (*LBHealthCheck, error) {
+	if hc == nil {
+		return nil, nil
+	}
+
+	check := &LBHealthCheck{
+		Type:               LBHealthCheckTCP,
+		Interval:           DefaultHealthCheckInterval,
+		Timeout:            DefaultHealthCheckTimeout,
+		HealthyThreshold:   DefaultHealthCheckHealthyThreshold,
+		UnhealthyThreshold: DefaultHealthCheckUnhealthyThreshold,
+		Path:               DefaultHealthCheckPath,
+	}
+
+	switch LBHealthCheckType(hc.Type) {
+	case "", LBHealthCheckTCP:
+	case LBHealthCheckHTTP:
+		check.Type = LBHealthCheckHTTP
+	default:
+		return nil, fmt.Errorf("unknown health check type %q", hc.Type)
+	}
+
+	if hc.Interval != 0 {
+		check.Interval = time.Duration(hc.Interval) * time.Second
+	}
+	if hc.Timeout != 0 {
+		check.Timeout = time.Duration(hc.Timeout) * time.Second
+	}
+	if check.Timeout > check.Interval {
+		return nil, fmt.Errorf("health check timeout %s exceeds interval %s", check.Timeout, check.Interval)
+	}
+	if hc.HealthyThreshold != 0 {
+		check.HealthyThreshold = int(hc.HealthyThreshold)
+	}
+	if hc.UnhealthyThreshold != 0 {
+		check.UnhealthyThreshold = int(hc.UnhealthyThreshold)
+	}
+	if hc.Path != "" {
+		check.Path = hc.Path
+	}
+
+	return check, nil
+}
+
+// GetModel returns the API model of the health check.
+func (hc *LBHealthCheck) GetModel() *models.ServiceHealthCheck {
+	if hc == nil {
+		return nil
+	}
+
+	m := &models.ServiceHealthCheck{
+		Type:               string(hc.Type),
+		Interval:           int64(hc.Interval / time.Second),
+		Timeout:            int64(hc.Timeout / time.Second),
+		HealthyThreshold:   int64(hc.HealthyThreshold),
+		UnhealthyThreshold: int64(hc.UnhealthyThreshold),
+	}
+	if hc.Type == LBHealthCheckHTTP {
+		m.Path = hc.Path
+	}
+	return m
+}
+
+// LBBackEndHealth is the health of a backend as determined by the health
+// check of its service.
+type LBBackEndHealth string
+
+const (
+	// LBBackEndHealthUnknown is the health of a backend until enough
+	// checks have been run. Such backends keep being selected.
+	LBBackEndHealthUnknown = LBBackEndHealth(models.BackendAddressHealthUnknown)
+
+	// LBBackEndHealthHealthy is the health of a backend which passed the
+	// health check HealthyThreshold times in a row.
+	LBBackEndHealthHealthy = LBBackEndHealth(models.BackendAddressHealthHealthy)
+
+	// LBBackEndHealthUnhealthy is the health of a backend which failed the
+	// health check UnhealthyThreshold times in a row. Such backends are
+	// not selected for new flows.
+	LBBackEndHealthUnhealthy = LBBackEndHealth(models.BackendAddressHealthUnhealthy)
+)
+
 // LBBackEnd represents load balancer backend.
 type LBBackEnd struct {
 	L3n4Addr
 	Weight uint16
+
+	// Name is an optional name of the backend
+	Name string
+
+	// NodeName is the name of the node the backend is running on, empty if
+	// unknown
+	NodeName string
+
+	// Zone is the zone the backend is running in, empty if unknown. If
+	// not set, the zone is derived from NodeName.
+	Zone string
+
+	// Draining is set once the backend was removed from the service. A
+	// draining backend is not selected for new connections but keeps
+	// serving its established ones until the drain timeout passes.
+	Draining bool
 }
 
 func (lbbe *LBBackEnd) String() string {
 	return fmt.Sprintf("%s, weight: %d", lbbe.L3n4Addr.String(), lbbe.Weight)
 }
 
+// LBSVCName is the name of a service. All frontends of a service share its
+// name.
+type LBSVCName struct {
+	Namespace string
+	Name      string
+}
+
+// String returns the name in the "namespace/name" format.
+func (n LBSVCName) String() string {
+	if n.Namespace == "" {
+		return n.Name
+	}
+	return n.Namespace + "/" + n.Name
+}
+
+// IsEmpty returns true if the service has no name.
+func (n LBSVCName) IsEmpty() bool {
+	return n.Name == ""
+}
+
+// LBSVCOptions are the load-balancing options and metadata of a service.
+type LBSVCOptions struct {
+	// Name of the service, empty for unnamed services
+	Name LBSVCName
+
+	// Labels are user defined metadata of the service
+	Labels map[string]string
+
+	// Type is the type of the frontend
+	Type LBSVCType
+
+	// Algorithm is used by the datapath to select a backend for new flows
+	Algorithm LBAlgorithm
+
+	// SessionAffinityTimeout is the number of seconds a client keeps being
+	// sent to the same backend since its last packet. 0 disables ClientIP
+	// session affinity.
+	SessionAffinityTimeout uint32
+
+	// HealthCheck is the active health check of the backends, nil if the
+	// backends are not health checked.
+	HealthCheck *LBHealthCheck
+
+	// Topology is the locality of the backends preferred for new flows.
+	// Remote backends are only selected if no preferred backend is
+	// healthy.
+	Topology LBTopology
+}
+
+// TopologyAware returns true if backends are preferred by their locality.
+func (o *LBSVCOptions) TopologyAware() bool {
+	return o.Topology != "" && o.Topology != LBTopologyNone
+}
+
+// SessionAffinity returns true if ClientIP session affinity is enabled.
+func (o *LBSVCOptions) SessionAffinity() bool {
+	return o.SessionAffinityTimeout != 0
+}
+
+// NewLBSVCOptionsFromModel returns the LBSVCOptions of the given service flags.
+func NewLBSVCOptionsFromModel(flags *models.ServiceFlags) (LBSVCOptions, error) {
+	opts := LBSVCOptions{Type: LBSVCTypeClusterIP, Algorithm: LBAlgorithmHash}
+	if flags == nil {
+		return opts, nil
+	}
+
+	svcType, err := NewLBSVCType(flags.Type)
+	if err != nil {
+		return opts, err
+	}
+	opts.Type = svcType
+
+	algorithm, err := NewLBAlgorithm(flags.LbAlgorithm)
+	if err != nil {
+		return opts, err
+	}
+	opts.Algorithm = algorithm
+
+	topology, err := NewLBTopology(flags.Topology)
+	if err != nil {
+		return opts, err
+	}
+	opts.Topology = topology
+
+	switch flags.SessionAffinity {
+	case "", models.ServiceFlagsSessionAffinityNone:
+	case models.ServiceFlagsSessionAffinityClientIP:
+		opts.SessionAffinityTimeout = DefaultSessionAffinityTimeout
+		if flags.SessionAffinityTimeout != 0 {
+			opts.SessionAffinityTimeout = uint32(flags.SessionAffinityTimeout)
+		}
+	default:
+		return opts, fmt.Errorf("unknown session affinity %q", flags.SessionAffinity)
+	}
+
+	return opts, nil
+}
+
 // LBSVC is essentially used for the REST API.
 type LBSVC struct {
 	Sha256 string
 	FE     L3n4AddrID
 	BES    []LBBackEnd
+	LBSVCOptions
 }
 
 func (s *LBSVC) GetModel() *models.Service {
@@ -72,14 +411,39 @@ func (s *LBSVC) GetModel() *models.Service {
 	id := int64(s.FE.ID)
 	svc := &models.Service{
 		ID:               id,
+		Name:             s.Name.Name,
+		Namespace:        s.Name.Namespace,
 		FrontendAddress:  s.FE.GetModel(),
 		BackendAddresses: make([]*models.BackendAddress, len(s.BES)),
 	}
 
+	if len(s.Labels) > 0 {
+		svc.Labels = make(map[string]string, len(s.Labels))
+		for k, v := range s.Labels {
+			svc.Labels[k] = v
+		}
+	}
+
 	for i, be := range s.BES {
 		svc.BackendAddresses[i] = be.GetBackendModel()
 	}
 
+	if s.Type != "" || s.Algorithm != "" || s.SessionAffinity() || s.TopologyAware() {
+		svc.Flags = &models.ServiceFlags{
+			Type:        string(s.Type),
+			LbAlgorithm: string(s.Algorithm),
+		}
+		if s.TopologyAware() {
+			svc.Flags.Topology = string(s.Topology)
+		}
+		if s.SessionAffinity() {
+			svc.Flags.SessionAffinity = models.ServiceFlagsSessionAffinityClientIP
+			svc.Flags.SessionAffinityTimeout = int64(s.SessionAffinityTimeout)
+		}
+	}
+
+	svc.HealthCheck = s.HealthCheck.GetModel()
+
 	return svc
 }
 
@@ -90,16 +454,43 @@ type SVCMap map[string]LBSVC
 // SVCMapID maps service IDs to service structures.
 type SVCMapID map[ServiceID]*LBSVC
 
+// SVCMapName maps service names to the sha256sums of the frontends of the
+// service.
+type SVCMapName map[LBSVCName]map[string]struct{}
+
+// Add adds the frontend of svc to the frontends of its name.
+func (m SVCMapName) Add(svc *LBSVC) {
+	if svc.Name.IsEmpty() {
+		return
+	}
+	if m[svc.Name] == nil {
+		m[svc.Name] = map[string]struct{}{}
+	}
+	m[svc.Name][svc.Sha256] = struct{}{}
+}
+
+// Delete removes the frontend of svc from the frontends of its name.
+func (m SVCMapName) Delete(svc *LBSVC) {
+	if svc.Name.IsEmpty() {
+		return
+	}
+	delete(m[svc.Name], svc.Sha256)
+	if len(m[svc.Name]) == 0 {
+		delete(m, svc.Name)
+	}
+}
+
 // RevNATMap is a map of the daemon's RevNATs.
 type RevNATMap map[ServiceID]L3n4Addr
 
 // LoadBalancer is the internal representation of the loadbalancer in the local cilium
 // daemon.
 type LoadBalancer struct {
-	BPFMapMU  lock.RWMutex
-	SVCMap    SVCMap
-	SVCMapID  SVCMapID
-	RevNATMap RevNATMap
+	BPFMapMU   lock.RWMutex
+	SVCMap     SVCMap
+	SVCMapID   SVCMapID
+	SVCMapName SVCMapName
+	RevNATMap  RevNATMap
 
 	K8sMU        lock.Mutex
 	K8sServices  map[K8sServiceNamespace]*K8sServiceInfo
@@ -119,14 +510,19 @@ func (lb *LoadBalancer) AddService(svc LBSVC) bool {
 		// If service already existed, remove old entry from Cilium's map
 		scopedLog.Debug("service is already in lb.SVCMapID; deleting old entry and updating it with new entry")
 		delete(lb.SVCMap, oldSvc.Sha256)
+		lb.SVCMapName.Delete(oldSvc)
+	}
+	if oldSvc, exists := lb.SVCMap[svc.Sha256]; exists {
+		lb.SVCMapName.Delete(&oldSvc)
 	}
 	scopedLog.Debug("adding service to loadbalancer")
 	lb.SVCMap[svc.Sha256] = svc
 	lb.SVCMapID[svc.FE.ID] = &svc
+	lb.SVCMapName.Add(&svc)
 	return !ok
 }
 
-// DeleteService deletes svc from lb's SVCMap and SVCMapID.
+// DeleteService deletes svc from lb's SVCMap, SVCMapID and SVCMapName.
 func (lb *LoadBalancer) DeleteService(svc *LBSVC) {
 	log.WithFields(logrus.Fields{
 		logfields.ServiceName: svc.FE.String(),
@@ -134,6 +530,7 @@ func (lb *LoadBalancer) DeleteService(svc *LBSVC) {
 	}).Debug("deleting service from loadbalancer")
 	delete(lb.SVCMap, svc.Sha256)
 	delete(lb.SVCMapID, svc.FE.ID)
+	lb.SVCMapName.Delete(svc)
 }
 
 func NewL4Type(name string) (L4Type, error) {
@@ -152,6 +549,7 @@ func NewLoadBalancer() *LoadBalancer {
 	return &LoadBalancer{
 		SVCMap:       SVCMap{},
 		SVCMapID:     SVCMapID{},
+		SVCMapName:   SVCMapName{},
 		RevNATMap:    RevNATMap{},
 		K8sServices:  map[K8sServiceNamespace]*K8sServiceInfo{},
 		K8sEndpoints: map[K8sServiceNamespace]*K8sServiceEndpoint{},
@@ -172,6 +570,33 @@ type K8sServiceInfo struct {
 	IsHeadless bool
 	Ports      map[FEPortName]*FEPort
 	Labels     map[string]string
+
+	// NodePorts are the node ports of the service, reachable on every
+	// address of the local node. Unlike Ports, the service ID of each
+	// frontend is tracked by the loadbalancer as there is one frontend per
+	// node address.
+	NodePorts map[FEPortName]*FEPort
+
+	// ExternalIPs are the external IPs of the service. Each of them is a
+	// frontend for all Ports.
+	ExternalIPs []net.IP
+
+	// SessionAffinityTimeout is the timeout in seconds of the ClientIP
+	// session affinity of the service, 0 if disabled.
+	SessionAffinityTimeout uint32
+
+	// ExternalName is the DNS name of an ExternalName service. These
+	// services are not load balanced.
+	ExternalName string
+
+	// Topology is the locality of the backends preferred for new
+	// connections to the service.
+	Topology LBTopology
+}
+
+// IsExternalName returns true if the service is an ExternalName service
+func (si *K8sServiceInfo) IsExternalName() bool {
+	return si.ExternalName != ""
 }
 
 // NewK8sServiceInfo creates a new K8sServiceInfo with the Ports map initialized.
@@ -181,6 +606,7 @@ func NewK8sServiceInfo(ip net.IP, headless bool, labels map[string]string) *K8sS
 		IsHeadless: headless,
 		Ports:      map[FEPortName]*FEPort{},
 		Labels:     labels,
+		NodePorts:  map[FEPortName]*FEPort{},
 	}
 }
 
@@ -191,14 +617,19 @@ type K8sServiceEndpoint struct {
 	// TODO: Replace bool for time.Time so we know last time the service endpoint was seen?
 	BEIPs map[string]bool
 	Ports map[FEPortName]*L4Addr
+
+	// NodeNames maps the backend IPs to the name of the node they are
+	// running on, if known.
+	NodeNames map[string]string
 }
 
 // NewK8sServiceEndpoint creates a new K8sServiceEndpoint with the backend BEIPs map and
 // Ports map initialized.
 func NewK8sServiceEndpoint() *K8sServiceEndpoint {
 	return &K8sServiceEndpoint{
-		BEIPs: map[string]bool{},
-		Ports: map[FEPortName]*L4Addr{},
+		BEIPs:     map[string]bool{},
+		Ports:     map[FEPortName]*L4Addr{},
+		NodeNames: map[string]string{},
 	}
 }
 
@@ -319,6 +750,9 @@ func NewLBBackEndFromBackendModel(base *models.BackendAddress) (*LBBackEnd, erro
 	return &LBBackEnd{
 		L3n4Addr: L3n4Addr{IP: ip, L4Addr: *l4addr},
 		Weight:   base.Weight,
+		Name:     base.Name,
+		NodeName: base.NodeName,
+		Zone:     base.Zone,
 	}, nil
 }
 
@@ -359,9 +793,13 @@ func (b *LBBackEnd) GetBackendModel() *models.BackendAddress {
 
 	ip := b.IP.String()
 	return &models.BackendAddress{
-		IP:     &ip,
-		Port:   b.Port,
-		Weight: b.Weight,
+		IP:       &ip,
+		Port:     b.Port,
+		Weight:   b.Weight,
+		Name:     b.Name,
+		NodeName: b.NodeName,
+		Zone:     b.Zone,
+		Draining: b.Draining,
 	}
 }
 
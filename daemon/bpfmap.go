@@ -0,0 +1,85 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	restapi "github.com/cilium/cilium/api/v1/server/restapi/daemon"
+	"github.com/cilium/cilium/pkg/apierror"
+	"github.com/cilium/cilium/pkg/bpf"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+type getMap struct {
+	daemon *Daemon
+}
+
+// NewGetMapHandler returns the handler listing all pinned BPF maps
+func NewGetMapHandler(d *Daemon) restapi.GetMapHandler {
+	return &getMap{daemon: d}
+}
+
+func (h *getMap) Handle(params restapi.GetMapParams) middleware.Responder {
+	maps, err := bpf.GetPinnedMapsModel()
+	if err != nil {
+		return apierror.Error(restapi.GetMapFailureCode, err)
+	}
+
+	return restapi.NewGetMapOK().WithPayload(maps)
+}
+
+type getMapName struct {
+	daemon *Daemon
+}
+
+// NewGetMapNameHandler returns the handler returning the attributes and the
+// cache contents of a pinned BPF map
+func NewGetMapNameHandler(d *Daemon) restapi.GetMapNameHandler {
+	return &getMapName{daemon: d}
+}
+
+func (h *getMapName) Handle(params restapi.GetMapNameParams) middleware.Responder {
+	m, err := bpf.GetPinnedMapModel(params.Name, false)
+	if err != nil {
+		return restapi.NewGetMapNameNotFound()
+	}
+
+	return restapi.NewGetMapNameOK().WithPayload(m)
+}
+
+type getMapNameEntries struct {
+	daemon *Daemon
+}
+
+// NewGetMapNameEntriesHandler returns the handler returning the decoded
+// entries of a pinned BPF map
+func NewGetMapNameEntriesHandler(d *Daemon) restapi.GetMapNameEntriesHandler {
+	return &getMapNameEntries{daemon: d}
+}
+
+func (h *getMapNameEntries) Handle(params restapi.GetMapNameEntriesParams) middleware.Responder {
+	pinned, err := bpf.OpenMap(params.Name)
+	if err != nil {
+		return restapi.NewGetMapNameEntriesNotFound()
+	}
+	pinned.Close()
+
+	m, err := bpf.GetPinnedMapModel(params.Name, true)
+	if err != nil {
+		return apierror.Error(restapi.GetMapNameEntriesFailureCode, err)
+	}
+
+	return restapi.NewGetMapNameEntriesOK().WithPayload(m)
+}
@@ -18,6 +18,7 @@ import (
 	"net"
 	"os"
 	"runtime"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/daemon/options"
@@ -60,6 +61,37 @@ type Config struct {
 	IPv4Disabled    bool       // Disable IPv4 allocation
 	LBInterface     string     // Set with name of the interface to loadbalance packets from
 
+	// BPFMasquerade masquerades packets of endpoints leaving the node in
+	// the datapath of Device instead of iptables
+	BPFMasquerade bool
+
+	// MasqueradeExcludeCIDRs are the IPv4 prefixes to which packets are
+	// not masqueraded by BPFMasquerade
+	MasqueradeExcludeCIDRs []*net.IPNet
+
+	// EnableMaglev enables the Maglev lookup tables in the datapath so
+	// that services can use the Maglev load-balancing algorithm
+	EnableMaglev bool
+
+	// EnableLBStats enables the accounting of the packets, bytes and
+	// connections of each service backend in the datapath
+	EnableLBStats bool
+
+	// EnableEgressGateway enables egress gateway policies redirecting the
+	// traffic of endpoints through gateway nodes
+	EnableEgressGateway bool
+
+	// EnableIPSec enables the encryption of the traffic between nodes
+	// with IPsec
+	EnableIPSec bool
+
+	// IPSecKeyFile is the path of the file holding the IPsec key
+	IPSecKeyFile string
+
+	// IPSecKeyRotationDuration is the time given to all nodes to install
+	// a new IPsec key before it is used
+	IPSecKeyRotationDuration time.Duration
+
 	Tunnel string // Tunnel mode
 
 	DryMode       bool // Do not create BPF maps, devices, ..
@@ -42,17 +42,24 @@ import (
 	"github.com/cilium/cilium/pkg/apierror"
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/clustermesh"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/egressgateway"
 	"github.com/cilium/cilium/pkg/endpoint"
 	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/healthcheck"
 	"github.com/cilium/cilium/pkg/ipam"
+	"github.com/cilium/cilium/pkg/ipsec"
 	"github.com/cilium/cilium/pkg/k8s"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/maps/egressmap"
 	"github.com/cilium/cilium/pkg/maps/lbmap"
 	"github.com/cilium/cilium/pkg/maps/lxcmap"
+	"github.com/cilium/cilium/pkg/maps/nat"
 	"github.com/cilium/cilium/pkg/maps/policymap"
 	"github.com/cilium/cilium/pkg/maps/tunnel"
 	"github.com/cilium/cilium/pkg/monitor"
@@ -60,6 +67,7 @@ import (
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/proxy"
 	"github.com/cilium/cilium/pkg/proxy/accesslog"
+	"github.com/cilium/cilium/pkg/servicefile"
 	"github.com/cilium/cilium/pkg/workloads"
 	"github.com/cilium/cilium/pkg/workloads/containerd"
 
@@ -86,6 +94,7 @@ const (
 	initArgDevice
 	initArgDevicePreFilter
 	initArgModePreFilter
+	initArgMasquerade
 	initArgMax
 )
 
@@ -106,12 +115,46 @@ type Daemon struct {
 	nodeMonitor  monitorLaunch.NodeMonitor
 	ciliumHealth *health.CiliumHealth
 
+	// clusterMesh is the connection to all remote clusters, nil if cluster
+	// mesh is not configured
+	clusterMesh *clustermesh.ClusterMesh
+
+	// serviceFiles synchronizes the services defined in service files, nil
+	// if no service file directory is configured
+	serviceFiles *servicefile.Watcher
+
+	// lbHealth runs the health checks of the backends of services
+	lbHealth *healthcheck.Manager
+
+	// egressGateway manages the egress gateway policies, nil if egress
+	// gateways are disabled
+	egressGateway *egressgateway.Manager
+
+	// ipsec encrypts the traffic between nodes, nil if encryption is
+	// disabled
+	ipsec *ipsec.Manager
+
+	// lbDrain holds the drain deadline of each draining backend by
+	// backend address and SHA256 sum of the service frontend. Protected
+	// by loadBalancer.BPFMapMU.
+	lbDrain map[string]map[string]time.Time
+
+	controllers *controller.Manager
+
 	// k8sAPIs is a set of k8s API in use. They are setup in EnableK8sWatcher,
 	// and may be disabled while the agent runs.
 	// This is on this object, instead of a global, because EnableK8sWatcher is
 	// on Daemon.
 	k8sAPIGroups k8sAPIGroupsUsed
 
+	// k8sExternalNames resolves the DNS names of k8s ExternalName services
+	k8sExternalNames *k8s.ExternalNameResolver
+
+	// k8sToServices holds the translator last applied to ToServices rules
+	// for each headless and ExternalName service. Protected by
+	// loadBalancer.K8sMU.
+	k8sToServices map[types.K8sServiceNamespace]k8s.RuleTranslator
+
 	// Used to synchronize generation of daemon's BPF programs and endpoint BPF
 	// programs.
 	compilationMutex *lock.RWMutex
@@ -491,6 +534,14 @@ func (c *customChain) installFeeder() error {
 // that the old feeder rules is also removed on agent start, otherwise,
 // flushing and removing the custom chains will fail.
 var ciliumChains = []customChain{
+	{
+		// Must precede ciliumPostNatChain so that packets redirected
+		// to egress gateways are not masqueraded to the node IP
+		name:       ciliumEgressGatewayChain,
+		table:      "nat",
+		hook:       "POSTROUTING",
+		feederArgs: []string{""},
+	},
 	{
 		name:       ciliumPostNatChain,
 		table:      "nat",
@@ -583,16 +634,19 @@ func (d *Daemon) installIptablesRules() error {
 		}
 
 		// Masquerade all traffic from node prefix not going to node prefix
-		// which is not going over the tunnel device
-		if err := runProg("iptables", []string{
-			"-t", "nat",
-			"-A", "CILIUM_POST",
-			"-s", node.GetIPv4AllocRange().String(),
-			"!", "-d", node.GetIPv4AllocRange().String(),
-			"!", "-o", "cilium_+",
-			"-m", "comment", "--comment", "cilium masquerade non-cluster",
-			"-j", "MASQUERADE"}, false); err != nil {
-			return err
+		// which is not going over the tunnel device, unless it is
+		// masqueraded by the datapath of the native device
+		if !d.conf.BPFMasquerade {
+			if err := runProg("iptables", []string{
+				"-t", "nat",
+				"-A", "CILIUM_POST",
+				"-s", node.GetIPv4AllocRange().String(),
+				"!", "-d", node.GetIPv4AllocRange().String(),
+				"!", "-o", "cilium_+",
+				"-m", "comment", "--comment", "cilium masquerade non-cluster",
+				"-j", "MASQUERADE"}, false); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -653,6 +707,7 @@ func (d *Daemon) compileBase() error {
 	args[initArgRundir] = d.conf.StateDir
 	args[initArgIPv4NodeIP] = node.GetInternalIPv4().String()
 	args[initArgIPv6NodeIP] = node.GetIPv6().String()
+	args[initArgMasquerade] = strconv.FormatBool(d.conf.BPFMasquerade)
 
 	if d.conf.Device != "undefined" {
 		_, err := netlink.LinkByName(d.conf.Device)
@@ -790,10 +845,41 @@ func (d *Daemon) init() error {
 	fmt.Fprintf(fw, "#define HOST_ID %d\n", policy.GetReservedID(labels.IDNameHost))
 	fmt.Fprintf(fw, "#define WORLD_ID %d\n", policy.GetReservedID(labels.IDNameWorld))
 	fmt.Fprintf(fw, "#define CLUSTER_ID %d\n", policy.GetReservedID(labels.IDNameCluster))
+	fmt.Fprintf(fw, "#define MIN_ALLOCATED_ID %d\n", policy.MinimalNumericIdentity)
+	fmt.Fprintf(fw, "#define LOCAL_CLUSTER_ID_BITS %#x\n", policy.LocalClusterIdentityBits())
 	fmt.Fprintf(fw, "#define LB_RR_MAX_SEQ %d\n", lbmap.MaxSeq)
+	fmt.Fprintf(fw, "#define LB_MAGLEV_TABLE_SIZE %d\n", lbmap.MaglevTableSize)
+	if d.conf.EnableMaglev {
+		fw.WriteString("#define ENABLE_MAGLEV 1\n")
+	}
+	if d.conf.EnableLBStats {
+		fw.WriteString("#define ENABLE_LB_STATS 1\n")
+	}
 
 	fmt.Fprintf(fw, "#define TUNNEL_ENDPOINT_MAP_SIZE %d\n", tunnel.MaxEntries)
 	fmt.Fprintf(fw, "#define ENDPOINTS_MAP_SIZE %d\n", lxcmap.MaxKeys)
+	fmt.Fprintf(fw, "#define ENDPOINT_POLICY_MAP_SIZE %d\n", policymap.MaxEntries)
+
+	if d.conf.BPFMasquerade {
+		externalIP := node.GetExternalIPv4().To4()
+		if externalIP == nil {
+			f.Close()
+			return fmt.Errorf("BPF masquerading requires an IPv4 node address")
+		}
+		fw.WriteString("#define ENABLE_MASQUERADE 1\n")
+		fmt.Fprintf(fw, "#define SNAT_IPV4_EXTERNAL %#x\n", byteorder.HostSliceToNetwork(externalIP, reflect.Uint32).(uint32))
+		fmt.Fprintf(fw, "#define SNAT_MAPPING_IPV4_SIZE %d\n", nat.MaxEntries)
+		fmt.Fprintf(fw, "#define SNAT_MAPPING_MIN_PORT %d\n", nat.MinPort)
+		fmt.Fprintf(fw, "#define SNAT_MAPPING_MAX_PORT %d\n", nat.MaxPort)
+		if len(d.conf.MasqueradeExcludeCIDRs) > 0 {
+			fmt.Fprintf(fw, "#define SNAT_IPV4_EXCLUDE_MAPPINGS %s\n", nat.FmtExcludeMappings(d.conf.MasqueradeExcludeCIDRs))
+		}
+	}
+
+	if d.conf.EnableEgressGateway {
+		fw.WriteString("#define ENABLE_EGRESS_GATEWAY 1\n")
+		fmt.Fprintf(fw, "#define EGRESS_GW_MAP_SIZE %d\n", egressmap.MaxEntries)
+	}
 
 	fmt.Fprintf(fw, "#define TRACE_PAYLOAD_LEN %dULL\n", tracePayloadLen)
 
@@ -833,6 +919,18 @@ func (d *Daemon) init() error {
 		if _, err := lbmap.RRSeq6Map.OpenOrCreate(); err != nil {
 			return err
 		}
+		if _, err := lbmap.Maglev6Map.OpenOrCreate(); err != nil {
+			return err
+		}
+		if _, err := lbmap.Affinity6Map.OpenOrCreate(); err != nil {
+			return err
+		}
+		if _, err := lbmap.Stats6Map.OpenOrCreate(); err != nil {
+			return err
+		}
+		if _, err := lbmap.AffinityMatchMap.OpenOrCreate(); err != nil {
+			return err
+		}
 		if !d.conf.IPv4Disabled {
 			if _, err := lbmap.Service4Map.OpenOrCreate(); err != nil {
 				return err
@@ -843,6 +941,15 @@ func (d *Daemon) init() error {
 			if _, err := lbmap.RRSeq4Map.OpenOrCreate(); err != nil {
 				return err
 			}
+			if _, err := lbmap.Maglev4Map.OpenOrCreate(); err != nil {
+				return err
+			}
+			if _, err := lbmap.Affinity4Map.OpenOrCreate(); err != nil {
+				return err
+			}
+			if _, err := lbmap.Stats4Map.OpenOrCreate(); err != nil {
+				return err
+			}
 		}
 		// Clean all lb entries
 		if !d.conf.RestoreState {
@@ -860,6 +967,18 @@ func (d *Daemon) init() error {
 			if err := lbmap.RRSeq6Map.DeleteAll(); err != nil {
 				return err
 			}
+			if err := lbmap.Maglev6Map.DeleteAll(); err != nil {
+				return err
+			}
+			if err := lbmap.Affinity6Map.DeleteAll(); err != nil {
+				return err
+			}
+			if err := lbmap.Stats6Map.DeleteAll(); err != nil {
+				return err
+			}
+			if err := lbmap.AffinityMatchMap.DeleteAll(); err != nil {
+				return err
+			}
 
 			if !d.conf.IPv4Disabled {
 				if err := lbmap.Service4Map.DeleteAll(); err != nil {
@@ -868,8 +987,19 @@ func (d *Daemon) init() error {
 				if err := lbmap.RRSeq4Map.DeleteAll(); err != nil {
 					return err
 				}
+				if err := lbmap.Maglev4Map.DeleteAll(); err != nil {
+					return err
+				}
+				if err := lbmap.Affinity4Map.DeleteAll(); err != nil {
+					return err
+				}
+				if err := lbmap.Stats4Map.DeleteAll(); err != nil {
+					return err
+				}
 			}
 		}
+
+		lbmap.StartAffinityGC()
 	}
 
 	return nil
@@ -890,10 +1020,13 @@ func NewDaemon(c *Config) (*Daemon, error) {
 	lb := types.NewLoadBalancer()
 
 	d := Daemon{
-		conf:         c,
-		loadBalancer: lb,
-		policy:       policy.NewPolicyRepository(),
-		uniqueID:     map[uint64]bool{},
+		conf:          c,
+		loadBalancer:  lb,
+		policy:        policy.NewPolicyRepository(),
+		uniqueID:      map[uint64]bool{},
+		lbDrain:       map[string]map[string]time.Time{},
+		controllers:   controller.NewManager(),
+		k8sToServices: map[types.K8sServiceNamespace]k8s.RuleTranslator{},
 
 		// FIXME
 		// The channel size has to be set to the maximum number of
@@ -902,6 +1035,13 @@ func NewDaemon(c *Config) (*Daemon, error) {
 		buildEndpointChan: make(chan *endpoint.Request, lxcmap.MaxKeys),
 		compilationMutex:  new(lock.RWMutex),
 	}
+	d.lbHealth = healthcheck.NewManager(d.updateBackendHealth)
+	d.k8sExternalNames = k8s.NewExternalNameResolver(nil, d.k8sExternalNameChanged)
+	d.controllers.UpdateController("lb-backend-drain",
+		controller.ControllerParams{
+			DoFunc:      d.removeDrainedBackends,
+			RunInterval: lbDrainInterval,
+		})
 
 	workloads.Init(&d)
 
@@ -1022,10 +1162,32 @@ func NewDaemon(c *Config) (*Daemon, error) {
 	ni, n := node.GetLocalNode()
 	node.UpdateNode(ni, n, node.TunnelRoute, nil)
 
+	// Encryption is set up once the local node addressing is known, the
+	// remote nodes are programmed as they become known
+	if !d.DryModeEnabled() {
+		d.initIPSec()
+	}
+
 	// This needs to be done after the node addressing has been configured
 	// as the node address is required as sufix
 	policy.InitIdentityAllocator(&d)
 
+	// Make the local node known to remote clusters and connect to the
+	// remote clusters after the identity allocator has been initialized
+	// as remote identities trigger policy updates
+	if clusterMeshConfig != "" {
+		node.PublishLocalNode()
+
+		cm, err := clustermesh.NewClusterMesh(clustermesh.Configuration{
+			Name:            node.GetClusterName(),
+			ConfigDirectory: clusterMeshConfig,
+		})
+		if err != nil {
+			log.WithError(err).Fatal("Unable to initialize cluster mesh")
+		}
+		d.clusterMesh = cm
+	}
+
 	if !d.conf.IPv4Disabled {
 		// Allocate IPv4 service loopback IP
 		loopbackIPv4, _, err := ipam.AllocateNext("ipv4")
@@ -1058,6 +1220,33 @@ func NewDaemon(c *Config) (*Daemon, error) {
 		containerd.IgnoreRunningContainers()
 	}
 
+	// Egress gateway policies are applied after the endpoints have been
+	// restored to avoid removing their map entries
+	if d.conf.EnableEgressGateway {
+		d.egressGateway = egressgateway.NewManager(&d)
+		if err := d.egressGateway.Start(); err != nil {
+			log.WithError(err).Fatal("Unable to start egress gateway")
+		}
+	}
+
+	// Statistics of services which were not restored are removed
+	d.startLBStats()
+	d.startPolicyStats()
+
+	// Services from service files are added after the services of the
+	// previous run have been restored
+	if serviceConfigDir != "" {
+		w, err := servicefile.NewWatcher(servicefile.Configuration{
+			Directory: serviceConfigDir,
+			Add:       d.addFileService,
+			Delete:    d.delFileService,
+		})
+		if err != nil {
+			log.WithError(err).Fatal("Unable to initialize service file synchronization")
+		}
+		d.serviceFiles = w
+	}
+
 	d.collectStaleMapGarbage()
 
 	// Allocate health endpoint IPs after restoring state
@@ -1358,11 +1547,13 @@ func numWorkerThreads() int {
 func (d *Daemon) GetServiceList() []*models.Service {
 	list := []*models.Service{}
 
+	stats := d.dumpLBStats()
+
 	d.loadBalancer.BPFMapMU.RLock()
 	defer d.loadBalancer.BPFMapMU.RUnlock()
 
 	for _, v := range d.loadBalancer.SVCMap {
-		list = append(list, v.GetModel())
+		list = append(list, d.getServiceModel(&v, stats))
 	}
 	return list
 }
@@ -1431,6 +1622,24 @@ func (d *Daemon) sendEvent(data []byte) error {
 	return nil
 }
 
+// IdentityReallocated resolves the identity of all endpoints using an
+// identity which has been rejected by the identity allocator
+func (d *Daemon) IdentityReallocated(oldID, newID policy.NumericIdentity) {
+	for _, ep := range endpointmanager.GetEndpoints() {
+		ep.Mutex.RLock()
+		id := ep.GetIdentity()
+		ep.Mutex.RUnlock()
+
+		if id == oldID {
+			log.WithFields(logrus.Fields{
+				logfields.EndpointID: ep.ID,
+				logfields.Identity:   oldID,
+			}).Infof("Identity has been reallocated as %d, resolving identity of endpoint", newID)
+			ep.RefreshIdentity(d)
+		}
+	}
+}
+
 // GetNodeSuffix returns the suffix to be appended to kvstore keys of this
 // agent
 func (d *Daemon) GetNodeSuffix() string {
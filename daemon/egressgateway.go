@@ -0,0 +1,208 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/cilium/cilium/api/v1/models"
+	. "github.com/cilium/cilium/api/v1/server/restapi/policy"
+	"github.com/cilium/cilium/pkg/apierror"
+	"github.com/cilium/cilium/pkg/egressgateway"
+	"github.com/cilium/cilium/pkg/endpointmanager"
+	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/node"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// ciliumEgressGatewayChain holds the SNAT rules of the egress gateway
+// policies for which the local node is the gateway
+const ciliumEgressGatewayChain = "CILIUM_EGRESS_GW"
+
+// GetEgressEndpoints returns all local endpoints with an IPv4 address
+func (d *Daemon) GetEgressEndpoints() []egressgateway.Endpoint {
+	eps := endpointmanager.GetEndpoints()
+	result := make([]egressgateway.Endpoint, 0, len(eps))
+	for _, ep := range eps {
+		ep.Mutex.RLock()
+		if ep.IPv4 != nil && ep.SecLabel != nil {
+			result = append(result, egressgateway.Endpoint{
+				IPv4:   ep.IPv4.IP(),
+				Labels: ep.SecLabel.Labels.LabelArray(),
+			})
+		}
+		ep.Mutex.RUnlock()
+	}
+	return result
+}
+
+// UpdateEgressGatewayRules replaces the rules of the egress gateway chain.
+// Packets of local endpoints which do not match any of their rules must not
+// be translated by the rules of remote endpoints.
+func (d *Daemon) UpdateEgressGatewayRules(rules []egressgateway.SNATRule) error {
+	if err := runProg("iptables", []string{
+		"-t", "nat",
+		"-F", ciliumEgressGatewayChain}, false); err != nil {
+		return err
+	}
+
+	returnInstalled := false
+	for _, r := range rules {
+		args := []string{"-t", "nat", "-A", ciliumEgressGatewayChain}
+		if r.Source != nil {
+			args = append(args, "-s", r.Source.String())
+		} else {
+			if !returnInstalled {
+				if err := installEgressGatewayReturnRule(); err != nil {
+					return err
+				}
+				returnInstalled = true
+			}
+			args = append(args, "-s", node.GetIPv4ClusterRange().String())
+		}
+		args = append(args,
+			"-d", r.Destination.String(),
+			"-m", "comment", "--comment", "cilium egress gateway",
+			"-j", "SNAT", "--to-source", r.EgressIP.String())
+
+		if err := runProg("iptables", args, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// installEgressGatewayReturnRule skips the rules of remote endpoints for
+// packets of local endpoints
+func installEgressGatewayReturnRule() error {
+	return runProg("iptables", []string{
+		"-t", "nat",
+		"-A", ciliumEgressGatewayChain,
+		"-s", node.GetIPv4AllocRange().String(),
+		"-m", "comment", "--comment", "cilium egress gateway skip local endpoints",
+		"-j", "RETURN"}, false)
+}
+
+// parseCiliumEgressGatewayPolicyV2 returns the egress gateway policy of the
+// custom resource cegp. The policy is not sanitized.
+func parseCiliumEgressGatewayPolicyV2(cegp *cilium_v2.CiliumEgressGatewayPolicy) (*egressgateway.Policy, error) {
+	cidrs, err := egressgateway.ParseCIDRs(cegp.Spec.DestinationCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &egressgateway.Policy{
+		Name:             cegp.Name,
+		EndpointSelector: cegp.Spec.EndpointSelector,
+		DestinationCIDRs: cidrs,
+		GatewayNode:      cegp.Spec.GatewayNode,
+		EgressIP:         net.ParseIP(cegp.Spec.EgressIP),
+	}, nil
+}
+
+func (d *Daemon) addCiliumEgressGatewayPolicyV2(cegp *cilium_v2.CiliumEgressGatewayPolicy) {
+	scopedLog := log.WithField(logfields.EgressGatewayPolicy, cegp.Name)
+
+	p, err := parseCiliumEgressGatewayPolicyV2(cegp)
+	if err == nil {
+		_, err = d.egressGateway.Upsert(p)
+	}
+	if err != nil {
+		scopedLog.WithError(err).Warn("Ignoring invalid CiliumEgressGatewayPolicy")
+	}
+}
+
+func (d *Daemon) deleteCiliumEgressGatewayPolicyV2(cegp *cilium_v2.CiliumEgressGatewayPolicy) {
+	if err := d.egressGateway.Delete(cegp.Name); err != nil {
+		log.WithError(err).WithField(logfields.EgressGatewayPolicy, cegp.Name).
+			Debug("Unable to delete CiliumEgressGatewayPolicy")
+	}
+}
+
+type getEgressGatewayPolicy struct {
+	d *Daemon
+}
+
+func newGetEgressGatewayPolicyHandler(d *Daemon) GetPolicyEgressGatewayHandler {
+	return &getEgressGatewayPolicy{d: d}
+}
+
+func (h *getEgressGatewayPolicy) Handle(params GetPolicyEgressGatewayParams) middleware.Responder {
+	list := []*models.EgressGatewayPolicy{}
+	if h.d.egressGateway != nil {
+		for _, p := range h.d.egressGateway.GetPolicies() {
+			list = append(list, p.GetModel())
+		}
+	}
+	return NewGetPolicyEgressGatewayOK().WithPayload(list)
+}
+
+type putEgressGatewayPolicy struct {
+	d *Daemon
+}
+
+func newPutEgressGatewayPolicyHandler(d *Daemon) PutPolicyEgressGatewayNameHandler {
+	return &putEgressGatewayPolicy{d: d}
+}
+
+func (h *putEgressGatewayPolicy) Handle(params PutPolicyEgressGatewayNameParams) middleware.Responder {
+	if h.d.egressGateway == nil {
+		msg := fmt.Errorf("Egress gateway is not enabled in daemon")
+		return apierror.Error(PutPolicyEgressGatewayNameFailureCode, msg)
+	}
+
+	p, err := egressgateway.NewPolicyFromModel(params.Name, params.Policy)
+	if err != nil {
+		return apierror.Error(PutPolicyEgressGatewayNameInvalidCode, err)
+	}
+
+	created, err := h.d.egressGateway.Upsert(p)
+	if err != nil {
+		return apierror.Error(PutPolicyEgressGatewayNameInvalidCode, err)
+	}
+
+	if created {
+		return NewPutPolicyEgressGatewayNameCreated()
+	}
+	return NewPutPolicyEgressGatewayNameOK()
+}
+
+type deleteEgressGatewayPolicy struct {
+	d *Daemon
+}
+
+func newDeleteEgressGatewayPolicyHandler(d *Daemon) DeletePolicyEgressGatewayNameHandler {
+	return &deleteEgressGatewayPolicy{d: d}
+}
+
+func (h *deleteEgressGatewayPolicy) Handle(params DeletePolicyEgressGatewayNameParams) middleware.Responder {
+	if h.d.egressGateway == nil {
+		msg := fmt.Errorf("Egress gateway is not enabled in daemon")
+		return apierror.Error(DeletePolicyEgressGatewayNameFailureCode, msg)
+	}
+
+	if h.d.egressGateway.Get(params.Name) == nil {
+		return NewDeletePolicyEgressGatewayNameNotFound()
+	}
+
+	if err := h.d.egressGateway.Delete(params.Name); err != nil {
+		return apierror.Error(DeletePolicyEgressGatewayNameFailureCode, err)
+	}
+	return NewDeletePolicyEgressGatewayNameOK()
+}
@@ -586,6 +586,32 @@ func (h *getEndpointIDHealthz) Handle(params GetEndpointIDHealthzParams) middlew
 	}
 }
 
+type getEndpointIDPolicyStats struct {
+	d *Daemon
+}
+
+func NewGetEndpointIDPolicyStatsHandler(d *Daemon) GetEndpointIDPolicyStatsHandler {
+	return &getEndpointIDPolicyStats{d: d}
+}
+
+func (h *getEndpointIDPolicyStats) Handle(params GetEndpointIDPolicyStatsParams) middleware.Responder {
+	log.WithField(logfields.EndpointID, params.ID).Debug("GET /endpoint/{id}/policy-stats request")
+
+	ep, err := endpointmanager.Lookup(params.ID)
+	if err != nil {
+		return apierror.Error(GetEndpointIDPolicyStatsInvalidCode, err)
+	} else if ep == nil {
+		return NewGetEndpointIDPolicyStatsNotFound()
+	}
+
+	stats, err := ep.GetPolicyStatsModel()
+	if err != nil {
+		return apierror.Error(GetEndpointIDPolicyStatsFailureCode, err)
+	}
+
+	return NewGetEndpointIDPolicyStatsOK().WithPayload(stats)
+}
+
 func checkLabels(add, del labels.Labels) (addLabels, delLabels labels.Labels, ok bool) {
 	addLabels, _ = labels.FilterLabels(add)
 	delLabels, _ = labels.FilterLabels(del)
@@ -0,0 +1,91 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/ipsec"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/node"
+)
+
+const (
+	// vxlanPort is the UDP port of the vxlan tunnel device. The port is
+	// not configured explicitly so the kernel default applies.
+	vxlanPort = 8472
+
+	// genevePort is the UDP port of the geneve tunnel device
+	genevePort = 6081
+)
+
+// tunnelPort returns the UDP port of the overlay network, 0 if the daemon
+// runs in direct routing mode
+func (d *Daemon) tunnelPort() int {
+	if d.conf.Device != "undefined" {
+		return 0
+	}
+	if d.conf.Tunnel == "geneve" {
+		return genevePort
+	}
+	return vxlanPort
+}
+
+// initIPSec starts encrypting the traffic to all remote nodes with the key
+// of the configured key file. If encryption is disabled, the xfrm states
+// and policies of a previous run are removed.
+func (d *Daemon) initIPSec() {
+	if !d.conf.EnableIPSec {
+		if err := ipsec.DeleteAll(); err != nil {
+			log.WithError(err).Debug("Unable to remove IPsec states and policies")
+		}
+		return
+	}
+
+	m, err := ipsec.NewManager(ipsec.Config{
+		LocalIP:        node.GetExternalIPv4(),
+		LocalAllocCIDR: node.GetIPv4AllocRange(),
+		TunnelPort:     d.tunnelPort(),
+		RotationDelay:  d.conf.IPSecKeyRotationDuration,
+	})
+	if err != nil {
+		log.WithError(err).Fatal("Unable to initialize IPsec")
+	}
+
+	if err := m.WatchKeyFile(d.conf.IPSecKeyFile); err != nil {
+		log.WithError(err).WithField(logfields.Path, d.conf.IPSecKeyFile).Fatal("Unable to install IPsec key")
+	}
+
+	node.RegisterObserver(m)
+	d.ipsec = m
+}
+
+func (d *Daemon) getEncryptionStatus() *models.EncryptionStatus {
+	if d.ipsec == nil {
+		return &models.EncryptionStatus{Mode: models.EncryptionStatusModeDisabled}
+	}
+	return d.ipsec.Status()
+}
+
+// getNodeEncryptionKey returns the SPI of the key encrypting the traffic to
+// the node, for the local node the SPI of the key used for outgoing traffic
+func (d *Daemon) getNodeEncryptionKey(ni node.Identity) uint32 {
+	if d.ipsec == nil {
+		return 0
+	}
+	if local, _ := node.GetLocalNode(); ni == local {
+		return d.ipsec.GetActiveKey()
+	}
+	return d.ipsec.GetNodeKey(ni)
+}
@@ -19,10 +19,12 @@ import (
 	"net"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/annotation"
 	"github.com/cilium/cilium/pkg/k8s"
 	cilium_v1 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v1"
 	cilium_v2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
@@ -60,6 +62,7 @@ const (
 	k8sAPIGroupIngressV1Beta1    = "extensions/v1beta1::Ingress"
 	k8sAPIGroupCiliumV1          = "cilium/v1::CiliumNetworkPolicy"
 	k8sAPIGroupCiliumV2          = "cilium/v2::CiliumNetworkPolicy"
+	k8sAPIGroupCiliumEgressV2    = "cilium/v2::CiliumEgressGatewayPolicy"
 )
 
 var (
@@ -519,6 +522,48 @@ func (d *Daemon) EnableK8sWatcher(reSyncPeriod time.Duration) error {
 
 	si.Start(wait.NeverStop)
 
+	if d.egressGateway != nil && ciliumv2VerConstr.Check(sv) {
+		serCEGPs := serializer.NewFunctionQueue(20)
+
+		_, egressController := cache.NewInformer(
+			cache.NewListWatchFromClient(ciliumNPClient.CiliumV2().RESTClient(),
+				cilium_v2.EgressGatewayPolicyPluralName, v1.NamespaceAll, fields.Everything()),
+			&cilium_v2.CiliumEgressGatewayPolicy{},
+			reSyncPeriod,
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					metrics.SetTSValue(metrics.EventTSK8s, time.Now())
+					if cegp := copyObjToV2CEGP(obj); cegp != nil {
+						serCEGPs.Enqueue(func() error {
+							d.addCiliumEgressGatewayPolicyV2(cegp)
+							return nil
+						}, serializer.NoRetry)
+					}
+				},
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					metrics.SetTSValue(metrics.EventTSK8s, time.Now())
+					if newCEGP := copyObjToV2CEGP(newObj); newCEGP != nil {
+						serCEGPs.Enqueue(func() error {
+							d.addCiliumEgressGatewayPolicyV2(newCEGP)
+							return nil
+						}, serializer.NoRetry)
+					}
+				},
+				DeleteFunc: func(obj interface{}) {
+					metrics.SetTSValue(metrics.EventTSK8s, time.Now())
+					if cegp := copyObjToV2CEGP(obj); cegp != nil {
+						serCEGPs.Enqueue(func() error {
+							d.deleteCiliumEgressGatewayPolicyV2(cegp)
+							return nil
+						}, serializer.NoRetry)
+					}
+				},
+			},
+		)
+		go egressController.Run(wait.NeverStop)
+		d.k8sAPIGroups.addAPI(k8sAPIGroupCiliumEgressV2)
+	}
+
 	_, nodesController := cache.NewInformer(
 		cache.NewListWatchFromClient(k8s.Client().CoreV1().RESTClient(),
 			"nodes", v1.NamespaceAll, fields.Everything()),
@@ -632,6 +677,16 @@ func copyObjToV2CNP(obj interface{}) *cilium_v2.CiliumNetworkPolicy {
 	return cnp.DeepCopy()
 }
 
+func copyObjToV2CEGP(obj interface{}) *cilium_v2.CiliumEgressGatewayPolicy {
+	cegp, ok := obj.(*cilium_v2.CiliumEgressGatewayPolicy)
+	if !ok {
+		log.WithField(logfields.Object, logfields.Repr(obj)).
+			Warn("Ignoring invalid k8s v2 CiliumEgressGatewayPolicy")
+		return nil
+	}
+	return cegp.DeepCopy()
+}
+
 func copyObjToV1Node(obj interface{}) *v1.Node {
 	node, ok := obj.(*v1.Node)
 	if !ok {
@@ -759,7 +814,9 @@ func (d *Daemon) addK8sServiceV1(svc *v1.Service) {
 		break
 
 	case v1.ServiceTypeExternalName:
-		// External-name services must be ignored
+		// ExternalName services are not load balanced, their DNS name
+		// is only resolved to translate ToServices rules
+		d.addK8sExternalNameV1(svc)
 		return
 
 	default:
@@ -784,8 +841,21 @@ func (d *Daemon) addK8sServiceV1(svc *v1.Service) {
 	}
 	newSI := types.NewK8sServiceInfo(clusterIP, headless, svc.Labels)
 
-	// FIXME: Add support for
-	//  - NodePort
+	if svc.Spec.SessionAffinity == v1.ServiceAffinityClientIP {
+		newSI.SessionAffinityTimeout = uint32(v1.DefaultClientIPServiceAffinitySeconds)
+		if cfg := svc.Spec.SessionAffinityConfig; cfg != nil && cfg.ClientIP != nil && cfg.ClientIP.TimeoutSeconds != nil {
+			newSI.SessionAffinityTimeout = uint32(*cfg.ClientIP.TimeoutSeconds)
+		}
+	}
+
+	if value, ok := svc.Annotations[annotation.ServiceTopology]; ok {
+		if topology, err := types.NewLBTopology(value); err != nil {
+			scopedLog.WithError(err).WithField(annotation.ServiceTopology, value).
+				Warn("Ignoring invalid topology annotation of k8s service")
+		} else {
+			newSI.Topology = topology
+		}
+	}
 
 	for _, port := range svc.Spec.Ports {
 		p, err := types.NewFEPort(types.L4Type(port.Protocol), uint16(port.Port))
@@ -796,14 +866,73 @@ func (d *Daemon) addK8sServiceV1(svc *v1.Service) {
 		if _, ok := newSI.Ports[types.FEPortName(port.Name)]; !ok {
 			newSI.Ports[types.FEPortName(port.Name)] = p
 		}
+
+		// Kubernetes allocates a node port to NodePort and LoadBalancer
+		// services.
+		if port.NodePort == 0 {
+			continue
+		}
+		np, err := types.NewFEPort(types.L4Type(port.Protocol), uint16(port.NodePort))
+		if err != nil {
+			scopedLog.WithError(err).WithField("port", port).Error("Unable to add service node port")
+			continue
+		}
+		if _, ok := newSI.NodePorts[types.FEPortName(port.Name)]; !ok {
+			newSI.NodePorts[types.FEPortName(port.Name)] = np
+		}
+	}
+
+	for _, externalIP := range svc.Spec.ExternalIPs {
+		ip := net.ParseIP(externalIP)
+		if ip == nil {
+			scopedLog.WithField(logfields.IPAddr, externalIP).Error("Unable to add service external IP: invalid IP address")
+			continue
+		}
+		newSI.ExternalIPs = append(newSI.ExternalIPs, ip)
 	}
 
 	d.loadBalancer.K8sMU.Lock()
 	defer d.loadBalancer.K8sMU.Unlock()
 
+	// Frontends on node ports or external IPs which have been removed from
+	// the service would otherwise be left behind.
+	if oldSI, ok := d.loadBalancer.K8sServices[svcns]; ok {
+		if oldSI.IsExternalName() {
+			d.k8sExternalNames.Delete(svcns)
+		} else {
+			d.delStaleK8sFrontends(svcns, oldSI, newSI)
+		}
+	}
+
 	d.loadBalancer.K8sServices[svcns] = newSI
 
 	d.syncLB(&svcns, nil, nil)
+	d.syncK8sToServicesLocked(svcns)
+}
+
+// addK8sExternalNameV1 adds or updates an ExternalName service. If the
+// service was load balanced before its type changed, its frontends are
+// removed.
+func (d *Daemon) addK8sExternalNameV1(svc *v1.Service) {
+	svcns := types.K8sServiceNamespace{
+		ServiceName: svc.ObjectMeta.Name,
+		Namespace:   svc.ObjectMeta.Namespace,
+	}
+
+	newSI := types.NewK8sServiceInfo(nil, false, svc.Labels)
+	newSI.ExternalName = svc.Spec.ExternalName
+
+	d.loadBalancer.K8sMU.Lock()
+	defer d.loadBalancer.K8sMU.Unlock()
+
+	if oldSI, ok := d.loadBalancer.K8sServices[svcns]; ok && !oldSI.IsExternalName() {
+		d.syncLB(nil, nil, &svcns)
+	}
+
+	d.loadBalancer.K8sServices[svcns] = newSI
+	d.k8sExternalNames.Upsert(svcns, newSI.ExternalName)
+
+	d.syncK8sToServicesLocked(svcns)
 }
 
 func (d *Daemon) updateK8sServiceV1(oldSvc, newSvc *v1.Service) {
@@ -834,7 +963,15 @@ func (d *Daemon) deleteK8sServiceV1(svc *v1.Service) {
 
 	d.loadBalancer.K8sMU.Lock()
 	defer d.loadBalancer.K8sMU.Unlock()
-	d.syncLB(nil, nil, svcns)
+
+	if svcInfo, ok := d.loadBalancer.K8sServices[*svcns]; ok && svcInfo.IsExternalName() {
+		d.k8sExternalNames.Delete(*svcns)
+		delete(d.loadBalancer.K8sServices, *svcns)
+	} else {
+		d.syncLB(nil, nil, svcns)
+	}
+
+	d.syncK8sToServicesLocked(*svcns)
 }
 
 func (d *Daemon) addK8sEndpointV1(ep *v1.Endpoints) {
@@ -854,6 +991,9 @@ func (d *Daemon) addK8sEndpointV1(ep *v1.Endpoints) {
 	for _, sub := range ep.Subsets {
 		for _, addr := range sub.Addresses {
 			newSvcEP.BEIPs[addr.IP] = true
+			if addr.NodeName != nil {
+				newSvcEP.NodeNames[addr.IP] = *addr.NodeName
+			}
 		}
 		for _, port := range sub.Ports {
 			lbPort, err := types.NewL4Addr(types.L4Type(port.Protocol), uint16(port.Port))
@@ -879,16 +1019,7 @@ func (d *Daemon) addK8sEndpointV1(ep *v1.Endpoints) {
 		}
 	}
 
-	svc, ok := d.loadBalancer.K8sServices[svcns]
-	if ok && svc.IsHeadless {
-		translator := k8s.NewK8sTranslator(svcns, *newSvcEP, false, svc.Labels)
-		err := d.policy.TranslateRules(translator)
-		if err != nil {
-			log.Errorf("Unable to repopulate egress policies from ToService rules: %v", err)
-		} else {
-			d.TriggerPolicyUpdates(true)
-		}
-	}
+	d.syncK8sToServicesLocked(svcns)
 }
 
 func (d *Daemon) updateK8sEndpointV1(oldEP, newEP *v1.Endpoints) {
@@ -920,20 +1051,8 @@ func (d *Daemon) deleteK8sEndpointV1(ep *v1.Endpoints) {
 	d.loadBalancer.K8sMU.Lock()
 	defer d.loadBalancer.K8sMU.Unlock()
 
-	if endpoint, ok := d.loadBalancer.K8sEndpoints[svcns]; ok {
-		svc, ok := d.loadBalancer.K8sServices[svcns]
-		if ok && svc.IsHeadless {
-			translator := k8s.NewK8sTranslator(svcns, *endpoint, true, svc.Labels)
-			err := d.policy.TranslateRules(translator)
-			if err != nil {
-				log.Errorf("Unable to depopulate egress policies from ToService rules: %v", err)
-			} else {
-				d.TriggerPolicyUpdates(true)
-			}
-		}
-	}
-
 	d.syncLB(nil, nil, &svcns)
+	d.syncK8sToServicesLocked(svcns)
 	if d.conf.IsLBEnabled() {
 		if err := d.syncExternalLB(nil, nil, &svcns); err != nil {
 			scopedLog.WithError(err).Error("Unable to remove endpoints on ingress service")
@@ -942,6 +1061,77 @@ func (d *Daemon) deleteK8sEndpointV1(ep *v1.Endpoints) {
 	}
 }
 
+// syncK8sToServicesLocked updates the ToCIDR rules generated from ToServices
+// rules selecting the given service to the current state of the service.
+// The CIDRs of headless services are generated from their endpoints, the
+// CIDRs of ExternalName services from the addresses their DNS name resolves
+// to. The CIDRs generated for the previous state are removed first so that
+// addresses removed from the service as well as changes of the service type
+// are cleaned up. Must be called with d.loadBalancer.K8sMU held.
+func (d *Daemon) syncK8sToServicesLocked(svcns types.K8sServiceNamespace) {
+	var translator *k8s.RuleTranslator
+	if svc, ok := d.loadBalancer.K8sServices[svcns]; ok {
+		var endpoint *types.K8sServiceEndpoint
+		switch {
+		case svc.IsExternalName():
+			endpoint = d.k8sExternalNames.Endpoint(svcns)
+		case svc.IsHeadless:
+			endpoint = d.loadBalancer.K8sEndpoints[svcns]
+		}
+		if endpoint != nil {
+			t := k8s.NewK8sTranslator(svcns, *endpoint, false, svc.Labels)
+			translator = &t
+		}
+	}
+
+	old, ok := d.k8sToServices[svcns]
+	if !ok && translator == nil {
+		return
+	}
+	if ok && translator != nil && reflect.DeepEqual(old, *translator) {
+		return
+	}
+
+	if ok {
+		old.Revert = true
+		if err := d.policy.TranslateRules(old); err != nil {
+			log.Errorf("Unable to depopulate egress policies from ToService rules: %v", err)
+		}
+		delete(d.k8sToServices, svcns)
+	}
+
+	if translator != nil {
+		if err := d.policy.TranslateRules(*translator); err != nil {
+			log.Errorf("Unable to repopulate egress policies from ToService rules: %v", err)
+		} else {
+			d.k8sToServices[svcns] = *translator
+		}
+	}
+
+	d.TriggerPolicyUpdates(true)
+}
+
+// k8sToServicesEndpointsLocked returns the endpoints of all services for
+// which ToServices rules are currently translated. Must be called with
+// d.loadBalancer.K8sMU held.
+func (d *Daemon) k8sToServicesEndpointsLocked() map[types.K8sServiceNamespace]*types.K8sServiceEndpoint {
+	endpoints := make(map[types.K8sServiceNamespace]*types.K8sServiceEndpoint, len(d.k8sToServices))
+	for svcns, translator := range d.k8sToServices {
+		endpoint := translator.Endpoint
+		endpoints[svcns] = &endpoint
+	}
+	return endpoints
+}
+
+// k8sExternalNameChanged is called when the addresses of an ExternalName
+// service have changed
+func (d *Daemon) k8sExternalNameChanged(svcns types.K8sServiceNamespace) {
+	d.loadBalancer.K8sMU.Lock()
+	defer d.loadBalancer.K8sMU.Unlock()
+
+	d.syncK8sToServicesLocked(svcns)
+}
+
 func areIPsConsistent(ipv4Enabled, isSvcIPv4 bool, svc types.K8sServiceNamespace, se *types.K8sServiceEndpoint) error {
 	if isSvcIPv4 {
 		if !ipv4Enabled {
@@ -1027,9 +1217,185 @@ func (d *Daemon) delK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sSer
 			scopedLog.Debugf("# cilium lb delete-rev-nat %d", svcPort.ID)
 		}
 	}
+
+	for _, fe := range getK8sExtraFrontends(svcInfo) {
+		d.delK8sFrontend(scopedLog, fe)
+	}
 	return nil
 }
 
+// k8sFrontend is a frontend of a k8s service other than its cluster IP.
+type k8sFrontend struct {
+	svcType  types.LBSVCType
+	ip       net.IP
+	portName types.FEPortName
+	port     *types.FEPort
+}
+
+// getK8sExtraFrontends returns the NodePort and ExternalIPs frontends of the
+// given service which are enabled. Only the frontends with an address of the
+// same family as the cluster IP are returned as the backends are of that
+// family as well.
+//
+// Like cluster IPs, these frontends are only translated by the datapath of
+// the local endpoints, i.e. for traffic originating from a pod on this node.
+// Traffic arriving on the node from outside is not load balanced by cilium
+// and still relies on kube-proxy.
+func getK8sExtraFrontends(svcInfo *types.K8sServiceInfo) []k8sFrontend {
+	frontends := []k8sFrontend{}
+	if svcInfo.IsHeadless {
+		return frontends
+	}
+
+	isSvcIPv4 := svcInfo.FEIP.To4() != nil
+	appendFrontends := func(svcType types.LBSVCType, ip net.IP, ports map[types.FEPortName]*types.FEPort) {
+		if ip == nil || (ip.To4() != nil) != isSvcIPv4 {
+			return
+		}
+		uniqPorts := getUniqPorts(ports)
+		for portName, port := range ports {
+			if !uniqPorts[port.Port] {
+				continue
+			}
+			uniqPorts[port.Port] = false
+			frontends = append(frontends, k8sFrontend{
+				svcType:  svcType,
+				ip:       ip,
+				portName: portName,
+				port:     port,
+			})
+		}
+	}
+
+	if !viper.GetBool("disable-k8s-node-port") && len(svcInfo.NodePorts) > 0 {
+		_, localNode := node.GetLocalNode()
+		for _, addr := range localNode.IPAddresses {
+			appendFrontends(types.LBSVCTypeNodePort, addr.IP, svcInfo.NodePorts)
+		}
+	}
+
+	if !viper.GetBool("disable-k8s-external-ips") {
+		for _, ip := range svcInfo.ExternalIPs {
+			appendFrontends(types.LBSVCTypeExternalIPs, ip, svcInfo.Ports)
+		}
+	}
+
+	return frontends
+}
+
+// delStaleK8sFrontends deletes the NodePort and ExternalIPs frontends of
+// oldSvcInfo which are no longer present in newSvcInfo.
+func (d *Daemon) delStaleK8sFrontends(svc types.K8sServiceNamespace, oldSvcInfo, newSvcInfo *types.K8sServiceInfo) {
+	if viper.GetBool("disable-k8s-services") {
+		return
+	}
+
+	scopedLog := log.WithFields(logrus.Fields{
+		logfields.K8sSvcName:   svc.ServiceName,
+		logfields.K8sNamespace: svc.Namespace,
+	})
+
+	current := map[string]bool{}
+	for _, fe := range getK8sExtraFrontends(newSvcInfo) {
+		current[net.JoinHostPort(fe.ip.String(), strconv.Itoa(int(fe.port.Port)))] = true
+	}
+
+	for _, fe := range getK8sExtraFrontends(oldSvcInfo) {
+		if !current[net.JoinHostPort(fe.ip.String(), strconv.Itoa(int(fe.port.Port)))] {
+			d.delK8sFrontend(scopedLog, fe)
+		}
+	}
+}
+
+// delK8sFrontend deletes the given frontend of a k8s service together with
+// its reverse NAT entry and releases its service ID.
+func (d *Daemon) delK8sFrontend(scopedLog *logrus.Entry, fe k8sFrontend) {
+	feAddr, err := types.NewL3n4Addr(fe.port.Protocol, fe.ip, fe.port.Port)
+	if err != nil {
+		scopedLog.WithError(err).Error("Error while creating a New L3n4Addr. Ignoring frontend")
+		return
+	}
+
+	svc := d.svcGetBySHA256Sum(feAddr.SHA256Sum())
+	if svc == nil {
+		return
+	}
+	id := svc.FE.ID
+
+	if err := DeleteL3n4AddrIDByUUID(uint32(id)); err != nil {
+		scopedLog.WithError(err).Warn("Error while cleaning service ID")
+	}
+
+	if err := d.svcDeleteByFrontend(feAddr); err != nil {
+		scopedLog.WithError(err).WithField(logfields.Object, logfields.Repr(feAddr)).
+			Warn("Error deleting service by frontend")
+	} else {
+		scopedLog.Debugf("# cilium lb delete-service %s %d 0", fe.ip, fe.port.Port)
+	}
+
+	if err := d.RevNATDelete(id); err != nil {
+		scopedLog.WithError(err).WithField(logfields.ServiceID, id).Warn("Error deleting reverse NAT")
+	} else {
+		scopedLog.Debugf("# cilium lb delete-rev-nat %d", id)
+	}
+}
+
+// addK8sFrontend adds the given frontend of a k8s service with the backends
+// of the matching endpoint port. The service ID of the frontend is taken from
+// the loadbalancer if the frontend already exists.
+func (d *Daemon) addK8sFrontend(scopedLog *logrus.Entry, fe k8sFrontend, se *types.K8sServiceEndpoint, opts types.LBSVCOptions) {
+	scopedLog = scopedLog.WithFields(logrus.Fields{
+		logfields.IPAddr:   fe.ip,
+		logfields.Port:     fe.port.Port,
+		logfields.Protocol: fe.port.Protocol,
+	})
+
+	feAddr, err := types.NewL3n4Addr(fe.port.Protocol, fe.ip, fe.port.Port)
+	if err != nil {
+		scopedLog.WithError(err).Error("Error while creating a new L3n4Addr. Ignoring frontend...")
+		return
+	}
+
+	var id types.ServiceID
+	if svc := d.svcGetBySHA256Sum(feAddr.SHA256Sum()); svc != nil {
+		id = svc.FE.ID
+	} else {
+		feAddrID, err := PutL3n4Addr(*feAddr, 0)
+		if err != nil {
+			scopedLog.WithError(err).Error("Error while getting a new service ID. Ignoring frontend...")
+			return
+		}
+		id = feAddrID.ID
+	}
+
+	opts.Type = fe.svcType
+	feAddrID := types.L3n4AddrID{L3n4Addr: *feAddr, ID: id}
+	if _, err := d.svcAdd(feAddrID, getK8sBackends(se, fe.portName), true, opts); err != nil {
+		scopedLog.WithError(err).Error("Error while inserting service in LB map")
+	}
+}
+
+// getK8sBackends returns the backends of the given endpoint for the port with
+// the given name.
+func getK8sBackends(se *types.K8sServiceEndpoint, portName types.FEPortName) []types.LBBackEnd {
+	besValues := []types.LBBackEnd{}
+
+	k8sBEPort := se.Ports[portName]
+	if k8sBEPort == nil {
+		return besValues
+	}
+
+	for epIP := range se.BEIPs {
+		bePort := types.LBBackEnd{
+			L3n4Addr: types.L3n4Addr{IP: net.ParseIP(epIP), L4Addr: *k8sBEPort},
+			Weight:   0,
+			NodeName: se.NodeNames[epIP],
+		}
+		besValues = append(besValues, bePort)
+	}
+	return besValues
+}
+
 func (d *Daemon) addK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sServiceInfo, se *types.K8sServiceEndpoint) error {
 	// If east-west load balancing is disabled, we should not sync(add or delete)
 	// K8s service to a cilium service.
@@ -1050,6 +1416,17 @@ func (d *Daemon) addK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sSer
 		}
 	}
 
+	opts := types.LBSVCOptions{
+		Name: types.LBSVCName{
+			Namespace: svc.Namespace,
+			Name:      svc.ServiceName,
+		},
+		Type:                   types.LBSVCTypeClusterIP,
+		Algorithm:              types.LBAlgorithmHash,
+		SessionAffinityTimeout: svcInfo.SessionAffinityTimeout,
+		Topology:               svcInfo.Topology,
+	}
+
 	uniqPorts := getUniqPorts(svcInfo.Ports)
 
 	for fePortName, fePort := range svcInfo.Ports {
@@ -1057,7 +1434,6 @@ func (d *Daemon) addK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sSer
 			continue
 		}
 
-		k8sBEPort := se.Ports[fePortName]
 		uniqPorts[fePort.Port] = false
 
 		if fePort.ID == 0 {
@@ -1089,17 +1465,7 @@ func (d *Daemon) addK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sSer
 			fePort.ID = feAddrID.ID
 		}
 
-		besValues := []types.LBBackEnd{}
-
-		if k8sBEPort != nil {
-			for epIP := range se.BEIPs {
-				bePort := types.LBBackEnd{
-					L3n4Addr: types.L3n4Addr{IP: net.ParseIP(epIP), L4Addr: *k8sBEPort},
-					Weight:   0,
-				}
-				besValues = append(besValues, bePort)
-			}
-		}
+		besValues := getK8sBackends(se, fePortName)
 
 		fe, err := types.NewL3n4AddrID(fePort.Protocol, svcInfo.FEIP, fePort.Port, fePort.ID)
 		if err != nil {
@@ -1109,17 +1475,23 @@ func (d *Daemon) addK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sSer
 			}).Error("Error while creating a New L3n4AddrID. Ignoring service...")
 			continue
 		}
-		if _, err := d.svcAdd(*fe, besValues, true); err != nil {
+		if _, err := d.svcAdd(*fe, besValues, true, opts); err != nil {
 			scopedLog.WithError(err).Error("Error while inserting service in LB map")
 		}
 	}
+
+	for _, fe := range getK8sExtraFrontends(svcInfo) {
+		d.addK8sFrontend(scopedLog, fe, se, opts)
+	}
 	return nil
 }
 
 func (d *Daemon) syncLB(newSN, modSN, delSN *types.K8sServiceNamespace) {
 	deleteSN := func(delSN types.K8sServiceNamespace) {
 		svc, ok := d.loadBalancer.K8sServices[delSN]
-		if !ok {
+		if !ok || svc.IsExternalName() {
+			// ExternalName services are not load balanced, only
+			// their stale endpoints are removed
 			delete(d.loadBalancer.K8sEndpoints, delSN)
 			return
 		}
@@ -1144,7 +1516,7 @@ func (d *Daemon) syncLB(newSN, modSN, delSN *types.K8sServiceNamespace) {
 
 	addSN := func(addSN types.K8sServiceNamespace) {
 		svcInfo, ok := d.loadBalancer.K8sServices[addSN]
-		if !ok {
+		if !ok || svcInfo.IsExternalName() {
 			return
 		}
 
@@ -1439,7 +1811,7 @@ func (d *Daemon) addCiliumNetworkPolicyV1(ciliumV1Store cache.Store, cnp *cilium
 	rules, err := cnp.Parse()
 	if err == nil && len(rules) > 0 {
 		d.loadBalancer.K8sMU.Lock()
-		err = k8s.PreprocessRules(rules, d.loadBalancer.K8sEndpoints, d.loadBalancer.K8sServices)
+		err = k8s.PreprocessRules(rules, d.k8sToServicesEndpointsLocked(), d.loadBalancer.K8sServices)
 		d.loadBalancer.K8sMU.Unlock()
 		if err == nil {
 			_, err = d.PolicyAdd(rules, &AddOptions{Replace: true})
@@ -1543,7 +1915,7 @@ func (d *Daemon) addCiliumNetworkPolicyV2(ciliumV2Store cache.Store, cnp *cilium
 	rules, err := cnp.Parse()
 	if err == nil && len(rules) > 0 {
 		d.loadBalancer.K8sMU.Lock()
-		err = k8s.PreprocessRules(rules, d.loadBalancer.K8sEndpoints, d.loadBalancer.K8sServices)
+		err = k8s.PreprocessRules(rules, d.k8sToServicesEndpointsLocked(), d.loadBalancer.K8sServices)
 		d.loadBalancer.K8sMU.Unlock()
 		if err == nil {
 			_, err = d.PolicyAdd(rules, &AddOptions{Replace: true})
@@ -1651,6 +2023,10 @@ func (d *Daemon) addK8sNodeV1(k8sNode *v1.Node) {
 
 	node.UpdateNode(ni, n, routeTypes, ownAddr)
 
+	if n.Zone != "" {
+		d.updateTopologyServices()
+	}
+
 	log.WithFields(logrus.Fields{
 		logfields.K8sNodeID:     ni,
 		logfields.K8sAPIVersion: k8sNode.TypeMeta.APIVersion,
@@ -1683,6 +2059,10 @@ func (d *Daemon) updateK8sNodeV1(_, k8sNode *v1.Node) {
 
 	node.UpdateNode(ni, newNode, routeTypes, ownAddr)
 
+	if oldNode == nil || oldNode.Zone != newNode.Zone {
+		d.updateTopologyServices()
+	}
+
 	log.WithFields(logrus.Fields{
 		logfields.K8sNodeID:     ni,
 		logfields.K8sAPIVersion: k8sNode.TypeMeta.APIVersion,
@@ -15,8 +15,12 @@
 package main
 
 import (
+	"net"
 	"time"
 
+	"github.com/cilium/cilium/common/types"
+
+	"github.com/spf13/viper"
 	. "gopkg.in/check.v1"
 )
 
@@ -40,3 +44,30 @@ func (ds *DaemonSuite) TestK8sErrorLogTimeout(c *C) {
 	shouldLogTime := startTime.Add(k8sErrLogTimeout).Add(time.Nanosecond)
 	c.Assert(k8sErrorUpdateCheckUnmuteTime(errstr, shouldLogTime), Equals, true)
 }
+
+func (ds *DaemonSuite) TestGetK8sExtraFrontends(c *C) {
+	viper.Set("disable-k8s-node-port", true)
+	defer viper.Set("disable-k8s-node-port", false)
+
+	svcInfo := types.NewK8sServiceInfo(net.ParseIP("10.0.0.1"), false, nil)
+	svcInfo.Ports["http"], _ = types.NewFEPort(types.TCP, 80)
+	svcInfo.Ports["http-udp"], _ = types.NewFEPort(types.UDP, 80)
+	svcInfo.NodePorts["http"], _ = types.NewFEPort(types.TCP, 30080)
+	svcInfo.ExternalIPs = []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("f00d::1")}
+
+	// Only the external IP of the same family as the cluster IP is used
+	// and the same port is only added once.
+	frontends := getK8sExtraFrontends(svcInfo)
+	c.Assert(len(frontends), Equals, 1)
+	c.Assert(frontends[0].svcType, Equals, types.LBSVCTypeExternalIPs)
+	c.Assert(frontends[0].ip.Equal(net.ParseIP("192.0.2.1")), Equals, true)
+	c.Assert(frontends[0].port.Port, Equals, uint16(80))
+
+	viper.Set("disable-k8s-external-ips", true)
+	defer viper.Set("disable-k8s-external-ips", false)
+	c.Assert(len(getK8sExtraFrontends(svcInfo)), Equals, 0)
+
+	viper.Set("disable-k8s-external-ips", false)
+	svcInfo.IsHeadless = true
+	c.Assert(len(getK8sExtraFrontends(svcInfo)), Equals, 0)
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	restapi "github.com/cilium/cilium/api/v1/server/restapi/daemon"
+	"github.com/cilium/cilium/pkg/apierror"
+	"github.com/cilium/cilium/pkg/kvstore"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+func lockInfoModel(info *kvstore.LockInfo) *models.KVStoreLock {
+	return &models.KVStoreLock{
+		Path:       info.Path,
+		Key:        info.Key,
+		Node:       info.Node,
+		Pid:        int64(info.PID),
+		Operation:  info.Operation,
+		AcquiredAt: info.AcquiredAt.Format(time.RFC3339Nano),
+	}
+}
+
+type getKvstoreLocks struct {
+	daemon *Daemon
+}
+
+// NewGetKvstoreLocksHandler returns the handler listing all locks held in
+// the kvstore
+func NewGetKvstoreLocksHandler(d *Daemon) restapi.GetKvstoreLocksHandler {
+	return &getKvstoreLocks{daemon: d}
+}
+
+func (h *getKvstoreLocks) Handle(params restapi.GetKvstoreLocksParams) middleware.Responder {
+	locks, err := kvstore.ListLocks()
+	if err != nil {
+		return apierror.Error(restapi.GetKvstoreLocksFailureCode, err)
+	}
+
+	payload := make([]*models.KVStoreLock, 0, len(locks))
+	for _, info := range locks {
+		payload = append(payload, lockInfoModel(info))
+	}
+
+	return restapi.NewGetKvstoreLocksOK().WithPayload(payload)
+}
+
+type deleteKvstoreLocks struct {
+	daemon *Daemon
+}
+
+// NewDeleteKvstoreLocksHandler returns the handler forcefully releasing a
+// lock held in the kvstore
+func NewDeleteKvstoreLocksHandler(d *Daemon) restapi.DeleteKvstoreLocksHandler {
+	return &deleteKvstoreLocks{daemon: d}
+}
+
+func (h *deleteKvstoreLocks) Handle(params restapi.DeleteKvstoreLocksParams) middleware.Responder {
+	info, err := kvstore.ForceReleaseLock(params.Path)
+	switch {
+	case err == kvstore.ErrLockNotHeld:
+		return restapi.NewDeleteKvstoreLocksNotFound()
+	case err != nil && info == nil:
+		return apierror.Error(restapi.DeleteKvstoreLocksFailureCode, err)
+	case err != nil:
+		// The lock has been released but the audit entry could not be
+		// written, the release itself succeeded
+		log.WithError(err).WithField("path", params.Path).Warning("Lock released without audit entry")
+	}
+
+	return restapi.NewDeleteKvstoreLocksOK().WithPayload(lockInfoModel(info))
+}
@@ -0,0 +1,200 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/lbmap"
+	"github.com/cilium/cilium/pkg/metrics"
+)
+
+// lbStatsGCInterval is the interval at which the statistics of removed
+// services and backends are deleted.
+const lbStatsGCInterval = time.Minute
+
+// lbStats holds the load-balancing statistics maintained by the datapath,
+// indexed by service ID and backend address.
+type lbStats map[types.ServiceID]map[string]lbmap.StatsValue
+
+// get returns the statistics of backend be of the service frontend fe.
+func (s lbStats) get(fe *types.L3n4AddrID, be *types.LBBackEnd) (lbmap.StatsValue, bool) {
+	v, ok := s[fe.ID][lbStatsBackend(fe, be)]
+	return v, ok
+}
+
+// getBackendStatistics returns the API model of the statistics of backend be
+// of the service frontend fe, or nil if none are known.
+func (s lbStats) getBackendStatistics(fe *types.L3n4AddrID, be *types.LBBackEnd) *models.BackendStatistics {
+	v, ok := s.get(fe, be)
+	if !ok {
+		return nil
+	}
+	return &models.BackendStatistics{
+		Packets:     int64(v.Packets),
+		Bytes:       int64(v.Bytes),
+		Connections: int64(v.Connections),
+	}
+}
+
+// lbStatsBackend returns the backend address of be as accounted by the
+// datapath. Backends without a port receive the port of the frontend.
+func lbStatsBackend(fe *types.L3n4AddrID, be *types.LBBackEnd) string {
+	addr := types.L3n4Addr{IP: be.IP, L4Addr: types.L4Addr{Port: be.Port}}
+	if addr.Port == 0 {
+		addr.Port = fe.Port
+	}
+	return addr.String()
+}
+
+// lbStatsMaps returns the load-balancing statistics maps in use.
+func (d *Daemon) lbStatsMaps() []*bpf.Map {
+	maps := []*bpf.Map{lbmap.Stats6Map}
+	if !d.conf.IPv4Disabled {
+		maps = append(maps, lbmap.Stats4Map)
+	}
+	return maps
+}
+
+// dumpLBStatsEntries returns all entries of the load-balancing statistics
+// maps.
+func (d *Daemon) dumpLBStatsEntries() ([]lbmap.StatsEntry, error) {
+	entries := []lbmap.StatsEntry{}
+	for _, m := range d.lbStatsMaps() {
+		mapEntries, err := lbmap.DumpStats(m)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, mapEntries...)
+	}
+	return entries, nil
+}
+
+// dumpLBStats returns the load-balancing statistics of all backends. Returns
+// nil if the statistics are not available.
+func (d *Daemon) dumpLBStats() lbStats {
+	if d.DryModeEnabled() || !d.conf.EnableLBStats {
+		return nil
+	}
+
+	entries, err := d.dumpLBStatsEntries()
+	if err != nil {
+		log.WithError(err).Debug("Unable to dump load-balancing statistics")
+		return nil
+	}
+
+	stats := lbStats{}
+	for _, entry := range entries {
+		id := types.ServiceID(entry.Key.GetRevNat())
+		if stats[id] == nil {
+			stats[id] = map[string]lbmap.StatsValue{}
+		}
+		be := entry.Key.GetBackend()
+		stats[id][be.String()] = entry.Value
+	}
+	return stats
+}
+
+// gcLBStats deletes the statistics of backends which are no longer part of
+// their service, including the backends of removed services.
+func (d *Daemon) gcLBStats() error {
+	entries, err := d.dumpLBStatsEntries()
+	if err != nil {
+		return err
+	}
+
+	d.loadBalancer.BPFMapMU.RLock()
+	live := map[types.ServiceID]map[string]struct{}{}
+	for id, svc := range d.loadBalancer.SVCMapID {
+		live[id] = make(map[string]struct{}, len(svc.BES))
+		for i := range svc.BES {
+			live[id][lbStatsBackend(&svc.FE, &svc.BES[i])] = struct{}{}
+		}
+	}
+	d.loadBalancer.BPFMapMU.RUnlock()
+
+	for _, entry := range entries {
+		be := entry.Key.GetBackend()
+		if _, ok := live[types.ServiceID(entry.Key.GetRevNat())][be.String()]; ok {
+			continue
+		}
+		if err := lbmap.DeleteStats(entry.Key); err != nil {
+			log.WithError(err).WithField(logfields.BPFMapKey, entry.Key).
+				Warn("Unable to delete load-balancing statistics entry")
+		}
+	}
+
+	return nil
+}
+
+// lbBackendStats returns the load-balancing statistics of all backends for
+// the Prometheus metrics.
+func (d *Daemon) lbBackendStats() []metrics.LBBackendStats {
+	stats := d.dumpLBStats()
+	if stats == nil {
+		return nil
+	}
+
+	d.loadBalancer.BPFMapMU.RLock()
+	defer d.loadBalancer.BPFMapMU.RUnlock()
+
+	result := []metrics.LBBackendStats{}
+	for _, svc := range d.loadBalancer.SVCMapID {
+		frontend := svc.FE.L3n4Addr.String()
+		name := frontend
+		if !svc.Name.IsEmpty() {
+			name = svc.Name.String()
+		}
+		seen := map[string]bool{}
+		for i := range svc.BES {
+			backend := lbStatsBackend(&svc.FE, &svc.BES[i])
+			v, ok := stats.get(&svc.FE, &svc.BES[i])
+			if !ok || seen[backend] {
+				continue
+			}
+			seen[backend] = true
+			result = append(result, metrics.LBBackendStats{
+				Service:     name,
+				Frontend:    frontend,
+				Backend:     backend,
+				Packets:     v.Packets,
+				Bytes:       v.Bytes,
+				Connections: v.Connections,
+			})
+		}
+	}
+	return result
+}
+
+// startLBStats starts exporting the load-balancing statistics and the
+// removal of the statistics of removed backends. It must be called once the
+// services of the previous run have been restored.
+func (d *Daemon) startLBStats() {
+	if d.DryModeEnabled() || !d.conf.EnableLBStats {
+		return
+	}
+
+	metrics.MustRegister(metrics.NewLBStatsCollector(d.lbBackendStats))
+	d.controllers.UpdateController("lb-stats-gc",
+		controller.ControllerParams{
+			DoFunc:      d.gcLBStats,
+			RunInterval: lbStatsGCInterval,
+		})
+}
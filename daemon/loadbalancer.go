@@ -16,22 +16,36 @@ package main
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
 	. "github.com/cilium/cilium/api/v1/server/restapi/service"
 	"github.com/cilium/cilium/common/types"
 	"github.com/cilium/cilium/pkg/apierror"
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/maps/lbmap"
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/node"
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// defaultLBDrainTimeout is the default time a backend removed from a
+	// service keeps serving its established connections.
+	defaultLBDrainTimeout = time.Minute
+
+	// lbDrainInterval is the interval at which draining backends are
+	// removed from their service once their drain timeout passed.
+	lbDrainInterval = 10 * time.Second
+)
+
 // addSVC2BPFMap adds the given bpf service to the bpf maps. If addRevNAT is set, adds the
 // RevNAT value (feCilium.L3n4Addr) to the lb's RevNAT map for the given feCilium.ID.
 func (d *Daemon) addSVC2BPFMap(feCilium types.L3n4AddrID, feBPF lbmap.ServiceKey,
-	besBPF []lbmap.ServiceValue, addRevNAT bool) error {
+	besBPF []lbmap.ServiceValue, addRevNAT bool, opts types.LBSVCOptions) error {
 	log.WithField(logfields.ServiceName, feCilium.String()).Debug("adding service to BPF maps")
 
 	// Try to delete service before adding it and ignore errors as it might not exist.
@@ -40,7 +54,7 @@ func (d *Daemon) addSVC2BPFMap(feCilium types.L3n4AddrID, feBPF lbmap.ServiceKey
 		log.WithError(err).WithField(logfields.ServiceName, feCilium.L3n4Addr.String()).Debug("error deleting service before adding it")
 	}
 
-	err = lbmap.AddSVC2BPFMap(feBPF, besBPF, addRevNAT, int(feCilium.ID))
+	err = lbmap.AddSVC2BPFMap(feBPF, besBPF, addRevNAT, int(feCilium.ID), opts)
 	if err != nil {
 		if addRevNAT {
 			delete(d.loadBalancer.RevNATMap, feCilium.ID)
@@ -60,7 +74,8 @@ func (d *Daemon) addSVC2BPFMap(feCilium types.L3n4AddrID, feBPF lbmap.ServiceKey
 // returned to the caller.
 //
 // Returns true if service was created.
-func (d *Daemon) SVCAdd(feL3n4Addr types.L3n4AddrID, be []types.LBBackEnd, addRevNAT bool) (bool, error) {
+func (d *Daemon) SVCAdd(feL3n4Addr types.L3n4AddrID, be []types.LBBackEnd, addRevNAT bool,
+	opts types.LBSVCOptions) (bool, error) {
 	log.WithField(logfields.ServiceID, feL3n4Addr.String()).Debug("adding service")
 	if feL3n4Addr.ID == 0 {
 		return false, fmt.Errorf("invalid service ID 0")
@@ -88,17 +103,19 @@ func (d *Daemon) SVCAdd(feL3n4Addr types.L3n4AddrID, be []types.LBBackEnd, addRe
 		return false, fmt.Errorf("service ID %d is already registered to L3n4Addr %s, please choose a different ID", feL3n4Addr.ID, feAddr.String())
 	}
 
-	return d.svcAdd(feL3n4Addr, be, addRevNAT)
+	return d.svcAdd(feL3n4Addr, be, addRevNAT, opts)
 }
 
 // svcAdd adds a service from the given feL3n4Addr (frontend) and LBBackEnd (backends).
 // If addRevNAT is set, the RevNAT entry is also created for this particular service.
+// The opts select how the datapath picks a backend for new flows.
 // If any of the backend addresses set in bes have a different L3 address type than the
 // one set in fe, it returns an error without modifying the bpf LB map. If any backend
 // entry fails while updating the LB map, the frontend won't be inserted in the LB map
 // therefore there won't be any traffic going to the given backends.
 // All of the backends added will be DeepCopied to the internal load balancer map.
-func (d *Daemon) svcAdd(feL3n4Addr types.L3n4AddrID, bes []types.LBBackEnd, addRevNAT bool) (bool, error) {
+func (d *Daemon) svcAdd(feL3n4Addr types.L3n4AddrID, bes []types.LBBackEnd, addRevNAT bool,
+	opts types.LBSVCOptions) (bool, error) {
 	log.WithFields(logrus.Fields{
 		logfields.ServiceID: feL3n4Addr.String(),
 		logfields.Object:    logfields.Repr(bes),
@@ -112,68 +129,451 @@ func (d *Daemon) svcAdd(feL3n4Addr types.L3n4AddrID, bes []types.LBBackEnd, addR
 	}
 
 	svc := types.LBSVC{
-		FE:     feL3n4Addr,
-		BES:    beCpy,
-		Sha256: feL3n4Addr.L3n4Addr.SHA256Sum(),
+		FE:           feL3n4Addr,
+		BES:          beCpy,
+		Sha256:       feL3n4Addr.L3n4Addr.SHA256Sum(),
+		LBSVCOptions: opts,
+	}
+
+	d.loadBalancer.BPFMapMU.Lock()
+	defer d.loadBalancer.BPFMapMU.Unlock()
+
+	return d.svcAddLocked(svc, addRevNAT)
+}
+
+// svcAddLocked adds svc to the BPF maps and the internal load balancer map.
+// Backends removed from an existing service are kept as draining backends.
+// Must be called with BPFMapMU held.
+func (d *Daemon) svcAddLocked(svc types.LBSVC, addRevNAT bool) (bool, error) {
+	feL3n4Addr, opts := svc.FE, svc.LBSVCOptions
+	if opts.Algorithm == types.LBAlgorithmMaglev && !d.conf.EnableMaglev {
+		return false, fmt.Errorf("load-balancing algorithm %s requires --enable-lb-maglev", opts.Algorithm)
+	}
+
+	oldSvc, hadOldSvc := d.loadBalancer.SVCMap[svc.Sha256]
+	if hadOldSvc {
+		svc.BES = d.drainBackends(svc.Sha256, oldSvc.BES, svc.BES)
 	}
 
 	fe, besValues, err := lbmap.LBSVC2ServiceKeynValue(svc)
+	if err != nil {
+		return false, err
+	}
 
+	err = d.addSVC2BPFMap(feL3n4Addr, fe, besValues, addRevNAT, opts)
 	if err != nil {
 		return false, err
 	}
 
+	// Keep clients with session affinity on their backend if it still
+	// exists, even if its index has changed.
+	if hadOldSvc && oldSvc.FE.ID == svc.FE.ID && oldSvc.SessionAffinity() && svc.SessionAffinity() {
+		if err := lbmap.RemapAffinity(fe.AffinityMap(), uint16(svc.FE.ID), affinitySlaves(&oldSvc, &svc)); err != nil {
+			log.WithError(err).WithField(logfields.ServiceName, svc.FE.String()).
+				Warn("Unable to update session affinity entries of service")
+		}
+	}
+
+	created := d.loadBalancer.AddService(svc)
+	d.updateServiceMetadata(&svc)
+
+	// The service was re-added with all backends selectable, exclude the
+	// ones currently failing their health check, draining or, for topology
+	// aware services, not among the closest backends to the local node.
+	d.lbHealth.UpdateService(&svc)
+	if selectable := d.selectableBackends(&svc); hasUnhealthy(selectable) {
+		if err := lbmap.UpdateBackendHealth(fe, besValues, selectable, opts); err != nil {
+			log.WithError(err).WithField(logfields.ServiceName, svc.FE.String()).
+				Warn("Unable to exclude unhealthy backends of service")
+		}
+	}
+
+	return created, nil
+}
+
+func hasUnhealthy(healthy []bool) bool {
+	for _, h := range healthy {
+		if !h {
+			return true
+		}
+	}
+	return false
+}
+
+// selectableBackends returns whether each backend of svc may be selected for
+// new connections, i.e. it is healthy, not draining and, if svc is topology
+// aware, among the closest of such backends to the local node. The slaves of
+// the other backends are marked with lbmap.ServiceFlagExcluded by
+// lbmap.UpdateBackendHealth so that the datapath skips them regardless of the
+// backend selection algorithm. Returns nil if svc is not health checked, not
+// topology aware and has no draining backends.
+func (d *Daemon) selectableBackends(svc *types.LBSVC) []bool {
+	selectable := d.lbHealth.Healthy(svc.Sha256, svc.BES)
+	for i, be := range svc.BES {
+		if !be.Draining {
+			continue
+		}
+		if selectable == nil {
+			selectable = allSelectable(len(svc.BES))
+		}
+		selectable[i] = false
+	}
+
+	if svc.TopologyAware() {
+		if selectable == nil {
+			selectable = allSelectable(len(svc.BES))
+		}
+		localNode := node.GetName()
+		selectable = preferLocalBackends(svc.BES, selectable, svc.Topology,
+			localNode, nodeZone(localNode), nodeZone)
+	}
+	return selectable
+}
+
+func allSelectable(n int) []bool {
+	selectable := make([]bool, n)
+	for i := range selectable {
+		selectable[i] = true
+	}
+	return selectable
+}
+
+// nodeZone returns the zone of the node with the given name, or an empty
+// string if the node or its zone is unknown.
+func nodeZone(name string) string {
+	if name == "" {
+		return ""
+	}
+	if n := node.GetNode(node.Identity{Name: name}); n != nil {
+		return n.Zone
+	}
+	return ""
+}
+
+// preferLocalBackends narrows down the selectable backends of a service to
+// the ones closest to the local node according to topology: backends on
+// localNode first for LBTopologyNode, then backends in localZone. The zone of
+// a backend without an explicit zone is looked up by its node name with
+// zoneOf. If none of the preferred backends is selectable, selectable is
+// returned unchanged so that new connections fall back to remote backends.
+func preferLocalBackends(bes []types.LBBackEnd, selectable []bool, topology types.LBTopology,
+	localNode, localZone string, zoneOf func(nodeName string) string) []bool {
+
+	tiers := []func(be *types.LBBackEnd) bool{}
+	if topology == types.LBTopologyNode && localNode != "" {
+		tiers = append(tiers, func(be *types.LBBackEnd) bool {
+			return be.NodeName == localNode
+		})
+	}
+	if (topology == types.LBTopologyNode || topology == types.LBTopologyZone) && localZone != "" {
+		tiers = append(tiers, func(be *types.LBBackEnd) bool {
+			zone := be.Zone
+			if zone == "" {
+				zone = zoneOf(be.NodeName)
+			}
+			return zone == localZone
+		})
+	}
+
+	for _, local := range tiers {
+		preferred := make([]bool, len(bes))
+		found := false
+		for i := range bes {
+			if selectable[i] && local(&bes[i]) {
+				preferred[i] = true
+				found = true
+			}
+		}
+		if found {
+			return preferred
+		}
+	}
+	return selectable
+}
+
+// drainBackends returns the backends of the service with the given SHA256 sum
+// of its frontend when updating its backends from oldBES to bes. Backends
+// removed from the service are kept in their slot as draining backends until
+// the drain timeout passes, so that their established connections keep
+// working while new connections go to the remaining backends. Must be called
+// with BPFMapMU held.
+func (d *Daemon) drainBackends(sha256 string, oldBES, bes []types.LBBackEnd) []types.LBBackEnd {
+	now := time.Now()
+	oldDeadlines := d.lbDrain[sha256]
+	delete(d.lbDrain, sha256)
+
+	// Without any backend left, new connections could not be moved
+	// away from draining backends.
+	if lbDrainTimeout == 0 || len(bes) == 0 {
+		return bes
+	}
+
+	active := make(map[string]int, len(bes))
+	for i, be := range bes {
+		active[be.L3n4Addr.String()] = i
+	}
+
+	deadlines := map[string]time.Time{}
+	for _, be := range oldBES {
+		addr := be.L3n4Addr.String()
+		if _, ok := active[addr]; ok {
+			continue
+		}
+		deadline, ok := oldDeadlines[addr]
+		if !ok {
+			deadline = now.Add(lbDrainTimeout)
+		}
+		if now.Before(deadline) {
+			deadlines[addr] = deadline
+		}
+	}
+	if len(deadlines) == 0 {
+		return bes
+	}
+	d.lbDrain[sha256] = deadlines
+
+	// Keep all previous backends in their slot so that established
+	// connections are not moved to other backends.
+	drained := make([]types.LBBackEnd, 0, len(bes)+len(deadlines))
+	added := make(map[string]bool, len(bes))
+	for _, be := range oldBES {
+		addr := be.L3n4Addr.String()
+		if i, ok := active[addr]; ok {
+			drained = append(drained, bes[i])
+			added[addr] = true
+		} else if _, ok := deadlines[addr]; ok {
+			be.Draining = true
+			drained = append(drained, be)
+		}
+	}
+	for _, be := range bes {
+		if !added[be.L3n4Addr.String()] {
+			drained = append(drained, be)
+		}
+	}
+	return drained
+}
+
+// removeDrainedBackends removes the draining backends whose drain timeout
+// passed from their services.
+func (d *Daemon) removeDrainedBackends() error {
 	d.loadBalancer.BPFMapMU.Lock()
 	defer d.loadBalancer.BPFMapMU.Unlock()
 
-	err = d.addSVC2BPFMap(feL3n4Addr, fe, besValues, addRevNAT)
-	if err != nil {
-		return false, err
+	now := time.Now()
+	draining := 0
+	drained := []types.LBSVC{}
+	for sha256, svc := range d.loadBalancer.SVCMap {
+		active := make([]types.LBBackEnd, 0, len(svc.BES))
+		expired := false
+		for _, be := range svc.BES {
+			if !be.Draining {
+				active = append(active, be)
+				continue
+			}
+
+			// Backends restored from the BPF maps have no
+			// deadline yet.
+			addr := be.L3n4Addr.String()
+			deadline, ok := d.lbDrain[sha256][addr]
+			if !ok {
+				deadline = now.Add(lbDrainTimeout)
+				if d.lbDrain[sha256] == nil {
+					d.lbDrain[sha256] = map[string]time.Time{}
+				}
+				d.lbDrain[sha256][addr] = deadline
+			}
+			if now.Before(deadline) {
+				draining++
+			} else {
+				expired = true
+			}
+		}
+
+		if expired {
+			svc.BES = active
+			drained = append(drained, svc)
+		}
 	}
+	metrics.LBBackendsDraining.Set(float64(draining))
 
-	return d.loadBalancer.AddService(svc), nil
+	for _, svc := range drained {
+		if _, err := d.svcAddLocked(svc, false); err != nil {
+			log.WithError(err).WithField(logfields.ServiceName, svc.FE.String()).
+				Warn("Unable to remove drained backends of service")
+		}
+	}
+
+	return nil
 }
 
-type putServiceID struct {
-	d *Daemon
+// updateBackendHealth updates the backend selection of the service with the
+// given SHA256 sum of its frontend according to the health of its backends.
+func (d *Daemon) updateBackendHealth(sha256 string) {
+	d.loadBalancer.BPFMapMU.Lock()
+	defer d.loadBalancer.BPFMapMU.Unlock()
+
+	svc, ok := d.loadBalancer.SVCMap[sha256]
+	if !ok {
+		return
+	}
+
+	d.updateBackendSelectionLocked(svc)
 }
 
-func NewPutServiceIDHandler(d *Daemon) PutServiceIDHandler {
-	return &putServiceID{d: d}
+// updateTopologyServices updates the backend selection of all topology aware
+// services. It must be called when the zone of a node may have changed.
+func (d *Daemon) updateTopologyServices() {
+	d.loadBalancer.BPFMapMU.Lock()
+	defer d.loadBalancer.BPFMapMU.Unlock()
+
+	for _, svc := range d.loadBalancer.SVCMap {
+		if svc.TopologyAware() {
+			d.updateBackendSelectionLocked(svc)
+		}
+	}
 }
 
-func (h *putServiceID) Handle(params PutServiceIDParams) middleware.Responder {
-	log.WithField(logfields.Params, logfields.Repr(params)).Debug("PUT /service/{id} request")
+// updateBackendSelectionLocked applies the backends of svc which may be
+// selected for new connections to the BPF maps. Must be called with BPFMapMU
+// held.
+func (d *Daemon) updateBackendSelectionLocked(svc types.LBSVC) {
+	healthy := d.selectableBackends(&svc)
+	if healthy == nil {
+		return
+	}
+
+	scopedLog := log.WithField(logfields.ServiceName, svc.FE.String())
+
+	fe, besValues, err := lbmap.LBSVC2ServiceKeynValue(svc)
+	if err != nil {
+		scopedLog.WithError(err).Warn("Unable to create BPF key and values of service")
+		return
+	}
+
+	if err := lbmap.UpdateBackendHealth(fe, besValues, healthy, svc.LBSVCOptions); err != nil {
+		scopedLog.WithError(err).Warn("Unable to update backend selection of service")
+	}
+}
+
+// getServiceModel returns the API model of svc including the health and the
+// load-balancing statistics of its backends.
+func (d *Daemon) getServiceModel(svc *types.LBSVC, stats lbStats) *models.Service {
+	model := svc.GetModel()
+	if model == nil {
+		return model
+	}
+
+	sha256 := svc.FE.SHA256Sum()
+	for i := range svc.BES {
+		if svc.HealthCheck != nil {
+			if health := d.lbHealth.Health(sha256, svc.BES[i].L3n4Addr); health != "" {
+				model.BackendAddresses[i].Health = string(health)
+			}
+		}
+		model.BackendAddresses[i].Statistics = stats.getBackendStatistics(&svc.FE, &svc.BES[i])
+	}
+	return model
+}
 
-	f, err := types.NewL3n4AddrFromModel(params.Config.FrontendAddress)
+// affinitySlaves returns the mapping of backend indexes from oldSvc to newSvc
+// for all backends present in both services.
+func affinitySlaves(oldSvc, newSvc *types.LBSVC) map[uint16]uint16 {
+	newIdx := map[string]uint16{}
+	for i, be := range newSvc.BES {
+		newIdx[be.L3n4Addr.String()] = uint16(i + 1)
+	}
+
+	slaves := map[uint16]uint16{}
+	for i, be := range oldSvc.BES {
+		if idx, ok := newIdx[be.L3n4Addr.String()]; ok {
+			slaves[uint16(i+1)] = idx
+		}
+	}
+	return slaves
+}
+
+// newLBSVCFromModel returns the service described by the given API model
+// and whether a reverse NAT entry must be created for it. On error, the API
+// error code of PUT /service/{id} matching the failure is returned.
+func newLBSVCFromModel(m *models.Service) (*types.LBSVC, bool, int, error) {
+	f, err := types.NewL3n4AddrFromModel(m.FrontendAddress)
 	if err != nil {
-		return apierror.Error(PutServiceIDInvalidFrontendCode, err)
+		return nil, false, PutServiceIDInvalidFrontendCode, err
 	}
 
 	frontend := types.L3n4AddrID{
 		L3n4Addr: *f,
-		ID:       types.ServiceID(params.Config.ID),
+		ID:       types.ServiceID(m.ID),
 	}
 
 	backends := []types.LBBackEnd{}
-	for _, v := range params.Config.BackendAddresses {
+	for _, v := range m.BackendAddresses {
 		b, err := types.NewLBBackEndFromBackendModel(v)
 		if err != nil {
-			return apierror.Error(PutServiceIDInvalidBackendCode, err)
+			return nil, false, PutServiceIDInvalidBackendCode, err
 		}
 		backends = append(backends, *b)
 	}
 
 	revnat := false
-	if params.Config.Flags != nil {
-		revnat = params.Config.Flags.DirectServerReturn
+	if m.Flags != nil {
+		revnat = m.Flags.DirectServerReturn
+	}
+
+	opts, err := types.NewLBSVCOptionsFromModel(m.Flags)
+	if err != nil {
+		return nil, false, PutServiceIDFailureCode, err
+	}
+
+	opts.HealthCheck, err = types.NewLBHealthCheckFromModel(m.HealthCheck)
+	if err != nil {
+		return nil, false, PutServiceIDFailureCode, err
+	}
+
+	// Neither TCP nor HTTP health checks can probe UDP backends
+	if opts.HealthCheck != nil && frontend.Protocol == types.UDP {
+		return nil, false, PutServiceIDFailureCode, fmt.Errorf("health checks are not supported for UDP services")
+	}
+
+	if m.Name == "" && m.Namespace != "" {
+		return nil, false, PutServiceIDFailureCode, fmt.Errorf("namespace %q given without a service name", m.Namespace)
+	}
+	opts.Name = types.LBSVCName{
+		Namespace: m.Namespace,
+		Name:      m.Name,
+	}
+	opts.Labels = m.Labels
+
+	return &types.LBSVC{
+		FE:           frontend,
+		BES:          backends,
+		Sha256:       frontend.L3n4Addr.SHA256Sum(),
+		LBSVCOptions: opts,
+	}, revnat, 0, nil
+}
+
+type putServiceID struct {
+	d *Daemon
+}
+
+func NewPutServiceIDHandler(d *Daemon) PutServiceIDHandler {
+	return &putServiceID{d: d}
+}
+
+func (h *putServiceID) Handle(params PutServiceIDParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("PUT /service/{id} request")
+
+	svc, revnat, code, err := newLBSVCFromModel(params.Config)
+	if err != nil {
+		return apierror.Error(code, err)
 	}
 
 	// FIXME
 	// Add flag to indicate whether service should be registered in
 	// global key value store
 
-	if created, err := h.d.SVCAdd(frontend, backends, revnat); err != nil {
+	if created, err := h.d.SVCAdd(svc.FE, svc.BES, revnat, svc.LBSVCOptions); err != nil {
 		return apierror.Error(PutServiceIDFailureCode, err)
 	} else if created {
 		return NewPutServiceIDCreated()
@@ -214,6 +614,8 @@ func (h *deleteServiceID) Handle(params DeleteServiceIDParams) middleware.Respon
 		log.WithError(err).WithField(logfields.Object, logfields.Repr(svc)).Warn("DELETE /service/{id}: error deleting service")
 		return apierror.Error(DeleteServiceIDFailureCode, err)
 	}
+	h.d.lbHealth.DeleteService(svc.Sha256)
+	delete(h.d.lbDrain, svc.Sha256)
 
 	return NewDeleteServiceIDOK()
 }
@@ -231,7 +633,12 @@ func (d *Daemon) svcDeleteByFrontend(frontend *types.L3n4Addr) error {
 	d.loadBalancer.BPFMapMU.Lock()
 	defer d.loadBalancer.BPFMapMU.Unlock()
 
-	return d.svcDeleteByFrontendLocked(frontend)
+	if err := d.svcDeleteByFrontendLocked(frontend); err != nil {
+		return err
+	}
+	d.lbHealth.DeleteService(frontend.SHA256Sum())
+	delete(d.lbDrain, frontend.SHA256Sum())
+	return nil
 }
 
 func (d *Daemon) svcDelete(svc *types.LBSVC) error {
@@ -239,6 +646,7 @@ func (d *Daemon) svcDelete(svc *types.LBSVC) error {
 		return err
 	}
 	d.loadBalancer.DeleteService(svc)
+	d.removeServiceMetadata(svc)
 	return nil
 }
 
@@ -285,6 +693,8 @@ func (d *Daemon) svcDeleteBPF(svc *types.LBSVC) error {
 		return fmt.Errorf("deleting service failed for %s: %s", svcKey, err)
 	}
 
+	lbmap.DeleteAffinityMatch(uint16(svc.FE.ID))
+
 	return nil
 }
 
@@ -305,7 +715,7 @@ func (h *getServiceID) Handle(params GetServiceIDParams) middleware.Responder {
 	defer d.loadBalancer.BPFMapMU.RUnlock()
 
 	if svc, ok := d.loadBalancer.SVCMapID[types.ServiceID(params.ID)]; ok {
-		return NewGetServiceIDOK().WithPayload(svc.GetModel())
+		return NewGetServiceIDOK().WithPayload(d.getServiceModel(svc, d.dumpLBStats()))
 	}
 	return NewGetServiceIDNotFound()
 }
@@ -327,9 +737,36 @@ func (d *Daemon) svcGetBySHA256Sum(feL3n4SHA256Sum string) *types.LBSVC {
 		beCpy = append(beCpy, v)
 	}
 	return &types.LBSVC{
-		FE:  *v.FE.DeepCopy(),
-		BES: beCpy,
+		FE:           *v.FE.DeepCopy(),
+		BES:          beCpy,
+		LBSVCOptions: v.LBSVCOptions,
+	}
+}
+
+// svcGetByName returns a DeepCopy of all frontends, with their backends, of
+// the service with the given name.
+func (d *Daemon) svcGetByName(name types.LBSVCName) []*types.LBSVC {
+	d.loadBalancer.BPFMapMU.RLock()
+	defer d.loadBalancer.BPFMapMU.RUnlock()
+
+	svcs := []*types.LBSVC{}
+	for sha256 := range d.loadBalancer.SVCMapName[name] {
+		v, ok := d.loadBalancer.SVCMap[sha256]
+		if !ok {
+			continue
+		}
+		beCpy := []types.LBBackEnd{}
+		for _, v := range v.BES {
+			beCpy = append(beCpy, v)
+		}
+		svcs = append(svcs, &types.LBSVC{
+			Sha256:       v.Sha256,
+			FE:           *v.FE.DeepCopy(),
+			BES:          beCpy,
+			LBSVCOptions: v.LBSVCOptions,
+		})
 	}
+	return svcs
 }
 
 type getService struct {
@@ -342,7 +779,31 @@ func NewGetServiceHandler(d *Daemon) GetServiceHandler {
 
 func (h *getService) Handle(params GetServiceParams) middleware.Responder {
 	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /service request")
-	list := h.d.GetServiceList()
+
+	if params.Name == nil {
+		list := h.d.GetServiceList()
+		if params.Namespace != nil {
+			filtered := []*models.Service{}
+			for _, svc := range list {
+				if svc.Namespace == *params.Namespace {
+					filtered = append(filtered, svc)
+				}
+			}
+			list = filtered
+		}
+		return NewGetServiceOK().WithPayload(list)
+	}
+
+	name := types.LBSVCName{Name: *params.Name}
+	if params.Namespace != nil {
+		name.Namespace = *params.Namespace
+	}
+
+	list := []*models.Service{}
+	stats := h.d.dumpLBStats()
+	for _, svc := range h.d.svcGetByName(name) {
+		list = append(list, h.d.getServiceModel(svc, stats))
+	}
 	return NewGetServiceOK().WithPayload(list)
 }
 
@@ -451,6 +912,7 @@ func (d *Daemon) SyncLBMap() error {
 	newRevNATMap := types.RevNATMap{}
 	failedSyncSVC := []types.LBSVC{}
 	failedSyncRevNAT := map[types.ServiceID]types.L3n4Addr{}
+	maglevSVCs := map[string]bool{}
 
 	addSVC2BPFMap := func(oldID types.ServiceID, svc types.LBSVC) error {
 		scopedLog := log.WithFields(logrus.Fields{
@@ -493,7 +955,7 @@ func (d *Daemon) SyncLBMap() error {
 				" This entry will be removed from the bpf's LB map.", svc.FE.String(), svc.BES, err)
 		}
 
-		err = d.addSVC2BPFMap(svc.FE, fe, besValues, false)
+		err = d.addSVC2BPFMap(svc.FE, fe, besValues, false, svc.LBSVCOptions)
 		if err != nil {
 			return fmt.Errorf("Unable to add service FE: %s: %s."+
 				" This entry will be removed from the bpf's LB map.", svc.FE.String(), err)
@@ -542,6 +1004,16 @@ func (d *Daemon) SyncLBMap() error {
 		newRevNATMap[fe.ID] = fe.L3n4Addr
 	}
 
+	parseMaglevEntries := func(key bpf.MapKey, _ bpf.MapValue) {
+		svcKey := key.(lbmap.ServiceKey)
+		fe, err := lbmap.ServiceKey2L3n4Addr(svcKey)
+		if err != nil {
+			log.WithError(err).WithField(logfields.BPFMapKey, svcKey).Error("SyncLBMap.parseMaglevEntries")
+			return
+		}
+		maglevSVCs[fe.SHA256Sum()] = true
+	}
+
 	if !d.conf.IPv4Disabled {
 		// lbmap.RRSeq4Map is updated as part of Service4Map and does
 		// not need separate dump.
@@ -553,6 +1025,10 @@ func (d *Daemon) SyncLBMap() error {
 		if err != nil {
 			log.WithError(err).Warn("error dumping RevNat4Map")
 		}
+		err = lbmap.Maglev4Map.Dump(lbmap.Service4MaglevDumpParser, parseMaglevEntries)
+		if err != nil {
+			log.WithError(err).Warn("error dumping Maglev4Map")
+		}
 	}
 
 	// lbmap.RRSeq6Map is updated as part of Service6Map and does not need
@@ -565,6 +1041,43 @@ func (d *Daemon) SyncLBMap() error {
 	if err != nil {
 		log.WithError(err).Warn("error dumping RevNat6Map")
 	}
+	err = lbmap.Maglev6Map.Dump(lbmap.Service6MaglevDumpParser, parseMaglevEntries)
+	if err != nil {
+		log.WithError(err).Warn("error dumping Maglev6Map")
+	}
+
+	affinityTimeouts, err := lbmap.DumpAffinityMatch()
+	if err != nil {
+		log.WithError(err).Warn("error dumping AffinityMatchMap")
+	}
+
+	metadata, err := readServiceMetadata(d.conf.StateDir)
+	if err != nil {
+		log.WithError(err).Warn("Unable to read stored service metadata")
+	}
+
+	// Restore the options of the services from the BPF maps: services
+	// with a Maglev lookup table were added with the Maglev algorithm and
+	// services with an affinity match entry have session affinity. If
+	// Maglev has been disabled since, the services fall back to the hash
+	// algorithm. Names, labels and backend names are not stored in the
+	// BPF maps and are restored from the state directory.
+	restoreOptions := func(svc *types.LBSVC) {
+		if maglevSVCs[svc.Sha256] && d.conf.EnableMaglev {
+			svc.Algorithm = types.LBAlgorithmMaglev
+		}
+		svc.SessionAffinityTimeout = affinityTimeouts[uint16(svc.FE.ID)]
+		if m, ok := metadata[svc.Sha256]; ok {
+			m.apply(svc)
+		}
+	}
+	for sha, svc := range newSVCMap {
+		restoreOptions(&svc)
+		newSVCMap[sha] = svc
+	}
+	for _, svc := range newSVCList {
+		restoreOptions(svc)
+	}
 
 	// Need to do this outside of parseSVCEntries to avoid deadlock, because we
 	// are modifying the BPF maps, and calling Dump on a Map RLocks the maps.
@@ -593,6 +1106,7 @@ func (d *Daemon) SyncLBMap() error {
 			}).Info("Frontend service ID read from BPF map was out of sync with KVStore, got new ID")
 			oldID := svc.FE.ID
 			svc.FE.ID = kvL3n4AddrID.ID
+			lbmap.DeleteAffinityMatch(uint16(oldID))
 			// If we cannot add the service to the BPF maps, update the list of
 			// services that failed to sync.
 			if err := addSVC2BPFMap(oldID, *svc); err != nil {
@@ -626,6 +1140,7 @@ func (d *Daemon) SyncLBMap() error {
 		if err := d.svcDeleteBPF(&svc); err != nil {
 			log.WithError(err).WithField(logfields.Object, logfields.Repr(svc.FE)).Warn("Unable to clean service from BPF map")
 		}
+		d.removeServiceMetadata(&svc)
 	}
 
 	for id, revNAT := range failedSyncRevNAT {
@@ -652,6 +1167,19 @@ func (d *Daemon) SyncLBMap() error {
 	d.loadBalancer.SVCMap = newSVCMap
 	d.loadBalancer.SVCMapID = newSVCMapID
 	d.loadBalancer.RevNATMap = newRevNATMap
+	d.loadBalancer.SVCMapName = types.SVCMapName{}
+	for _, svc := range newSVCMap {
+		d.loadBalancer.SVCMapName.Add(&svc)
+	}
+
+	// Remove the metadata of services which no longer exist
+	for sha := range metadata {
+		if _, ok := newSVCMap[sha]; !ok {
+			if err := deleteServiceMetadata(d.conf.StateDir, sha); err != nil {
+				log.WithError(err).WithField(logfields.SHA, sha).Warn("Unable to remove stored service metadata")
+			}
+		}
+	}
 
 	return nil
 }
@@ -42,6 +42,8 @@ import (
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/nat"
+	"github.com/cilium/cilium/pkg/maps/policymap"
 	"github.com/cilium/cilium/pkg/metrics"
 	"github.com/cilium/cilium/pkg/monitor"
 	"github.com/cilium/cilium/pkg/node"
@@ -88,22 +90,33 @@ var (
 	// autoIPv6NodeRoutes automatically adds L3 direct routing when using direct mode (-d)
 	autoIPv6NodeRoutes    bool
 	bpfRoot               string
+	clusterID             int
+	clusterMeshConfig     string
+	clusterName           string
 	cmdRefDir             string
 	containerRuntimes     []string
+	ctGCMaxInterval       time.Duration
 	disableConntrack      bool
 	dockerEndpoint        string
 	enableLogstash        bool
 	enableTracing         bool
+	identityQuarantine    time.Duration
 	k8sAPIServer          string
+	k8sExtNameRefresh     time.Duration
 	k8sKubeConfigPath     string
 	kvStore               string
+	kvStoreDegraded       time.Duration
 	labelPrefixFile       string
+	lbDrainTimeout        time.Duration
 	loggers               []string
 	logstashAddr          string
 	logstashProbeTimer    uint32
 	masquerade            bool
+	masqExcludeCIDRs      []string
 	nat46prefix           string
+	policyStatsLimit      int
 	prometheusServeAddr   string
+	serviceConfigDir      string
 	singleClusterRoute    bool
 	socketPath            string
 	tracePayloadLen       int
@@ -116,6 +129,7 @@ var (
 	v6Prefix              string
 	v6ServicePrefix       string
 	validLabels           []string
+	wellKnownIdentityFile string
 )
 
 var (
@@ -316,12 +330,20 @@ func init() {
 		"auto-ipv6-node-routes", false, "Automatically adds IPv6 L3 routes to reach other nodes for non-overlay mode (--device) (BETA)")
 	flags.StringVar(&bpfRoot,
 		"bpf-root", "", "Path to BPF filesystem")
+	flags.IntVar(&clusterID,
+		"cluster-id", 0, "Unique identifier of the cluster (0-255)")
+	flags.StringVar(&clusterMeshConfig,
+		"clustermesh-config", "", "Path to the directory containing the etcd configuration files of remote clusters")
+	flags.StringVar(&clusterName,
+		"cluster-name", node.DefaultClusterName, "Name of the cluster")
 	flags.StringVar(&cfgFile,
 		"config", "", `Configuration file (default "$HOME/ciliumd.yaml")`)
 	flags.StringSliceVar(&containerRuntimes,
 		"container-runtime", []string{"auto"}, `Sets the container runtime(s) used by Cilium { docker | none | auto }"`)
 	flags.Var(option.NewNamedMapOptions("container-runtime-endpoints", &containerRuntimesOpts, nil),
 		"container-runtime-endpoint", `Container runtime(s) endpoint(s). (default: --container-runtime-endpoint=docker=`+workloads.GetRuntimeDefaultOpt(workloads.Docker).Endpoint+`)`)
+	flags.DurationVar(&ctGCMaxInterval,
+		"conntrack-gc-max-interval", endpointmanager.DefaultConntrackGCMaxInterval, "Longest interval between two garbage collection runs of the connection tracking tables")
 	flags.BoolP(
 		"debug", "D", false, "Enable debugging mode")
 	flags.StringSlice(argDebugVerbose, []string{}, "List of enabled verbose debug groups")
@@ -331,16 +353,36 @@ func init() {
 		"disable-conntrack", false, "Disable connection tracking")
 	flags.BoolVar(&config.IPv4Disabled,
 		"disable-ipv4", false, "Disable IPv4 mode")
+	flags.Bool("disable-k8s-external-ips",
+		false, "Disable load balancing of K8s service external IPs for traffic from local endpoints by cilium")
+	flags.Bool("disable-k8s-node-port",
+		false, "Disable load balancing of K8s node ports on the node addresses for traffic from local endpoints by cilium")
 	flags.Bool("disable-k8s-services",
 		false, "Disable east-west K8s load balancing by cilium")
 	flags.StringVarP(&dockerEndpoint,
 		"docker", "e", workloads.GetRuntimeDefaultOpt(workloads.Docker).Endpoint, "Path to docker runtime socket (DEPRECATED: use container-runtime-endpoint instead)")
+	flags.BoolVar(&config.BPFMasquerade,
+		"enable-bpf-masquerade", false, "Masquerade packets from endpoints leaving the host in BPF instead of iptables (requires --device)")
+	flags.BoolVar(&config.EnableEgressGateway,
+		"enable-egress-gateway", false, "Enable egress gateway policies (requires tunnel mode)")
+	flags.BoolVar(&config.EnableIPSec,
+		"enable-ipsec", false, "Encrypt the traffic between nodes with IPsec (requires --ipsec-key-file)")
+	flags.BoolVar(&config.EnableMaglev,
+		"enable-lb-maglev", false, "Enable the Maglev load-balancing algorithm for services")
+	flags.BoolVar(&config.EnableLBStats,
+		"enable-lb-stats", false, "Enable the traffic statistics of service backends")
 	flags.String("enable-policy", endpoint.DefaultEnforcement, "Enable policy enforcement")
 	flags.BoolVar(&enableTracing,
 		"enable-tracing", false, "Enable tracing while determining policy (debugging)")
 	flags.BoolVar(&useEnvoy,
 		"envoy-proxy", true, "This flag is deprecated and will be removed in the next release")
 	flags.MarkHidden("envoy-proxy")
+	flags.DurationVar(&identityQuarantine,
+		"identity-quarantine-period", policy.DefaultIdentityQuarantinePeriod, "Time a released security identity is quarantined before it can be reused")
+	flags.StringVar(&config.IPSecKeyFile,
+		"ipsec-key-file", "", "Path of the file holding the IPsec key, e.g. a mounted Kubernetes secret")
+	flags.DurationVar(&config.IPSecKeyRotationDuration,
+		"ipsec-key-rotation-duration", 5*time.Minute, "Time given to all nodes to install a new IPsec key before it is used")
 	flags.IntVar(&v4ClusterCidrMaskSize,
 		"ipv4-cluster-cidr-mask-size", 8, "Mask size for the cluster wide CIDR")
 	flags.StringVar(&v4Prefix,
@@ -353,6 +395,8 @@ func init() {
 		"ipv6-service-range", AutoCIDR, "Kubernetes IPv6 services CIDR if not inside cluster prefix")
 	flags.StringVar(&k8sAPIServer,
 		"k8s-api-server", "", "Kubernetes api address server (for https use --k8s-kubeconfig-path instead)")
+	flags.DurationVar(&k8sExtNameRefresh,
+		"k8s-external-name-refresh", k8s.DefaultExternalNameRefreshInterval, "Interval in which the DNS names of K8s ExternalName services are resolved again")
 	flags.StringVar(&k8sKubeConfigPath,
 		"k8s-kubeconfig-path", "", "Absolute path of the kubernetes kubeconfig file")
 	flags.BoolVar(&config.KeepConfig,
@@ -363,12 +407,16 @@ func init() {
 		"kvstore", "", "Key-value store type")
 	flags.Var(option.NewNamedMapOptions("kvstore-opts", &kvStoreOpts, nil),
 		"kvstore-opt", "Key-value store options")
+	flags.DurationVar(&kvStoreDegraded,
+		"kvstore-degraded-threshold", kvstore.DefaultDegradedThreshold, "Time the kvstore must be unreachable before running in degraded mode")
 	flags.StringVar(&labelPrefixFile,
 		"label-prefix-file", "", "Valid label prefixes file path")
 	flags.StringSliceVar(&validLabels,
 		"labels", []string{}, "List of label prefixes used to determine identity of an endpoint")
 	flags.StringVar(&config.LBInterface,
 		"lb", "", "Enables load balancer mode where load balancer bpf program is attached to the given interface")
+	flags.DurationVar(&lbDrainTimeout,
+		"lb-drain-timeout", defaultLBDrainTimeout, "Time a backend removed from a service keeps serving its established connections (0 to disable)")
 	flags.StringVar(&config.LibDir,
 		"lib-dir", defaults.LibraryPath, "Directory path to store runtime build environment")
 	flags.StringSliceVar(&loggers,
@@ -383,14 +431,22 @@ func init() {
 		"logstash-probe-timer", 10, "Logstash probe timer (seconds)")
 	flags.StringVar(&nat46prefix,
 		"nat46-range", node.DefaultNAT46Prefix, "IPv6 prefix to map IPv4 addresses to")
+	flags.IntVar(&policymap.MaxEntries,
+		"policy-map-entries", policymap.MAX_KEYS, "Maximum number of entries of the policy map of an endpoint")
+	flags.IntVar(&policyStatsLimit,
+		"policy-stats-metrics-limit", defaultPolicyStatsMetricsLimit, "Maximum number of policy entries exported as individual metrics (0 to disable)")
 	flags.BoolVar(&masquerade,
 		"masquerade", true, "Masquerade packets from endpoints leaving the host")
+	flags.StringSliceVar(&masqExcludeCIDRs,
+		"masquerade-exclude-cidrs", []string{}, "IPv4 prefixes to which packets are not masqueraded by --enable-bpf-masquerade, e.g. the native routing prefix")
 	flags.StringVar(&v6Address,
 		"ipv6-node", "auto", "IPv6 address of node")
 	flags.StringVar(&v4Address,
 		"ipv4-node", "auto", "IPv4 address of node")
 	flags.BoolVar(&config.RestoreState,
 		"restore", true, "Restores state, if possible, from previous daemon")
+	flags.StringVar(&serviceConfigDir,
+		"service-config-dir", "", "Path to a directory of service definition files (YAML or JSON) to synchronize")
 	flags.BoolVar(&singleClusterRoute, "single-cluster-route", false,
 		"Use a single cluster route instead of per node routes")
 	flags.StringVar(&socketPath,
@@ -409,6 +465,8 @@ func init() {
 		"prefilter-device", "", "undefined", "Device facing external network for XDP prefiltering")
 	flags.StringVarP(&config.ModePreFilter,
 		"prefilter-mode", "", ModePreFilterNative, "Prefilter mode { "+ModePreFilterNative+" | "+ModePreFilterGeneric+" } (default: "+ModePreFilterNative+")")
+	flags.StringVar(&wellKnownIdentityFile,
+		"well-known-identities-file", "", "Path to file mapping label sets to fixed numeric identities")
 	// We expect only one of the possible variables to be filled. The evaluation order is:
 	// --prometheus-serve-addr, CILIUM_PROMETHEUS_SERVE_ADDR, then PROMETHEUS_SERVE_ADDR
 	// The second environment variable (without the CILIUM_ prefix) is here to
@@ -582,6 +640,49 @@ func initEnv(cmd *cobra.Command) {
 			ModePreFilterNative, ModePreFilterGeneric)
 	}
 
+	if policymap.MaxEntries <= 0 {
+		log.Fatal("Invalid setting for --policy-map-entries, must be greater than 0")
+	}
+
+	if config.BPFMasquerade {
+		switch {
+		case !masquerade:
+			log.Fatal("--enable-bpf-masquerade requires --masquerade")
+		case config.Device == "undefined":
+			log.Fatal("--enable-bpf-masquerade requires --device")
+		case config.IsLBEnabled():
+			log.Fatal("--enable-bpf-masquerade is not supported in load balancer mode")
+		case config.IPv4Disabled:
+			log.Fatal("--enable-bpf-masquerade requires IPv4")
+		}
+	}
+
+	if config.EnableEgressGateway {
+		switch {
+		case config.Device != "undefined":
+			log.Fatal("--enable-egress-gateway requires tunnel mode")
+		case config.IPv4Disabled:
+			log.Fatal("--enable-egress-gateway requires IPv4")
+		}
+	}
+
+	if config.EnableIPSec {
+		switch {
+		case config.IPSecKeyFile == "":
+			log.Fatal("--enable-ipsec requires --ipsec-key-file")
+		case config.IPv4Disabled:
+			log.Fatal("--enable-ipsec requires IPv4")
+		}
+	}
+
+	for _, cidr := range masqExcludeCIDRs {
+		_, prefix, err := net.ParseCIDR(cidr)
+		if err != nil || prefix.IP.To4() == nil {
+			log.WithField(logfields.V4Prefix, cidr).Fatal("Invalid setting for --masquerade-exclude-cidrs, must be IPv4 prefixes")
+		}
+		config.MasqueradeExcludeCIDRs = append(config.MasqueradeExcludeCIDRs, prefix)
+	}
+
 	scopedLog = log.WithField(logfields.Path, socketPath)
 	socketDir := path.Dir(socketPath)
 	if err := os.MkdirAll(socketDir, defaults.RuntimePathRights); err != nil {
@@ -616,7 +717,29 @@ func initEnv(cmd *cobra.Command) {
 	config.Opts.Set(endpoint.OptionConntrackLocal, false)
 
 	policy.SetPolicyEnabled(strings.ToLower(viper.GetString("enable-policy")))
+	policy.IdentityQuarantinePeriod = identityQuarantine
+
+	if k8sExtNameRefresh <= 0 {
+		log.WithField("interval", k8sExtNameRefresh).Fatal("Invalid refresh interval of ExternalName services, must be positive")
+	}
+	k8s.ExternalNameRefreshInterval = k8sExtNameRefresh
+
+	if ctGCMaxInterval < endpointmanager.ConntrackGCMinInterval {
+		log.WithField("interval", ctGCMaxInterval).Fatalf("Invalid maximum garbage collection interval of connection tracking tables, must be at least %s",
+			endpointmanager.ConntrackGCMinInterval)
+	}
+	endpointmanager.ConntrackGCMaxInterval = ctGCMaxInterval
+
+	if err := node.SetClusterName(clusterName); err != nil {
+		log.WithError(err).Fatal("Invalid cluster name")
+	}
+
+	if err := node.SetClusterID(clusterID); err != nil {
+		log.WithError(err).Fatal("Invalid cluster ID")
+	}
 
+	kvstore.DegradedThreshold = kvStoreDegraded
+	kvstore.LockOwnerNode = node.GetName()
 	if err := kvstore.Setup(kvStore, kvStoreOpts); err != nil {
 		addrkey := fmt.Sprintf("%s.address", kvStore)
 		addr := kvStoreOpts[addrkey]
@@ -630,6 +753,10 @@ func initEnv(cmd *cobra.Command) {
 		log.WithError(err).Fatal("Unable to parse Label prefix configuration")
 	}
 
+	if err := policy.ParseWellKnownIdentityCfg(wellKnownIdentityFile); err != nil {
+		log.WithError(err).Fatal("Unable to parse well-known identities configuration")
+	}
+
 	_, r, err := net.ParseCIDR(nat46prefix)
 	if err != nil {
 		log.WithError(err).WithField(logfields.V6Prefix, nat46prefix).Fatal("Invalid NAT46 prefix")
@@ -691,6 +818,9 @@ func runDaemon() {
 
 	policy.Init()
 	endpointmanager.EnableConntrackGC(!d.conf.IPv4Disabled, true)
+	if d.conf.BPFMasquerade {
+		nat.StartGC()
+	}
 
 	if enableLogstash {
 		go EnableLogstash(logstashAddr, int(logstashProbeTimer))
@@ -765,6 +895,9 @@ func runDaemon() {
 	// /endpoint/{id}/healthz
 	api.EndpointGetEndpointIDHealthzHandler = NewGetEndpointIDHealthzHandler(d)
 
+	// /endpoint/{id}/policy-stats
+	api.EndpointGetEndpointIDPolicyStatsHandler = NewGetEndpointIDPolicyStatsHandler(d)
+
 	// /identity/
 	api.PolicyGetIdentityHandler = newGetIdentityHandler(d)
 	api.PolicyGetIdentityIDHandler = newGetIdentityIDHandler(d)
@@ -777,6 +910,13 @@ func runDaemon() {
 	// /policy/resolve/
 	api.PolicyGetPolicyResolveHandler = NewGetPolicyResolveHandler(d)
 
+	// /policy/egress-gateway/
+	api.PolicyGetPolicyEgressGatewayHandler = newGetEgressGatewayPolicyHandler(d)
+
+	// /policy/egress-gateway/{name}/
+	api.PolicyPutPolicyEgressGatewayNameHandler = newPutEgressGatewayPolicyHandler(d)
+	api.PolicyDeletePolicyEgressGatewayNameHandler = newDeleteEgressGatewayPolicyHandler(d)
+
 	// /service/{id}/
 	api.ServiceGetServiceIDHandler = NewGetServiceIDHandler(d)
 	api.ServiceDeleteServiceIDHandler = NewDeleteServiceIDHandler(d)
@@ -798,6 +938,15 @@ func runDaemon() {
 	// /debuginfo
 	api.DaemonGetDebuginfoHandler = NewGetDebugInfoHandler(d)
 
+	// /map
+	api.DaemonGetMapHandler = NewGetMapHandler(d)
+	api.DaemonGetMapNameHandler = NewGetMapNameHandler(d)
+	api.DaemonGetMapNameEntriesHandler = NewGetMapNameEntriesHandler(d)
+
+	// /kvstore/locks
+	api.DaemonGetKvstoreLocksHandler = NewGetKvstoreLocksHandler(d)
+	api.DaemonDeleteKvstoreLocksHandler = NewDeleteKvstoreLocksHandler(d)
+
 	server := server.NewServer(api)
 	server.EnabledListeners = []string{"unix"}
 	server.SocketPath = flags.Filename(socketPath)
@@ -0,0 +1,77 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/policymap"
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+// defaultPolicyStatsMetricsLimit is the default maximum number of policy
+// entries exported as individual metric series.
+const defaultPolicyStatsMetricsLimit = 1000
+
+// policyEntryPort returns the port label of the policy map entry dump.
+// Entries allowing all ports are labeled models.PortProtocolANY.
+func policyEntryPort(dump *policymap.PolicyEntryDump) string {
+	port := dump.Key.GetDestPort()
+	if port == 0 {
+		return models.PortProtocolANY
+	}
+	return fmt.Sprintf("%d/%s", port, u8proto.U8proto(dump.Key.Nexthdr))
+}
+
+// policyEntryStats returns the hit counters of the policy entries of all
+// endpoints for the Prometheus metrics.
+func (d *Daemon) policyEntryStats() []metrics.PolicyEntryStats {
+	result := []metrics.PolicyEntryStats{}
+	for _, ep := range endpointmanager.GetEndpoints() {
+		dumps, err := ep.GetPolicyStats()
+		if err != nil {
+			log.WithError(err).WithField(logfields.EndpointID, ep.ID).
+				Debug("Unable to dump policy map")
+			continue
+		}
+
+		endpointID := strconv.Itoa(int(ep.ID))
+		for i := range dumps {
+			result = append(result, metrics.PolicyEntryStats{
+				Endpoint: endpointID,
+				Identity: strconv.Itoa(int(dumps[i].Key.Identity)),
+				Port:     policyEntryPort(&dumps[i]),
+				Packets:  dumps[i].Packets,
+				Bytes:    dumps[i].Bytes,
+			})
+		}
+	}
+	return result
+}
+
+// startPolicyStats starts exporting the policy hit counters of all endpoints
+// unless disabled by a limit of 0.
+func (d *Daemon) startPolicyStats() {
+	if d.DryModeEnabled() || policyStatsLimit <= 0 {
+		return
+	}
+
+	metrics.MustRegister(metrics.NewPolicyStatsCollector(d.policyEntryStats, policyStatsLimit))
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// addFileService adds or updates a service defined in a service file. A
+// service ID is allocated if the file does not specify one.
+func (d *Daemon) addFileService(m *models.Service) error {
+	svc, revnat, _, err := newLBSVCFromModel(m)
+	if err != nil {
+		return err
+	}
+
+	if svc.FE.ID != 0 {
+		_, err = d.SVCAdd(svc.FE, svc.BES, revnat, svc.LBSVCOptions)
+		return err
+	}
+
+	feAddrID, err := PutL3n4Addr(svc.FE.L3n4Addr, 0)
+	if err != nil {
+		return fmt.Errorf("unable to allocate service ID: %s", err)
+	}
+	svc.FE.ID = feAddrID.ID
+
+	_, err = d.svcAdd(svc.FE, svc.BES, revnat, svc.LBSVCOptions)
+	return err
+}
+
+// delFileService deletes a service which was removed from a service file
+func (d *Daemon) delFileService(m *models.Service) error {
+	feAddr, err := types.NewL3n4AddrFromModel(m.FrontendAddress)
+	if err != nil {
+		return err
+	}
+
+	svc := d.svcGetBySHA256Sum(feAddr.SHA256Sum())
+	if svc == nil {
+		return nil
+	}
+	id := svc.FE.ID
+
+	scopedLog := log.WithField(logfields.ServiceID, id)
+	if err := DeleteL3n4AddrIDByUUID(uint32(id)); err != nil {
+		scopedLog.WithError(err).Warn("Error while cleaning service ID")
+	}
+
+	if err := d.svcDeleteByFrontend(feAddr); err != nil {
+		return err
+	}
+
+	if err := d.RevNATDelete(id); err != nil {
+		scopedLog.WithError(err).Warn("Error deleting reverse NAT")
+	}
+
+	return nil
+}
@@ -15,12 +15,19 @@
 package main
 
 import (
+	"fmt"
 	"net"
+	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/api/v1/server/restapi/service"
 	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/comparator"
 	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/maps/lbmap"
+	"github.com/cilium/cilium/pkg/node"
 
 	. "gopkg.in/check.v1"
 )
@@ -146,3 +153,219 @@ func (ds *DaemonSuite) TestGetMaxServiceID(c *C) {
 	c.Assert(err, Equals, nil)
 	c.Assert(id, Equals, (common.MaxSetOfServiceID - 1))
 }
+
+func (ds *DaemonSuite) TestDrainBackends(c *C) {
+	d := &Daemon{lbDrain: map[string]map[string]time.Time{}}
+	be := func(port uint16) types.LBBackEnd {
+		return types.LBBackEnd{
+			L3n4Addr: types.L3n4Addr{
+				IP:     net.ParseIP("10.0.0.1"),
+				L4Addr: types.L4Addr{Port: port, Protocol: types.TCP},
+			},
+		}
+	}
+	draining := func(port uint16) types.LBBackEnd {
+		b := be(port)
+		b.Draining = true
+		return b
+	}
+
+	// Removed backends keep their slot, new ones are appended
+	bes := d.drainBackends("svc", []types.LBBackEnd{be(1), be(2), be(3)},
+		[]types.LBBackEnd{be(3), be(1), be(4)})
+	c.Assert(bes, comparator.DeepEquals, []types.LBBackEnd{be(1), draining(2), be(3), be(4)})
+	c.Assert(d.lbDrain["svc"], HasLen, 1)
+
+	// Re-adding a draining backend stops its drain
+	bes = d.drainBackends("svc", bes, []types.LBBackEnd{be(1), be(2), be(3), be(4)})
+	c.Assert(bes, comparator.DeepEquals, []types.LBBackEnd{be(1), be(2), be(3), be(4)})
+	c.Assert(d.lbDrain["svc"], HasLen, 0)
+
+	// Backends past their deadline are removed
+	bes = d.drainBackends("svc", bes, []types.LBBackEnd{be(1), be(3), be(4)})
+	c.Assert(bes, comparator.DeepEquals, []types.LBBackEnd{be(1), draining(2), be(3), be(4)})
+	for addr := range d.lbDrain["svc"] {
+		d.lbDrain["svc"][addr] = time.Now().Add(-time.Second)
+	}
+	bes = d.drainBackends("svc", bes, []types.LBBackEnd{be(1), be(3), be(4)})
+	c.Assert(bes, comparator.DeepEquals, []types.LBBackEnd{be(1), be(3), be(4)})
+	c.Assert(d.lbDrain["svc"], HasLen, 0)
+
+	// Nothing is drained without any backend left
+	bes = d.drainBackends("svc", bes, []types.LBBackEnd{})
+	c.Assert(bes, HasLen, 0)
+}
+
+func (ds *DaemonSuite) TestPreferLocalBackends(c *C) {
+	be := func(nodeName, zone string) types.LBBackEnd {
+		return types.LBBackEnd{NodeName: nodeName, Zone: zone}
+	}
+	zones := map[string]string{"n1": "z1", "n2": "z1", "n3": "z2"}
+	zoneOf := func(name string) string { return zones[name] }
+	all := []bool{true, true, true, true}
+
+	bes := []types.LBBackEnd{be("n1", ""), be("n2", ""), be("n3", ""), be("", "z1")}
+
+	// Backends on the local node are preferred
+	sel := preferLocalBackends(bes, all, types.LBTopologyNode, "n1", "z1", zoneOf)
+	c.Assert(sel, comparator.DeepEquals, []bool{true, false, false, false})
+
+	// Then backends in the local zone, by node or explicit zone
+	sel = preferLocalBackends(bes, []bool{false, true, true, true}, types.LBTopologyNode, "n1", "z1", zoneOf)
+	c.Assert(sel, comparator.DeepEquals, []bool{false, true, false, true})
+
+	sel = preferLocalBackends(bes, all, types.LBTopologyZone, "n1", "z1", zoneOf)
+	c.Assert(sel, comparator.DeepEquals, []bool{true, true, false, true})
+
+	// Remote backends are only selected if no local one is selectable
+	sel = preferLocalBackends(bes, []bool{false, false, true, false}, types.LBTopologyZone, "n1", "z1", zoneOf)
+	c.Assert(sel, comparator.DeepEquals, []bool{false, false, true, false})
+
+	// Without a known local zone only the local node is preferred
+	sel = preferLocalBackends(bes, []bool{false, true, true, true}, types.LBTopologyNode, "n1", "", zoneOf)
+	c.Assert(sel, comparator.DeepEquals, []bool{false, true, true, true})
+
+	sel = preferLocalBackends(bes, all, types.LBTopologyNone, "n1", "z1", zoneOf)
+	c.Assert(sel, comparator.DeepEquals, all)
+}
+
+func (ds *DaemonSuite) TestLBStatsBackend(c *C) {
+	fe, err := types.NewL3n4AddrID(types.TCP, net.ParseIP("10.0.0.1"), 80, 1)
+	c.Assert(err, IsNil)
+	be, err := types.NewLBBackEnd(types.TCP, net.ParseIP("10.0.1.1"), 8080, 0)
+	c.Assert(err, IsNil)
+	l3be, err := types.NewLBBackEnd(types.TCP, net.ParseIP("10.0.1.2"), 0, 0)
+	c.Assert(err, IsNil)
+
+	// Backends without a port are accounted on the port of the frontend
+	c.Assert(lbStatsBackend(fe, be), Equals, "10.0.1.1:8080")
+	c.Assert(lbStatsBackend(fe, l3be), Equals, "10.0.1.2:80")
+
+	stats := lbStats{1: {"10.0.1.2:80": {Packets: 2, Bytes: 100, Connections: 1}}}
+	c.Assert(stats.getBackendStatistics(fe, be), IsNil)
+	c.Assert(stats.getBackendStatistics(fe, l3be), comparator.DeepEquals,
+		&models.BackendStatistics{Packets: 2, Bytes: 100, Connections: 1})
+
+	var none lbStats
+	c.Assert(none.getBackendStatistics(fe, l3be), IsNil)
+}
+
+// withServiceMaps runs fn with the service maps backed by an in-memory map
+// backend.
+func withServiceMaps(c *C, fn func()) {
+	prev := bpf.SetMapBackend(bpf.NewMemoryBackend())
+	defer bpf.SetMapBackend(prev)
+
+	for _, m := range []*bpf.Map{
+		lbmap.Service4Map, lbmap.RevNat4Map, lbmap.RRSeq4Map, lbmap.Maglev4Map,
+		lbmap.Service6Map, lbmap.RevNat6Map, lbmap.RRSeq6Map, lbmap.Maglev6Map,
+		lbmap.AffinityMatchMap,
+	} {
+		_, err := m.OpenOrCreate()
+		c.Assert(err, IsNil)
+		defer m.Close()
+	}
+
+	fn()
+}
+
+func (ds *DaemonSuite) TestServiceMetadataRestore(c *C) {
+	withServiceMaps(c, func() { ds.testServiceMetadataRestore(c) })
+}
+
+func (ds *DaemonSuite) testServiceMetadataRestore(c *C) {
+	feAddr, err := types.NewL3n4Addr(types.TCP, net.ParseIP("10.96.0.10"), 80)
+	c.Assert(err, IsNil)
+	fe, err := PutL3n4Addr(*feAddr, 0)
+	c.Assert(err, IsNil)
+
+	be1, err := types.NewLBBackEnd(types.TCP, net.ParseIP("10.0.1.1"), 8080, 0)
+	c.Assert(err, IsNil)
+	be1.Name = "web-1"
+	be2, err := types.NewLBBackEnd(types.TCP, net.ParseIP("10.0.1.2"), 8080, 0)
+	c.Assert(err, IsNil)
+
+	name := types.LBSVCName{Namespace: "default", Name: "web"}
+	opts := types.LBSVCOptions{
+		Name:      name,
+		Labels:    map[string]string{"app": "web"},
+		Algorithm: types.LBAlgorithmHash,
+	}
+	_, err = ds.d.svcAdd(*fe, []types.LBBackEnd{*be1, *be2}, true, opts)
+	c.Assert(err, IsNil)
+	c.Assert(ds.d.svcGetByName(name), HasLen, 1)
+
+	// Restart with an empty load balancer and restore from the BPF maps
+	ds.d.loadBalancer = types.NewLoadBalancer()
+	c.Assert(ds.d.SyncLBMap(), IsNil)
+
+	svcs := ds.d.svcGetByName(name)
+	c.Assert(svcs, HasLen, 1)
+	c.Assert(svcs[0].Labels, comparator.DeepEquals, map[string]string{"app": "web"})
+	c.Assert(svcs[0].BES, HasLen, 2)
+	c.Assert(svcs[0].BES[0].Name, Equals, "web-1")
+	c.Assert(svcs[0].BES[1].Name, Equals, "")
+
+	// The metadata of deleted services is removed
+	c.Assert(ds.d.svcDeleteByFrontend(&fe.L3n4Addr), IsNil)
+	metadata, err := readServiceMetadata(ds.d.conf.StateDir)
+	c.Assert(err, IsNil)
+	c.Assert(metadata, HasLen, 0)
+}
+
+func (ds *DaemonSuite) TestTopologyAwareServiceSlaves(c *C) {
+	withServiceMaps(c, func() {
+		fe, err := types.NewL3n4AddrID(types.TCP, net.ParseIP("10.96.0.11"), 80, 1)
+		c.Assert(err, IsNil)
+
+		bes := []types.LBBackEnd{}
+		for i, nodeName := range []string{"remote", node.GetName(), "remote"} {
+			be, err := types.NewLBBackEnd(types.TCP, net.ParseIP(fmt.Sprintf("10.0.2.%d", i+1)), 8080, 0)
+			c.Assert(err, IsNil)
+			be.NodeName = nodeName
+			bes = append(bes, *be)
+		}
+
+		opts := types.LBSVCOptions{
+			Algorithm: types.LBAlgorithmHash,
+			Topology:  types.LBTopologyNode,
+		}
+		_, err = ds.d.svcAdd(*fe, bes, true, opts)
+		c.Assert(err, IsNil)
+
+		// Only the slave of the local backend may be selected by the
+		// datapath for new connections
+		key, _, err := lbmap.LBSVC2ServiceKeynValue(ds.d.loadBalancer.SVCMap[fe.SHA256Sum()])
+		c.Assert(err, IsNil)
+		for i, excluded := range []bool{true, false, true} {
+			key.SetBackend(i + 1)
+			slave, err := lbmap.LookupService(key)
+			c.Assert(err, IsNil)
+			c.Assert(slave.GetFlags()&lbmap.ServiceFlagExcluded != 0, Equals, excluded)
+		}
+
+		c.Assert(ds.d.svcDeleteByFrontend(&fe.L3n4Addr), IsNil)
+	})
+}
+
+func (ds *DaemonSuite) TestUDPServiceHealthCheck(c *C) {
+	svc := &models.Service{
+		ID: 1,
+		FrontendAddress: &models.FrontendAddress{
+			IP:       "10.0.0.1",
+			Port:     53,
+			Protocol: models.FrontendAddressProtocolUDP,
+		},
+		HealthCheck: &models.ServiceHealthCheck{
+			Type: models.ServiceHealthCheckTypeTCP,
+		},
+	}
+
+	_, _, code, err := newLBSVCFromModel(svc)
+	c.Assert(err, Not(IsNil))
+	c.Assert(code, Equals, service.PutServiceIDFailureCode)
+
+	svc.HealthCheck = nil
+	_, _, _, err = newLBSVCFromModel(svc)
+	c.Assert(err, IsNil)
+}
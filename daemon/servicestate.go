@@ -0,0 +1,175 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/daemon/defaults"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+const (
+	// serviceStateDir is the directory within the state directory in which
+	// the metadata of the services is stored
+	serviceStateDir = "services"
+
+	// serviceStateSuffix is the suffix of the file holding the metadata of
+	// a service, the file is named after the SHA256 sum of the frontend
+	serviceStateSuffix = ".json"
+)
+
+// serviceMetadata is the metadata of a service which is not stored in the BPF
+// maps and must be restored from the state directory after a restart.
+type serviceMetadata struct {
+	// Name is the name of the service
+	Name types.LBSVCName `json:"name"`
+
+	// Labels are the user defined labels of the service
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// BackendNames maps the address of each named backend to its name
+	BackendNames map[string]string `json:"backend-names,omitempty"`
+}
+
+// newServiceMetadata returns the metadata of svc or nil if svc has none.
+func newServiceMetadata(svc *types.LBSVC) *serviceMetadata {
+	m := &serviceMetadata{
+		Name:         svc.Name,
+		Labels:       svc.Labels,
+		BackendNames: map[string]string{},
+	}
+	for _, be := range svc.BES {
+		if be.Name != "" {
+			m.BackendNames[be.L3n4Addr.String()] = be.Name
+		}
+	}
+
+	if m.Name.IsEmpty() && len(m.Labels) == 0 && len(m.BackendNames) == 0 {
+		return nil
+	}
+	return m
+}
+
+// apply sets the name, labels and backend names of svc to the ones in m.
+func (m *serviceMetadata) apply(svc *types.LBSVC) {
+	svc.Name = m.Name
+	svc.Labels = m.Labels
+	for i := range svc.BES {
+		svc.BES[i].Name = m.BackendNames[svc.BES[i].L3n4Addr.String()]
+	}
+}
+
+func serviceStatePath(stateDir, sha256 string) string {
+	return filepath.Join(stateDir, serviceStateDir, sha256+serviceStateSuffix)
+}
+
+// writeServiceMetadata stores the metadata of svc in stateDir so that it can
+// be restored after a restart. The stored metadata is removed if svc has
+// none.
+func writeServiceMetadata(stateDir string, svc *types.LBSVC) error {
+	m := newServiceMetadata(svc)
+	if m == nil {
+		return deleteServiceMetadata(stateDir, svc.Sha256)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(stateDir, serviceStateDir)
+	if err := os.MkdirAll(dir, defaults.StateDirRights); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a crash does not leave a
+	// partially written file behind
+	path := serviceStatePath(stateDir, svc.Sha256)
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// deleteServiceMetadata removes the stored metadata of the service with the
+// given SHA256 sum of its frontend.
+func deleteServiceMetadata(stateDir, sha256 string) error {
+	err := os.Remove(serviceStatePath(stateDir, sha256))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// readServiceMetadata returns the metadata stored in stateDir indexed by the
+// SHA256 sum of the frontend of the services. Files which cannot be decoded
+// are skipped.
+func readServiceMetadata(stateDir string) (map[string]*serviceMetadata, error) {
+	files, err := ioutil.ReadDir(filepath.Join(stateDir, serviceStateDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]*serviceMetadata{}, nil
+		}
+		return nil, err
+	}
+
+	metadata := make(map[string]*serviceMetadata, len(files))
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), serviceStateSuffix) {
+			continue
+		}
+
+		path := filepath.Join(stateDir, serviceStateDir, f.Name())
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.WithError(err).WithField(logfields.Path, path).Warn("Unable to read service metadata")
+			continue
+		}
+
+		m := &serviceMetadata{}
+		if err := json.Unmarshal(b, m); err != nil {
+			log.WithError(err).WithField(logfields.Path, path).Warn("Unable to decode service metadata")
+			continue
+		}
+		metadata[strings.TrimSuffix(f.Name(), serviceStateSuffix)] = m
+	}
+
+	return metadata, nil
+}
+
+// updateServiceMetadata stores the metadata of svc in the state directory.
+// Failures are logged as the service itself remains functional.
+func (d *Daemon) updateServiceMetadata(svc *types.LBSVC) {
+	if err := writeServiceMetadata(d.conf.StateDir, svc); err != nil {
+		log.WithError(err).WithField(logfields.ServiceName, svc.FE.String()).
+			Warn("Unable to store service metadata, it will be lost on restart")
+	}
+}
+
+// removeServiceMetadata removes the stored metadata of svc from the state
+// directory.
+func (d *Daemon) removeServiceMetadata(svc *types.LBSVC) {
+	if err := deleteServiceMetadata(d.conf.StateDir, svc.Sha256); err != nil {
+		log.WithError(err).WithField(logfields.ServiceName, svc.FE.String()).
+			Warn("Unable to remove stored service metadata")
+	}
+}
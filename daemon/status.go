@@ -16,10 +16,12 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	. "github.com/cilium/cilium/api/v1/server/restapi/daemon"
 	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/endpointmanager"
 	"github.com/cilium/cilium/pkg/k8s"
 	"github.com/cilium/cilium/pkg/kvstore"
 	"github.com/cilium/cilium/pkg/node"
@@ -93,8 +95,10 @@ func (h *getHealthz) getNodeStatus() *models.ClusterStatus {
 	clusterStatus := models.ClusterStatus{
 		Self: local.Name,
 	}
-	for _, node := range node.GetNodes() {
-		clusterStatus.Nodes = append(clusterStatus.Nodes, node.GetModel(ipv4))
+	for ni, node := range node.GetNodes() {
+		model := node.GetModel(ipv4)
+		model.EncryptionKey = int64(h.daemon.getNodeEncryptionKey(ni))
+		clusterStatus.Nodes = append(clusterStatus.Nodes, model)
 	}
 	if len(clusterStatus.Nodes) == 0 {
 		return nil
@@ -115,7 +119,16 @@ func (h *getHealthz) getStatus(d *Daemon) models.StatusResponse {
 
 	checkLocks(d)
 
-	if info, err := kvstore.Client().Status(); err != nil {
+	kvstoreHealth := kvstore.GetHealthStatus()
+	if kvstoreHealth.Degraded {
+		// Avoid waiting for the status check to time out, the health
+		// check has already determined that the kvstore is unreachable
+		sr.Kvstore = &models.Status{
+			State: models.StatusStateWarning,
+			Msg: fmt.Sprintf("Degraded: unreachable since %s, serving cached identities - Err: %s",
+				kvstoreHealth.LastSuccess.Format(time.RFC3339), kvstoreHealth.LastError),
+		}
+	} else if info, err := kvstore.Client().Status(); err != nil {
 		sr.Kvstore = &models.Status{State: models.StatusStateFailure, Msg: fmt.Sprintf("Err: %s - %s", err, info)}
 	} else {
 		sr.Kvstore = &models.Status{State: models.StatusStateOk, Msg: info}
@@ -125,7 +138,12 @@ func (h *getHealthz) getStatus(d *Daemon) models.StatusResponse {
 
 	sr.Kubernetes = d.getK8sStatus()
 
-	if sr.Kvstore.State != models.StatusStateOk {
+	if kvstoreHealth.Degraded {
+		sr.Cilium = &models.Status{
+			State: sr.Kvstore.State,
+			Msg:   "Kvstore is unreachable, running in degraded mode",
+		}
+	} else if sr.Kvstore.State != models.StatusStateOk {
 		sr.Cilium = &models.Status{
 			State: sr.Kvstore.State,
 			Msg:   "Kvstore service is not ready",
@@ -153,5 +171,15 @@ func (h *getHealthz) getStatus(d *Daemon) models.StatusResponse {
 	sr.Cluster = h.getNodeStatus()
 	sr.Cluster.CiliumHealth = d.ciliumHealth.GetStatus()
 
+	sr.Conntrack = endpointmanager.GetConntrackStatus()
+
+	sr.Encryption = d.getEncryptionStatus()
+
+	if d.serviceFiles != nil {
+		sr.ServiceFiles = d.serviceFiles.Status()
+	} else {
+		sr.ServiceFiles = &models.ServiceFilesStatus{State: models.ServiceFilesStatusStateDisabled}
+	}
+
 	return sr
 }
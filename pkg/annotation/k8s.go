@@ -33,4 +33,13 @@ const (
 	// V6HealthName is the annotation name used to store the IPv6
 	// address of the cilium-health endpoint in the node's annotations.
 	V6HealthName = "io.cilium.network.ipv6-health-ip"
+
+	// ServiceTopology is the annotation name used to select the locality
+	// of the backends of a service which are preferred for new
+	// connections. Valid values are "none", "node" and "zone".
+	ServiceTopology = "io.cilium.service.topology"
+
+	// NodeZoneLabel is the well-known node label holding the zone the
+	// node is running in.
+	NodeZoneLabel = "failure-domain.beta.kubernetes.io/zone"
 )
@@ -0,0 +1,147 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"unsafe"
+)
+
+// MapBackend is the implementation of the operations on BPF maps. All map
+// operations of this package, including the ones of Map, are performed by
+// the backend in use. The default backend performs the bpf() system call and
+// operates on the BPF filesystem.
+type MapBackend interface {
+	// CreateMap creates a new map and returns its file descriptor
+	CreateMap(mapType int, keySize, valueSize, maxEntries, flags uint32) (int, error)
+
+	// OpenOrCreateMap opens the map pinned at path or creates and pins a
+	// new map if none exists. Returns true if the map was created.
+	OpenOrCreateMap(path string, mapType int, keySize, valueSize, maxEntries, flags uint32) (int, bool, error)
+
+	// UpdateElement writes value into the entry key of the map in fd
+	UpdateElement(fd int, key, value unsafe.Pointer, flags uint64) error
+
+	// LookupElement reads the value of the entry key of the map in fd
+	LookupElement(fd int, key, value unsafe.Pointer) error
+
+	// DeleteElement removes the entry key from the map in fd
+	DeleteElement(fd int, key unsafe.Pointer) error
+
+	// GetNextKey stores the key following key of the map in fd in nextKey
+	GetNextKey(fd int, key, nextKey unsafe.Pointer) error
+
+	// ObjPin pins the object in fd at pathname
+	ObjPin(fd int, pathname string) error
+
+	// ObjGet opens the object pinned at pathname
+	ObjGet(pathname string) (int, error)
+
+	// ObjClose closes fd
+	ObjClose(fd int) error
+
+	// Unpin removes the pinned object at pathname
+	Unpin(pathname string) error
+
+	// ListPinned returns the paths of all objects pinned in the directory
+	// dir
+	ListPinned(dir string) ([]string, error)
+
+	// GetMapInfo returns the attributes of the map in fd of process pid
+	GetMapInfo(pid int, fd int) (*MapInfo, error)
+}
+
+// syscallBackend is the MapBackend operating on the kernel via the bpf()
+// system call.
+type syscallBackend struct{}
+
+// backend is the MapBackend in use
+var backend MapBackend = syscallBackend{}
+
+// SetMapBackend replaces the MapBackend in use and returns the previous one.
+// It must be called before any map is opened, typically by unit tests to
+// switch to a MemoryBackend.
+func SetMapBackend(b MapBackend) MapBackend {
+	old := backend
+	backend = b
+	return old
+}
+
+// CreateMap creates a Map of type mapType, with key size keySize, a value size of
+// valueSize and the maximum amount of entries of maxEntries.
+// mapType should be one of the bpf_map_type in "uapi/linux/bpf.h"
+func CreateMap(mapType int, keySize, valueSize, maxEntries, flags uint32) (int, error) {
+	return backend.CreateMap(mapType, keySize, valueSize, maxEntries, flags)
+}
+
+// OpenOrCreateMap opens the map pinned at path or creates and pins a new map
+// if none exists. 'bool' returns 'true' if the map was created.
+func OpenOrCreateMap(path string, mapType int, keySize, valueSize, maxEntries, flags uint32) (int, bool, error) {
+	return backend.OpenOrCreateMap(path, mapType, keySize, valueSize, maxEntries, flags)
+}
+
+// UpdateElement updates the map in fd with the given value in the given key.
+// The flags can have the following values:
+// bpf.BPF_ANY to create new element or update existing;
+// bpf.BPF_NOEXIST to create new element if it didn't exist;
+// bpf.BPF_EXIST to update existing element.
+func UpdateElement(fd int, key, value unsafe.Pointer, flags uint64) error {
+	return backend.UpdateElement(fd, key, value, flags)
+}
+
+// LookupElement looks up for the map value stored in fd with the given key. The value
+// is stored in the value unsafe.Pointer.
+func LookupElement(fd int, key, value unsafe.Pointer) error {
+	return backend.LookupElement(fd, key, value)
+}
+
+// DeleteElement deletes the map element with the given key.
+func DeleteElement(fd int, key unsafe.Pointer) error {
+	return backend.DeleteElement(fd, key)
+}
+
+// GetNextKey stores, in nextKey, the next key after the key of the map in fd.
+func GetNextKey(fd int, key, nextKey unsafe.Pointer) error {
+	return backend.GetNextKey(fd, key, nextKey)
+}
+
+// ObjPin stores the map's fd in pathname.
+func ObjPin(fd int, pathname string) error {
+	return backend.ObjPin(fd, pathname)
+}
+
+// ObjGet reads the pathname and returns the map's fd read.
+func ObjGet(pathname string) (int, error) {
+	return backend.ObjGet(pathname)
+}
+
+// ObjClose closes the map's fd.
+func ObjClose(fd int) error {
+	return backend.ObjClose(fd)
+}
+
+// Unpin removes the pinned object at pathname.
+func Unpin(pathname string) error {
+	return backend.Unpin(pathname)
+}
+
+// ListPinned returns the paths of all objects pinned in the directory dir.
+func ListPinned(dir string) ([]string, error) {
+	return backend.ListPinned(dir)
+}
+
+// GetMapInfo returns the attributes of the map in fd of process pid.
+func GetMapInfo(pid int, fd int) (*MapInfo, error) {
+	return backend.GetMapInfo(pid, fd)
+}
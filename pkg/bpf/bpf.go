@@ -19,6 +19,7 @@ import "C"
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math"
 	"os"
 	"path/filepath"
@@ -79,7 +80,7 @@ const (
 // CreateMap creates a Map of type mapType, with key size keySize, a value size of
 // valueSize and the maximum amount of entries of maxEntries.
 // mapType should be one of the bpf_map_type in "uapi/linux/bpf.h"
-func CreateMap(mapType int, keySize, valueSize, maxEntries, flags uint32) (int, error) {
+func (s syscallBackend) CreateMap(mapType int, keySize, valueSize, maxEntries, flags uint32) (int, error) {
 	// This struct must be in sync with union bpf_attr's anonymous struct
 	// used by the BPF_MAP_CREATE command
 	uba := struct {
@@ -109,6 +110,27 @@ func CreateMap(mapType int, keySize, valueSize, maxEntries, flags uint32) (int,
 	return 0, fmt.Errorf("Unable to create map: %s", err)
 }
 
+// Unpin removes the pinned object at pathname.
+func (s syscallBackend) Unpin(pathname string) error {
+	return os.Remove(pathname)
+}
+
+// ListPinned returns the paths of all objects pinned in the directory dir.
+func (s syscallBackend) ListPinned(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		if !f.IsDir() {
+			paths = append(paths, filepath.Join(dir, f.Name()))
+		}
+	}
+	return paths, nil
+}
+
 // This struct must be in sync with union bpf_attr's anonymous struct used by
 // BPF_MAP_*_ELEM commands
 type bpfAttrMapOpElem struct {
@@ -124,7 +146,7 @@ type bpfAttrMapOpElem struct {
 // bpf.BPF_ANY to create new element or update existing;
 // bpf.BPF_NOEXIST to create new element if it didn't exist;
 // bpf.BPF_EXIST to update existing element.
-func UpdateElement(fd int, key, value unsafe.Pointer, flags uint64) error {
+func (s syscallBackend) UpdateElement(fd int, key, value unsafe.Pointer, flags uint64) error {
 	uba := bpfAttrMapOpElem{
 		mapFd: uint32(fd),
 		key:   uint64(uintptr(key)),
@@ -148,7 +170,7 @@ func UpdateElement(fd int, key, value unsafe.Pointer, flags uint64) error {
 
 // LookupElement looks up for the map value stored in fd with the given key. The value
 // is stored in the value unsafe.Pointer.
-func LookupElement(fd int, key, value unsafe.Pointer) error {
+func (s syscallBackend) LookupElement(fd int, key, value unsafe.Pointer) error {
 	uba := bpfAttrMapOpElem{
 		mapFd: uint32(fd),
 		key:   uint64(uintptr(key)),
@@ -170,7 +192,7 @@ func LookupElement(fd int, key, value unsafe.Pointer) error {
 }
 
 // DeleteElement deletes the map element with the given key.
-func DeleteElement(fd int, key unsafe.Pointer) error {
+func (s syscallBackend) DeleteElement(fd int, key unsafe.Pointer) error {
 	uba := bpfAttrMapOpElem{
 		mapFd: uint32(fd),
 		key:   uint64(uintptr(key)),
@@ -190,7 +212,7 @@ func DeleteElement(fd int, key unsafe.Pointer) error {
 }
 
 // GetNextKey stores, in nextKey, the next key after the key of the map in fd.
-func GetNextKey(fd int, key, nextKey unsafe.Pointer) error {
+func (s syscallBackend) GetNextKey(fd int, key, nextKey unsafe.Pointer) error {
 	uba := bpfAttrMapOpElem{
 		mapFd: uint32(fd),
 		key:   uint64(uintptr(key)),
@@ -219,7 +241,7 @@ type bpfAttrObjOp struct {
 }
 
 // ObjPin stores the map's fd in pathname.
-func ObjPin(fd int, pathname string) error {
+func (s syscallBackend) ObjPin(fd int, pathname string) error {
 	pathStr := C.CString(pathname)
 	defer C.free(unsafe.Pointer(pathStr))
 	uba := bpfAttrObjOp{
@@ -242,7 +264,7 @@ func ObjPin(fd int, pathname string) error {
 }
 
 // ObjGet reads the pathname and returns the map's fd read.
-func ObjGet(pathname string) (int, error) {
+func (s syscallBackend) ObjGet(pathname string) (int, error) {
 	pathStr := C.CString(pathname)
 	defer C.free(unsafe.Pointer(pathStr))
 	uba := bpfAttrObjOp{
@@ -264,14 +286,16 @@ func ObjGet(pathname string) (int, error) {
 }
 
 // ObjClose closes the map's fd.
-func ObjClose(fd int) error {
+func (s syscallBackend) ObjClose(fd int) error {
 	if fd > 0 {
 		return unix.Close(fd)
 	}
 	return nil
 }
 
-func OpenOrCreateMap(path string, mapType int, keySize, valueSize, maxEntries, flags uint32) (int, bool, error) {
+// OpenOrCreateMap opens the map pinned at path or creates and pins a new map
+// if none exists.
+func (s syscallBackend) OpenOrCreateMap(path string, mapType int, keySize, valueSize, maxEntries, flags uint32) (int, bool, error) {
 	var fd int
 
 	isNewMap := false
@@ -297,7 +321,7 @@ func OpenOrCreateMap(path string, mapType int, keySize, valueSize, maxEntries, f
 			}
 		}
 
-		fd, err = CreateMap(
+		fd, err = s.CreateMap(
 			mapType,
 			keySize,
 			valueSize,
@@ -309,7 +333,7 @@ func OpenOrCreateMap(path string, mapType int, keySize, valueSize, maxEntries, f
 			if err != nil {
 				// In case of error, we need to close
 				// this fd since it was open by CreateMap
-				ObjClose(fd)
+				s.ObjClose(fd)
 			}
 		}()
 
@@ -319,7 +343,7 @@ func OpenOrCreateMap(path string, mapType int, keySize, valueSize, maxEntries, f
 			return 0, isNewMap, err
 		}
 
-		err = ObjPin(fd, path)
+		err = s.ObjPin(fd, path)
 		if err != nil {
 			return 0, isNewMap, err
 		}
@@ -327,7 +351,7 @@ func OpenOrCreateMap(path string, mapType int, keySize, valueSize, maxEntries, f
 		return fd, isNewMap, nil
 	}
 
-	fd, err = ObjGet(path)
+	fd, err = s.ObjGet(path)
 	return fd, isNewMap, err
 }
 
@@ -26,7 +26,6 @@ import (
 	"github.com/cilium/cilium/pkg/logging/logfields"
 
 	"github.com/sirupsen/logrus"
-	"golang.org/x/sys/unix"
 )
 
 // MapType is an enumeration for valid BPF map types
@@ -116,6 +115,25 @@ type Map struct {
 	// NonPersistent is true if the map does not contain persistent data
 	// and should be removed on startup.
 	NonPersistent bool
+
+	// VolatileValues is true if the datapath modifies the values of the
+	// map entries, e.g. to maintain counters. The reconciliation of a
+	// cached map then only restores missing entries.
+	VolatileValues bool
+
+	// MigrateEntries is true if the entries of the pinned map are copied
+	// into a new map when the pinned map was created with a different
+	// maximum number of entries or flags. Otherwise, only empty maps are
+	// recreated on such a mismatch.
+	MigrateEntries bool
+
+	// cache is the desired state of the map, it is nil unless the map
+	// was created WithCache(). Protected by lock.
+	cache map[string]*cacheEntry
+
+	// outstandingErrors is the number of cache entries which failed to
+	// sync. Protected by lock.
+	outstandingErrors int
 }
 
 func NewMap(name string, mapType MapType, keySize int, valueSize int, maxEntries int, flags uint32) *Map {
@@ -130,6 +148,9 @@ func NewMap(name string, mapType MapType, keySize int, valueSize int, maxEntries
 		},
 		name: path.Base(name),
 	}
+	if path.IsAbs(name) {
+		m.path = name
+	}
 	m.setPathIfUnset()
 	return m
 }
@@ -144,6 +165,16 @@ func (m *Map) GetFd() int {
 	return m.fd
 }
 
+// Name returns the basename of the map
+func (m *Map) Name() string {
+	return m.name
+}
+
+// Path returns the path of the map in the BPF filesystem
+func (m *Map) Path() string {
+	return m.path
+}
+
 // DeepEquals compares the current map against another map to see that the
 // attributes of the two maps are the same.
 func (m *Map) DeepEquals(other *Map) bool {
@@ -153,7 +184,9 @@ func (m *Map) DeepEquals(other *Map) bool {
 		m.NonPersistent == other.NonPersistent
 }
 
-func GetMapInfo(pid int, fd int) (*MapInfo, error) {
+// GetMapInfo parses the attributes of the map in fd of process pid from
+// procfs.
+func (s syscallBackend) GetMapInfo(pid int, fd int) (*MapInfo, error) {
 
 	fdinfoFile := fmt.Sprintf("/proc/%d/fdinfo/%d", pid, fd)
 
@@ -237,14 +270,21 @@ func (m *Map) setPathIfUnset() error {
 	return nil
 }
 
-func (m *Map) migrate(fd int) (bool, error) {
+// migrate compares the attributes of the map pinned at the path of m, opened
+// in fd, against the attributes of m. On a mismatch, the pinned map is removed
+// if it is empty and retry is returned as true so that the map is recreated.
+// If m.MigrateEntries is set and the maps only differ in their maximum number
+// of entries or flags, the pinned map is removed as well and migrateFd is
+// returned as fd so that its entries can be copied into the recreated map.
+func (m *Map) migrate(fd int) (retry bool, migrateFd int, err error) {
 	info, err := GetMapInfo(os.Getpid(), fd)
 	if err != nil {
-		return false, nil
+		return false, 0, nil
 	}
 
 	scopedLog := log.WithField(logfields.Path, m.path)
 	mismatch := false
+	compatible := true
 
 	if info.MapType != m.MapType {
 		scopedLog.WithFields(logrus.Fields{
@@ -252,6 +292,7 @@ func (m *Map) migrate(fd int) (bool, error) {
 			"new": m.MapType,
 		}).Info("Map type mismatch for BPF map")
 		mismatch = true
+		compatible = false
 	}
 
 	if info.KeySize != m.KeySize {
@@ -260,6 +301,7 @@ func (m *Map) migrate(fd int) (bool, error) {
 			"new": m.KeySize,
 		}).Info("Key-size mismatch for BPF map")
 		mismatch = true
+		compatible = false
 	}
 
 	if info.ValueSize != m.ValueSize {
@@ -268,6 +310,7 @@ func (m *Map) migrate(fd int) (bool, error) {
 			"new": m.ValueSize,
 		}).Info("Value-size mismatch for BPF map")
 		mismatch = true
+		compatible = false
 	}
 
 	if info.MaxEntries != m.MaxEntries {
@@ -286,17 +329,46 @@ func (m *Map) migrate(fd int) (bool, error) {
 		mismatch = true
 	}
 	if mismatch {
-		b, err := m.containsEntries()
+		b, err := containsEntries(fd, info)
 		if err == nil && !b {
 			scopedLog.Info("Safely removing empty map so it can be recreated")
-			os.Remove(m.path)
-			return true, nil
+			Unpin(m.path)
+			return true, 0, nil
+		}
+
+		if err == nil && compatible && m.MigrateEntries {
+			scopedLog.Info("Recreating map and migrating its entries")
+			Unpin(m.path)
+			return true, fd, nil
 		}
 
-		return false, fmt.Errorf("could not resolve BPF map mismatch (see log for details)")
+		return false, 0, fmt.Errorf("could not resolve BPF map mismatch (see log for details)")
 	}
 
-	return false, nil
+	return false, 0, nil
+}
+
+// migrateEntries copies all entries of the map in fromFd into the map in fd.
+// Entries which do not fit into the map in fd are dropped. Returns the number
+// of copied and dropped entries.
+func (m *Map) migrateEntries(fromFd, fd int) (copied, dropped int, err error) {
+	key := make([]byte, m.KeySize)
+	nextKey := make([]byte, m.KeySize)
+	value := make([]byte, m.ValueSize)
+
+	for GetNextKey(fromFd, unsafe.Pointer(&key[0]), unsafe.Pointer(&nextKey[0])) == nil {
+		if err := LookupElement(fromFd, unsafe.Pointer(&nextKey[0]), unsafe.Pointer(&value[0])); err != nil {
+			return copied, dropped, err
+		}
+		if err := UpdateElement(fd, unsafe.Pointer(&nextKey[0]), unsafe.Pointer(&value[0]), BPF_ANY); err != nil {
+			dropped++
+		} else {
+			copied++
+		}
+		copy(key, nextKey)
+	}
+
+	return copied, dropped, nil
 }
 
 func (m *Map) OpenOrCreate() (bool, error) {
@@ -314,28 +386,50 @@ func (m *Map) OpenOrCreate() (bool, error) {
 	// If the map represents non-persistent data, always remove the map
 	// before opening or creating.
 	if m.NonPersistent {
-		os.Remove(m.path)
+		Unpin(m.path)
 	}
 
+	migrateFd := 0
+
 reopen:
 	fd, isNew, err := OpenOrCreateMap(m.path, int(m.MapType), m.KeySize, m.ValueSize, m.MaxEntries, m.Flags)
 	if err != nil {
+		if migrateFd != 0 {
+			ObjClose(migrateFd)
+		}
 		return false, err
 	}
 
 	// Only persistent maps need to be migrated, non-persistent maps will
 	// have been deleted above before opening.
-	if !m.NonPersistent {
-		if retry, err := m.migrate(fd); err != nil {
+	if !m.NonPersistent && migrateFd == 0 {
+		if retry, oldFd, err := m.migrate(fd); err != nil {
 			if isNew {
-				os.Remove(m.path)
+				Unpin(m.path)
 			}
 			return false, err
 		} else if retry {
+			migrateFd = oldFd
 			goto reopen
 		}
 	}
+
+	if migrateFd != 0 {
+		scopedLog := log.WithField(logfields.Path, m.path)
+		copied, dropped, err := m.migrateEntries(migrateFd, fd)
+		ObjClose(migrateFd)
+		if err != nil {
+			scopedLog.WithError(err).Warn("Unable to migrate all entries of BPF map")
+		}
+		if dropped > 0 {
+			scopedLog.WithField("dropped", dropped).Warn("Entries of BPF map did not fit into the recreated map")
+		}
+		scopedLog.WithField("entries", copied).Info("Migrated entries of BPF map")
+		isNew = false
+	}
+
 	m.fd = fd
+	m.registerCache()
 
 	return isNew, nil
 }
@@ -358,16 +452,19 @@ func (m *Map) Open() error {
 		}
 
 		m.fd = fd
+		m.registerCache()
 	})
 	return err
 }
 
 func (m *Map) Close() error {
+	m.unregisterCache()
+
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
 	if m.fd != 0 {
-		unix.Close(m.fd)
+		ObjClose(m.fd)
 		m.fd = 0
 	}
 
@@ -425,15 +522,15 @@ func (m *Map) Dump(parser DumpParser, cb DumpCallback) error {
 	return nil
 }
 
-// containsEntries returns true if the map contains at least one entry
-// must hold map mutex
-func (m *Map) containsEntries() (bool, error) {
-	key := make([]byte, m.KeySize)
-	nextKey := make([]byte, m.KeySize)
-	value := make([]byte, m.ValueSize)
+// containsEntries returns true if the map in fd, which has the attributes
+// info, contains at least one entry
+func containsEntries(fd int, info *MapInfo) (bool, error) {
+	key := make([]byte, info.KeySize)
+	nextKey := make([]byte, info.KeySize)
+	value := make([]byte, info.ValueSize)
 
 	err := GetNextKey(
-		m.fd,
+		fd,
 		unsafe.Pointer(&key[0]),
 		unsafe.Pointer(&nextKey[0]),
 	)
@@ -443,7 +540,7 @@ func (m *Map) containsEntries() (bool, error) {
 	}
 
 	err = LookupElement(
-		m.fd,
+		fd,
 		unsafe.Pointer(&nextKey[0]),
 		unsafe.Pointer(&value[0]),
 	)
@@ -472,26 +569,41 @@ func (m *Map) Lookup(key MapKey) (MapValue, error) {
 	return value, nil
 }
 
+// Update writes the entry key/value into the map. If the map is cached,
+// the entry is recorded in the desired state and retried in the background
+// on failure.
 func (m *Map) Update(key MapKey, value MapValue) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	if err := m.Open(); err != nil {
-		return err
+	err := m.Open()
+	if err == nil {
+		err = UpdateElement(m.fd, key.GetKeyPtr(), value.GetValuePtr(), 0)
+	}
+
+	if m.cache != nil {
+		m.cacheUpdate(key, value, err)
 	}
 
-	return UpdateElement(m.fd, key.GetKeyPtr(), value.GetValuePtr(), 0)
+	return err
 }
 
+// Delete removes the entry key from the map. If the map is cached, a failed
+// deletion is retried in the background.
 func (m *Map) Delete(key MapKey) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	if err := m.Open(); err != nil {
-		return err
+	err := m.Open()
+	if err == nil {
+		err = DeleteElement(m.fd, key.GetKeyPtr())
 	}
 
-	return DeleteElement(m.fd, key.GetKeyPtr())
+	if m.cache != nil {
+		m.cacheDelete(key, err)
+	}
+
+	return err
 }
 
 // DeleteAll deletes all entries of a map by traversing the map and deleting individual
@@ -508,6 +620,10 @@ func (m *Map) DeleteAll() error {
 		return err
 	}
 
+	if m.cache != nil {
+		m.cacheFlush()
+	}
+
 	for {
 		err := GetNextKey(
 			m.fd,
@@ -0,0 +1,410 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+	"unsafe"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+const (
+	// cacheSyncInterval is the interval in which the kernel state of cached
+	// maps is reconciled against the desired state.
+	cacheSyncInterval = time.Minute
+
+	// cacheSyncRetryBase is the initial time to wait before retrying the
+	// synchronization of cache entries which failed to sync.
+	cacheSyncRetryBase = 5 * time.Second
+)
+
+var (
+	// cacheControllers runs the reconciliation of all cached maps
+	cacheControllers = controller.NewManager()
+
+	cachedMapsMutex lock.RWMutex
+
+	// cachedMaps contains all open cached maps indexed by path
+	cachedMaps = map[string]*Map{}
+)
+
+// DesiredAction is the action to be performed on a map entry in order to
+// bring the kernel map in sync with the desired state.
+type DesiredAction int
+
+const (
+	// OK indicates that the entry is in sync with the kernel map
+	OK DesiredAction = iota
+
+	// Insert indicates that the entry must be inserted into the kernel map
+	Insert
+
+	// Delete indicates that the entry must be removed from the kernel map
+	Delete
+)
+
+func (d DesiredAction) String() string {
+	switch d {
+	case OK:
+		return models.BPFMapEntryDesiredActionOk
+	case Insert:
+		return models.BPFMapEntryDesiredActionInsert
+	case Delete:
+		return models.BPFMapEntryDesiredActionDelete
+	}
+
+	return "unknown"
+}
+
+// cacheEntry is the desired state of a single entry of a cached map
+type cacheEntry struct {
+	// key and value are the raw bytes of the entry as written to the
+	// kernel map
+	key   []byte
+	value []byte
+
+	// keyString and valueString are the human readable representations
+	// of the key and value
+	keyString   string
+	valueString string
+
+	DesiredAction DesiredAction
+	LastError     error
+}
+
+// WithCache enables the userspace cache of the map and returns the map. The
+// cache holds the desired state of all entries written through Update() and
+// Delete(). Entries which failed to sync and entries which were modified
+// outside of the agent are reconciled in the background.
+func (m *Map) WithCache() *Map {
+	m.cache = map[string]*cacheEntry{}
+	return m
+}
+
+// HasCache returns true if the userspace cache of the map is enabled
+func (m *Map) HasCache() bool {
+	return m.cache != nil
+}
+
+// bytesOf returns a copy of the size bytes starting at ptr
+func bytesOf(ptr unsafe.Pointer, size uint32) []byte {
+	b := make([]byte, size)
+	copy(b, (*[1 << 30]byte)(ptr)[:size:size])
+	return b
+}
+
+// stringOf returns the human readable representation of a map key or value
+func stringOf(v interface{}) string {
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%+v", v)
+}
+
+// errorString returns the message of err or an empty string if err is nil
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// cacheControllerName returns the name of the controller reconciling the map
+func (m *Map) cacheControllerName() string {
+	return fmt.Sprintf("bpf-map-sync-%s", m.name)
+}
+
+// registerCache makes the cached map available to GetMap() and starts the
+// periodic reconciliation. Must be called with m.lock held.
+func (m *Map) registerCache() {
+	if m.cache == nil {
+		return
+	}
+
+	cachedMapsMutex.Lock()
+	cachedMaps[m.path] = m
+	cachedMapsMutex.Unlock()
+
+	m.scheduleCacheSync()
+}
+
+// unregisterCache stops the reconciliation of the map and removes it from
+// the list of cached maps.
+func (m *Map) unregisterCache() {
+	if m.cache == nil {
+		return
+	}
+
+	cachedMapsMutex.Lock()
+	if cachedMaps[m.path] == m {
+		delete(cachedMaps, m.path)
+	}
+	cachedMapsMutex.Unlock()
+
+	cacheControllers.RemoveController(m.cacheControllerName())
+}
+
+// scheduleCacheSync (re)starts the controller reconciling the map. The
+// controller runs immediately and then periodically.
+func (m *Map) scheduleCacheSync() {
+	cacheControllers.UpdateController(m.cacheControllerName(),
+		controller.ControllerParams{
+			DoFunc:                 m.syncCache,
+			RunInterval:            cacheSyncInterval,
+			ErrorRetryBaseDuration: cacheSyncRetryBase,
+		})
+}
+
+// setCacheError records the result of a sync attempt of entry e and
+// maintains the number of outstanding errors. Returns true if the map
+// previously had no outstanding errors and now has one. Must be called with
+// m.lock held.
+func (m *Map) setCacheError(e *cacheEntry, err error) bool {
+	if e.LastError != nil {
+		m.outstandingErrors--
+	}
+	e.LastError = err
+	if err != nil {
+		m.outstandingErrors++
+		return m.outstandingErrors == 1
+	}
+	return false
+}
+
+// cacheUpdate records the desired state of an updated entry along with the
+// result of the update. Must be called with m.lock held.
+func (m *Map) cacheUpdate(key MapKey, value MapValue, err error) {
+	k := bytesOf(key.GetKeyPtr(), m.KeySize)
+	e, ok := m.cache[string(k)]
+	if !ok {
+		e = &cacheEntry{key: k}
+		m.cache[string(k)] = e
+	}
+
+	e.value = bytesOf(value.GetValuePtr(), m.ValueSize)
+	e.keyString = stringOf(key)
+	e.valueString = stringOf(value)
+	e.DesiredAction = OK
+	if err != nil {
+		e.DesiredAction = Insert
+	}
+
+	if m.setCacheError(e, err) {
+		m.scheduleCacheSync()
+	}
+}
+
+// cacheDelete records the removal of an entry along with the result of the
+// deletion. Must be called with m.lock held.
+func (m *Map) cacheDelete(key MapKey, err error) {
+	k := bytesOf(key.GetKeyPtr(), m.KeySize)
+	e, ok := m.cache[string(k)]
+	if err == nil {
+		if ok {
+			m.setCacheError(e, nil)
+			delete(m.cache, string(k))
+		}
+		return
+	}
+
+	if !ok {
+		e = &cacheEntry{key: k, keyString: stringOf(key)}
+		m.cache[string(k)] = e
+	}
+	e.value = nil
+	e.valueString = ""
+	e.DesiredAction = Delete
+
+	if m.setCacheError(e, err) {
+		m.scheduleCacheSync()
+	}
+}
+
+// cacheFlush removes all entries from the cache. Must be called with m.lock
+// held.
+func (m *Map) cacheFlush() {
+	m.cache = map[string]*cacheEntry{}
+	m.outstandingErrors = 0
+}
+
+// syncCache reconciles the kernel map against the desired state held in the
+// cache. Entries missing in the kernel map, entries with a diverging value
+// and entries which failed to sync are written again, entries pending
+// deletion are removed. Entries of the kernel map which are unknown to the
+// cache are left untouched. Returns an error if any entry remains out of
+// sync.
+func (m *Map) syncCache() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.cache == nil || m.fd == 0 {
+		return nil
+	}
+
+	scopedLog := log.WithField(logfields.Path, m.path)
+	value := make([]byte, m.ValueSize)
+	repaired, failed := 0, 0
+
+	for k, e := range m.cache {
+		present := LookupElement(m.fd, unsafe.Pointer(&e.key[0]), unsafe.Pointer(&value[0])) == nil
+
+		var err error
+		switch e.DesiredAction {
+		case Delete:
+			if present {
+				err = DeleteElement(m.fd, unsafe.Pointer(&e.key[0]))
+			}
+			if err == nil {
+				m.setCacheError(e, nil)
+				delete(m.cache, k)
+				continue
+			}
+		default:
+			if present && e.DesiredAction == OK &&
+				(m.VolatileValues || bytes.Equal(value, e.value)) {
+				continue
+			}
+			if e.DesiredAction == OK {
+				scopedLog.WithField(logfields.BPFMapKey, e.keyString).
+					Debug("BPF map entry modified outside of the agent, restoring it")
+				repaired++
+			}
+			err = UpdateElement(m.fd, unsafe.Pointer(&e.key[0]), unsafe.Pointer(&e.value[0]), 0)
+			if err == nil {
+				e.DesiredAction = OK
+			} else {
+				e.DesiredAction = Insert
+			}
+		}
+
+		m.setCacheError(e, err)
+		if err != nil {
+			failed++
+		}
+	}
+
+	if repaired > 0 {
+		scopedLog.WithField("entries", repaired).
+			Warning("Restored BPF map entries which were modified outside of the agent")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d entries of BPF map %s failed to sync", failed, m.name)
+	}
+
+	return nil
+}
+
+// DumpCache parses all entries of the desired state of a cached map using
+// parser and invokes cb for each of them. Entries pending deletion are
+// skipped. Unlike Dump(), the kernel map is not accessed.
+func (m *Map) DumpCache(parser DumpParser, cb DumpCallback) error {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.cache == nil {
+		return fmt.Errorf("map %s is not cached", m.name)
+	}
+
+	for _, e := range m.cache {
+		if e.DesiredAction == Delete {
+			continue
+		}
+
+		k, v, err := parser(e.key, e.value)
+		if err != nil {
+			return err
+		}
+
+		if cb != nil {
+			cb(k, v)
+		}
+	}
+
+	return nil
+}
+
+// LookupCache returns the desired value of key of a cached map. Unlike
+// Lookup(), the kernel map is not accessed.
+func (m *Map) LookupCache(key MapKey) (MapValue, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.cache == nil {
+		return nil, fmt.Errorf("map %s is not cached", m.name)
+	}
+
+	e, ok := m.cache[string(bytesOf(key.GetKeyPtr(), m.KeySize))]
+	if !ok || e.DesiredAction == Delete {
+		return nil, fmt.Errorf("key %s not found in map %s", stringOf(key), m.name)
+	}
+
+	value := key.NewValue()
+	copy((*[1 << 30]byte)(value.GetValuePtr())[:m.ValueSize:m.ValueSize], e.value)
+	return value, nil
+}
+
+// GetModel returns the API model of the cached map
+func (m *Map) GetModel() *models.BPFMap {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	mapModel := &models.BPFMap{
+		Path:  m.path,
+		Cache: make([]*models.BPFMapEntry, 0, len(m.cache)),
+	}
+
+	for _, e := range m.cache {
+		mapModel.Cache = append(mapModel.Cache, &models.BPFMapEntry{
+			Key:           e.keyString,
+			Value:         e.valueString,
+			DesiredAction: e.DesiredAction.String(),
+			LastError:     errorString(e.LastError),
+		})
+	}
+
+	sort.Slice(mapModel.Cache, func(i, j int) bool {
+		return mapModel.Cache[i].Key < mapModel.Cache[j].Key
+	})
+
+	return mapModel
+}
+
+// GetMap returns the open cached map with the given name or path, or nil if
+// no such map exists.
+func GetMap(name string) *Map {
+	cachedMapsMutex.RLock()
+	defer cachedMapsMutex.RUnlock()
+
+	if m, ok := cachedMaps[name]; ok {
+		return m
+	}
+
+	for _, m := range cachedMaps {
+		if m.name == path.Base(name) {
+			return m
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"unsafe"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/byteorder"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type BPFSuite struct{}
+
+var _ = Suite(&BPFSuite{})
+
+type testKey struct {
+	Key uint32
+}
+
+type testValue struct {
+	Value uint32
+}
+
+func (k *testKey) GetKeyPtr() unsafe.Pointer     { return unsafe.Pointer(k) }
+func (k *testKey) NewValue() MapValue            { return &testValue{} }
+func (k *testKey) String() string                { return fmt.Sprintf("key=%d", k.Key) }
+func (v *testValue) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(v) }
+func (v *testValue) String() string              { return fmt.Sprintf("value=%d", v.Value) }
+
+func testDumpParser(key []byte, value []byte) (MapKey, MapValue, error) {
+	k, v := testKey{}, testValue{}
+	if err := binary.Read(bytes.NewBuffer(key), byteorder.Native, &k); err != nil {
+		return nil, nil, err
+	}
+	if err := binary.Read(bytes.NewBuffer(value), byteorder.Native, &v); err != nil {
+		return nil, nil, err
+	}
+	return &k, &v, nil
+}
+
+func newTestCachedMap() *Map {
+	return NewMap("cilium_test",
+		MapTypeHash,
+		int(unsafe.Sizeof(testKey{})),
+		int(unsafe.Sizeof(testValue{})),
+		16, 0).WithCache()
+}
+
+func (s *BPFSuite) TestNewMapPath(c *C) {
+	m := NewMap("/sys/fs/bpf/tc/globals/cilium_test", MapTypeHash, 4, 4, 16, 0)
+	c.Assert(m.Name(), Equals, "cilium_test")
+	c.Assert(m.Path(), Equals, "/sys/fs/bpf/tc/globals/cilium_test")
+	c.Assert(m.HasCache(), Equals, false)
+}
+
+func (s *BPFSuite) TestCache(c *C) {
+	m := newTestCachedMap()
+	defer m.Close()
+	c.Assert(m.HasCache(), Equals, true)
+
+	m.lock.Lock()
+	m.cacheUpdate(&testKey{Key: 1}, &testValue{Value: 10}, nil)
+	m.cacheUpdate(&testKey{Key: 2}, &testValue{Value: 20}, fmt.Errorf("update failed"))
+	m.cacheDelete(&testKey{Key: 3}, fmt.Errorf("delete failed"))
+	c.Assert(m.outstandingErrors, Equals, 2)
+	m.lock.Unlock()
+
+	value, err := m.LookupCache(&testKey{Key: 2})
+	c.Assert(err, IsNil)
+	c.Assert(value, DeepEquals, &testValue{Value: 20})
+
+	// Entries pending deletion are not part of the desired state
+	_, err = m.LookupCache(&testKey{Key: 3})
+	c.Assert(err, Not(IsNil))
+
+	dump := map[uint32]uint32{}
+	err = m.DumpCache(testDumpParser, func(key MapKey, value MapValue) {
+		dump[key.(*testKey).Key] = value.(*testValue).Value
+	})
+	c.Assert(err, IsNil)
+	c.Assert(dump, DeepEquals, map[uint32]uint32{1: 10, 2: 20})
+
+	model := m.GetModel()
+	c.Assert(model.Path, Equals, m.Path())
+	c.Assert(model.Cache, DeepEquals, []*models.BPFMapEntry{
+		{Key: "key=1", Value: "value=10", DesiredAction: models.BPFMapEntryDesiredActionOk},
+		{Key: "key=2", Value: "value=20", DesiredAction: models.BPFMapEntryDesiredActionInsert, LastError: "update failed"},
+		{Key: "key=3", DesiredAction: models.BPFMapEntryDesiredActionDelete, LastError: "delete failed"},
+	})
+
+	// A successful update or deletion resolves the error
+	m.lock.Lock()
+	m.cacheUpdate(&testKey{Key: 2}, &testValue{Value: 21}, nil)
+	m.cacheDelete(&testKey{Key: 3}, nil)
+	c.Assert(m.outstandingErrors, Equals, 0)
+	c.Assert(len(m.cache), Equals, 2)
+
+	m.cacheFlush()
+	c.Assert(len(m.cache), Equals, 0)
+	m.lock.Unlock()
+}
+
+func (s *BPFSuite) TestUncachedMap(c *C) {
+	m := NewMap("cilium_test", MapTypeHash, 4, 4, 16, 0)
+
+	_, err := m.LookupCache(&testKey{Key: 1})
+	c.Assert(err, Not(IsNil))
+	c.Assert(m.DumpCache(testDumpParser, nil), Not(IsNil))
+}
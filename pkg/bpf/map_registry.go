@@ -0,0 +1,209 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// MapDescription documents a map of the datapath and decodes its entries so
+// that all pinned maps can be inspected generically.
+type MapDescription struct {
+	// Name is the name of the map. If Prefix is true, Name is the common
+	// prefix of the names of a group of maps, e.g. of the maps existing
+	// once per endpoint.
+	Name   string
+	Prefix bool
+
+	// Description documents the purpose of the map
+	Description string
+
+	// Parser decodes the raw keys and values of the map. Entries of maps
+	// without a parser are represented as hexadecimal bytes.
+	Parser DumpParser
+}
+
+var (
+	mapRegistryMutex lock.RWMutex
+
+	// mapRegistry contains the descriptions of all registered maps
+	// indexed by name
+	mapRegistry = map[string]MapDescription{}
+)
+
+// RegisterMap registers the description of a map. Map packages register
+// their maps on initialization.
+func RegisterMap(desc MapDescription) {
+	mapRegistryMutex.Lock()
+	mapRegistry[desc.Name] = desc
+	mapRegistryMutex.Unlock()
+}
+
+// LookupMapDescription returns the description of the map with the given
+// name. If no description is registered for the exact name, the description
+// with the longest matching prefix is returned. Returns nil if the map is
+// unknown.
+func LookupMapDescription(name string) *MapDescription {
+	mapRegistryMutex.RLock()
+	defer mapRegistryMutex.RUnlock()
+
+	if desc, ok := mapRegistry[name]; ok {
+		return &desc
+	}
+
+	var match *MapDescription
+	for _, desc := range mapRegistry {
+		if desc.Prefix && strings.HasPrefix(name, desc.Name) &&
+			(match == nil || len(desc.Name) > len(match.Name)) {
+			d := desc
+			match = &d
+		}
+	}
+
+	return match
+}
+
+// iterateEntries calls cb with the raw key and, if withValues is true, the
+// raw value of each entry of the map in fd which has the attributes info.
+func iterateEntries(fd int, info *MapInfo, withValues bool, cb func(key, value []byte) error) error {
+	key := make([]byte, info.KeySize)
+	nextKey := make([]byte, info.KeySize)
+	value := make([]byte, info.ValueSize)
+
+	for {
+		if err := GetNextKey(fd, unsafe.Pointer(&key[0]), unsafe.Pointer(&nextKey[0])); err != nil {
+			return nil
+		}
+
+		if withValues {
+			if err := LookupElement(fd, unsafe.Pointer(&nextKey[0]), unsafe.Pointer(&value[0])); err != nil {
+				return err
+			}
+		}
+
+		if err := cb(nextKey, value); err != nil {
+			return err
+		}
+
+		copy(key, nextKey)
+	}
+}
+
+// dumpSupported returns true if the values of maps of type t can be read
+// from userspace into buffers of the value size of the map. Per-CPU maps hold
+// a value per CPU and maps of file descriptors cannot be read at all.
+func dumpSupported(t MapType) bool {
+	switch t {
+	case MapTypeProgArray, MapTypePerfEventArray, MapTypePerCPUHash,
+		MapTypePerCPUArray, MapTypeCgroupArray, MapTypeLRUPerCPUHash,
+		MapTypeArrayOfMaps, MapTypeHashOfMaps:
+		return false
+	}
+	return true
+}
+
+// decodeEntry returns the human readable representation of a raw entry of
+// the map described by desc.
+func decodeEntry(desc *MapDescription, key, value []byte) *models.BPFMapEntry {
+	if desc != nil && desc.Parser != nil {
+		if k, v, err := desc.Parser(key, value); err == nil {
+			return &models.BPFMapEntry{Key: stringOf(k), Value: stringOf(v)}
+		}
+	}
+
+	return &models.BPFMapEntry{
+		Key:   fmt.Sprintf("% x", key),
+		Value: fmt.Sprintf("% x", value),
+	}
+}
+
+// GetPinnedMapModel returns the API model of the map pinned with the given
+// name or at the given path. The model contains the attributes of the map,
+// its number of entries and, if the map is cached, the contents of the cache.
+// If dump is true, the model also contains the decoded entries of the map.
+func GetPinnedMapModel(name string, dump bool) (*models.BPFMap, error) {
+	m, err := OpenMap(name)
+	if err != nil {
+		return nil, err
+	}
+	defer m.Close()
+
+	mapModel := &models.BPFMap{
+		Name:       m.name,
+		Path:       m.path,
+		Type:       m.MapType.String(),
+		KeySize:    int64(m.KeySize),
+		ValueSize:  int64(m.ValueSize),
+		MaxEntries: int64(m.MaxEntries),
+	}
+
+	desc := LookupMapDescription(m.name)
+	if desc != nil {
+		mapModel.Description = desc.Description
+	}
+
+	if cached := GetMap(m.path); cached != nil {
+		mapModel.Cache = cached.GetModel().Cache
+	}
+
+	if dump {
+		if !dumpSupported(m.MapType) {
+			return nil, fmt.Errorf("unable to dump map %s: maps of type %s are not supported", m.name, m.MapType)
+		}
+		mapModel.Entries = []*models.BPFMapEntry{}
+	}
+
+	err = iterateEntries(m.fd, &m.MapInfo, dump, func(key, value []byte) error {
+		mapModel.NumEntries++
+		if dump {
+			mapModel.Entries = append(mapModel.Entries, decodeEntry(desc, key, value))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to dump map %s: %s", m.name, err)
+	}
+
+	return mapModel, nil
+}
+
+// GetPinnedMapsModel returns the API model of all maps pinned in the BPF
+// filesystem. The entries of the maps are not dumped.
+func GetPinnedMapsModel() (*models.BPFMapList, error) {
+	paths, err := ListPinned(MapPrefixPath())
+	if err != nil {
+		return nil, err
+	}
+
+	mapList := &models.BPFMapList{
+		Maps: make([]*models.BPFMap, 0, len(paths)),
+	}
+	for _, p := range paths {
+		mapModel, err := GetPinnedMapModel(p, false)
+		if err != nil {
+			log.WithError(err).WithField(logfields.Path, p).Debug("Skipping pinned object")
+			continue
+		}
+		mapList.Maps = append(mapList.Maps, mapModel)
+	}
+
+	return mapList, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MemoryBackendSuite) TestLookupMapDescription(c *C) {
+	RegisterMap(MapDescription{Name: "cilium_test_", Prefix: true, Description: "prefix"})
+	RegisterMap(MapDescription{Name: "cilium_test_global", Description: "global"})
+	RegisterMap(MapDescription{Name: "cilium_test_global_", Prefix: true, Description: "longer prefix"})
+
+	c.Assert(LookupMapDescription("cilium_test_1").Description, Equals, "prefix")
+	c.Assert(LookupMapDescription("cilium_test_global").Description, Equals, "global")
+	c.Assert(LookupMapDescription("cilium_test_global_1").Description, Equals, "longer prefix")
+	c.Assert(LookupMapDescription("cilium_test_"), Not(IsNil))
+
+	// Exact names do not match as prefix
+	RegisterMap(MapDescription{Name: "cilium_exact", Description: "exact"})
+	c.Assert(LookupMapDescription("cilium_exact_1"), IsNil)
+	c.Assert(LookupMapDescription("cilium_unknown"), IsNil)
+}
+
+func (s *MemoryBackendSuite) TestGetPinnedMapModel(c *C) {
+	RegisterMap(MapDescription{
+		Name:        "cilium_test",
+		Description: "test map",
+		Parser:      testDumpParser,
+	})
+
+	m := newTestMap(MapTypeHash, 16)
+	_, err := m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	defer m.Close()
+	c.Assert(m.Update(&testKey{Key: 1}, &testValue{Value: 10}), IsNil)
+	c.Assert(m.Update(&testKey{Key: 2}, &testValue{Value: 20}), IsNil)
+
+	model, err := GetPinnedMapModel("cilium_test", false)
+	c.Assert(err, IsNil)
+	c.Assert(model.Name, Equals, "cilium_test")
+	c.Assert(model.Path, Equals, m.Path())
+	c.Assert(model.Description, Equals, "test map")
+	c.Assert(model.Type, Equals, MapTypeHash.String())
+	c.Assert(model.KeySize, Equals, int64(4))
+	c.Assert(model.ValueSize, Equals, int64(4))
+	c.Assert(model.MaxEntries, Equals, int64(16))
+	c.Assert(model.NumEntries, Equals, int64(2))
+	c.Assert(model.Entries, IsNil)
+
+	model, err = GetPinnedMapModel(m.Path(), true)
+	c.Assert(err, IsNil)
+	c.Assert(model.NumEntries, Equals, int64(2))
+	c.Assert(model.Entries, HasLen, 2)
+	for _, e := range model.Entries {
+		c.Assert(*e == models.BPFMapEntry{Key: "key=1", Value: "value=10"} ||
+			*e == models.BPFMapEntry{Key: "key=2", Value: "value=20"}, Equals, true)
+	}
+
+	_, err = GetPinnedMapModel("cilium_unknown", false)
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *MemoryBackendSuite) TestGetPinnedMapModelUnknown(c *C) {
+	m := NewMap(MapPath("cilium_unregistered"), MapTypeHash, 4, 4, 16, 0)
+	_, err := m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	defer m.Close()
+	c.Assert(m.Update(&testKey{Key: 1}, &testValue{Value: 10}), IsNil)
+
+	// Entries of unknown maps are represented as hexadecimal bytes
+	model, err := GetPinnedMapModel("cilium_unregistered", true)
+	c.Assert(err, IsNil)
+	c.Assert(model.Description, Equals, "")
+	c.Assert(model.Entries, HasLen, 1)
+	c.Assert(model.Entries[0].Key, Equals, "01 00 00 00")
+	c.Assert(model.Entries[0].Value, Equals, "0a 00 00 00")
+}
+
+func (s *MemoryBackendSuite) TestGetPinnedMapsModel(c *C) {
+	for _, name := range []string{"cilium_test_b", "cilium_test_a"} {
+		m := NewMap(MapPath(name), MapTypeHash, 4, 4, 16, 0)
+		_, err := m.OpenOrCreate()
+		c.Assert(err, IsNil)
+		defer m.Close()
+	}
+
+	list, err := GetPinnedMapsModel()
+	c.Assert(err, IsNil)
+	c.Assert(list.Maps, HasLen, 2)
+	c.Assert(list.Maps[0].Name, Equals, "cilium_test_a")
+	c.Assert(list.Maps[1].Name, Equals, "cilium_test_b")
+	c.Assert(list.Maps[0].Entries, IsNil)
+}
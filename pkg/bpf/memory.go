@@ -0,0 +1,473 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"unsafe"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/lock"
+
+	"golang.org/x/sys/unix"
+)
+
+// MemoryBackend is a MapBackend keeping all maps in memory. It requires
+// neither privileges nor a mounted BPF filesystem and is intended for unit
+// tests. Hash, LRU hash, array and longest prefix match trie maps are
+// supported and behave like their kernel counterparts with regard to key and
+// value sizes, the maximum number of entries, update flags and the iteration
+// semantics of GetNextKey(). Entries are iterated in insertion order.
+type MemoryBackend struct {
+	mutex  lock.Mutex
+	nextFd int
+	fds    map[int]*memoryMap
+	pinned map[string]*memoryMap
+}
+
+// NewMemoryBackend returns a new MemoryBackend without any maps
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		nextFd: 1,
+		fds:    map[int]*memoryMap{},
+		pinned: map[string]*memoryMap{},
+	}
+}
+
+// memoryMap is a single map of a MemoryBackend
+type memoryMap struct {
+	info MapInfo
+
+	// keys holds the keys of all entries in iteration order, index is
+	// the position of each key in keys.
+	keys  []string
+	index map[string]int
+
+	// values holds the value of each entry
+	values map[string][]byte
+
+	// lastUse is the value of clock when each entry was last accessed,
+	// used to evict the least recently used entry of LRU maps.
+	lastUse map[string]uint64
+	clock   uint64
+}
+
+func newMemoryMap(info MapInfo) *memoryMap {
+	m := &memoryMap{
+		info:    info,
+		index:   map[string]int{},
+		values:  map[string][]byte{},
+		lastUse: map[string]uint64{},
+	}
+
+	// All entries of an array map exist and are zero-initialized
+	if info.MapType == MapTypeArray {
+		key := make([]byte, 4)
+		for i := uint32(0); i < info.MaxEntries; i++ {
+			byteorder.Native.PutUint32(key, i)
+			m.insert(string(key), make([]byte, info.ValueSize))
+		}
+	}
+
+	return m
+}
+
+func (m *memoryMap) insert(key string, value []byte) {
+	m.index[key] = len(m.keys)
+	m.keys = append(m.keys, key)
+	m.values[key] = value
+}
+
+func (m *memoryMap) remove(key string) {
+	i := m.index[key]
+	m.keys = append(m.keys[:i], m.keys[i+1:]...)
+	for _, k := range m.keys[i:] {
+		m.index[k]--
+	}
+	delete(m.index, key)
+	delete(m.values, key)
+	delete(m.lastUse, key)
+}
+
+func (m *memoryMap) touch(key string) {
+	m.clock++
+	m.lastUse[key] = m.clock
+}
+
+// evict removes the least recently used entry
+func (m *memoryMap) evict() {
+	var oldest string
+	for i, k := range m.keys {
+		if i == 0 || m.lastUse[k] < m.lastUse[oldest] {
+			oldest = k
+		}
+	}
+	m.remove(oldest)
+}
+
+// prefixKey returns key of a longest prefix match trie with all bits beyond
+// the prefix length cleared. Returns false if the prefix length is invalid.
+func (m *memoryMap) prefixKey(key []byte) ([]byte, bool) {
+	prefixLen := byteorder.Native.Uint32(key)
+	if prefixLen > (m.info.KeySize-4)*8 {
+		return nil, false
+	}
+
+	k := make([]byte, len(key))
+	copy(k, key[:4])
+	for i := uint32(0); i < prefixLen; i++ {
+		if key[4+i/8]&(0x80>>(i%8)) != 0 {
+			k[4+i/8] |= 0x80 >> (i % 8)
+		}
+	}
+
+	return k, true
+}
+
+// longestPrefixMatch returns the entry of a longest prefix match trie with the
+// longest prefix matching key.
+func (m *memoryMap) longestPrefixMatch(key []byte) (string, bool) {
+	match, matchLen, found := "", uint32(0), false
+	prefixLen := byteorder.Native.Uint32(key)
+
+	for _, k := range m.keys {
+		entryLen := byteorder.Native.Uint32([]byte(k))
+		if entryLen > prefixLen || (found && entryLen <= matchLen) {
+			continue
+		}
+
+		masked := make([]byte, len(key))
+		copy(masked, key)
+		byteorder.Native.PutUint32(masked, entryLen)
+		masked, _ = m.prefixKey(masked)
+		if bytes.Equal(masked, []byte(k)) {
+			match, matchLen, found = k, entryLen, true
+		}
+	}
+
+	return match, found
+}
+
+func errnoError(op string, errno syscall.Errno) error {
+	return fmt.Errorf("Unable to %s: %s", op, errno)
+}
+
+// getMap returns the map in fd. Must be called with b.mutex held.
+func (b *MemoryBackend) getMap(fd int) (*memoryMap, error) {
+	m, ok := b.fds[fd]
+	if !ok {
+		return nil, unix.EBADF
+	}
+	return m, nil
+}
+
+// newFd returns a new file descriptor of m. Must be called with b.mutex held.
+func (b *MemoryBackend) newFd(m *memoryMap) int {
+	fd := b.nextFd
+	b.nextFd++
+	b.fds[fd] = m
+	return fd
+}
+
+// CreateMap creates a new in-memory map
+func (b *MemoryBackend) CreateMap(mapType int, keySize, valueSize, maxEntries, flags uint32) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch MapType(mapType) {
+	case MapTypeHash, MapTypeLRUHash:
+	case MapTypeArray:
+		if keySize != 4 {
+			return 0, errnoError("create map", unix.EINVAL)
+		}
+	case MapTypeLPMTrie:
+		if keySize <= 4 {
+			return 0, errnoError("create map", unix.EINVAL)
+		}
+	default:
+		return 0, errnoError("create map", unix.EINVAL)
+	}
+
+	if keySize == 0 || valueSize == 0 || maxEntries == 0 {
+		return 0, errnoError("create map", unix.EINVAL)
+	}
+
+	m := newMemoryMap(MapInfo{
+		MapType:       MapType(mapType),
+		KeySize:       keySize,
+		ValueSize:     valueSize,
+		MaxEntries:    maxEntries,
+		Flags:         flags,
+		OwnerProgType: ProgTypeUnspec,
+	})
+
+	return b.newFd(m), nil
+}
+
+// OpenOrCreateMap opens the in-memory map pinned at path or creates and pins
+// a new map if none exists.
+func (b *MemoryBackend) OpenOrCreateMap(path string, mapType int, keySize, valueSize, maxEntries, flags uint32) (int, bool, error) {
+	if fd, err := b.ObjGet(path); err == nil {
+		return fd, false, nil
+	}
+
+	fd, err := b.CreateMap(mapType, keySize, valueSize, maxEntries, flags)
+	if err != nil {
+		return 0, true, err
+	}
+
+	if err := b.ObjPin(fd, path); err != nil {
+		b.ObjClose(fd)
+		return 0, true, err
+	}
+
+	return fd, true, nil
+}
+
+// UpdateElement writes the entry key with value into the map in fd
+func (b *MemoryBackend) UpdateElement(fd int, key, value unsafe.Pointer, flags uint64) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	m, err := b.getMap(fd)
+	if err != nil {
+		return fmt.Errorf("Unable to update element: %s", err)
+	}
+
+	if flags > BPF_EXIST {
+		return errnoError("update element", unix.EINVAL)
+	}
+
+	k := bytesOf(key, m.info.KeySize)
+	v := bytesOf(value, m.info.ValueSize)
+
+	switch m.info.MapType {
+	case MapTypeArray:
+		if byteorder.Native.Uint32(k) >= m.info.MaxEntries {
+			return errnoError("update element", unix.E2BIG)
+		}
+		if flags == BPF_NOEXIST {
+			return errnoError("update element", unix.EEXIST)
+		}
+	case MapTypeLPMTrie:
+		var ok bool
+		if k, ok = m.prefixKey(k); !ok {
+			return errnoError("update element", unix.EINVAL)
+		}
+	}
+
+	_, exists := m.values[string(k)]
+	switch {
+	case exists && flags == BPF_NOEXIST:
+		return errnoError("update element", unix.EEXIST)
+	case !exists && flags == BPF_EXIST:
+		return errnoError("update element", unix.ENOENT)
+	}
+
+	if exists {
+		m.values[string(k)] = v
+	} else {
+		if uint32(len(m.keys)) >= m.info.MaxEntries {
+			if m.info.MapType != MapTypeLRUHash {
+				return errnoError("update element", unix.E2BIG)
+			}
+			m.evict()
+		}
+		m.insert(string(k), v)
+	}
+	m.touch(string(k))
+
+	return nil
+}
+
+// LookupElement reads the value of the entry key of the map in fd
+func (b *MemoryBackend) LookupElement(fd int, key, value unsafe.Pointer) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	m, err := b.getMap(fd)
+	if err != nil {
+		return fmt.Errorf("Unable to lookup element: %s", err)
+	}
+
+	k := string(bytesOf(key, m.info.KeySize))
+	if m.info.MapType == MapTypeLPMTrie {
+		var ok bool
+		if k, ok = m.longestPrefixMatch([]byte(k)); !ok {
+			return errnoError("lookup element", unix.ENOENT)
+		}
+	}
+
+	v, ok := m.values[k]
+	if !ok {
+		return errnoError("lookup element", unix.ENOENT)
+	}
+
+	copy((*[1 << 30]byte)(value)[:m.info.ValueSize:m.info.ValueSize], v)
+	m.touch(k)
+
+	return nil
+}
+
+// DeleteElement removes the entry key from the map in fd
+func (b *MemoryBackend) DeleteElement(fd int, key unsafe.Pointer) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	m, err := b.getMap(fd)
+	if err != nil {
+		return fmt.Errorf("Unable to delete element: %s", err)
+	}
+
+	k := bytesOf(key, m.info.KeySize)
+	switch m.info.MapType {
+	case MapTypeArray:
+		return errnoError("delete element", unix.EINVAL)
+	case MapTypeLPMTrie:
+		var ok bool
+		if k, ok = m.prefixKey(k); !ok {
+			return errnoError("delete element", unix.EINVAL)
+		}
+	}
+
+	if _, ok := m.values[string(k)]; !ok {
+		return errnoError("delete element", unix.ENOENT)
+	}
+	m.remove(string(k))
+
+	return nil
+}
+
+// GetNextKey stores the key following key of the map in fd in nextKey. Like
+// the kernel, the first key is returned if key does not exist, e.g. because it
+// was deleted during the iteration.
+func (b *MemoryBackend) GetNextKey(fd int, key, nextKey unsafe.Pointer) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	m, err := b.getMap(fd)
+	if err != nil {
+		return fmt.Errorf("Unable to get next key: %s", err)
+	}
+
+	next := 0
+	if i, ok := m.index[string(bytesOf(key, m.info.KeySize))]; ok {
+		next = i + 1
+	}
+
+	if next >= len(m.keys) {
+		return errnoError("get next key", unix.ENOENT)
+	}
+
+	copy((*[1 << 30]byte)(nextKey)[:m.info.KeySize:m.info.KeySize], m.keys[next])
+
+	return nil
+}
+
+// ObjPin pins the map in fd at pathname
+func (b *MemoryBackend) ObjPin(fd int, pathname string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	m, err := b.getMap(fd)
+	if err != nil {
+		return fmt.Errorf("Unable to pin object: %s", err)
+	}
+
+	if _, ok := b.pinned[pathname]; ok {
+		return errnoError("pin object", unix.EEXIST)
+	}
+	b.pinned[pathname] = m
+
+	return nil
+}
+
+// ObjGet opens the map pinned at pathname
+func (b *MemoryBackend) ObjGet(pathname string) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	m, ok := b.pinned[pathname]
+	if !ok {
+		return 0, fmt.Errorf("Unable to get object %s: %s", pathname, unix.ENOENT)
+	}
+
+	return b.newFd(m), nil
+}
+
+// ObjClose closes fd. The map remains available as long as it is pinned.
+func (b *MemoryBackend) ObjClose(fd int) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if fd <= 0 {
+		return nil
+	}
+
+	if _, err := b.getMap(fd); err != nil {
+		return err
+	}
+	delete(b.fds, fd)
+
+	return nil
+}
+
+// Unpin removes the pinned map at pathname
+func (b *MemoryBackend) Unpin(pathname string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, ok := b.pinned[pathname]; !ok {
+		return &os.PathError{Op: "remove", Path: pathname, Err: unix.ENOENT}
+	}
+	delete(b.pinned, pathname)
+
+	return nil
+}
+
+// ListPinned returns the paths of all maps pinned in the directory dir
+func (b *MemoryBackend) ListPinned(dir string) ([]string, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	paths := []string{}
+	for pathname := range b.pinned {
+		if filepath.Dir(pathname) == filepath.Clean(dir) {
+			paths = append(paths, pathname)
+		}
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// GetMapInfo returns the attributes of the map in fd. pid is ignored.
+func (b *MemoryBackend) GetMapInfo(pid int, fd int) (*MapInfo, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	m, err := b.getMap(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	info := m.info
+	return &info, nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"unsafe"
+
+	. "gopkg.in/check.v1"
+)
+
+type MemoryBackendSuite struct {
+	prev MapBackend
+}
+
+var _ = Suite(&MemoryBackendSuite{})
+
+func (s *MemoryBackendSuite) SetUpTest(c *C) {
+	s.prev = SetMapBackend(NewMemoryBackend())
+}
+
+func (s *MemoryBackendSuite) TearDownTest(c *C) {
+	SetMapBackend(s.prev)
+}
+
+func newTestMap(mapType MapType, maxEntries int) *Map {
+	return NewMap("/sys/fs/bpf/tc/globals/cilium_test",
+		mapType,
+		int(unsafe.Sizeof(testKey{})),
+		int(unsafe.Sizeof(testValue{})),
+		maxEntries, 0)
+}
+
+// dumpKeys returns the keys of m in iteration order
+func dumpKeys(c *C, m *Map) []uint32 {
+	keys := []uint32{}
+	err := m.Dump(testDumpParser, func(key MapKey, value MapValue) {
+		keys = append(keys, key.(*testKey).Key)
+	})
+	c.Assert(err, IsNil)
+	return keys
+}
+
+func (s *MemoryBackendSuite) TestCreateMap(c *C) {
+	_, err := CreateMap(int(MapTypeHash), 0, 4, 16, 0)
+	c.Assert(err, Not(IsNil))
+	_, err = CreateMap(int(MapTypeHash), 4, 0, 16, 0)
+	c.Assert(err, Not(IsNil))
+	_, err = CreateMap(int(MapTypeHash), 4, 4, 0, 0)
+	c.Assert(err, Not(IsNil))
+	_, err = CreateMap(int(MapTypeArray), 8, 4, 16, 0)
+	c.Assert(err, Not(IsNil))
+	_, err = CreateMap(int(MapTypePerCPUHash), 4, 4, 16, 0)
+	c.Assert(err, Not(IsNil))
+
+	fd, err := CreateMap(int(MapTypeHash), 4, 8, 16, 0)
+	c.Assert(err, IsNil)
+	info, err := GetMapInfo(0, fd)
+	c.Assert(err, IsNil)
+	c.Assert(info.MapType, Equals, MapTypeHash)
+	c.Assert(info.KeySize, Equals, uint32(4))
+	c.Assert(info.ValueSize, Equals, uint32(8))
+	c.Assert(info.MaxEntries, Equals, uint32(16))
+	c.Assert(ObjClose(fd), IsNil)
+	c.Assert(ObjClose(fd), Not(IsNil))
+}
+
+func (s *MemoryBackendSuite) TestPin(c *C) {
+	m := newTestMap(MapTypeHash, 16)
+	isNew, err := m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, true)
+	c.Assert(m.Update(&testKey{Key: 1}, &testValue{Value: 10}), IsNil)
+	c.Assert(m.Close(), IsNil)
+
+	// The pinned map retains its entries after being closed
+	m = newTestMap(MapTypeHash, 16)
+	isNew, err = m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	v, err := m.Lookup(&testKey{Key: 1})
+	c.Assert(err, IsNil)
+	c.Assert(v.(*testValue).Value, Equals, uint32(10))
+
+	// An empty map with different attributes replaces the pinned map
+	c.Assert(m.Delete(&testKey{Key: 1}), IsNil)
+	c.Assert(m.Close(), IsNil)
+	m = newTestMap(MapTypeHash, 32)
+	isNew, err = m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, true)
+	info, err := GetMapInfo(0, m.GetFd())
+	c.Assert(err, IsNil)
+	c.Assert(info.MaxEntries, Equals, uint32(32))
+	c.Assert(m.Close(), IsNil)
+
+	c.Assert(Unpin(m.Path()), IsNil)
+	c.Assert(Unpin(m.Path()), Not(IsNil))
+	_, err = ObjGet(m.Path())
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *MemoryBackendSuite) TestHash(c *C) {
+	m := newTestMap(MapTypeHash, 2)
+	_, err := m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	defer m.Close()
+
+	key1, key2, key3 := &testKey{Key: 1}, &testKey{Key: 2}, &testKey{Key: 3}
+	value := &testValue{Value: 10}
+
+	_, err = m.Lookup(key1)
+	c.Assert(err, Not(IsNil))
+	c.Assert(m.Delete(key1), Not(IsNil))
+
+	fd := m.GetFd()
+	c.Assert(UpdateElement(fd, key1.GetKeyPtr(), value.GetValuePtr(), BPF_EXIST), Not(IsNil))
+	c.Assert(UpdateElement(fd, key1.GetKeyPtr(), value.GetValuePtr(), BPF_NOEXIST), IsNil)
+	c.Assert(UpdateElement(fd, key1.GetKeyPtr(), value.GetValuePtr(), BPF_NOEXIST), Not(IsNil))
+	c.Assert(UpdateElement(fd, key1.GetKeyPtr(), value.GetValuePtr(), BPF_EXIST), IsNil)
+
+	c.Assert(m.Update(key2, &testValue{Value: 20}), IsNil)
+	v, err := m.Lookup(key2)
+	c.Assert(err, IsNil)
+	c.Assert(v.(*testValue).Value, Equals, uint32(20))
+
+	// The map is full, only existing entries may be updated
+	c.Assert(m.Update(key3, value), Not(IsNil))
+	c.Assert(m.Update(key2, &testValue{Value: 21}), IsNil)
+	c.Assert(dumpKeys(c, m), DeepEquals, []uint32{1, 2})
+
+	c.Assert(m.Delete(key1), IsNil)
+	c.Assert(m.Update(key3, value), IsNil)
+	c.Assert(dumpKeys(c, m), DeepEquals, []uint32{2, 3})
+}
+
+func (s *MemoryBackendSuite) TestLRUHash(c *C) {
+	m := newTestMap(MapTypeLRUHash, 3)
+	_, err := m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	defer m.Close()
+
+	for i := uint32(1); i <= 3; i++ {
+		c.Assert(m.Update(&testKey{Key: i}, &testValue{Value: i}), IsNil)
+	}
+
+	// Key 1 was used more recently than keys 2 and 3, which are evicted
+	_, err = m.Lookup(&testKey{Key: 1})
+	c.Assert(err, IsNil)
+	c.Assert(m.Update(&testKey{Key: 4}, &testValue{Value: 4}), IsNil)
+	c.Assert(m.Update(&testKey{Key: 5}, &testValue{Value: 5}), IsNil)
+	c.Assert(dumpKeys(c, m), DeepEquals, []uint32{1, 4, 5})
+}
+
+func (s *MemoryBackendSuite) TestArray(c *C) {
+	m := newTestMap(MapTypeArray, 4)
+	_, err := m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	defer m.Close()
+
+	// All entries exist and are zero-initialized, iteration starts at
+	// index 0 for any key out of range
+	next := &testKey{}
+	c.Assert(GetNextKey(m.GetFd(), (&testKey{Key: 4}).GetKeyPtr(), next.GetKeyPtr()), IsNil)
+	c.Assert(next.Key, Equals, uint32(0))
+	c.Assert(GetNextKey(m.GetFd(), (&testKey{Key: 2}).GetKeyPtr(), next.GetKeyPtr()), IsNil)
+	c.Assert(next.Key, Equals, uint32(3))
+	c.Assert(GetNextKey(m.GetFd(), (&testKey{Key: 3}).GetKeyPtr(), next.GetKeyPtr()), Not(IsNil))
+	v, err := m.Lookup(&testKey{Key: 3})
+	c.Assert(err, IsNil)
+	c.Assert(v.(*testValue).Value, Equals, uint32(0))
+	_, err = m.Lookup(&testKey{Key: 4})
+	c.Assert(err, Not(IsNil))
+
+	c.Assert(m.Update(&testKey{Key: 2}, &testValue{Value: 20}), IsNil)
+	v, err = m.Lookup(&testKey{Key: 2})
+	c.Assert(err, IsNil)
+	c.Assert(v.(*testValue).Value, Equals, uint32(20))
+
+	c.Assert(m.Update(&testKey{Key: 4}, &testValue{Value: 40}), Not(IsNil))
+	c.Assert(m.Delete(&testKey{Key: 2}), Not(IsNil))
+
+	key, value := &testKey{Key: 1}, &testValue{Value: 10}
+	c.Assert(UpdateElement(m.GetFd(), key.GetKeyPtr(), value.GetValuePtr(), BPF_NOEXIST), Not(IsNil))
+}
+
+func (s *MemoryBackendSuite) TestLPMTrie(c *C) {
+	type lpmKey struct {
+		PrefixLen uint32
+		Data      [4]byte
+	}
+
+	fd, err := CreateMap(int(MapTypeLPMTrie), uint32(unsafe.Sizeof(lpmKey{})), 4, 16, 0)
+	c.Assert(err, IsNil)
+	defer ObjClose(fd)
+
+	update := func(key lpmKey, value uint32) error {
+		return UpdateElement(fd, unsafe.Pointer(&key), unsafe.Pointer(&value), 0)
+	}
+	lookup := func(key lpmKey) (uint32, error) {
+		var value uint32
+		err := LookupElement(fd, unsafe.Pointer(&key), unsafe.Pointer(&value))
+		return value, err
+	}
+
+	c.Assert(update(lpmKey{8, [4]byte{10, 1, 2, 3}}, 8), IsNil)
+	c.Assert(update(lpmKey{24, [4]byte{10, 1, 2, 0}}, 24), IsNil)
+	c.Assert(update(lpmKey{33, [4]byte{10, 1, 2, 3}}, 33), Not(IsNil))
+
+	v, err := lookup(lpmKey{32, [4]byte{10, 1, 2, 3}})
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, uint32(24))
+	v, err = lookup(lpmKey{32, [4]byte{10, 2, 0, 1}})
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, uint32(8))
+	v, err = lookup(lpmKey{16, [4]byte{10, 1, 2, 3}})
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, uint32(8))
+	_, err = lookup(lpmKey{32, [4]byte{192, 168, 0, 1}})
+	c.Assert(err, Not(IsNil))
+
+	// Bits beyond the prefix length are ignored
+	key := lpmKey{8, [4]byte{10, 9, 9, 9}}
+	c.Assert(DeleteElement(fd, unsafe.Pointer(&key)), IsNil)
+	_, err = lookup(lpmKey{32, [4]byte{10, 2, 0, 1}})
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *MemoryBackendSuite) TestGetNextKey(c *C) {
+	m := newTestMap(MapTypeHash, 16)
+	_, err := m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	defer m.Close()
+
+	for i := uint32(1); i <= 3; i++ {
+		c.Assert(m.Update(&testKey{Key: i}, &testValue{Value: i}), IsNil)
+	}
+
+	next := &testKey{}
+	fd := m.GetFd()
+
+	// A key which does not exist returns the first key
+	c.Assert(GetNextKey(fd, (&testKey{Key: 10}).GetKeyPtr(), next.GetKeyPtr()), IsNil)
+	c.Assert(next.Key, Equals, uint32(1))
+	c.Assert(GetNextKey(fd, (&testKey{Key: 2}).GetKeyPtr(), next.GetKeyPtr()), IsNil)
+	c.Assert(next.Key, Equals, uint32(3))
+	c.Assert(GetNextKey(fd, (&testKey{Key: 3}).GetKeyPtr(), next.GetKeyPtr()), Not(IsNil))
+
+	// Deleting the current key restarts the iteration
+	c.Assert(m.Delete(&testKey{Key: 2}), IsNil)
+	c.Assert(GetNextKey(fd, (&testKey{Key: 2}).GetKeyPtr(), next.GetKeyPtr()), IsNil)
+	c.Assert(next.Key, Equals, uint32(1))
+
+	c.Assert(m.DeleteAll(), IsNil)
+	c.Assert(GetNextKey(fd, (&testKey{Key: 0}).GetKeyPtr(), next.GetKeyPtr()), Not(IsNil))
+}
+
+func (s *MemoryBackendSuite) TestMigrate(c *C) {
+	m := newTestMap(MapTypeHash, 4)
+	_, err := m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	for i := uint32(1); i <= 3; i++ {
+		c.Assert(m.Update(&testKey{Key: i}, &testValue{Value: i * 10}), IsNil)
+	}
+	c.Assert(m.Close(), IsNil)
+
+	// Maps with entries are not recreated unless their entries migrate
+	m = newTestMap(MapTypeHash, 8)
+	_, err = m.OpenOrCreate()
+	c.Assert(err, Not(IsNil))
+
+	m.MigrateEntries = true
+	isNew, err := m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	info, err := GetMapInfo(0, m.GetFd())
+	c.Assert(err, IsNil)
+	c.Assert(info.MaxEntries, Equals, uint32(8))
+	c.Assert(dumpKeys(c, m), DeepEquals, []uint32{1, 2, 3})
+	c.Assert(m.Close(), IsNil)
+
+	// Entries exceeding the new capacity are dropped
+	m = newTestMap(MapTypeHash, 2)
+	m.MigrateEntries = true
+	_, err = m.OpenOrCreate()
+	c.Assert(err, IsNil)
+	c.Assert(dumpKeys(c, m), HasLen, 2)
+	c.Assert(m.Close(), IsNil)
+
+	// Entries of maps with a different layout are never migrated
+	m = NewMap("/sys/fs/bpf/tc/globals/cilium_test", MapTypeHash, 8, 4, 2, 0)
+	m.MigrateEntries = true
+	_, err = m.OpenOrCreate()
+	c.Assert(err, Not(IsNil))
+}
@@ -180,6 +180,13 @@ type PerfEventConfig struct {
 	WakeupEvents int
 }
 
+func init() {
+	RegisterMap(MapDescription{
+		Name:        EventsMapName,
+		Description: "Perf ring buffers for notifications of the datapath",
+	})
+}
+
 func DefaultPerfEventConfig() *PerfEventConfig {
 	return &PerfEventConfig{
 		MapName:      EventsMapName,
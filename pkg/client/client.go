@@ -176,6 +176,29 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allControllers
 		fmt.Fprintf(w, "Cilium health daemon:\t%s\t%s\n", ch.State, ch.Msg)
 	}
 
+	if ct := sr.Conntrack; ct != nil && ct.State != models.StatusStateDisabled {
+		fmt.Fprintf(w, "Conntrack:\t%s\t%s\n", ct.State, ct.Msg)
+	}
+
+	if enc := sr.Encryption; enc != nil {
+		if enc.Mode == models.EncryptionStatusModeDisabled {
+			fmt.Fprintf(w, "Encryption:\tDisabled\n")
+		} else {
+			fmt.Fprintf(w, "Encryption:\t%s\t%s\n", enc.Mode, enc.Msg)
+		}
+	}
+
+	if sf := sr.ServiceFiles; sf != nil {
+		if sf.State == models.ServiceFilesStatusStateDisabled {
+			fmt.Fprintf(w, "Service files:\tDisabled\n")
+		} else {
+			fmt.Fprintf(w, "Service files:\t%s\t%d services from %s\n", sf.State, sf.Services, sf.Directory)
+			for _, f := range sf.InvalidFiles {
+				fmt.Fprintf(w, "  %s:\t%s\n", f.File, f.Error)
+			}
+		}
+	}
+
 	if sr.IPAM != nil {
 		fmt.Fprintf(w, "Allocated IPv4 addresses:\n")
 		for _, ipv4 := range sr.IPAM.IPV4 {
@@ -214,6 +237,9 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allControllers
 				formatNodeAddress(w, node.HealthEndpointAddress.IPV4, "Health Endpoint", "  ")
 				formatNodeAddress(w, node.HealthEndpointAddress.IPV6, "Health Endpoint", "  ")
 			}
+			if node.EncryptionKey != 0 {
+				fmt.Fprintf(w, "  Encryption Key:\tSPI %d\n", node.EncryptionKey)
+			}
 		}
 	}
 
@@ -84,6 +84,16 @@ func (c *Client) EndpointHealthGet(id string) (*models.EndpointHealth, error) {
 	return resp.Payload, nil
 }
 
+// EndpointPolicyStatsGet returns the policy hit counters of an endpoint
+func (c *Client) EndpointPolicyStatsGet(id string) (*models.EndpointPolicyStats, error) {
+	params := endpoint.NewGetEndpointIDPolicyStatsParams().WithID(id)
+	resp, err := c.Endpoint.GetEndpointIDPolicyStats(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
 // EndpointConfigGet returns endpoint configuration
 func (c *Client) EndpointConfigGet(id string) (*models.Configuration, error) {
 	params := endpoint.NewGetEndpointIDConfigParams().WithID(id)
@@ -0,0 +1,41 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"github.com/cilium/cilium/api/v1/client/daemon"
+	"github.com/cilium/cilium/api/v1/models"
+)
+
+// KVStoreLocks returns the list of all locks held in the kvstore.
+func (c *Client) KVStoreLocks() ([]*models.KVStoreLock, error) {
+	resp, err := c.Daemon.GetKvstoreLocks(nil)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// KVStoreLockRelease forcefully releases the lock held on the given path and
+// returns the owner information of the released lock.
+func (c *Client) KVStoreLockRelease(path string) (*models.KVStoreLock, error) {
+	params := daemon.NewDeleteKvstoreLocksParams().WithPath(path)
+
+	resp, err := c.Daemon.DeleteKvstoreLocks(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"github.com/cilium/cilium/api/v1/client/daemon"
+	"github.com/cilium/cilium/api/v1/models"
+)
+
+// MapList returns the list of all pinned BPF maps.
+func (c *Client) MapList() (*models.BPFMapList, error) {
+	resp, err := c.Daemon.GetMap(nil)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// MapGet returns the attributes and the cache contents of the BPF map with the
+// given name.
+func (c *Client) MapGet(name string) (*models.BPFMap, error) {
+	params := daemon.NewGetMapNameParams().WithName(name)
+
+	resp, err := c.Daemon.GetMapName(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// MapDump returns the decoded entries of the BPF map with the given name.
+func (c *Client) MapDump(name string) (*models.BPFMap, error) {
+	params := daemon.NewGetMapNameEntriesParams().WithName(name)
+
+	resp, err := c.Daemon.GetMapNameEntries(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
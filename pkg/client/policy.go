@@ -58,3 +58,28 @@ func (c *Client) PolicyResolveGet(context *models.IdentityContext) (*models.Poli
 	}
 	return resp.Payload, nil
 }
+
+// EgressGatewayPolicyList returns all egress gateway policies
+func (c *Client) EgressGatewayPolicyList() ([]*models.EgressGatewayPolicy, error) {
+	resp, err := c.Policy.GetPolicyEgressGateway(nil)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// EgressGatewayPolicyPut creates or updates the egress gateway policy with the
+// given name. Returns true if the policy was created.
+func (c *Client) EgressGatewayPolicyPut(name string, p *models.EgressGatewayPolicy) (bool, error) {
+	params := policy.NewPutPolicyEgressGatewayNameParams().WithName(name).WithPolicy(p)
+	_, created, err := c.Policy.PutPolicyEgressGatewayName(params)
+	return created != nil, Hint(err)
+}
+
+// EgressGatewayPolicyDelete deletes the egress gateway policy with the given
+// name
+func (c *Client) EgressGatewayPolicyDelete(name string) error {
+	params := policy.NewDeletePolicyEgressGatewayNameParams().WithName(name)
+	_, err := c.Policy.DeletePolicyEgressGatewayName(params)
+	return Hint(err)
+}
@@ -28,6 +28,20 @@ func (c *Client) GetServices() ([]*models.Service, error) {
 	return resp.Payload, nil
 }
 
+// GetServicesByName returns all frontends of the service with the given name
+// in the given namespace.
+func (c *Client) GetServicesByName(namespace, name string) ([]*models.Service, error) {
+	params := service.NewGetServiceParams().WithName(&name)
+	if namespace != "" {
+		params.SetNamespace(&namespace)
+	}
+	resp, err := c.Service.GetService(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
 // GetServiceID returns a service by ID.
 func (c *Client) GetServiceID(id int64) (*models.Service, error) {
 	params := service.NewGetServiceIDParams().WithID(id)
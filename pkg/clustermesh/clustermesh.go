@@ -0,0 +1,242 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustermesh
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "clustermesh")
+
+// Configuration is the configuration that must be provided to
+// NewClusterMesh()
+type Configuration struct {
+	// Name is the name of the local cluster. A configuration file with
+	// this name in the configuration directory is ignored.
+	Name string
+
+	// ConfigDirectory is the path to the directory that contains an etcd
+	// configuration file for each remote cluster. The name of the file is
+	// the name of the remote cluster.
+	ConfigDirectory string
+}
+
+// ClusterMesh is a cache of multiple remote clusters
+type ClusterMesh struct {
+	conf    Configuration
+	watcher *fsnotify.Watcher
+	stop    chan struct{}
+	done    chan struct{}
+
+	mutex    lock.RWMutex
+	clusters map[string]*remoteCluster
+}
+
+// NewClusterMesh creates a new cluster mesh. All files in the configuration
+// directory are connected to as remote clusters. The directory is watched for
+// changes so that remote clusters can be added, changed and removed at
+// runtime.
+func NewClusterMesh(c Configuration) (*ClusterMesh, error) {
+	if c.ConfigDirectory == "" {
+		return nil, fmt.Errorf("cluster mesh configuration directory must be specified")
+	}
+
+	if _, err := os.Stat(c.ConfigDirectory); err != nil {
+		return nil, fmt.Errorf("unable to access cluster mesh configuration directory: %s", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create fsnotify watcher: %s", err)
+	}
+
+	if err := watcher.Add(c.ConfigDirectory); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("unable to watch %s: %s", c.ConfigDirectory, err)
+	}
+
+	cm := &ClusterMesh{
+		conf:     c,
+		watcher:  watcher,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		clusters: map[string]*remoteCluster{},
+	}
+
+	cm.resync()
+
+	go cm.watch()
+
+	return cm, nil
+}
+
+// isClusterConfigFile returns true if the file in the configuration
+// directory represents a remote cluster. Hidden files are ignored, this
+// includes the "..data" indirection of Kubernetes secret mounts.
+func (cm *ClusterMesh) isClusterConfigFile(name string) bool {
+	if name == "" || strings.HasPrefix(name, ".") || name == cm.conf.Name {
+		return false
+	}
+
+	info, err := os.Stat(path.Join(cm.conf.ConfigDirectory, name))
+	if err != nil {
+		return false
+	}
+
+	return info.Mode().IsRegular()
+}
+
+// resync synchronizes the list of remote clusters with the content of the
+// configuration directory
+func (cm *ClusterMesh) resync() {
+	files, err := ioutil.ReadDir(cm.conf.ConfigDirectory)
+	if err != nil {
+		log.WithError(err).Warning("Unable to read cluster mesh configuration directory")
+		return
+	}
+
+	found := map[string]struct{}{}
+	for _, f := range files {
+		if cm.isClusterConfigFile(f.Name()) {
+			found[f.Name()] = struct{}{}
+		}
+	}
+
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	for name := range found {
+		if _, ok := cm.clusters[name]; !ok {
+			cm.addLocked(name)
+		}
+	}
+
+	for name := range cm.clusters {
+		if _, ok := found[name]; !ok {
+			cm.removeLocked(name)
+		}
+	}
+}
+
+func (cm *ClusterMesh) addLocked(name string) {
+	log.WithField(fieldClusterName, name).Info("Adding remote cluster")
+
+	rc := newRemoteCluster(name, path.Join(cm.conf.ConfigDirectory, name))
+	cm.clusters[name] = rc
+	rc.connect()
+}
+
+func (cm *ClusterMesh) removeLocked(name string) {
+	if rc, ok := cm.clusters[name]; ok {
+		log.WithField(fieldClusterName, name).Info("Removing remote cluster")
+
+		rc.disconnect()
+		delete(cm.clusters, name)
+	}
+}
+
+// changed handles a change of the configuration file of a remote cluster
+func (cm *ClusterMesh) changed(name string) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	if !cm.isClusterConfigFile(name) {
+		cm.removeLocked(name)
+		return
+	}
+
+	if rc, ok := cm.clusters[name]; ok {
+		log.WithField(fieldClusterName, name).Info("Configuration of remote cluster changed, reconnecting")
+		rc.connect()
+		return
+	}
+
+	cm.addLocked(name)
+}
+
+func (cm *ClusterMesh) watch() {
+	defer close(cm.done)
+
+	for {
+		select {
+		case event, ok := <-cm.watcher.Events:
+			if !ok {
+				return
+			}
+
+			log.WithField("event", event).Debug("Received fsnotify event")
+
+			name := path.Base(event.Name)
+			if strings.HasPrefix(name, ".") {
+				// Kubernetes secret mounts are updated by
+				// swapping the ..data symlink, resync all files
+				cm.resync()
+				continue
+			}
+
+			cm.changed(name)
+
+		case err, ok := <-cm.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.WithError(err).Warning("Error while watching cluster mesh configuration directory")
+
+		case <-cm.stop:
+			return
+		}
+	}
+}
+
+// Close stops watching the configuration directory and disconnects from all
+// remote clusters
+func (cm *ClusterMesh) Close() {
+	close(cm.stop)
+	<-cm.done
+	cm.watcher.Close()
+
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	for name := range cm.clusters {
+		cm.removeLocked(name)
+	}
+}
+
+// NumReadyClusters returns the number of remote clusters the agent is
+// connected to
+func (cm *ClusterMesh) NumReadyClusters() int {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+
+	n := 0
+	for _, rc := range cm.clusters {
+		if rc.isReady() {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,146 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustermesh
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/pkg/k8s/apis/cilium.io"
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/node"
+	"github.com/cilium/cilium/pkg/policy"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type ClusterMeshSuite struct {
+	// remoteClient is a client of the kvstore of the remote cluster
+	remoteClient kvstore.BackendOperations
+}
+
+var _ = Suite(&ClusterMeshSuite{})
+
+const (
+	// etcdAddress is the address of the etcd instance standing in for the
+	// kvstore of the remote cluster. The local cluster uses consul so
+	// that the state of both clusters remains separate.
+	etcdAddress = "http://127.0.0.1:4002"
+	etcdConfig  = "endpoints:\n- " + etcdAddress + "\n"
+
+	remoteName = "cluster1"
+)
+
+// remoteIdentity is an identity within the identity range of cluster ID 1
+var remoteIdentity = policy.NumericIdentity(1<<policy.ClusterIDShift | 256)
+
+func (s *ClusterMeshSuite) SetUpTest(c *C) {
+	kvstore.SetupDummy("consul")
+
+	client, err := kvstore.NewReadOnlyClient(kvstore.EtcdBackendName,
+		map[string]string{kvstore.EtcdAddrOption: etcdAddress})
+	c.Assert(err, IsNil)
+	s.remoteClient = client
+}
+
+func (s *ClusterMeshSuite) TearDownTest(c *C) {
+	s.remoteClient.DeletePrefix(path.Join(node.NodesPath, remoteName))
+	s.remoteClient.Delete(path.Join(policy.IdentitiesPath, "id", remoteIdentity.StringID()))
+	kvstore.CloseClient(s.remoteClient)
+	kvstore.Close()
+}
+
+func waitFor(c *C, cond func() bool) {
+	timeout := time.After(30 * time.Second)
+	for !cond() {
+		select {
+		case <-timeout:
+			c.Fatal("timeout while waiting for condition")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func (s *ClusterMeshSuite) TestClusterMesh(c *C) {
+	dir, err := ioutil.TempDir("", "clustermesh")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	// Populate the remote kvstore with a node and an identity
+	n := &node.Node{Name: "node1", Cluster: remoteName}
+	b, err := json.Marshal(n)
+	c.Assert(err, IsNil)
+	c.Assert(s.remoteClient.Update(path.Join(node.NodesPath, remoteName, n.Name), b, false), IsNil)
+
+	lbls := labels.NewLabelsFromModel([]string{"k8s:app=frontend"})
+	c.Assert(s.remoteClient.Update(path.Join(policy.IdentitiesPath, "id", remoteIdentity.StringID()),
+		[]byte(s.remoteClient.Encode(lbls.SortedList())), false), IsNil)
+
+	// The state of the remote cluster must not be visible in the local
+	// kvstore
+	v, err := kvstore.Get(path.Join(policy.IdentitiesPath, "id", remoteIdentity.StringID()))
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+
+	// Configuration files of the local cluster and hidden files are ignored
+	for _, name := range []string{remoteName, node.GetClusterName(), "..data"} {
+		c.Assert(ioutil.WriteFile(path.Join(dir, name), []byte(etcdConfig), 0644), IsNil)
+	}
+
+	cm, err := NewClusterMesh(Configuration{
+		Name:            node.GetClusterName(),
+		ConfigDirectory: dir,
+	})
+	c.Assert(err, IsNil)
+	defer cm.Close()
+
+	waitFor(c, func() bool { return cm.NumReadyClusters() == 1 })
+	cm.mutex.RLock()
+	c.Assert(len(cm.clusters), Equals, 1)
+	c.Assert(cm.clusters[remoteName], Not(IsNil))
+	cm.mutex.RUnlock()
+
+	ni := node.Identity{Name: n.Name, Cluster: remoteName}
+	waitFor(c, func() bool { return node.GetNode(ni) != nil })
+
+	waitFor(c, func() bool { return policy.LookupIdentityByID(remoteIdentity) != nil })
+	identity := policy.LookupIdentityByID(remoteIdentity)
+	c.Assert(identity.Labels[ciliumio.PolicyLabelCluster], Not(IsNil))
+	c.Assert(identity.Labels[ciliumio.PolicyLabelCluster].Value, Equals, remoteName)
+	c.Assert(identity.Labels["app"], Not(IsNil))
+
+	// Removing the configuration file disconnects the remote cluster
+	c.Assert(os.Remove(path.Join(dir, remoteName)), IsNil)
+	waitFor(c, func() bool { return cm.NumReadyClusters() == 0 })
+	waitFor(c, func() bool { return node.GetNode(ni) == nil })
+	c.Assert(policy.LookupIdentityByID(remoteIdentity), IsNil)
+}
+
+func (s *ClusterMeshSuite) TestNewClusterMeshInvalidConfig(c *C) {
+	_, err := NewClusterMesh(Configuration{})
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewClusterMesh(Configuration{ConfigDirectory: "/does/not/exist"})
+	c.Assert(err, Not(IsNil))
+}
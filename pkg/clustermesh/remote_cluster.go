@@ -0,0 +1,126 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustermesh
+
+import (
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/node"
+	"github.com/cilium/cilium/pkg/policy"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	fieldClusterName = "clusterName"
+	fieldConfig      = "config"
+)
+
+// remoteCluster represents a connection to the kvstore of a remote cluster
+type remoteCluster struct {
+	name       string
+	configPath string
+
+	controllers *controller.Manager
+
+	mutex      lock.RWMutex
+	client     kvstore.BackendOperations
+	nodes      *node.RemoteNodeWatcher
+	identities *policy.RemoteIdentityWatcher
+}
+
+func newRemoteCluster(name, configPath string) *remoteCluster {
+	return &remoteCluster{
+		name:        name,
+		configPath:  configPath,
+		controllers: controller.NewManager(),
+	}
+}
+
+func (rc *remoteCluster) getLogger() *logrus.Entry {
+	return log.WithFields(logrus.Fields{
+		fieldClusterName: rc.name,
+		fieldConfig:      rc.configPath,
+	})
+}
+
+// connect (re-)establishes the connection to the kvstore of the remote
+// cluster in the background. The connection is retried until it succeeds.
+func (rc *remoteCluster) connect() {
+	rc.controllers.UpdateController(rc.remoteConnectionControllerName(),
+		controller.ControllerParams{
+			DoFunc: func() error {
+				rc.releaseConnection()
+
+				client, err := kvstore.NewReadOnlyClient(kvstore.EtcdBackendName,
+					map[string]string{kvstore.EtcdOptionConfig: rc.configPath})
+				if err != nil {
+					rc.getLogger().WithError(err).Warning("Unable to connect to remote cluster")
+					return err
+				}
+
+				rc.mutex.Lock()
+				rc.client = client
+				rc.nodes = node.WatchRemoteNodes(rc.name, client)
+				rc.identities = policy.WatchRemoteIdentities(rc.name, client)
+				rc.mutex.Unlock()
+
+				rc.getLogger().Info("Connected to remote cluster")
+
+				return nil
+			},
+		},
+	)
+}
+
+func (rc *remoteCluster) remoteConnectionControllerName() string {
+	return "remote-etcd-" + rc.name
+}
+
+// releaseConnection stops all watchers and closes the kvstore client of the
+// remote cluster
+func (rc *remoteCluster) releaseConnection() {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if rc.nodes != nil {
+		rc.nodes.Stop()
+		rc.nodes = nil
+	}
+
+	if rc.identities != nil {
+		rc.identities.Stop()
+		rc.identities = nil
+	}
+
+	if rc.client != nil {
+		kvstore.CloseClient(rc.client)
+		rc.client = nil
+	}
+}
+
+// disconnect stops connecting to the remote cluster and releases all
+// resources
+func (rc *remoteCluster) disconnect() {
+	rc.controllers.RemoveAll()
+	rc.releaseConnection()
+}
+
+func (rc *remoteCluster) isReady() bool {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+	return rc.client != nil
+}
@@ -0,0 +1,374 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressgateway
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/egressmap"
+	"github.com/cilium/cilium/pkg/node"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// syncControllerName is the name of the controller reconciling the
+	// datapath with the egress gateway policies
+	syncControllerName = "egress-gateway-sync"
+
+	// syncInterval is the interval in which the datapath is reconciled to
+	// pick up changes of endpoints and nodes
+	syncInterval = 5 * time.Second
+)
+
+var (
+	log = logging.DefaultLogger.WithField(logfields.LogSubsys, "egressgateway")
+
+	// getLocalNodeName returns the name of the local node
+	getLocalNodeName = node.GetName
+
+	// getNodeIP returns the IP used to tunnel to the node with the given
+	// name, or nil if the node is unknown
+	getNodeIP = func(name string) net.IP {
+		if n := node.GetNode(node.Identity{Name: name}); n != nil {
+			return n.GetNodeIP(false)
+		}
+		return nil
+	}
+)
+
+// Endpoint is a local endpoint subject to egress gateway policies
+type Endpoint struct {
+	IPv4   net.IP
+	Labels labels.LabelArray
+}
+
+// SNATRule translates the source address of packets to Destination leaving
+// the gateway node to EgressIP
+type SNATRule struct {
+	// Source is the IP of a local endpoint, or nil for the packets of
+	// endpoints of remote nodes
+	Source      net.IP
+	Destination *net.IPNet
+	EgressIP    net.IP
+}
+
+// Owner is the interface the owner of a Manager must implement
+type Owner interface {
+	// GetEgressEndpoints returns all local endpoints with an IPv4 address
+	GetEgressEndpoints() []Endpoint
+
+	// UpdateEgressGatewayRules replaces the SNAT rules of the policies
+	// for which the local node is the gateway. Rules of local endpoints
+	// precede the rules of remote endpoints.
+	UpdateEgressGatewayRules(rules []SNATRule) error
+}
+
+// Manager manages the egress gateway policies and reconciles the datapath
+// with them
+type Manager struct {
+	// mutex protects all fields and serializes the reconciliation
+	mutex lock.Mutex
+
+	owner       Owner
+	policies    map[string]*Policy
+	rules       []SNATRule
+	started     bool
+	controllers *controller.Manager
+}
+
+// NewManager returns a new egress gateway policy manager. Start() must be
+// called to reconcile the datapath.
+func NewManager(owner Owner) *Manager {
+	return &Manager{
+		owner:       owner,
+		policies:    map[string]*Policy{},
+		controllers: controller.NewManager(),
+	}
+}
+
+// Start opens the egress gateway maps and starts the periodic
+// reconciliation of the datapath
+func (m *Manager) Start() error {
+	if err := egressmap.OpenMaps(); err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	m.started = true
+	m.mutex.Unlock()
+
+	m.trigger()
+	return nil
+}
+
+// trigger schedules an immediate reconciliation of the datapath if the
+// manager has been started
+func (m *Manager) trigger() {
+	m.mutex.Lock()
+	started := m.started
+	m.mutex.Unlock()
+
+	if !started {
+		return
+	}
+
+	m.controllers.UpdateController(syncControllerName,
+		controller.ControllerParams{
+			DoFunc:      m.sync,
+			RunInterval: syncInterval,
+		},
+	)
+}
+
+// Upsert adds or replaces the policy p. Returns true if the policy was
+// created.
+func (m *Manager) Upsert(p *Policy) (bool, error) {
+	if err := p.Sanitize(); err != nil {
+		return false, err
+	}
+
+	m.mutex.Lock()
+	_, exists := m.policies[p.Name]
+	m.policies[p.Name] = p
+	m.mutex.Unlock()
+
+	log.WithFields(logrus.Fields{
+		logfields.EgressGatewayPolicy: p.Name,
+		logfields.NodeName:            p.GatewayNode,
+		logfields.IPAddr:              p.EgressIP,
+	}).Info("Upserted egress gateway policy")
+
+	m.trigger()
+	return !exists, nil
+}
+
+// Delete removes the policy with the given name
+func (m *Manager) Delete(name string) error {
+	m.mutex.Lock()
+	_, exists := m.policies[name]
+	delete(m.policies, name)
+	m.mutex.Unlock()
+
+	if !exists {
+		return fmt.Errorf("egress gateway policy %q not found", name)
+	}
+
+	log.WithField(logfields.EgressGatewayPolicy, name).Info("Deleted egress gateway policy")
+
+	m.trigger()
+	return nil
+}
+
+// Get returns the policy with the given name or nil
+func (m *Manager) Get(name string) *Policy {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.policies[name]
+}
+
+// GetPolicies returns all policies sorted by name
+func (m *Manager) GetPolicies() []*Policy {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.sortedPolicies()
+}
+
+// sortedPolicies returns all policies sorted by name. Must be called with
+// m.mutex held.
+func (m *Manager) sortedPolicies() []*Policy {
+	policies := make([]*Policy, 0, len(m.policies))
+	for _, p := range m.policies {
+		policies = append(policies, p)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+	return policies
+}
+
+// desiredState is the datapath state derived from the policies
+type desiredState struct {
+	entries   map[egressmap.Key4]egressmap.Info4
+	gwEntries map[egressmap.GatewayKey4]egressmap.GatewayInfo4
+	rules     []SNATRule
+}
+
+// computeState derives the datapath state from the policies and the local
+// endpoints. Policies are applied in the order of their names, the first
+// policy matching an endpoint and a destination prefix takes precedence.
+func computeState(policies []*Policy, endpoints []Endpoint) *desiredState {
+	state := &desiredState{
+		entries:   map[egressmap.Key4]egressmap.Info4{},
+		gwEntries: map[egressmap.GatewayKey4]egressmap.GatewayInfo4{},
+	}
+	localRules, remoteRules := []SNATRule{}, []SNATRule{}
+	localNode := getLocalNodeName()
+
+	for _, p := range policies {
+		var gatewayIP net.IP
+
+		isGateway := p.GatewayNode == localNode
+		if !isGateway {
+			if gatewayIP = getNodeIP(p.GatewayNode); gatewayIP == nil {
+				log.WithFields(logrus.Fields{
+					logfields.EgressGatewayPolicy: p.Name,
+					logfields.NodeName:            p.GatewayNode,
+				}).Debug("Ignoring egress gateway policy of unknown gateway node")
+				continue
+			}
+		}
+
+		info := egressmap.NewInfo4(p.EgressIP, gatewayIP)
+		for _, ep := range endpoints {
+			if !p.EndpointSelector.Matches(ep.Labels) {
+				continue
+			}
+
+			for _, prefix := range p.DestinationCIDRs {
+				key := egressmap.NewKey4(ep.IPv4, prefix)
+				if _, ok := state.entries[key]; ok {
+					continue
+				}
+				state.entries[key] = info
+
+				if isGateway {
+					localRules = append(localRules, SNATRule{
+						Source:      ep.IPv4,
+						Destination: prefix,
+						EgressIP:    p.EgressIP,
+					})
+				}
+			}
+		}
+
+		if !isGateway {
+			continue
+		}
+
+		gwInfo := egressmap.GatewayInfo4{}
+		copy(gwInfo.EgressIP[:], p.EgressIP.To4())
+		for _, prefix := range p.DestinationCIDRs {
+			key := egressmap.NewGatewayKey4(prefix)
+			if _, ok := state.gwEntries[key]; ok {
+				continue
+			}
+			state.gwEntries[key] = gwInfo
+			remoteRules = append(remoteRules, SNATRule{
+				Destination: prefix,
+				EgressIP:    p.EgressIP,
+			})
+		}
+	}
+
+	state.rules = append(localRules, remoteRules...)
+	return state
+}
+
+// removeStaleEntries removes all entries which are not desired from the
+// egress gateway maps
+func removeStaleEntries(state *desiredState) error {
+	current, err := egressmap.Dump()
+	if err != nil {
+		return err
+	}
+	for key := range current {
+		if _, ok := state.entries[key]; !ok {
+			if err := egressmap.Map4.Delete(&key); err != nil {
+				return err
+			}
+		}
+	}
+
+	currentGw, err := egressmap.DumpGateway()
+	if err != nil {
+		return err
+	}
+	for key := range currentGw {
+		if _, ok := state.gwEntries[key]; !ok {
+			if err := egressmap.GatewayMap4.Delete(&key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeEntries writes all desired entries into the egress gateway maps
+func writeEntries(state *desiredState) error {
+	current, err := egressmap.Dump()
+	if err != nil {
+		return err
+	}
+	for key, info := range state.entries {
+		if old, ok := current[key]; !ok || old != info {
+			if err := egressmap.Map4.Update(&key, &info); err != nil {
+				return err
+			}
+		}
+	}
+
+	currentGw, err := egressmap.DumpGateway()
+	if err != nil {
+		return err
+	}
+	for key, info := range state.gwEntries {
+		if old, ok := currentGw[key]; !ok || old != info {
+			if err := egressmap.GatewayMap4.Update(&key, &info); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// sync reconciles the datapath with the policies
+func (m *Manager) sync() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	state := computeState(m.sortedPolicies(), m.owner.GetEgressEndpoints())
+
+	// The SNAT rules are updated after stale map entries have been removed
+	// and before new entries are written so that traffic is never
+	// forwarded by the gateway node without being translated.
+	if err := removeStaleEntries(state); err != nil {
+		return fmt.Errorf("unable to update egress gateway maps: %s", err)
+	}
+
+	if !reflect.DeepEqual(state.rules, m.rules) {
+		if err := m.owner.UpdateEgressGatewayRules(state.rules); err != nil {
+			return fmt.Errorf("unable to update egress gateway SNAT rules: %s", err)
+		}
+		m.rules = state.rules
+	}
+
+	if err := writeEntries(state); err != nil {
+		return fmt.Errorf("unable to update egress gateway maps: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressgateway
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/maps/egressmap"
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type EgressGatewaySuite struct {
+	prevBackend      bpf.MapBackend
+	prevLocalNode    func() string
+	prevGetNodeIP    func(string) net.IP
+	owner            *fakeOwner
+	manager          *Manager
+	frontendEndpoint Endpoint
+	backendEndpoint  Endpoint
+}
+
+var _ = Suite(&EgressGatewaySuite{})
+
+type fakeOwner struct {
+	endpoints []Endpoint
+	rules     []SNATRule
+	updates   int
+	err       error
+}
+
+func (o *fakeOwner) GetEgressEndpoints() []Endpoint {
+	return o.endpoints
+}
+
+func (o *fakeOwner) UpdateEgressGatewayRules(rules []SNATRule) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.rules = rules
+	o.updates++
+	return nil
+}
+
+func mustParseCIDR(c *C, s string) *net.IPNet {
+	_, prefix, err := net.ParseCIDR(s)
+	c.Assert(err, IsNil)
+	return prefix
+}
+
+func newTestPolicy(c *C, name, selector, gateway, egressIP string, cidrs ...string) *Policy {
+	p := &Policy{
+		Name:             name,
+		EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel(selector)),
+		GatewayNode:      gateway,
+		EgressIP:         net.ParseIP(egressIP),
+	}
+	for _, cidr := range cidrs {
+		p.DestinationCIDRs = append(p.DestinationCIDRs, mustParseCIDR(c, cidr))
+	}
+	return p
+}
+
+func (s *EgressGatewaySuite) SetUpTest(c *C) {
+	s.prevBackend = bpf.SetMapBackend(bpf.NewMemoryBackend())
+	c.Assert(egressmap.OpenMaps(), IsNil)
+	c.Assert(egressmap.Map4.DeleteAll(), IsNil)
+	c.Assert(egressmap.GatewayMap4.DeleteAll(), IsNil)
+
+	s.prevLocalNode, s.prevGetNodeIP = getLocalNodeName, getNodeIP
+	getLocalNodeName = func() string { return "node1" }
+	getNodeIP = func(name string) net.IP {
+		if name == "node2" {
+			return net.ParseIP("10.0.0.2")
+		}
+		return nil
+	}
+
+	s.frontendEndpoint = Endpoint{
+		IPv4:   net.ParseIP("10.1.0.1"),
+		Labels: labels.ParseLabelArray("k8s:app=frontend"),
+	}
+	s.backendEndpoint = Endpoint{
+		IPv4:   net.ParseIP("10.1.0.2"),
+		Labels: labels.ParseLabelArray("k8s:app=backend"),
+	}
+	s.owner = &fakeOwner{
+		endpoints: []Endpoint{s.frontendEndpoint, s.backendEndpoint},
+	}
+	s.manager = NewManager(s.owner)
+}
+
+func (s *EgressGatewaySuite) TearDownTest(c *C) {
+	getLocalNodeName, getNodeIP = s.prevLocalNode, s.prevGetNodeIP
+	c.Assert(egressmap.Map4.Close(), IsNil)
+	c.Assert(egressmap.GatewayMap4.Close(), IsNil)
+	bpf.SetMapBackend(s.prevBackend)
+}
+
+func (s *EgressGatewaySuite) TestUpsertDelete(c *C) {
+	p := newTestPolicy(c, "b", "k8s:app=frontend", "node2", "192.0.2.1", "203.0.113.0/24")
+	created, err := s.manager.Upsert(p)
+	c.Assert(err, IsNil)
+	c.Assert(created, Equals, true)
+
+	created, err = s.manager.Upsert(p)
+	c.Assert(err, IsNil)
+	c.Assert(created, Equals, false)
+
+	_, err = s.manager.Upsert(newTestPolicy(c, "a", "k8s:app=backend", "node1", "192.0.2.2", "198.51.100.0/24"))
+	c.Assert(err, IsNil)
+
+	policies := s.manager.GetPolicies()
+	c.Assert(len(policies), Equals, 2)
+	c.Assert(policies[0].Name, Equals, "a")
+	c.Assert(policies[1].Name, Equals, "b")
+	c.Assert(s.manager.Get("b"), Equals, p)
+
+	c.Assert(s.manager.Delete("b"), IsNil)
+	c.Assert(s.manager.Get("b"), IsNil)
+	c.Assert(s.manager.Delete("b"), Not(IsNil))
+}
+
+func (s *EgressGatewaySuite) TestUpsertInvalid(c *C) {
+	invalid := []*Policy{
+		newTestPolicy(c, "", "k8s:app=frontend", "node2", "192.0.2.1", "203.0.113.0/24"),
+		{
+			Name:             "no-selector",
+			DestinationCIDRs: []*net.IPNet{mustParseCIDR(c, "203.0.113.0/24")},
+			GatewayNode:      "node2",
+			EgressIP:         net.ParseIP("192.0.2.1"),
+		},
+		newTestPolicy(c, "no-cidrs", "k8s:app=frontend", "node2", "192.0.2.1"),
+		newTestPolicy(c, "ipv6-cidr", "k8s:app=frontend", "node2", "192.0.2.1", "f00d::/64"),
+		newTestPolicy(c, "no-gateway", "k8s:app=frontend", "", "192.0.2.1", "203.0.113.0/24"),
+		newTestPolicy(c, "ipv6-egress-ip", "k8s:app=frontend", "node2", "f00d::1", "203.0.113.0/24"),
+	}
+
+	for _, p := range invalid {
+		_, err := s.manager.Upsert(p)
+		c.Assert(err, Not(IsNil), Commentf("policy %q", p.Name))
+	}
+	c.Assert(len(s.manager.GetPolicies()), Equals, 0)
+}
+
+func (s *EgressGatewaySuite) TestComputeState(c *C) {
+	remote := newTestPolicy(c, "remote", "k8s:app=frontend", "node2", "192.0.2.1", "203.0.113.0/24")
+	local := newTestPolicy(c, "local", "k8s:app=backend", "node1", "192.0.2.2", "198.51.100.0/24", "203.0.113.0/24")
+	unknown := newTestPolicy(c, "unknown", "k8s:app=frontend", "node3", "192.0.2.3", "198.51.100.0/24")
+
+	state := computeState([]*Policy{local, remote, unknown}, s.owner.endpoints)
+
+	c.Assert(state.entries, DeepEquals, map[egressmap.Key4]egressmap.Info4{
+		egressmap.NewKey4(s.frontendEndpoint.IPv4, remote.DestinationCIDRs[0]): egressmap.NewInfo4(remote.EgressIP, net.ParseIP("10.0.0.2")),
+		egressmap.NewKey4(s.backendEndpoint.IPv4, local.DestinationCIDRs[0]):   egressmap.NewInfo4(local.EgressIP, nil),
+		egressmap.NewKey4(s.backendEndpoint.IPv4, local.DestinationCIDRs[1]):   egressmap.NewInfo4(local.EgressIP, nil),
+	})
+
+	gwInfo := egressmap.GatewayInfo4{}
+	copy(gwInfo.EgressIP[:], local.EgressIP.To4())
+	c.Assert(state.gwEntries, DeepEquals, map[egressmap.GatewayKey4]egressmap.GatewayInfo4{
+		egressmap.NewGatewayKey4(local.DestinationCIDRs[0]): gwInfo,
+		egressmap.NewGatewayKey4(local.DestinationCIDRs[1]): gwInfo,
+	})
+
+	c.Assert(state.rules, DeepEquals, []SNATRule{
+		{Source: s.backendEndpoint.IPv4, Destination: local.DestinationCIDRs[0], EgressIP: local.EgressIP},
+		{Source: s.backendEndpoint.IPv4, Destination: local.DestinationCIDRs[1], EgressIP: local.EgressIP},
+		{Destination: local.DestinationCIDRs[0], EgressIP: local.EgressIP},
+		{Destination: local.DestinationCIDRs[1], EgressIP: local.EgressIP},
+	})
+}
+
+func (s *EgressGatewaySuite) TestComputeStatePrecedence(c *C) {
+	first := newTestPolicy(c, "a", "k8s:app=frontend", "node2", "192.0.2.1", "203.0.113.0/24")
+	second := newTestPolicy(c, "b", "k8s:app=frontend", "node1", "192.0.2.2", "203.0.113.0/24")
+
+	state := computeState([]*Policy{first, second}, s.owner.endpoints)
+
+	key := egressmap.NewKey4(s.frontendEndpoint.IPv4, first.DestinationCIDRs[0])
+	c.Assert(len(state.entries), Equals, 1)
+	c.Assert(state.entries[key], Equals, egressmap.NewInfo4(first.EgressIP, net.ParseIP("10.0.0.2")))
+
+	// The local node remains the gateway of the destination for remote
+	// endpoints selected by the second policy
+	c.Assert(state.rules, DeepEquals, []SNATRule{
+		{Destination: second.DestinationCIDRs[0], EgressIP: second.EgressIP},
+	})
+}
+
+func (s *EgressGatewaySuite) TestSync(c *C) {
+	remote := newTestPolicy(c, "remote", "k8s:app=frontend", "node2", "192.0.2.1", "203.0.113.0/24")
+	local := newTestPolicy(c, "local", "k8s:app=backend", "node1", "192.0.2.2", "198.51.100.0/24")
+
+	_, err := s.manager.Upsert(remote)
+	c.Assert(err, IsNil)
+	_, err = s.manager.Upsert(local)
+	c.Assert(err, IsNil)
+	c.Assert(s.manager.sync(), IsNil)
+
+	entries, err := egressmap.Dump()
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 2)
+	gwEntries, err := egressmap.DumpGateway()
+	c.Assert(err, IsNil)
+	c.Assert(len(gwEntries), Equals, 1)
+	c.Assert(len(s.owner.rules), Equals, 2)
+	c.Assert(s.owner.updates, Equals, 1)
+
+	// The datapath lookup of a packet of the frontend endpoint returns
+	// the remote gateway
+	key := egressmap.NewKey4(s.frontendEndpoint.IPv4, mustParseCIDR(c, "203.0.113.7/32"))
+	value, err := egressmap.Map4.Lookup(&key)
+	c.Assert(err, IsNil)
+	c.Assert(*value.(*egressmap.Info4), Equals, egressmap.NewInfo4(remote.EgressIP, net.ParseIP("10.0.0.2")))
+
+	// Unchanged rules are not updated again
+	c.Assert(s.manager.sync(), IsNil)
+	c.Assert(s.owner.updates, Equals, 1)
+
+	c.Assert(s.manager.Delete("local"), IsNil)
+	c.Assert(s.manager.sync(), IsNil)
+
+	entries, err = egressmap.Dump()
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 1)
+	gwEntries, err = egressmap.DumpGateway()
+	c.Assert(err, IsNil)
+	c.Assert(len(gwEntries), Equals, 0)
+	c.Assert(len(s.owner.rules), Equals, 0)
+	c.Assert(s.owner.updates, Equals, 2)
+}
+
+func (s *EgressGatewaySuite) TestSyncRulesFailure(c *C) {
+	local := newTestPolicy(c, "local", "k8s:app=backend", "node1", "192.0.2.2", "198.51.100.0/24")
+	_, err := s.manager.Upsert(local)
+	c.Assert(err, IsNil)
+
+	// Map entries are not written if the SNAT rules cannot be installed
+	s.owner.err = fmt.Errorf("iptables failure")
+	c.Assert(s.manager.sync(), Not(IsNil))
+	entries, err := egressmap.Dump()
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 0)
+
+	s.owner.err = nil
+	c.Assert(s.manager.sync(), IsNil)
+	entries, err = egressmap.Dump()
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 1)
+}
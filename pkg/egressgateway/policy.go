@@ -0,0 +1,134 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressgateway
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// Policy redirects the traffic of the endpoints selected by EndpointSelector
+// to DestinationCIDRs through the node GatewayNode, which translates the
+// source address to EgressIP.
+type Policy struct {
+	// Name is the unique name of the policy
+	Name string
+
+	// EndpointSelector selects the endpoints of the policy
+	EndpointSelector api.EndpointSelector
+
+	// DestinationCIDRs are the IPv4 prefixes of the external destinations
+	DestinationCIDRs []*net.IPNet
+
+	// GatewayNode is the name of the node forwarding the traffic
+	GatewayNode string
+
+	// EgressIP is the source address of the traffic leaving the gateway
+	// node. It must be assigned to an interface of the gateway node.
+	EgressIP net.IP
+}
+
+// ParseCIDRs parses a list of IPv4 prefixes
+func ParseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	prefixes := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, prefix, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination CIDR %q: %s", c, err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// Sanitize validates the policy
+func (p *Policy) Sanitize() error {
+	if p.Name == "" {
+		return fmt.Errorf("policy name must not be empty")
+	}
+
+	if p.EndpointSelector.LabelSelector == nil {
+		return fmt.Errorf("endpoint selector must be specified")
+	}
+
+	if len(p.DestinationCIDRs) == 0 {
+		return fmt.Errorf("at least one destination CIDR must be specified")
+	}
+
+	for _, prefix := range p.DestinationCIDRs {
+		if prefix.IP.To4() == nil {
+			return fmt.Errorf("destination CIDR %s is not an IPv4 prefix", prefix)
+		}
+	}
+
+	if p.GatewayNode == "" {
+		return fmt.Errorf("gateway node must be specified")
+	}
+
+	if p.EgressIP.To4() == nil {
+		return fmt.Errorf("egress IP %q is not an IPv4 address", p.EgressIP)
+	}
+
+	return nil
+}
+
+// NewPolicyFromModel returns the policy with the given name from its API
+// model. The policy is not sanitized.
+func NewPolicyFromModel(name string, m *models.EgressGatewayPolicy) (*Policy, error) {
+	p := &Policy{
+		Name:        name,
+		GatewayNode: m.GatewayNode,
+		EgressIP:    net.ParseIP(m.EgressIP),
+	}
+
+	if m.Name != "" && m.Name != name {
+		return nil, fmt.Errorf("policy name %q does not match %q", m.Name, name)
+	}
+
+	if err := p.EndpointSelector.UnmarshalJSON([]byte(m.EndpointSelector)); err != nil {
+		return nil, fmt.Errorf("invalid endpoint selector: %s", err)
+	}
+
+	cidrs, err := ParseCIDRs(m.DestinationCidrs)
+	if err != nil {
+		return nil, err
+	}
+	p.DestinationCIDRs = cidrs
+
+	if p.EgressIP == nil {
+		return nil, fmt.Errorf("invalid egress IP %q", m.EgressIP)
+	}
+
+	return p, nil
+}
+
+// GetModel returns the API model of the policy
+func (p *Policy) GetModel() *models.EgressGatewayPolicy {
+	cidrs := make([]string, 0, len(p.DestinationCIDRs))
+	for _, prefix := range p.DestinationCIDRs {
+		cidrs = append(cidrs, prefix.String())
+	}
+
+	return &models.EgressGatewayPolicy{
+		Name:             p.Name,
+		EndpointSelector: p.EndpointSelector.String(),
+		DestinationCidrs: cidrs,
+		GatewayNode:      p.GatewayNode,
+		EgressIP:         p.EgressIP.String(),
+	}
+}
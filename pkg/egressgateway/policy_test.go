@@ -0,0 +1,57 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressgateway
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *EgressGatewaySuite) TestPolicyModel(c *C) {
+	p := newTestPolicy(c, "partner", "k8s:app=frontend", "node2", "192.0.2.1", "203.0.113.0/24", "198.51.100.0/24")
+
+	model := p.GetModel()
+	c.Assert(model.Name, Equals, "partner")
+	c.Assert(model.DestinationCidrs, DeepEquals, []string{"203.0.113.0/24", "198.51.100.0/24"})
+	c.Assert(model.GatewayNode, Equals, "node2")
+	c.Assert(model.EgressIP, Equals, "192.0.2.1")
+
+	parsed, err := NewPolicyFromModel("partner", model)
+	c.Assert(err, IsNil)
+	c.Assert(parsed.Sanitize(), IsNil)
+	c.Assert(parsed.EndpointSelector.Matches(s.frontendEndpoint.Labels), Equals, true)
+	c.Assert(parsed.EndpointSelector.Matches(s.backendEndpoint.Labels), Equals, false)
+	c.Assert(parsed.GetModel(), DeepEquals, model)
+}
+
+func (s *EgressGatewaySuite) TestPolicyModelInvalid(c *C) {
+	valid := newTestPolicy(c, "partner", "k8s:app=frontend", "node2", "192.0.2.1", "203.0.113.0/24").GetModel()
+
+	_, err := NewPolicyFromModel("other", valid)
+	c.Assert(err, Not(IsNil))
+
+	invalid := []func(m *models.EgressGatewayPolicy){
+		func(m *models.EgressGatewayPolicy) { m.EndpointSelector = "{" },
+		func(m *models.EgressGatewayPolicy) { m.DestinationCidrs = []string{"203.0.113.0"} },
+		func(m *models.EgressGatewayPolicy) { m.EgressIP = "192.0.2" },
+	}
+	for i, modify := range invalid {
+		m := *valid
+		modify(&m)
+		_, err := NewPolicyFromModel("partner", &m)
+		c.Assert(err, Not(IsNil), Commentf("case %d", i))
+	}
+}
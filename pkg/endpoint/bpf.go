@@ -194,9 +194,12 @@ func (e *Endpoint) writeHeaderfile(prefix string, owner Owner) error {
 
 	fmt.Fprintf(fw, "#define LXC_ID %#x\n", e.ID)
 	fmt.Fprintf(fw, "#define LXC_ID_NB %#x\n", byteorder.HostToNetwork(e.ID))
+	// SECLABEL_NB is stored in the 20 bit IPv6 flow label and thus only
+	// carries the cluster local part of the identity. The cluster bits
+	// are restored by bpf_netdev.
 	if e.SecLabel != nil {
 		fmt.Fprintf(fw, "#define SECLABEL %s\n", e.SecLabel.ID.StringID())
-		fmt.Fprintf(fw, "#define SECLABEL_NB %#x\n", byteorder.HostToNetwork(e.SecLabel.ID.Uint32()))
+		fmt.Fprintf(fw, "#define SECLABEL_NB %#x\n", byteorder.HostToNetwork(e.SecLabel.ID.ClusterLocal().Uint32()))
 	} else {
 		invalid := policy.InvalidIdentity
 		fmt.Fprintf(fw, "#define SECLABEL %s\n", invalid.StringID())
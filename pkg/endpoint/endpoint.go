@@ -44,6 +44,7 @@ import (
 	"github.com/cilium/cilium/pkg/maps/policymap"
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/u8proto"
 
 	"github.com/sirupsen/logrus"
 )
@@ -160,6 +161,16 @@ func init() {
 	for k, v := range EndpointMutableOptionLibrary {
 		EndpointOptionLibrary[k] = v
 	}
+
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        CallsMapName,
+		Prefix:      true,
+		Description: "Tail call programs of an endpoint or device",
+	})
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        PolicyGlobalMapName,
+		Description: "Policy programs of all endpoints indexed by endpoint ID",
+	})
 }
 
 const (
@@ -320,6 +331,10 @@ type Endpoint struct {
 	// endpoint mutex after policy recalculation.
 	forcePolicyCompute bool
 
+	// policyMapEntries is the number of policy map entries required by the
+	// most recently computed policy
+	policyMapEntries int
+
 	// BuildMutex synchronizes builds of individual endpoints and locks out
 	// deletion during builds
 	//
@@ -482,6 +497,7 @@ func (e *Endpoint) GetModelRLocked() *models.Endpoint {
 		Health:         e.getHealthModel(),
 		Policy:         e.GetPolicyModel(),
 		PolicyEnabled:  &policy,
+		PolicyMap:      e.getPolicyMapModel(),
 		PolicyRevision: int64(e.policyRevision),
 		Addressing: &models.EndpointAddressing{
 			IPV4: e.IPv4.String(),
@@ -602,6 +618,75 @@ func (e *Endpoint) GetPolicyModel() *models.EndpointPolicy {
 	}
 }
 
+// getPolicyMapModel returns the fill level of the endpoint's policy map.
+// e.Mutex must be RLocked.
+func (e *Endpoint) getPolicyMapModel() *models.EndpointPolicyMap {
+	if e.PolicyMap == nil {
+		return nil
+	}
+
+	return &models.EndpointPolicyMap{
+		Entries:    int64(e.policyMapEntries),
+		MaxEntries: int64(e.PolicyMap.MaxEntries),
+	}
+}
+
+// GetPolicyStats returns the entries of the endpoint's policy map including
+// their packet and byte counters.
+func (e *Endpoint) GetPolicyStats() ([]policymap.PolicyEntryDump, error) {
+	e.Mutex.RLock()
+	defer e.Mutex.RUnlock()
+
+	if e.PolicyMap == nil {
+		return []policymap.PolicyEntryDump{}, nil
+	}
+	return e.PolicyMap.DumpToSlice()
+}
+
+// GetPolicyStatsModel returns the packet and byte counters of the entries of
+// the endpoint's policy map as an API model, joined with the labels of the
+// identities the entries allow. Entries are ordered by identity and port.
+func (e *Endpoint) GetPolicyStatsModel() (*models.EndpointPolicyStats, error) {
+	dumps, err := e.GetPolicyStats()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.EndpointPolicyStats{
+		ID:      int64(e.ID),
+		Entries: []*models.PolicyStatsEntry{},
+	}
+
+	for _, dump := range dumps {
+		id := policy.NumericIdentity(dump.Key.Identity)
+		entry := &models.PolicyStatsEntry{
+			Identity: int64(id),
+			Labels:   policy.ResolveIdentityLabels(id).GetModel(),
+			Packets:  int64(dump.Packets),
+			Bytes:    int64(dump.Bytes),
+		}
+		if port := dump.Key.GetDestPort(); port != 0 {
+			entry.Port = int64(port)
+			entry.Protocol = u8proto.U8proto(dump.Key.Nexthdr).String()
+		}
+		stats.Entries = append(stats.Entries, entry)
+	}
+
+	sort.Slice(stats.Entries, func(i, j int) bool {
+		a, b := stats.Entries[i], stats.Entries[j]
+		switch {
+		case a.Identity != b.Identity:
+			return a.Identity < b.Identity
+		case a.Port != b.Port:
+			return a.Port < b.Port
+		default:
+			return a.Protocol < b.Protocol
+		}
+	})
+
+	return stats, nil
+}
+
 // GetID returns the endpoint's ID
 func (e *Endpoint) GetID() uint64 {
 	return uint64(e.ID)
@@ -1034,7 +1119,7 @@ func (e *Endpoint) GetBPFValue() (*lxcmap.EndpointInfo, error) {
 		// Store security label in network byte order so it can be
 		// written into the packet without an additional byte order
 		// conversion.
-		SecLabelID: byteorder.HostToNetwork(uint16(e.GetIdentity())).(uint16),
+		SecLabelID: byteorder.HostToNetwork(e.GetIdentity().Uint32()).(uint32),
 		LxcID:      e.ID,
 		MAC:        lxcmap.MAC(mac),
 		NodeMAC:    lxcmap.MAC(nodeMAC),
@@ -1673,6 +1758,18 @@ func (e *Endpoint) ModifyIdentityLabels(owner Owner, addLabels, delLabels labels
 	return nil
 }
 
+// RefreshIdentity resolves the identity of the endpoint again without a change
+// of labels. This is required when the identity allocator rejected the
+// identity currently in use by the endpoint.
+func (e *Endpoint) RefreshIdentity(owner Owner) {
+	e.Mutex.Lock()
+	e.identityRevision++
+	rev := e.identityRevision
+	e.Mutex.Unlock()
+
+	e.runLabelsResolver(owner, rev)
+}
+
 // UpdateLabels is called to update the labels of an endpoint. Calls to this
 // function do not necessarily mean that the labels actually changed. The
 // container runtime layer will periodically synchronize labels.
@@ -21,7 +21,12 @@ import (
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common/addressing"
+	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/comparator"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/maps/policymap"
+	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
 
 	. "gopkg.in/check.v1"
 )
@@ -290,3 +295,81 @@ func (s *EndpointSuite) TestEndpointState(c *C) {
 	c.Assert(e.SetStateLocked(StateDisconnecting, "test"), Equals, false)
 	c.Assert(e.SetStateLocked(StateDisconnected, "test"), Equals, true)
 }
+
+func (s *EndpointSuite) TestGetPolicyStatsModel(c *C) {
+	prev := bpf.SetMapBackend(bpf.NewMemoryBackend())
+	defer bpf.SetMapBackend(prev)
+
+	e := Endpoint{ID: 4370}
+	stats, err := e.GetPolicyStatsModel()
+	c.Assert(err, IsNil)
+	c.Assert(stats, DeepEquals, &models.EndpointPolicyStats{
+		ID:      4370,
+		Entries: []*models.PolicyStatsEntry{},
+	})
+
+	pm, _, err := policymap.OpenMap("cilium_policy_4370")
+	c.Assert(err, IsNil)
+	defer pm.Close()
+	e.PolicyMap = pm
+
+	id := policy.NumericIdentity(1000)
+	cache := policy.GetConsumableCache()
+	consumable := cache.GetOrCreate(id, policy.NewIdentity(id, labels.NewLabelsFromModel([]string{"k8s:app=foo"})))
+	defer cache.Remove(consumable)
+
+	c.Assert(pm.AllowL4(1000, 80, 6), IsNil)
+	c.Assert(pm.AllowConsumer(1001), IsNil)
+	c.Assert(pm.AllowConsumer(1000), IsNil)
+
+	stats, err = e.GetPolicyStatsModel()
+	c.Assert(err, IsNil)
+	c.Assert(stats.ID, Equals, int64(4370))
+	c.Assert(stats.Entries, DeepEquals, []*models.PolicyStatsEntry{
+		{Identity: 1000, Labels: models.Labels{"k8s:app=foo"}},
+		{Identity: 1000, Labels: models.Labels{"k8s:app=foo"}, Port: 80, Protocol: "TCP"},
+		{Identity: 1001, Labels: models.Labels{}},
+	})
+}
+
+func (s *EndpointSuite) TestRequiredPolicyMapEntries(c *C) {
+	labelsMap := policy.IdentityCache{
+		1000: labels.ParseLabelArray("k8s:app=foo"),
+		1001: labels.ParseLabelArray("k8s:app=bar"),
+	}
+	l4 := policy.NewL4Policy()
+	l4.Ingress["80/TCP"] = policy.L4Filter{
+		Port:          80,
+		Protocol:      api.ProtoTCP,
+		U8Proto:       6,
+		FromEndpoints: []api.EndpointSelector{api.NewESFromLabels(labels.ParseSelectLabel("k8s:app=foo"))},
+	}
+	consumers := []policy.NumericIdentity{1000, 1001}
+
+	c.Assert(requiredPolicyMapEntries(&labelsMap, nil, false, nil), Equals, 0)
+	c.Assert(requiredPolicyMapEntries(&labelsMap, nil, false, consumers), Equals, 2)
+	c.Assert(requiredPolicyMapEntries(&labelsMap, l4, false, consumers), Equals, 3)
+	c.Assert(requiredPolicyMapEntries(&labelsMap, l4, true, consumers), Equals, 4)
+
+	// Entries shared by the L3 policy and the host are only counted once
+	consumers = append(consumers, policy.ReservedIdentityHost, 1000)
+	c.Assert(requiredPolicyMapEntries(&labelsMap, l4, true, consumers), Equals, 4)
+}
+
+func (s *EndpointSuite) TestGetPolicyMapModel(c *C) {
+	prev := bpf.SetMapBackend(bpf.NewMemoryBackend())
+	defer bpf.SetMapBackend(prev)
+
+	e := Endpoint{ID: 4370, policyMapEntries: 10}
+	c.Assert(e.getPolicyMapModel(), IsNil)
+
+	pm, _, err := policymap.OpenMap("cilium_policy_4370")
+	c.Assert(err, IsNil)
+	defer pm.Close()
+	e.PolicyMap = pm
+
+	c.Assert(e.getPolicyMapModel(), DeepEquals, &models.EndpointPolicyMap{
+		Entries:    10,
+		MaxEntries: int64(policymap.MaxEntries),
+	})
+}
@@ -322,12 +322,83 @@ func (e *Endpoint) resolveL4Policy(owner Owner, repo *policy.Repository, c *poli
 	return nil
 }
 
+// policyMapKey is the key of an entry of the policy map of an endpoint
+type policyMapKey struct {
+	identity policy.NumericIdentity
+	port     uint16
+	proto    uint8
+}
+
+// allowedConsumers returns the identities of labelsMap allowed to access the
+// consumable c at L3.
+// Must be called with global repo.Mutex and c.Mutex held
+func (e *Endpoint) allowedConsumers(owner Owner, labelsMap *policy.IdentityCache,
+	repo *policy.Repository, c *policy.Consumable) []policy.NumericIdentity {
+
+	ctx := policy.SearchContext{
+		To: c.LabelArray,
+	}
+	if owner.TracingEnabled() {
+		ctx.Trace = policy.TRACE_ENABLED
+	}
+
+	consumers := []policy.NumericIdentity{}
+	for srcID, srcLabels := range *labelsMap {
+		ctx.From = srcLabels
+		e.getLogger().WithFields(logrus.Fields{
+			logfields.PolicyID: srcID,
+			"ctx":              ctx,
+		}).Debug("Evaluating context for source PolicyID")
+
+		if repo.AllowsLabelAccess(&ctx) == api.Allowed {
+			consumers = append(consumers, srcID)
+		}
+	}
+
+	return consumers
+}
+
+// allowsLocalhost returns true if the host must be allowed to access the
+// consumable c regardless of the policy.
+func allowsLocalhost(owner Owner, c *policy.Consumable) bool {
+	return owner.AlwaysAllowLocalhost() || c.L4Policy.HasRedirect()
+}
+
+// requiredPolicyMapEntries returns the number of policy map entries required
+// to implement the L3-dependent L4 policy l4 and to allow the consumers and,
+// if allowLocalhost is true, the host.
+func requiredPolicyMapEntries(labelsMap *policy.IdentityCache, l4 *policy.L4Policy,
+	allowLocalhost bool, consumers []policy.NumericIdentity) int {
+
+	keys := map[policyMapKey]struct{}{}
+
+	if l4 != nil {
+		for _, filter := range l4.Ingress {
+			for _, sel := range filter.FromEndpoints {
+				for _, id := range getSecurityIdentities(labelsMap, &sel) {
+					keys[policyMapKey{id, uint16(filter.Port), uint8(filter.U8Proto)}] = struct{}{}
+				}
+			}
+		}
+	}
+
+	if allowLocalhost {
+		keys[policyMapKey{identity: policy.ReservedIdentityHost}] = struct{}{}
+	}
+
+	for _, id := range consumers {
+		keys[policyMapKey{identity: id}] = struct{}{}
+	}
+
+	return len(keys)
+}
+
 // Must be called with global endpoint.Mutex held
 // Returns a boolean to signalize if the policy was changed;
 // and a map matching which rules were successfully added/modified;
 // and a map matching which rules were successfully removed.
 func (e *Endpoint) regenerateConsumable(owner Owner, labelsMap *policy.IdentityCache,
-	repo *policy.Repository, c *policy.Consumable) (changed bool, rulesAdd policy.SecurityIDContexts, rulesRm policy.SecurityIDContexts) {
+	consumers []policy.NumericIdentity, c *policy.Consumable) (changed bool, rulesAdd policy.SecurityIDContexts, rulesRm policy.SecurityIDContexts) {
 
 	var (
 		l4Rm policy.SecurityIDContexts
@@ -420,30 +491,15 @@ func (e *Endpoint) regenerateConsumable(owner Owner, labelsMap *policy.IdentityC
 		}
 	}
 
-	if owner.AlwaysAllowLocalhost() || c.L4Policy.HasRedirect() {
+	if allowsLocalhost(owner, c) {
 		if e.allowConsumer(owner, policy.ReservedIdentityHost) {
 			changed = true
 		}
 	}
 
-	ctx := policy.SearchContext{
-		To: c.LabelArray,
-	}
-	if owner.TracingEnabled() {
-		ctx.Trace = policy.TRACE_ENABLED
-	}
-
-	for srcID, srcLabels := range *labelsMap {
-		ctx.From = srcLabels
-		e.getLogger().WithFields(logrus.Fields{
-			logfields.PolicyID: srcID,
-			"ctx":              ctx,
-		}).Debug("Evaluating context for source PolicyID")
-
-		if repo.AllowsLabelAccess(&ctx) == api.Allowed {
-			if e.allowConsumer(owner, srcID) {
-				changed = true
-			}
+	for _, srcID := range consumers {
+		if e.allowConsumer(owner, srcID) {
+			changed = true
 		}
 	}
 
@@ -640,6 +696,16 @@ func (e *Endpoint) regeneratePolicy(owner Owner, opts models.ConfigurationMap) (
 		return false, nil, nil, err
 	}
 
+	// Refuse policies exceeding the policy map before writing any entries
+	// so that the last policy remains fully in effect.
+	consumers := e.allowedConsumers(owner, labelsMap, repo, c)
+	e.policyMapEntries = requiredPolicyMapEntries(labelsMap, c.L4Policy,
+		allowsLocalhost(owner, c), consumers)
+	if e.PolicyMap != nil && e.policyMapEntries > int(e.PolicyMap.MaxEntries) {
+		return false, nil, nil, fmt.Errorf("policy requires %d policy map entries but the policy map is limited to %d entries, see --policy-map-entries",
+			e.policyMapEntries, e.PolicyMap.MaxEntries)
+	}
+
 	// no failures after this point
 
 	// Apply possible option changes before regenerating maps, as map regeneration
@@ -677,7 +743,7 @@ func (e *Endpoint) regeneratePolicy(owner Owner, opts models.ConfigurationMap) (
 
 	optsChanged := e.applyOptsLocked(opts)
 
-	policyChanged2, consumersAdd, consumersRm := e.regenerateConsumable(owner, labelsMap, repo, c)
+	policyChanged2, consumersAdd, consumersRm := e.regenerateConsumable(owner, labelsMap, consumers, c)
 	if policyChanged2 {
 		policyChanged = true
 	}
@@ -17,23 +17,79 @@ package endpointmanager
 import (
 	"fmt"
 	"net"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/metrics"
 	"github.com/cilium/cilium/pkg/policy"
 
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	// GcInterval is the garbage collection interval.
-	// #FIXME find a way to change this value otherwise we'll have lots of tests flakes
-	// Change to be a flag
-	GcInterval int = 10
+	// ConntrackGCMinInterval is the shortest interval between two garbage
+	// collection runs. It is also the interval after the first run.
+	ConntrackGCMinInterval = 10 * time.Second
+
+	// DefaultConntrackGCMaxInterval is the default longest interval between
+	// two garbage collection runs.
+	DefaultConntrackGCMaxInterval = 5 * time.Minute
+
+	// ConntrackFillWarningThreshold is the ratio of used to maximum entries
+	// of a CT map above which a warning is reported in the status and the
+	// garbage collection interval is reset to ConntrackGCMinInterval.
+	ConntrackFillWarningThreshold = 0.9
+)
+
+var (
+	// ConntrackGCMaxInterval is the longest interval between two garbage
+	// collection runs. Must not be shorter than ConntrackGCMinInterval and
+	// must be set before EnableConntrackGC() is called.
+	ConntrackGCMaxInterval = DefaultConntrackGCMaxInterval
+)
+
+// conntrackGCResult is the result of a garbage collection run on a CT map
+type conntrackGCResult struct {
+	// path is the path of the CT map
+	path string
+
+	// metricName is the name of the CT map in metrics. All local CT maps of
+	// an address family share a single name.
+	metricName string
+
+	maxEntries uint32
+	stats      ctmap.GCStats
+}
+
+// fillRatio returns the ratio of used to maximum entries of the CT map
+func (r *conntrackGCResult) fillRatio() float64 {
+	if r.maxEntries == 0 {
+		return 0
+	}
+	return float64(r.stats.Alive()) / float64(r.maxEntries)
+}
+
+var (
+	// conntrackStatusMutex protects conntrackGCInterval and
+	// conntrackLastResults
+	conntrackStatusMutex lock.RWMutex
+
+	// conntrackGCInterval is the current interval between two garbage
+	// collection runs, or 0 if the garbage collection is not enabled.
+	conntrackGCInterval time.Duration
+
+	// conntrackLastResults are the results of the last garbage collection
+	// run of each CT map, indexed by path
+	conntrackLastResults = map[string]*conntrackGCResult{}
 )
 
 // RunGC run CT's garbage collector for the given endpoint. `isLocal` refers if
@@ -41,8 +97,15 @@ const (
 // map. `filter` represents the filter type to be used while looping all CT
 // entries.
 func RunGC(e *endpoint.Endpoint, isLocal, isIPv6 bool, filter *ctmap.GCFilter) {
+	runGC(e, isLocal, isIPv6, filter)
+}
+
+// runGC is RunGC returning the result of the garbage collection run, or nil if
+// the CT map could not be opened or is an LRU map, which is not scanned.
+func runGC(e *endpoint.Endpoint, isLocal, isIPv6 bool, filter *ctmap.GCFilter) *conntrackGCResult {
 	var file string
 	var mapType string
+	var metricName string
 	// TODO: We need to optimize this a bit in future, so we traverse
 	// the global table less often.
 
@@ -54,6 +117,7 @@ func RunGC(e *endpoint.Endpoint, isLocal, isIPv6 bool, filter *ctmap.GCFilter) {
 			mapType = ctmap.MapName4
 		}
 		file = bpf.MapPath(mapType + strconv.Itoa(int(e.ID)))
+		metricName = mapType + "local"
 	} else {
 		if isIPv6 {
 			mapType = ctmap.MapName6Global
@@ -61,33 +125,181 @@ func RunGC(e *endpoint.Endpoint, isLocal, isIPv6 bool, filter *ctmap.GCFilter) {
 			mapType = ctmap.MapName4Global
 		}
 		file = bpf.MapPath(mapType)
+		metricName = mapType
 	}
 
 	m, err := bpf.OpenMap(file)
 	if err != nil {
 		log.WithError(err).WithField(logfields.Path, file).Warn("Unable to open map")
 		e.LogStatus(endpoint.BPF, endpoint.Warning, fmt.Sprintf("Unable to open CT map %s: %s", file, err))
-		return
+		return nil
 	}
 	defer m.Close()
 
-	deleted := ctmap.GC(m, mapType, filter)
+	stats := ctmap.GC(m, mapType, filter)
 
-	if deleted > 0 {
+	if stats.Deleted > 0 {
 		log.WithFields(logrus.Fields{
 			logfields.Path:  file,
 			"ctFilter.type": filter.TypeString(),
-			"count":         deleted,
+			"count":         stats.Deleted,
 		}).Debug("Deleted filtered entries from map")
 	}
+
+	if m.MapInfo.MapType == bpf.MapTypeLRUHash {
+		return nil
+	}
+
+	return &conntrackGCResult{
+		path:       file,
+		metricName: metricName,
+		maxEntries: m.MapInfo.MaxEntries,
+		stats:      stats,
+	}
+}
+
+// nextConntrackGCInterval returns the interval until the next garbage
+// collection run based on the previous interval, the highest ratio of deleted
+// to scanned entries and the highest fill level of all CT maps in the last
+// run. The interval is shortened if many entries expired since the previous
+// run and extended if few did, within ConntrackGCMinInterval and
+// ConntrackGCMaxInterval.
+func nextConntrackGCInterval(prev time.Duration, deleteRatio, fillRatio float64) time.Duration {
+	interval := prev
+
+	switch {
+	case fillRatio >= ConntrackFillWarningThreshold:
+		interval = ConntrackGCMinInterval
+	case deleteRatio > 0.25:
+		if deleteRatio > 0.9 {
+			deleteRatio = 0.9
+		}
+		interval = time.Duration(float64(interval) * (1.0 - deleteRatio))
+	case deleteRatio < 0.05:
+		interval = time.Duration(float64(interval) * 1.5)
+	}
+
+	interval -= interval % time.Second
+	if interval < ConntrackGCMinInterval {
+		interval = ConntrackGCMinInterval
+	} else if interval > ConntrackGCMaxInterval {
+		interval = ConntrackGCMaxInterval
+	}
+
+	return interval
+}
+
+// updateConntrackGCResults records the results of a garbage collection run
+// over all CT maps, exports them as metrics and returns the interval until the
+// next run.
+func updateConntrackGCResults(results []*conntrackGCResult) time.Duration {
+	maxDeleteRatio, maxFillRatio := 0.0, 0.0
+	entries := map[string]int{}
+	occupancy := map[string]float64{}
+
+	for _, r := range results {
+		if r.stats.DeleteRatio() > maxDeleteRatio {
+			maxDeleteRatio = r.stats.DeleteRatio()
+		}
+		if r.fillRatio() > maxFillRatio {
+			maxFillRatio = r.fillRatio()
+		}
+
+		entries[r.metricName] += r.stats.Alive()
+		if r.fillRatio() > occupancy[r.metricName] {
+			occupancy[r.metricName] = r.fillRatio()
+		}
+
+		metrics.ConntrackGCEntriesScanned.WithLabelValues(r.metricName).Add(float64(r.stats.Scanned))
+		metrics.ConntrackGCEntriesDeleted.WithLabelValues(r.metricName).Add(float64(r.stats.Deleted))
+		metrics.ConntrackGCDuration.WithLabelValues(r.metricName).Observe(r.stats.Duration.Seconds())
+	}
+
+	for name, n := range entries {
+		metrics.ConntrackEntries.WithLabelValues(name).Set(float64(n))
+		metrics.ConntrackOccupancy.WithLabelValues(name).Set(occupancy[name])
+	}
+
+	conntrackStatusMutex.Lock()
+	defer conntrackStatusMutex.Unlock()
+
+	conntrackLastResults = make(map[string]*conntrackGCResult, len(results))
+	for _, r := range results {
+		conntrackLastResults[r.path] = r
+	}
+
+	if conntrackGCInterval == 0 {
+		conntrackGCInterval = ConntrackGCMinInterval
+	} else {
+		conntrackGCInterval = nextConntrackGCInterval(conntrackGCInterval, maxDeleteRatio, maxFillRatio)
+	}
+	metrics.ConntrackGCInterval.Set(conntrackGCInterval.Seconds())
+
+	if maxFillRatio >= ConntrackFillWarningThreshold {
+		log.WithFields(logrus.Fields{
+			"fillRatio": maxFillRatio,
+			"interval":  conntrackGCInterval,
+		}).Warning("Connection tracking table is close to full, new connections may be dropped")
+	}
+
+	return conntrackGCInterval
+}
+
+// GetConntrackStatus returns the status of the CT maps as seen by the last
+// garbage collection run. A warning is reported for each CT map whose fill
+// level exceeds ConntrackFillWarningThreshold.
+func GetConntrackStatus() *models.Status {
+	conntrackStatusMutex.RLock()
+	defer conntrackStatusMutex.RUnlock()
+
+	if conntrackGCInterval == 0 {
+		return &models.Status{State: models.StatusStateDisabled}
+	}
+
+	paths := make([]string, 0, len(conntrackLastResults))
+	for path := range conntrackLastResults {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var highest *conntrackGCResult
+	warnings := []string{}
+	for _, path := range paths {
+		r := conntrackLastResults[path]
+		if highest == nil || r.fillRatio() > highest.fillRatio() {
+			highest = r
+		}
+		if r.fillRatio() >= ConntrackFillWarningThreshold {
+			warnings = append(warnings, fmt.Sprintf("%s %.0f%% full (%d/%d)",
+				filepath.Base(r.path), r.fillRatio()*100, r.stats.Alive(), r.maxEntries))
+		}
+	}
+
+	if len(warnings) > 0 {
+		return &models.Status{
+			State: models.StatusStateWarning,
+			Msg: fmt.Sprintf("%s, GC interval %s",
+				strings.Join(warnings, ", "), conntrackGCInterval),
+		}
+	}
+
+	msg := fmt.Sprintf("GC interval %s", conntrackGCInterval)
+	if highest != nil {
+		msg = fmt.Sprintf("%s, highest fill level %.0f%% (%s)",
+			msg, highest.fillRatio()*100, filepath.Base(highest.path))
+	}
+
+	return &models.Status{State: models.StatusStateOk, Msg: msg}
 }
 
-// EnableConntrackGC enables the connection tracking garbage collection.
+// EnableConntrackGC enables the connection tracking garbage collection. The
+// interval between two runs adapts to the ratio of expired entries and to the
+// fill level of the CT maps, see nextConntrackGCInterval().
 func EnableConntrackGC(ipv4, ipv6 bool) {
 	go func() {
 		seenGlobal := false
-		sleepTime := time.Duration(GcInterval) * time.Second
 		for {
+			results := []*conntrackGCResult{}
 			eps := GetEndpoints()
 			for _, e := range eps {
 				e.Mutex.RLock()
@@ -119,13 +331,17 @@ func EnableConntrackGC(ipv4, ipv6 bool) {
 				// We can unlock the endpoint mutex sense
 				// in runGC it will be locked as needed.
 				if ipv6 {
-					RunGC(e, isLocal, true, ctmap.NewGCFilterBy(ctmap.GCFilterByTime))
+					if r := runGC(e, isLocal, true, ctmap.NewGCFilterBy(ctmap.GCFilterByTime)); r != nil {
+						results = append(results, r)
+					}
 				}
 				if ipv4 {
-					RunGC(e, isLocal, false, ctmap.NewGCFilterBy(ctmap.GCFilterByTime))
+					if r := runGC(e, isLocal, false, ctmap.NewGCFilterBy(ctmap.GCFilterByTime)); r != nil {
+						results = append(results, r)
+					}
 				}
 			}
-			time.Sleep(sleepTime)
+			time.Sleep(updateConntrackGCResults(results))
 			seenGlobal = false
 		}
 	}()
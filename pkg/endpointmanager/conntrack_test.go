@@ -0,0 +1,88 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type EndpointManagerSuite struct{}
+
+var _ = Suite(&EndpointManagerSuite{})
+
+func (s *EndpointManagerSuite) TestNextConntrackGCInterval(c *C) {
+	// Few entries expired, the interval grows
+	c.Assert(nextConntrackGCInterval(time.Minute, 0.01, 0.1), Equals, 90*time.Second)
+	c.Assert(nextConntrackGCInterval(ConntrackGCMaxInterval, 0.0, 0.1), Equals, ConntrackGCMaxInterval)
+
+	// Some entries expired, the interval is kept
+	c.Assert(nextConntrackGCInterval(time.Minute, 0.1, 0.1), Equals, time.Minute)
+
+	// Many entries expired, the interval shrinks
+	c.Assert(nextConntrackGCInterval(time.Minute, 0.5, 0.1), Equals, 30*time.Second)
+	c.Assert(nextConntrackGCInterval(time.Minute, 1.0, 0.1), Equals, ConntrackGCMinInterval)
+
+	// The table is close to full
+	c.Assert(nextConntrackGCInterval(ConntrackGCMaxInterval, 0.0, 0.95), Equals, ConntrackGCMinInterval)
+
+	// A maximum interval equal to the minimum interval pins the interval
+	defer func(max time.Duration) { ConntrackGCMaxInterval = max }(ConntrackGCMaxInterval)
+	ConntrackGCMaxInterval = ConntrackGCMinInterval
+	c.Assert(nextConntrackGCInterval(ConntrackGCMinInterval, 0.0, 0.1), Equals, ConntrackGCMinInterval)
+}
+
+func (s *EndpointManagerSuite) TestConntrackStatus(c *C) {
+	defer func() {
+		conntrackGCInterval = 0
+		conntrackLastResults = map[string]*conntrackGCResult{}
+	}()
+
+	c.Assert(GetConntrackStatus().State, Equals, models.StatusStateDisabled)
+
+	global := &conntrackGCResult{
+		path:       "/sys/fs/bpf/tc/globals/cilium_ct4_global",
+		metricName: ctmap.MapName4Global,
+		maxEntries: 100,
+		stats:      ctmap.GCStats{Scanned: 60, Deleted: 10},
+	}
+	local := &conntrackGCResult{
+		path:       "/sys/fs/bpf/tc/globals/cilium_ct4_1",
+		metricName: ctmap.MapName4 + "local",
+		maxEntries: 100,
+		stats:      ctmap.GCStats{Scanned: 20},
+	}
+
+	c.Assert(updateConntrackGCResults([]*conntrackGCResult{global, local}), Equals, ConntrackGCMinInterval)
+	status := GetConntrackStatus()
+	c.Assert(status.State, Equals, models.StatusStateOk)
+	c.Assert(status.Msg, Equals, "GC interval 10s, highest fill level 50% (cilium_ct4_global)")
+
+	global.stats = ctmap.GCStats{Scanned: 95}
+	c.Assert(updateConntrackGCResults([]*conntrackGCResult{global, local}), Equals, ConntrackGCMinInterval)
+	status = GetConntrackStatus()
+	c.Assert(status.State, Equals, models.StatusStateWarning)
+	c.Assert(status.Msg, Equals, "cilium_ct4_global 95% full (95/100), GC interval 10s")
+}
@@ -0,0 +1,299 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"sync"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "lb-health-check")
+
+// OnChangeFunc is called with the SHA256 sum of the frontend of a service
+// whenever one of its backends became or stopped being unhealthy.
+type OnChangeFunc func(sha256 string)
+
+// Manager runs the health checks of the backends of services. Each health
+// checked service has its own controller running the checks of all its
+// backends in the interval of the health check.
+type Manager struct {
+	mutex       lock.RWMutex
+	services    map[string]*service
+	controllers *controller.Manager
+	onChange    OnChangeFunc
+	probe       probeFunc
+}
+
+// service is a health checked service
+type service struct {
+	name     string
+	check    types.LBHealthCheck
+	backends map[string]*backend
+}
+
+// backend is the health state of a backend of a service
+type backend struct {
+	// key is the address of the backend as configured in the service
+	key string
+
+	// addr is the address the health check connects to
+	addr types.L3n4Addr
+
+	health    types.LBBackEndHealth
+	successes int
+	failures  int
+}
+
+// update records the result of a health check of the backend and returns
+// true if the backend became or stopped being unhealthy.
+func (b *backend) update(err error, check *types.LBHealthCheck) bool {
+	wasUnhealthy := b.health == types.LBBackEndHealthUnhealthy
+
+	if err == nil {
+		b.failures = 0
+		b.successes++
+		if b.successes >= check.HealthyThreshold {
+			b.health = types.LBBackEndHealthHealthy
+		}
+	} else {
+		b.successes = 0
+		b.failures++
+		if b.failures >= check.UnhealthyThreshold {
+			b.health = types.LBBackEndHealthUnhealthy
+		}
+	}
+
+	return wasUnhealthy != (b.health == types.LBBackEndHealthUnhealthy)
+}
+
+// NewManager returns a new Manager calling onChange whenever the health of a
+// backend changed in a way which affects backend selection.
+func NewManager(onChange OnChangeFunc) *Manager {
+	return &Manager{
+		services:    map[string]*service{},
+		controllers: controller.NewManager(),
+		onChange:    onChange,
+		probe:       probe,
+	}
+}
+
+func controllerName(svc *service) string {
+	return "lb-health-check-" + svc.name
+}
+
+// UpdateService starts health checking the backends of svc if it has a
+// health check, and stops health checking it otherwise. Backends which were
+// already part of the service keep their health unless the health check
+// changed. UDP services are never health checked as the TCP and HTTP checks
+// cannot probe them.
+func (m *Manager) UpdateService(svc *types.LBSVC) {
+	sha256 := svc.FE.SHA256Sum()
+	if svc.HealthCheck == nil {
+		m.DeleteService(sha256)
+		return
+	}
+
+	if svc.FE.Protocol == types.UDP {
+		log.WithField(logfields.ServiceName, svc.FE.String()).
+			Warning("Ignoring health check of UDP service")
+		m.DeleteService(sha256)
+		return
+	}
+
+	m.mutex.Lock()
+	s, ok := m.services[sha256]
+	restart := !ok || s.check != *svc.HealthCheck
+	if !ok {
+		s = &service{name: svc.FE.String()}
+		m.services[sha256] = s
+	}
+	s.check = *svc.HealthCheck
+
+	backends := make(map[string]*backend, len(svc.BES))
+	for _, be := range svc.BES {
+		key := be.L3n4Addr.String()
+		if old, ok := s.backends[key]; ok && !restart {
+			backends[key] = old
+			continue
+		}
+
+		addr := *be.L3n4Addr.DeepCopy()
+		// Backends without a port listen on the port of the frontend
+		if addr.Port == 0 {
+			addr.Port = svc.FE.Port
+		}
+		backends[key] = &backend{
+			key:    key,
+			addr:   addr,
+			health: types.LBBackEndHealthUnknown,
+		}
+	}
+	s.backends = backends
+	m.mutex.Unlock()
+
+	m.updateUnhealthyMetric()
+
+	if restart {
+		m.controllers.UpdateController(controllerName(s),
+			controller.ControllerParams{
+				DoFunc: func() error {
+					return m.runChecks(sha256)
+				},
+				RunInterval: svc.HealthCheck.Interval,
+			},
+		)
+	}
+}
+
+// DeleteService stops health checking the service with the given SHA256 sum
+// of its frontend.
+func (m *Manager) DeleteService(sha256 string) {
+	m.mutex.Lock()
+	s, ok := m.services[sha256]
+	delete(m.services, sha256)
+	m.mutex.Unlock()
+
+	if ok {
+		m.controllers.RemoveController(controllerName(s))
+		m.updateUnhealthyMetric()
+	}
+}
+
+// Healthy returns for each of the given backends of the service with the
+// given SHA256 sum of its frontend whether it is healthy. Backends with an
+// unknown health are considered healthy. Returns nil if the service is not
+// health checked.
+func (m *Manager) Healthy(sha256 string, bes []types.LBBackEnd) []bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	s, ok := m.services[sha256]
+	if !ok {
+		return nil
+	}
+
+	healthy := make([]bool, len(bes))
+	for i, be := range bes {
+		b, ok := s.backends[be.L3n4Addr.String()]
+		healthy[i] = !ok || b.health != types.LBBackEndHealthUnhealthy
+	}
+	return healthy
+}
+
+// Health returns the health of the given backend of the service with the
+// given SHA256 sum of its frontend. Returns an empty string if the backend
+// is not health checked.
+func (m *Manager) Health(sha256 string, be types.L3n4Addr) types.LBBackEndHealth {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if s, ok := m.services[sha256]; ok {
+		if b, ok := s.backends[be.String()]; ok {
+			return b.health
+		}
+	}
+	return ""
+}
+
+// runChecks runs the health check of all backends of the service with the
+// given SHA256 sum of its frontend in parallel.
+func (m *Manager) runChecks(sha256 string) error {
+	m.mutex.RLock()
+	s, ok := m.services[sha256]
+	if !ok {
+		m.mutex.RUnlock()
+		return nil
+	}
+	check := s.check
+	backends := make([]*backend, 0, len(s.backends))
+	for _, b := range s.backends {
+		backends = append(backends, b)
+	}
+	m.mutex.RUnlock()
+
+	errs := make([]error, len(backends))
+	wg := sync.WaitGroup{}
+	for i, b := range backends {
+		wg.Add(1)
+		go func(i int, addr types.L3n4Addr) {
+			defer wg.Done()
+			errs[i] = m.probe(&check, addr)
+		}(i, b.addr)
+	}
+	wg.Wait()
+
+	changed := false
+
+	m.mutex.Lock()
+	// The service may have been deleted or updated while the checks ran,
+	// only record the results of backends which are still checked.
+	if cur, ok := m.services[sha256]; ok && cur == s && cur.check == check {
+		for i, b := range backends {
+			if s.backends[b.key] != b {
+				continue
+			}
+
+			if errs[i] == nil {
+				metrics.LBHealthChecks.WithLabelValues(metrics.LabelValueOutcomeSuccess).Inc()
+			} else {
+				metrics.LBHealthChecks.WithLabelValues(metrics.LabelValueOutcomeFail).Inc()
+			}
+
+			if b.update(errs[i], &check) {
+				changed = true
+				log.WithError(errs[i]).WithFields(logrus.Fields{
+					logfields.ServiceName: s.name,
+					"backend":             b.key,
+					"health":              b.health,
+				}).Info("Health of service backend changed")
+			}
+		}
+	}
+	m.mutex.Unlock()
+
+	if changed {
+		m.updateUnhealthyMetric()
+		if m.onChange != nil {
+			m.onChange(sha256)
+		}
+	}
+
+	return nil
+}
+
+// updateUnhealthyMetric updates the number of unhealthy backends of all
+// services.
+func (m *Manager) updateUnhealthyMetric() {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	unhealthy := 0
+	for _, s := range m.services {
+		for _, b := range s.backends {
+			if b.health == types.LBBackEndHealthUnhealthy {
+				unhealthy++
+			}
+		}
+	}
+	metrics.LBBackendsUnhealthy.Set(float64(unhealthy))
+}
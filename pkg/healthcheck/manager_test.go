@@ -0,0 +1,178 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/common/types"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type HealthCheckSuite struct{}
+
+var _ = Suite(&HealthCheckSuite{})
+
+var testCheck = types.LBHealthCheck{
+	Type:               types.LBHealthCheckTCP,
+	Interval:           time.Hour,
+	Timeout:            time.Second,
+	HealthyThreshold:   2,
+	UnhealthyThreshold: 3,
+}
+
+func (s *HealthCheckSuite) TestBackendUpdate(c *C) {
+	b := &backend{health: types.LBBackEndHealthUnknown}
+	failed := fmt.Errorf("failed")
+
+	c.Assert(b.update(nil, &testCheck), Equals, false)
+	c.Assert(b.health, Equals, types.LBBackEndHealthUnknown)
+	c.Assert(b.update(nil, &testCheck), Equals, false)
+	c.Assert(b.health, Equals, types.LBBackEndHealthHealthy)
+
+	// A success resets the number of consecutive failures
+	c.Assert(b.update(failed, &testCheck), Equals, false)
+	c.Assert(b.update(failed, &testCheck), Equals, false)
+	c.Assert(b.update(nil, &testCheck), Equals, false)
+	c.Assert(b.update(failed, &testCheck), Equals, false)
+	c.Assert(b.update(failed, &testCheck), Equals, false)
+	c.Assert(b.health, Equals, types.LBBackEndHealthHealthy)
+	c.Assert(b.update(failed, &testCheck), Equals, true)
+	c.Assert(b.health, Equals, types.LBBackEndHealthUnhealthy)
+
+	c.Assert(b.update(nil, &testCheck), Equals, false)
+	c.Assert(b.health, Equals, types.LBBackEndHealthUnhealthy)
+	c.Assert(b.update(nil, &testCheck), Equals, true)
+	c.Assert(b.health, Equals, types.LBBackEndHealthHealthy)
+}
+
+func (s *HealthCheckSuite) TestProbeTCP(c *C) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	addr := l.Addr().String()
+
+	c.Assert(probeTCP(addr, time.Second), IsNil)
+	l.Close()
+	c.Assert(probeTCP(addr, time.Second), Not(IsNil))
+}
+
+func (s *HealthCheckSuite) TestProbeHTTP(c *C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			w.WriteHeader(http.StatusOK)
+		case "/moved":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	hostPort := strings.TrimPrefix(srv.URL, "http://")
+
+	c.Assert(probeHTTP(hostPort, "/healthz", time.Second), IsNil)
+	c.Assert(probeHTTP(hostPort, "healthz", time.Second), IsNil)
+	c.Assert(probeHTTP(hostPort, "/moved", time.Second), IsNil)
+	c.Assert(probeHTTP(hostPort, "/", time.Second), Not(IsNil))
+}
+
+func (s *HealthCheckSuite) TestManager(c *C) {
+	changed := make(chan string, 10)
+	manager := NewManager(func(sha256 string) { changed <- sha256 })
+
+	// Backends on port 8080 fail the check
+	manager.probe = func(check *types.LBHealthCheck, addr types.L3n4Addr) error {
+		if addr.Port == 8080 {
+			return fmt.Errorf("connection refused")
+		}
+		return nil
+	}
+
+	check := testCheck
+	check.HealthyThreshold = 1
+	check.UnhealthyThreshold = 1
+
+	fe, err := types.NewL3n4AddrID(types.TCP, net.ParseIP("10.0.0.1"), 80, 1)
+	c.Assert(err, IsNil)
+	be1, err := types.NewLBBackEnd(types.TCP, net.ParseIP("10.0.1.1"), 0, 0)
+	c.Assert(err, IsNil)
+	be2, err := types.NewLBBackEnd(types.TCP, net.ParseIP("10.0.1.2"), 8080, 0)
+	c.Assert(err, IsNil)
+
+	svc := &types.LBSVC{
+		FE:           *fe,
+		BES:          []types.LBBackEnd{*be1, *be2},
+		LBSVCOptions: types.LBSVCOptions{HealthCheck: &check},
+	}
+	sha256 := fe.SHA256Sum()
+
+	manager.UpdateService(svc)
+	c.Assert(manager.Healthy(sha256, svc.BES), DeepEquals, []bool{true, true})
+
+	c.Assert(manager.runChecks(sha256), IsNil)
+	c.Assert(manager.Healthy(sha256, svc.BES), DeepEquals, []bool{true, false})
+	c.Assert(manager.Health(sha256, be1.L3n4Addr), Equals, types.LBBackEndHealthHealthy)
+	c.Assert(manager.Health(sha256, be2.L3n4Addr), Equals, types.LBBackEndHealthUnhealthy)
+
+	select {
+	case s := <-changed:
+		c.Assert(s, Equals, sha256)
+	case <-time.After(5 * time.Second):
+		c.Fatalf("no health change reported")
+	}
+
+	// Removing the health check stops health checking
+	svc.HealthCheck = nil
+	manager.UpdateService(svc)
+	c.Assert(manager.Healthy(sha256, svc.BES), IsNil)
+	c.Assert(manager.Health(sha256, be1.L3n4Addr), Equals, types.LBBackEndHealth(""))
+}
+
+func (s *HealthCheckSuite) TestManagerUDP(c *C) {
+	manager := NewManager(nil)
+	manager.probe = func(check *types.LBHealthCheck, addr types.L3n4Addr) error {
+		return fmt.Errorf("UDP backend must not be probed")
+	}
+
+	check := testCheck
+	fe, err := types.NewL3n4AddrID(types.UDP, net.ParseIP("10.0.0.1"), 53, 1)
+	c.Assert(err, IsNil)
+	be, err := types.NewLBBackEnd(types.UDP, net.ParseIP("10.0.1.1"), 53, 0)
+	c.Assert(err, IsNil)
+
+	svc := &types.LBSVC{
+		FE:           *fe,
+		BES:          []types.LBBackEnd{*be},
+		LBSVCOptions: types.LBSVCOptions{HealthCheck: &check},
+	}
+	sha256 := fe.SHA256Sum()
+
+	// UDP services are not health checked
+	manager.UpdateService(svc)
+	c.Assert(manager.Healthy(sha256, svc.BES), IsNil)
+	c.Assert(manager.Health(sha256, be.L3n4Addr), Equals, types.LBBackEndHealth(""))
+}
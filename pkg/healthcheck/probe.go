@@ -0,0 +1,77 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/common/types"
+)
+
+// probeFunc checks the health of the backend with the given address and
+// returns an error if it is not healthy.
+type probeFunc func(check *types.LBHealthCheck, addr types.L3n4Addr) error
+
+// probe runs the given health check against addr.
+func probe(check *types.LBHealthCheck, addr types.L3n4Addr) error {
+	hostPort := net.JoinHostPort(addr.IP.String(), strconv.Itoa(int(addr.Port)))
+
+	switch check.Type {
+	case types.LBHealthCheckHTTP:
+		return probeHTTP(hostPort, check.Path, check.Timeout)
+	default:
+		return probeTCP(hostPort, check.Timeout)
+	}
+}
+
+// probeTCP checks if a TCP connection to hostPort can be established.
+func probeTCP(hostPort string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", hostPort, timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// probeHTTP checks if hostPort responds to an HTTP GET request of path with a
+// 2xx or 3xx status code. Redirects are not followed.
+func probeHTTP(hostPort, path string, timeout time.Duration) error {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	client := http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get("http://" + hostPort + path)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("unexpected HTTP status code %d", resp.StatusCode)
+	}
+	return nil
+}
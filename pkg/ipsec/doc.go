@@ -0,0 +1,20 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipsec implements transparent encryption of the traffic between
+// nodes. Traffic to and from remote nodes is encrypted with IPsec ESP by
+// programming the xfrm states and policies of the kernel. The key is read
+// from a file, typically a mounted Kubernetes secret, and can be rotated
+// without dropping traffic by replacing it with a key of a different SPI.
+package ipsec
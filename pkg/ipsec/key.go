@@ -0,0 +1,162 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+// Key is a key used to encrypt the traffic between nodes
+type Key struct {
+	// SPI is the security parameter index identifying the key in ESP
+	// packets. Each key must have a unique SPI.
+	SPI uint32
+
+	// Aead is the algorithm for authenticated encryption, if set, Auth
+	// and Crypt are nil
+	Aead *netlink.XfrmStateAlgo
+
+	// Auth is the authentication algorithm
+	Auth *netlink.XfrmStateAlgo
+
+	// Crypt is the encryption algorithm
+	Crypt *netlink.XfrmStateAlgo
+}
+
+// String returns the key without revealing the key material
+func (k *Key) String() string {
+	if k.Aead != nil {
+		return fmt.Sprintf("SPI %d %s", k.SPI, k.Aead.Name)
+	}
+	return fmt.Sprintf("SPI %d %s %s", k.SPI, k.Auth.Name, k.Crypt.Name)
+}
+
+// Equal returns true if both keys are identical
+func (k *Key) Equal(o *Key) bool {
+	return reflect.DeepEqual(k, o)
+}
+
+func parseKeyMaterial(s string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex key: %s", err)
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("empty key")
+	}
+	return key, nil
+}
+
+// ParseKey parses a key in one of the following formats:
+//
+//	<spi> <aead-algorithm> <hex-key> <icv-length>
+//	<spi> <auth-algorithm> <hex-key> <crypt-algorithm> <hex-key>
+//
+// e.g. "3 rfc4106(gcm(aes)) 0x4b9e...3f1c 128" or
+// "1 hmac(sha256) 0x0a1b...9e8f cbc(aes) 0x3c4d...7a6b".
+// The algorithm names are those of the kernel crypto API.
+func ParseKey(s string) (*Key, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 4 && len(fields) != 5 {
+		return nil, fmt.Errorf("key must consist of 4 or 5 fields, got %d", len(fields))
+	}
+
+	spi, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil || spi == 0 {
+		return nil, fmt.Errorf("invalid SPI %q: must be a positive 32 bit integer", fields[0])
+	}
+
+	k := &Key{SPI: uint32(spi)}
+
+	key, err := parseKeyMaterial(fields[2])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 4 {
+		icv, err := strconv.ParseUint(fields[3], 10, 16)
+		if err != nil || icv == 0 {
+			return nil, fmt.Errorf("invalid ICV length %q", fields[3])
+		}
+		k.Aead = &netlink.XfrmStateAlgo{Name: fields[1], Key: key, ICVLen: int(icv)}
+		return k, nil
+	}
+
+	cryptKey, err := parseKeyMaterial(fields[4])
+	if err != nil {
+		return nil, err
+	}
+	k.Auth = &netlink.XfrmStateAlgo{Name: fields[1], Key: key}
+	k.Crypt = &netlink.XfrmStateAlgo{Name: fields[3], Key: cryptKey}
+
+	return k, nil
+}
+
+// ReadKey reads a single key from r. Empty lines and lines starting with
+// '#' are ignored.
+func ReadKey(r io.Reader) (*Key, error) {
+	var key *Key
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if key != nil {
+			return nil, fmt.Errorf("line %d: only a single key may be specified", line)
+		}
+
+		k, err := ParseKey(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		key = k
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if key == nil {
+		return nil, fmt.Errorf("no key found")
+	}
+
+	return key, nil
+}
+
+// LoadKeyFile reads the key from the file at path
+func LoadKeyFile(path string) (*Key, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	k, err := ReadKey(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read key from %s: %s", path, err)
+	}
+	return k, nil
+}
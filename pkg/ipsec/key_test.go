@@ -0,0 +1,97 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+	. "gopkg.in/check.v1"
+)
+
+func (s *IPSecSuite) TestParseKey(c *C) {
+	k, err := ParseKey("3 rfc4106(gcm(aes)) 0x0102030405060708090a0b0c0d0e0f1011121314 128")
+	c.Assert(err, IsNil)
+	c.Assert(k.SPI, Equals, uint32(3))
+	c.Assert(k.Aead, DeepEquals, &netlink.XfrmStateAlgo{
+		Name:   "rfc4106(gcm(aes))",
+		Key:    []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+		ICVLen: 128,
+	})
+	c.Assert(k.Auth, IsNil)
+	c.Assert(k.Crypt, IsNil)
+	c.Assert(k.String(), Equals, "SPI 3 rfc4106(gcm(aes))")
+
+	k, err = ParseKey("1  hmac(sha256) 0a0b  cbc(aes) 0x0c0d")
+	c.Assert(err, IsNil)
+	c.Assert(k.SPI, Equals, uint32(1))
+	c.Assert(k.Aead, IsNil)
+	c.Assert(k.Auth, DeepEquals, &netlink.XfrmStateAlgo{Name: "hmac(sha256)", Key: []byte{10, 11}})
+	c.Assert(k.Crypt, DeepEquals, &netlink.XfrmStateAlgo{Name: "cbc(aes)", Key: []byte{12, 13}})
+	c.Assert(k.String(), Equals, "SPI 1 hmac(sha256) cbc(aes)")
+}
+
+func (s *IPSecSuite) TestParseKeyInvalid(c *C) {
+	invalid := []string{
+		"",
+		"3 rfc4106(gcm(aes)) 0x0102",
+		"0 rfc4106(gcm(aes)) 0x0102 128",
+		"-1 rfc4106(gcm(aes)) 0x0102 128",
+		"4294967296 rfc4106(gcm(aes)) 0x0102 128",
+		"3 rfc4106(gcm(aes)) 0x010 128",
+		"3 rfc4106(gcm(aes)) 0x 128",
+		"3 rfc4106(gcm(aes)) 0x0102 0",
+		"3 rfc4106(gcm(aes)) 0x0102 abc",
+		"1 hmac(sha256) 0x0a0b cbc(aes) 0xzz",
+		"1 hmac(sha256) 0x0a0b cbc(aes) 0x0c0d extra",
+	}
+	for _, s := range invalid {
+		_, err := ParseKey(s)
+		c.Assert(err, Not(IsNil), Commentf("key %q", s))
+	}
+}
+
+func (s *IPSecSuite) TestReadKey(c *C) {
+	k, err := ReadKey(strings.NewReader("# rotated on 2018-06-01\n\n  5 rfc4106(gcm(aes)) 0x0102 96  \n"))
+	c.Assert(err, IsNil)
+	c.Assert(k.SPI, Equals, uint32(5))
+
+	_, err = ReadKey(strings.NewReader("# no key\n"))
+	c.Assert(err, ErrorMatches, "no key found")
+
+	_, err = ReadKey(strings.NewReader("5 rfc4106(gcm(aes)) 0x0102 96\n6 rfc4106(gcm(aes)) 0x0102 96\n"))
+	c.Assert(err, ErrorMatches, "line 2: only a single key may be specified")
+
+	_, err = ReadKey(strings.NewReader("# comment\n5 rfc4106(gcm(aes)) 0x0102\n"))
+	c.Assert(err, ErrorMatches, "line 2: .*")
+}
+
+func (s *IPSecSuite) TestLoadKeyFile(c *C) {
+	dir, err := ioutil.TempDir("", "ipsec")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "keys")
+	_, err = LoadKeyFile(path)
+	c.Assert(err, Not(IsNil))
+
+	c.Assert(ioutil.WriteFile(path, []byte("7 rfc4106(gcm(aes)) 0x0102 128\n"), 0600), IsNil)
+	k, err := LoadKeyFile(path)
+	c.Assert(err, IsNil)
+	c.Assert(k.SPI, Equals, uint32(7))
+}
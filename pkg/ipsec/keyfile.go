@@ -0,0 +1,108 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchKeyFile installs the key of the file at path and watches the file for
+// changes. A changed key is installed with SetKey(). The directory of the
+// file is watched as Kubernetes secret mounts are updated by swapping the
+// "..data" symlink.
+func (m *Manager) WatchKeyFile(path string) error {
+	k, err := LoadKeyFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := m.SetKey(k); err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("unable to create fsnotify watcher: %s", err)
+	}
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("unable to watch %s: %s", filepath.Dir(path), err)
+	}
+
+	m.mutex.Lock()
+	m.watcher = watcher
+	m.mutex.Unlock()
+
+	go m.watch(watcher, path)
+
+	return nil
+}
+
+// reloadKeyFile installs the key of the file at path if it has changed
+func (m *Manager) reloadKeyFile(path string) {
+	k, err := LoadKeyFile(path)
+	if err == nil {
+		err = m.SetKey(k)
+	}
+
+	m.mutex.Lock()
+	m.keyErr = err
+	m.mutex.Unlock()
+
+	if err != nil {
+		log.WithError(err).WithField(logfields.Path, path).Warning("Unable to install key, keeping previous key")
+	}
+}
+
+func (m *Manager) watch(watcher *fsnotify.Watcher, path string) {
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			log.WithField("event", event).Debug("Received fsnotify event")
+			m.reloadKeyFile(path)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.WithError(err).Warning("Error while watching key file")
+		}
+	}
+}
+
+// Close stops watching the key file and cancels pending key rotations. The
+// installed xfrm states and policies are left in place.
+func (m *Manager) Close() {
+	m.mutex.Lock()
+	m.closed = true
+	watcher := m.watcher
+	m.watcher = nil
+	m.mutex.Unlock()
+
+	// The watcher is closed without holding the mutex as closing waits
+	// for pending events to be consumed
+	if watcher != nil {
+		watcher.Close()
+	}
+}
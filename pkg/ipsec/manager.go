@@ -0,0 +1,468 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/node"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "ipsec")
+
+// Config is the configuration of a Manager
+type Config struct {
+	// LocalIP is the IPv4 address of the local node used to reach the
+	// remote nodes
+	LocalIP net.IP
+
+	// LocalAllocCIDR is the IPv4 allocation CIDR of the local node
+	LocalAllocCIDR *net.IPNet
+
+	// TunnelPort is the UDP port of the overlay network. If set, the
+	// overlay traffic between the nodes is encrypted in transport mode.
+	// Otherwise the traffic between the allocation CIDRs of the nodes is
+	// encrypted in tunnel mode.
+	TunnelPort int
+
+	// RotationDelay is the time all nodes are given to pick up a new key
+	// before it is used for outgoing traffic. The previous key is removed
+	// after the same delay has passed again.
+	RotationDelay time.Duration
+}
+
+// keyState is a key installed by the manager
+type keyState struct {
+	key *Key
+
+	// outbound is true if the key is used to encrypt outgoing traffic
+	outbound bool
+}
+
+// remoteNode is a remote node the traffic to and from which is encrypted
+type remoteNode struct {
+	ip        net.IP
+	allocCIDR *net.IPNet
+
+	// spi is the SPI of the key used to encrypt the traffic to the node,
+	// 0 if the traffic is not encrypted
+	spi uint32
+}
+
+// Manager encrypts the traffic between the local node and all remote nodes.
+// It implements node.Observer to follow the remote nodes.
+type Manager struct {
+	// mutex protects all fields and serializes the programming of the
+	// xfrm states and policies
+	mutex lock.Mutex
+
+	conf   Config
+	handle xfrmHandle
+
+	// keys are the installed keys from the oldest to the most recent one
+	keys  []*keyState
+	nodes map[node.Identity]*remoteNode
+
+	// keyErr is the error of the last attempt to load the key file
+	keyErr  error
+	watcher *fsnotify.Watcher
+	closed  bool
+
+	// afterFunc calls f in its own goroutine after d has elapsed
+	afterFunc func(d time.Duration, f func())
+}
+
+// NewManager returns a manager programming the xfrm states and policies of
+// the current network namespace. SetKey() or WatchKeyFile() must be called
+// to install the initial key.
+func NewManager(conf Config) (*Manager, error) {
+	h, err := netlink.NewHandle(syscall.NETLINK_XFRM)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open xfrm netlink socket: %s", err)
+	}
+
+	return newManager(conf, h), nil
+}
+
+func newManager(conf Config, h xfrmHandle) *Manager {
+	return &Manager{
+		conf:   conf,
+		handle: h,
+		nodes:  map[node.Identity]*remoteNode{},
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+}
+
+func (m *Manager) mode() netlink.Mode {
+	if m.conf.TunnelPort != 0 {
+		return netlink.XFRM_MODE_TRANSPORT
+	}
+	return netlink.XFRM_MODE_TUNNEL
+}
+
+// activeKey returns the most recent key used for outgoing traffic
+func (m *Manager) activeKey() *Key {
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		if m.keys[i].outbound {
+			return m.keys[i].key
+		}
+	}
+	return nil
+}
+
+// inboundState returns the state decrypting the traffic of all remote nodes
+// encrypted with key k
+func (m *Manager) inboundState(k *Key) *netlink.XfrmState {
+	return newState(k, net.IPv4zero, m.conf.LocalIP, m.mode())
+}
+
+func (m *Manager) outboundState(k *Key, rn *remoteNode) *netlink.XfrmState {
+	return newState(k, m.conf.LocalIP, rn.ip, m.mode())
+}
+
+// policies returns the policies selecting the traffic to and from the
+// remote node for encryption
+func (m *Manager) policies(rn *remoteNode) []*netlink.XfrmPolicy {
+	if m.conf.TunnelPort != 0 {
+		return []*netlink.XfrmPolicy{{
+			Src:     hostNet(m.conf.LocalIP),
+			Dst:     hostNet(rn.ip),
+			Proto:   syscall.IPPROTO_UDP,
+			DstPort: m.conf.TunnelPort,
+			Dir:     netlink.XFRM_DIR_OUT,
+			Tmpls: []netlink.XfrmPolicyTmpl{
+				newTemplate(m.conf.LocalIP, rn.ip, netlink.XFRM_MODE_TRANSPORT),
+			},
+		}}
+	}
+
+	// Decrypted packets are only accepted if they match an inbound
+	// template, the source of the template is left unspecified as the
+	// inbound states are shared by all remote nodes
+	inbound := newTemplate(net.IPv4zero, m.conf.LocalIP, netlink.XFRM_MODE_TUNNEL)
+	return []*netlink.XfrmPolicy{
+		{
+			Src: m.conf.LocalAllocCIDR,
+			Dst: rn.allocCIDR,
+			Dir: netlink.XFRM_DIR_OUT,
+			Tmpls: []netlink.XfrmPolicyTmpl{
+				newTemplate(m.conf.LocalIP, rn.ip, netlink.XFRM_MODE_TUNNEL),
+			},
+		},
+		{
+			Src:   rn.allocCIDR,
+			Dst:   m.conf.LocalAllocCIDR,
+			Dir:   netlink.XFRM_DIR_IN,
+			Tmpls: []netlink.XfrmPolicyTmpl{inbound},
+		},
+		{
+			Src:   rn.allocCIDR,
+			Dst:   m.conf.LocalAllocCIDR,
+			Dir:   netlink.XFRM_DIR_FWD,
+			Tmpls: []netlink.XfrmPolicyTmpl{inbound},
+		},
+	}
+}
+
+// programNode installs the outbound states of all keys used for outgoing
+// traffic and the policies of the remote node
+func (m *Manager) programNode(rn *remoteNode) error {
+	rn.spi = 0
+
+	for _, ks := range m.keys {
+		if ks.outbound {
+			if err := upsertState(m.handle, m.outboundState(ks.key, rn)); err != nil {
+				return fmt.Errorf("unable to install state for SPI %d: %s", ks.key.SPI, err)
+			}
+		}
+	}
+
+	for _, p := range m.policies(rn) {
+		if err := m.handle.XfrmPolicyUpdate(p); err != nil {
+			return fmt.Errorf("unable to install %s policy: %s", p.Dir, err)
+		}
+	}
+
+	if k := m.activeKey(); k != nil {
+		rn.spi = k.SPI
+	}
+
+	return nil
+}
+
+// unprogramNode removes the policies and outbound states of the remote node
+func (m *Manager) unprogramNode(rn *remoteNode) error {
+	for _, p := range m.policies(rn) {
+		if err := deletePolicy(m.handle, p); err != nil {
+			return fmt.Errorf("unable to remove %s policy: %s", p.Dir, err)
+		}
+	}
+
+	for _, ks := range m.keys {
+		if err := deleteState(m.handle, m.outboundState(ks.key, rn)); err != nil {
+			return fmt.Errorf("unable to remove state for SPI %d: %s", ks.key.SPI, err)
+		}
+	}
+
+	rn.spi = 0
+	return nil
+}
+
+func (m *Manager) programNodes() {
+	for ni, rn := range m.nodes {
+		if err := m.programNode(rn); err != nil {
+			log.WithError(err).WithField(logfields.Node, ni).Warning("Unable to encrypt traffic to node")
+		}
+	}
+}
+
+// SetKey installs the key k. The first key is used right away. Subsequent
+// keys only decrypt incoming traffic at first and are used for outgoing
+// traffic after the rotation delay. This gives all nodes time to install
+// the key before it is used. The previous keys are removed once the rotation
+// delay has passed again.
+func (m *Manager) SetKey(k *Key) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.keys) == 0 {
+		return m.setInitialKey(k)
+	}
+
+	for i, ks := range m.keys {
+		if ks.key.SPI != k.SPI {
+			continue
+		}
+		if i == len(m.keys)-1 && ks.key.Equal(k) {
+			return nil
+		}
+		return fmt.Errorf("SPI %d is already in use by another key, a new key requires a new SPI", k.SPI)
+	}
+
+	if err := upsertState(m.handle, m.inboundState(k)); err != nil {
+		return fmt.Errorf("unable to install inbound state for SPI %d: %s", k.SPI, err)
+	}
+	m.keys = append(m.keys, &keyState{key: k})
+
+	log.WithField(logfields.IPSecKey, k).Info("Installed new key, rotating keys")
+
+	m.afterFunc(m.conf.RotationDelay, func() { m.activateKey(k.SPI) })
+
+	return nil
+}
+
+// setInitialKey installs k as the key used for all traffic. States of
+// keys left behind by a previous run are removed.
+func (m *Manager) setInitialKey(k *Key) error {
+	err := deleteStates(m.handle, func(s *netlink.XfrmState) bool {
+		return s.Spi == int(k.SPI)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to remove stale states: %s", err)
+	}
+
+	if err := upsertState(m.handle, m.inboundState(k)); err != nil {
+		return fmt.Errorf("unable to install inbound state for SPI %d: %s", k.SPI, err)
+	}
+	m.keys = []*keyState{{key: k, outbound: true}}
+
+	log.WithField(logfields.IPSecKey, k).Info("Installed initial key")
+
+	m.programNodes()
+
+	return nil
+}
+
+// findKey returns the index of the key with the given SPI or -1
+func (m *Manager) findKey(spi uint32) int {
+	for i, ks := range m.keys {
+		if ks.key.SPI == spi {
+			return i
+		}
+	}
+	return -1
+}
+
+// activateKey uses the key with the given SPI for outgoing traffic in favour
+// of all previous keys
+func (m *Manager) activateKey(spi uint32) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.findKey(spi)
+	if m.closed || idx < 0 {
+		return
+	}
+
+	// The kernel prefers the most recently added of multiple matching
+	// states, the previous outbound states are removed once the new ones
+	// are in place
+	m.keys[idx].outbound = true
+	m.programNodes()
+
+	for _, ks := range m.keys[:idx] {
+		ks.outbound = false
+		for ni, rn := range m.nodes {
+			if err := deleteState(m.handle, m.outboundState(ks.key, rn)); err != nil {
+				log.WithError(err).WithField(logfields.Node, ni).Warning("Unable to remove outbound state of previous key")
+			}
+		}
+	}
+
+	log.WithField(logfields.IPSecKey, m.keys[idx].key).Info("Encrypting traffic with new key")
+
+	m.afterFunc(m.conf.RotationDelay, func() { m.removePreviousKeys(spi) })
+}
+
+// removePreviousKeys removes all keys installed before the key with the
+// given SPI
+func (m *Manager) removePreviousKeys(spi uint32) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	idx := m.findKey(spi)
+	if m.closed || idx <= 0 {
+		return
+	}
+
+	for _, ks := range m.keys[:idx] {
+		if err := deleteState(m.handle, m.inboundState(ks.key)); err != nil {
+			log.WithError(err).WithField(logfields.IPSecKey, ks.key).Warning("Unable to remove inbound state of previous key")
+		}
+		log.WithField(logfields.IPSecKey, ks.key).Info("Removed previous key")
+	}
+	m.keys = m.keys[idx:]
+}
+
+// NodeUpdated programs the encryption of the traffic to and from the node,
+// it implements node.Observer
+func (m *Manager) NodeUpdated(ni node.Identity, n node.Node) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	scopedLog := log.WithField(logfields.Node, ni)
+
+	rn := &remoteNode{ip: n.GetNodeIP(false), allocCIDR: n.IPv4AllocCIDR}
+	valid := rn.ip != nil && !rn.ip.Equal(m.conf.LocalIP) &&
+		(m.conf.TunnelPort != 0 || rn.allocCIDR != nil)
+
+	if old, ok := m.nodes[ni]; ok {
+		if valid && old.ip.Equal(rn.ip) && old.allocCIDR.String() == rn.allocCIDR.String() {
+			// Retry programming the node if it failed before
+			if old.spi != 0 || len(m.keys) == 0 {
+				return
+			}
+			rn = old
+		} else {
+			if err := m.unprogramNode(old); err != nil {
+				scopedLog.WithError(err).Warning("Unable to remove encryption of previous node addresses")
+			}
+			delete(m.nodes, ni)
+		}
+	}
+
+	if !valid {
+		return
+	}
+
+	m.nodes[ni] = rn
+	if len(m.keys) == 0 {
+		return
+	}
+
+	if err := m.programNode(rn); err != nil {
+		scopedLog.WithError(err).Warning("Unable to encrypt traffic to node")
+	} else {
+		scopedLog.WithFields(logrus.Fields{
+			logfields.IPAddr: rn.ip,
+			logfields.SPI:    rn.spi,
+		}).Debug("Encrypting traffic to node")
+	}
+}
+
+// NodeDeleted removes the encryption of the traffic to and from the node,
+// it implements node.Observer
+func (m *Manager) NodeDeleted(ni node.Identity, n node.Node) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if rn, ok := m.nodes[ni]; ok {
+		if err := m.unprogramNode(rn); err != nil {
+			log.WithError(err).WithField(logfields.Node, ni).Warning("Unable to remove encryption of node")
+		}
+		delete(m.nodes, ni)
+	}
+}
+
+// GetNodeKey returns the SPI of the key encrypting the traffic to the remote
+// node, 0 if the traffic to the node is not encrypted
+func (m *Manager) GetNodeKey(ni node.Identity) uint32 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if rn, ok := m.nodes[ni]; ok {
+		return rn.spi
+	}
+	return 0
+}
+
+// GetActiveKey returns the SPI of the key used to encrypt outgoing traffic,
+// 0 if no key has been installed
+func (m *Manager) GetActiveKey() uint32 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if k := m.activeKey(); k != nil {
+		return k.SPI
+	}
+	return 0
+}
+
+// Status returns the status of the encryption
+func (m *Manager) Status() *models.EncryptionStatus {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	msg := "No key installed"
+	if k := m.activeKey(); k != nil {
+		msg = fmt.Sprintf("Key SPI %d", k.SPI)
+		if newest := m.keys[len(m.keys)-1].key; newest != k {
+			msg += fmt.Sprintf(", rotating to SPI %d", newest.SPI)
+		} else if len(m.keys) > 1 {
+			msg += fmt.Sprintf(", removing SPI %d", m.keys[0].key.SPI)
+		}
+	}
+	if m.keyErr != nil {
+		msg += fmt.Sprintf(", unable to load key file: %s", m.keyErr)
+	}
+
+	return &models.EncryptionStatus{
+		Mode: models.EncryptionStatusModeIPsec,
+		Msg:  msg,
+	}
+}
@@ -0,0 +1,377 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/node"
+
+	"github.com/vishvananda/netlink"
+	"k8s.io/api/core/v1"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type IPSecSuite struct{}
+
+var _ = Suite(&IPSecSuite{})
+
+// fakeHandle mimics the xfrm states and policies of the kernel. States are
+// identified by destination and SPI, policies by direction and selector.
+type fakeHandle struct {
+	states   map[string]netlink.XfrmState
+	policies map[string]netlink.XfrmPolicy
+	err      error
+}
+
+func newFakeHandle() *fakeHandle {
+	return &fakeHandle{
+		states:   map[string]netlink.XfrmState{},
+		policies: map[string]netlink.XfrmPolicy{},
+	}
+}
+
+func stateKey(s *netlink.XfrmState) string {
+	return fmt.Sprintf("%s %d", s.Dst, s.Spi)
+}
+
+func policyKey(p *netlink.XfrmPolicy) string {
+	return fmt.Sprintf("%s %s %s %d %d", p.Dir, p.Src, p.Dst, p.Proto, p.DstPort)
+}
+
+func (h *fakeHandle) XfrmStateAdd(s *netlink.XfrmState) error {
+	if h.err != nil {
+		return h.err
+	}
+	if _, ok := h.states[stateKey(s)]; ok {
+		return syscall.EEXIST
+	}
+	h.states[stateKey(s)] = *s
+	return nil
+}
+
+func (h *fakeHandle) XfrmStateUpdate(s *netlink.XfrmState) error {
+	if _, ok := h.states[stateKey(s)]; !ok {
+		return syscall.ESRCH
+	}
+	h.states[stateKey(s)] = *s
+	return nil
+}
+
+func (h *fakeHandle) XfrmStateDel(s *netlink.XfrmState) error {
+	if _, ok := h.states[stateKey(s)]; !ok {
+		return syscall.ESRCH
+	}
+	delete(h.states, stateKey(s))
+	return nil
+}
+
+func (h *fakeHandle) XfrmStateList(family int) ([]netlink.XfrmState, error) {
+	states := []netlink.XfrmState{}
+	for _, s := range h.states {
+		states = append(states, s)
+	}
+	return states, nil
+}
+
+func (h *fakeHandle) XfrmPolicyUpdate(p *netlink.XfrmPolicy) error {
+	if h.err != nil {
+		return h.err
+	}
+	h.policies[policyKey(p)] = *p
+	return nil
+}
+
+func (h *fakeHandle) XfrmPolicyDel(p *netlink.XfrmPolicy) error {
+	if _, ok := h.policies[policyKey(p)]; !ok {
+		return syscall.ENOENT
+	}
+	delete(h.policies, policyKey(p))
+	return nil
+}
+
+func (h *fakeHandle) XfrmPolicyList(family int) ([]netlink.XfrmPolicy, error) {
+	policies := []netlink.XfrmPolicy{}
+	for _, p := range h.policies {
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+// stateKeys returns the sorted identifiers of all states
+func (h *fakeHandle) stateKeys() []string {
+	keys := []string{}
+	for k := range h.states {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (h *fakeHandle) policyKeys() []string {
+	keys := []string{}
+	for k := range h.policies {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fakeTimers records the functions scheduled by the manager
+type fakeTimers struct {
+	pending []func()
+}
+
+func (t *fakeTimers) afterFunc(d time.Duration, f func()) {
+	t.pending = append(t.pending, f)
+}
+
+// fire runs the functions scheduled so far
+func (t *fakeTimers) fire() {
+	pending := t.pending
+	t.pending = nil
+	for _, f := range pending {
+		f()
+	}
+}
+
+func mustParseKey(c *C, s string) *Key {
+	k, err := ParseKey(s)
+	c.Assert(err, IsNil)
+	return k
+}
+
+func mustParseCIDR(c *C, s string) *net.IPNet {
+	_, prefix, err := net.ParseCIDR(s)
+	c.Assert(err, IsNil)
+	return prefix
+}
+
+func newTestNode(c *C, name, ip, allocCIDR string) (node.Identity, node.Node) {
+	return node.Identity{Name: name}, node.Node{
+		Name: name,
+		IPAddresses: []node.Address{
+			{AddressType: v1.NodeInternalIP, IP: net.ParseIP(ip)},
+		},
+		IPv4AllocCIDR: mustParseCIDR(c, allocCIDR),
+	}
+}
+
+func newTestManager(c *C, tunnelPort int) (*Manager, *fakeHandle, *fakeTimers) {
+	h := newFakeHandle()
+	t := &fakeTimers{}
+	m := newManager(Config{
+		LocalIP:        net.ParseIP("192.168.0.1"),
+		LocalAllocCIDR: mustParseCIDR(c, "10.1.0.0/16"),
+		TunnelPort:     tunnelPort,
+		RotationDelay:  time.Minute,
+	}, h)
+	m.afterFunc = t.afterFunc
+	return m, h, t
+}
+
+func (s *IPSecSuite) TestTransportMode(c *C) {
+	m, h, _ := newTestManager(c, 8472)
+
+	// Nodes known before the key is installed are programmed with it
+	m.NodeUpdated(newTestNode(c, "node2", "192.168.0.2", "10.2.0.0/16"))
+	c.Assert(h.stateKeys(), HasLen, 0)
+	c.Assert(h.policyKeys(), HasLen, 0)
+
+	c.Assert(m.SetKey(mustParseKey(c, "1 rfc4106(gcm(aes)) 0x0102 128")), IsNil)
+	m.NodeUpdated(newTestNode(c, "node3", "192.168.0.3", "10.3.0.0/16"))
+
+	// The local node is ignored
+	m.NodeUpdated(newTestNode(c, "node1", "192.168.0.1", "10.1.0.0/16"))
+
+	c.Assert(h.stateKeys(), DeepEquals, []string{
+		"192.168.0.1 1",
+		"192.168.0.2 1",
+		"192.168.0.3 1",
+	})
+	c.Assert(h.policyKeys(), DeepEquals, []string{
+		"dir out 192.168.0.1/32 192.168.0.2/32 17 8472",
+		"dir out 192.168.0.1/32 192.168.0.3/32 17 8472",
+	})
+
+	in := h.states["192.168.0.1 1"]
+	c.Assert(in.Src.Equal(net.IPv4zero), Equals, true)
+	c.Assert(in.Mode, Equals, netlink.XFRM_MODE_TRANSPORT)
+	c.Assert(in.Aead.Name, Equals, "rfc4106(gcm(aes))")
+	c.Assert(in.Reqid, Equals, reqID)
+
+	out := h.states["192.168.0.2 1"]
+	c.Assert(out.Src.Equal(net.ParseIP("192.168.0.1")), Equals, true)
+	c.Assert(out.Proto, Equals, netlink.XFRM_PROTO_ESP)
+
+	c.Assert(m.GetNodeKey(node.Identity{Name: "node2"}), Equals, uint32(1))
+	c.Assert(m.GetNodeKey(node.Identity{Name: "node1"}), Equals, uint32(0))
+	c.Assert(m.GetActiveKey(), Equals, uint32(1))
+
+	m.NodeDeleted(newTestNode(c, "node2", "192.168.0.2", "10.2.0.0/16"))
+	c.Assert(h.stateKeys(), DeepEquals, []string{"192.168.0.1 1", "192.168.0.3 1"})
+	c.Assert(h.policyKeys(), DeepEquals, []string{"dir out 192.168.0.1/32 192.168.0.3/32 17 8472"})
+	c.Assert(m.GetNodeKey(node.Identity{Name: "node2"}), Equals, uint32(0))
+
+	// A changed node IP replaces the programming of the node
+	m.NodeUpdated(newTestNode(c, "node3", "192.168.0.4", "10.3.0.0/16"))
+	c.Assert(h.stateKeys(), DeepEquals, []string{"192.168.0.1 1", "192.168.0.4 1"})
+	c.Assert(h.policyKeys(), DeepEquals, []string{"dir out 192.168.0.1/32 192.168.0.4/32 17 8472"})
+}
+
+func (s *IPSecSuite) TestTunnelMode(c *C) {
+	m, h, _ := newTestManager(c, 0)
+
+	c.Assert(m.SetKey(mustParseKey(c, "1 hmac(sha256) 0x0a0b cbc(aes) 0x0c0d")), IsNil)
+	m.NodeUpdated(newTestNode(c, "node2", "192.168.0.2", "10.2.0.0/16"))
+
+	c.Assert(h.stateKeys(), DeepEquals, []string{"192.168.0.1 1", "192.168.0.2 1"})
+	c.Assert(h.policyKeys(), DeepEquals, []string{
+		"dir fwd 10.2.0.0/16 10.1.0.0/16 0 0",
+		"dir in 10.2.0.0/16 10.1.0.0/16 0 0",
+		"dir out 10.1.0.0/16 10.2.0.0/16 0 0",
+	})
+
+	out := h.states["192.168.0.2 1"]
+	c.Assert(out.Mode, Equals, netlink.XFRM_MODE_TUNNEL)
+	c.Assert(out.Auth.Name, Equals, "hmac(sha256)")
+	c.Assert(out.Crypt.Name, Equals, "cbc(aes)")
+
+	outPolicy := h.policies["dir out 10.1.0.0/16 10.2.0.0/16 0 0"]
+	c.Assert(outPolicy.Tmpls, HasLen, 1)
+	c.Assert(outPolicy.Tmpls[0].Dst.Equal(net.ParseIP("192.168.0.2")), Equals, true)
+	c.Assert(outPolicy.Tmpls[0].Reqid, Equals, reqID)
+
+	// Nodes without allocation CIDR cannot be encrypted in tunnel mode
+	ni, n := newTestNode(c, "node2", "192.168.0.2", "10.2.0.0/16")
+	n.IPv4AllocCIDR = nil
+	m.NodeUpdated(ni, n)
+	c.Assert(h.stateKeys(), DeepEquals, []string{"192.168.0.1 1"})
+	c.Assert(h.policyKeys(), HasLen, 0)
+	c.Assert(m.GetNodeKey(ni), Equals, uint32(0))
+}
+
+func (s *IPSecSuite) TestKeyRotation(c *C) {
+	m, h, t := newTestManager(c, 8472)
+
+	c.Assert(m.SetKey(mustParseKey(c, "1 rfc4106(gcm(aes)) 0x0102 128")), IsNil)
+	m.NodeUpdated(newTestNode(c, "node2", "192.168.0.2", "10.2.0.0/16"))
+
+	// Installing the same key again has no effect
+	c.Assert(m.SetKey(mustParseKey(c, "1 rfc4106(gcm(aes)) 0x0102 128")), IsNil)
+	c.Assert(t.pending, HasLen, 0)
+
+	// A different key must not reuse the SPI
+	c.Assert(m.SetKey(mustParseKey(c, "1 rfc4106(gcm(aes)) 0x0304 128")), Not(IsNil))
+
+	// The new key is used for incoming traffic right away
+	c.Assert(m.SetKey(mustParseKey(c, "2 rfc4106(gcm(aes)) 0x0304 128")), IsNil)
+	c.Assert(h.stateKeys(), DeepEquals, []string{
+		"192.168.0.1 1",
+		"192.168.0.1 2",
+		"192.168.0.2 1",
+	})
+	c.Assert(m.GetNodeKey(node.Identity{Name: "node2"}), Equals, uint32(1))
+	c.Assert(m.Status().Msg, Equals, "Key SPI 1, rotating to SPI 2")
+
+	// Nodes added during the rotation only use the previous key
+	m.NodeUpdated(newTestNode(c, "node3", "192.168.0.3", "10.3.0.0/16"))
+	c.Assert(m.GetNodeKey(node.Identity{Name: "node3"}), Equals, uint32(1))
+
+	// After the rotation delay, outgoing traffic uses the new key
+	t.fire()
+	c.Assert(h.stateKeys(), DeepEquals, []string{
+		"192.168.0.1 1",
+		"192.168.0.1 2",
+		"192.168.0.2 2",
+		"192.168.0.3 2",
+	})
+	c.Assert(m.GetNodeKey(node.Identity{Name: "node2"}), Equals, uint32(2))
+	c.Assert(m.GetActiveKey(), Equals, uint32(2))
+	c.Assert(m.Status().Msg, Equals, "Key SPI 2, removing SPI 1")
+
+	// After another delay, the previous key is removed
+	t.fire()
+	c.Assert(h.stateKeys(), DeepEquals, []string{
+		"192.168.0.1 2",
+		"192.168.0.2 2",
+		"192.168.0.3 2",
+	})
+	c.Assert(m.Status(), DeepEquals, &models.EncryptionStatus{
+		Mode: models.EncryptionStatusModeIPsec,
+		Msg:  "Key SPI 2",
+	})
+	c.Assert(t.pending, HasLen, 0)
+
+	// The SPI of a removed key can be used again
+	c.Assert(m.SetKey(mustParseKey(c, "1 rfc4106(gcm(aes)) 0x0506 128")), IsNil)
+
+	// Pending rotations are cancelled once the manager is closed
+	m.Close()
+	t.fire()
+	c.Assert(m.GetActiveKey(), Equals, uint32(2))
+}
+
+func (s *IPSecSuite) TestStaleStates(c *C) {
+	m, h, _ := newTestManager(c, 8472)
+
+	// States of a previous run are replaced, states not managed by
+	// Cilium are left untouched
+	h.states["192.168.0.1 1"] = netlink.XfrmState{Dst: net.ParseIP("192.168.0.1"), Spi: 1, Reqid: reqID}
+	h.states["192.168.0.2 1"] = netlink.XfrmState{Dst: net.ParseIP("192.168.0.2"), Spi: 1, Reqid: reqID}
+	h.states["192.168.0.2 5"] = netlink.XfrmState{Dst: net.ParseIP("192.168.0.2"), Spi: 5, Reqid: reqID}
+	h.states["192.168.0.9 5"] = netlink.XfrmState{Dst: net.ParseIP("192.168.0.9"), Spi: 5, Reqid: 1}
+
+	c.Assert(m.SetKey(mustParseKey(c, "1 rfc4106(gcm(aes)) 0x0102 128")), IsNil)
+	c.Assert(h.stateKeys(), DeepEquals, []string{
+		"192.168.0.1 1",
+		"192.168.0.2 1",
+		"192.168.0.9 5",
+	})
+	c.Assert(h.states["192.168.0.1 1"].Aead, Not(IsNil))
+
+	m.NodeUpdated(newTestNode(c, "node2", "192.168.0.2", "10.2.0.0/16"))
+	h.policies["other"] = netlink.XfrmPolicy{Tmpls: []netlink.XfrmPolicyTmpl{{Reqid: 1}}}
+
+	c.Assert(deleteAll(h), IsNil)
+	c.Assert(h.stateKeys(), DeepEquals, []string{"192.168.0.9 5"})
+	c.Assert(h.policyKeys(), HasLen, 1)
+}
+
+func (s *IPSecSuite) TestProgrammingFailure(c *C) {
+	m, h, _ := newTestManager(c, 8472)
+	c.Assert(m.SetKey(mustParseKey(c, "1 rfc4106(gcm(aes)) 0x0102 128")), IsNil)
+
+	h.err = fmt.Errorf("no xfrm")
+	m.NodeUpdated(newTestNode(c, "node2", "192.168.0.2", "10.2.0.0/16"))
+	c.Assert(m.GetNodeKey(node.Identity{Name: "node2"}), Equals, uint32(0))
+
+	// The programming is retried on the next update of the node
+	h.err = nil
+	m.NodeUpdated(newTestNode(c, "node2", "192.168.0.2", "10.2.0.0/16"))
+	c.Assert(m.GetNodeKey(node.Identity{Name: "node2"}), Equals, uint32(1))
+}
@@ -0,0 +1,155 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"net"
+	"syscall"
+
+	"github.com/vishvananda/netlink"
+)
+
+// reqID identifies the xfrm states and policies managed by Cilium
+const reqID = 0xc111
+
+// xfrmHandle programs the xfrm states and policies of a network namespace.
+// It is implemented by *netlink.Handle.
+type xfrmHandle interface {
+	XfrmStateAdd(state *netlink.XfrmState) error
+	XfrmStateUpdate(state *netlink.XfrmState) error
+	XfrmStateDel(state *netlink.XfrmState) error
+	XfrmStateList(family int) ([]netlink.XfrmState, error)
+	XfrmPolicyUpdate(policy *netlink.XfrmPolicy) error
+	XfrmPolicyDel(policy *netlink.XfrmPolicy) error
+	XfrmPolicyList(family int) ([]netlink.XfrmPolicy, error)
+}
+
+func copyAlgo(a *netlink.XfrmStateAlgo) *netlink.XfrmStateAlgo {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	return &c
+}
+
+// newState returns the ESP state of key k for the traffic from src to dst.
+// The replay window is disabled as inbound states are shared by all remote
+// nodes which each use their own sequence numbers.
+func newState(k *Key, src, dst net.IP, mode netlink.Mode) *netlink.XfrmState {
+	return &netlink.XfrmState{
+		Src:   src,
+		Dst:   dst,
+		Proto: netlink.XFRM_PROTO_ESP,
+		Mode:  mode,
+		Spi:   int(k.SPI),
+		Reqid: reqID,
+		Aead:  copyAlgo(k.Aead),
+		Auth:  copyAlgo(k.Auth),
+		Crypt: copyAlgo(k.Crypt),
+	}
+}
+
+func newTemplate(src, dst net.IP, mode netlink.Mode) netlink.XfrmPolicyTmpl {
+	return netlink.XfrmPolicyTmpl{
+		Src:   src,
+		Dst:   dst,
+		Proto: netlink.XFRM_PROTO_ESP,
+		Mode:  mode,
+		Reqid: reqID,
+	}
+}
+
+func hostNet(ip net.IP) *net.IPNet {
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)}
+}
+
+// upsertState adds the state or updates it if it already exists
+func upsertState(h xfrmHandle, state *netlink.XfrmState) error {
+	err := h.XfrmStateAdd(state)
+	if err == syscall.EEXIST {
+		err = h.XfrmStateUpdate(state)
+	}
+	return err
+}
+
+// deleteState deletes the state, a state which does not exist is ignored
+func deleteState(h xfrmHandle, state *netlink.XfrmState) error {
+	if err := h.XfrmStateDel(state); err != nil && err != syscall.ESRCH {
+		return err
+	}
+	return nil
+}
+
+// deletePolicy deletes the policy, a policy which does not exist is ignored
+func deletePolicy(h xfrmHandle, policy *netlink.XfrmPolicy) error {
+	if err := h.XfrmPolicyDel(policy); err != nil && err != syscall.ENOENT {
+		return err
+	}
+	return nil
+}
+
+func isCiliumPolicy(p *netlink.XfrmPolicy) bool {
+	for _, t := range p.Tmpls {
+		if t.Reqid == reqID {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteStates deletes all IPv4 states managed by Cilium for which keep
+// returns false
+func deleteStates(h xfrmHandle, keep func(s *netlink.XfrmState) bool) error {
+	states, err := h.XfrmStateList(netlink.FAMILY_V4)
+	if err != nil {
+		return err
+	}
+	for i := range states {
+		if states[i].Reqid == reqID && !keep(&states[i]) {
+			if err := deleteState(h, &states[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func deleteAll(h xfrmHandle) error {
+	policies, err := h.XfrmPolicyList(netlink.FAMILY_V4)
+	if err != nil {
+		return err
+	}
+	for i := range policies {
+		if isCiliumPolicy(&policies[i]) {
+			if err := deletePolicy(h, &policies[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return deleteStates(h, func(*netlink.XfrmState) bool { return false })
+}
+
+// DeleteAll removes all xfrm states and policies installed by Cilium. It is
+// used to clean up after encryption has been disabled.
+func DeleteAll() error {
+	h, err := netlink.NewHandle(syscall.NETLINK_XFRM)
+	if err != nil {
+		return err
+	}
+	defer h.Delete()
+
+	return deleteAll(h)
+}
@@ -0,0 +1,114 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"net"
+	"os"
+	"runtime"
+	"sort"
+	"syscall"
+
+	"github.com/cilium/cilium/pkg/node"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	. "gopkg.in/check.v1"
+)
+
+// newTestNetNS returns a netlink handle on a new network namespace
+func newTestNetNS(c *C) (*netlink.Handle, netns.NsHandle) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	orig, err := netns.Get()
+	c.Assert(err, IsNil)
+	defer orig.Close()
+
+	ns, err := netns.New()
+	c.Assert(err, IsNil)
+	c.Assert(netns.Set(orig), IsNil)
+
+	h, err := netlink.NewHandleAt(ns, syscall.NETLINK_XFRM)
+	c.Assert(err, IsNil)
+
+	return h, ns
+}
+
+func listStates(c *C, h *netlink.Handle) []string {
+	states, err := h.XfrmStateList(netlink.FAMILY_V4)
+	c.Assert(err, IsNil)
+
+	result := []string{}
+	for _, s := range states {
+		result = append(result, stateKey(&s))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func listPolicies(c *C, h *netlink.Handle) []string {
+	policies, err := h.XfrmPolicyList(netlink.FAMILY_V4)
+	c.Assert(err, IsNil)
+
+	result := []string{}
+	for _, p := range policies {
+		result = append(result, policyKey(&p))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (s *IPSecSuite) TestXfrmNetNS(c *C) {
+	if os.Getenv("CILIUM_ENABLE_XFRM_UNIT_TEST") == "" {
+		c.Skip("skipping xfrm unit test; CILIUM_ENABLE_XFRM_UNIT_TEST not set")
+	}
+
+	h, ns := newTestNetNS(c)
+	defer ns.Close()
+	defer h.Delete()
+
+	t := &fakeTimers{}
+	m := newManager(Config{
+		LocalIP:        net.ParseIP("192.168.0.1"),
+		LocalAllocCIDR: mustParseCIDR(c, "10.1.0.0/16"),
+	}, h)
+	m.afterFunc = t.afterFunc
+
+	c.Assert(m.SetKey(mustParseKey(c, "1 hmac(sha256) 0x000102030405060708090a0b0c0d0e0f cbc(aes) 0x101112131415161718191a1b1c1d1e1f")), IsNil)
+	m.NodeUpdated(newTestNode(c, "node2", "192.168.0.2", "10.2.0.0/16"))
+
+	c.Assert(listStates(c, h), DeepEquals, []string{"192.168.0.1 1", "192.168.0.2 1"})
+	c.Assert(listPolicies(c, h), DeepEquals, []string{
+		"dir fwd 10.2.0.0/16 10.1.0.0/16 0 0",
+		"dir in 10.2.0.0/16 10.1.0.0/16 0 0",
+		"dir out 10.1.0.0/16 10.2.0.0/16 0 0",
+	})
+
+	// Rotate to a new key
+	c.Assert(m.SetKey(mustParseKey(c, "2 hmac(sha256) 0x202122232425262728292a2b2c2d2e2f cbc(aes) 0x303132333435363738393a3b3c3d3e3f")), IsNil)
+	c.Assert(listStates(c, h), DeepEquals, []string{"192.168.0.1 1", "192.168.0.1 2", "192.168.0.2 1"})
+	t.fire()
+	c.Assert(listStates(c, h), DeepEquals, []string{"192.168.0.1 1", "192.168.0.1 2", "192.168.0.2 2"})
+	t.fire()
+	c.Assert(listStates(c, h), DeepEquals, []string{"192.168.0.1 2", "192.168.0.2 2"})
+
+	m.NodeDeleted(node.Identity{Name: "node2"}, node.Node{})
+	c.Assert(listStates(c, h), DeepEquals, []string{"192.168.0.1 2"})
+	c.Assert(listPolicies(c, h), HasLen, 0)
+
+	c.Assert(deleteAll(h), IsNil)
+	c.Assert(listStates(c, h), HasLen, 0)
+}
@@ -24,6 +24,10 @@ const (
 	PolicyLabelName = "io.cilium.k8s-policy-name"
 	// PolicyLabelNamespace is the policy's namespace set in k8s.
 	PolicyLabelNamespace = "io.cilium.k8s-policy-namespace"
+	// PolicyLabelCluster is the label added to identities of remote
+	// clusters. It refers to the name of the cluster the identity was
+	// allocated in.
+	PolicyLabelCluster = "io.cilium.k8s.policy.cluster"
 	// PodNamespaceLabel is the label used in kubernetes containers to
 	// specify which namespace they belong to.
 	PodNamespaceLabel = types.KubernetesPodNamespaceLabel
@@ -43,6 +43,18 @@ const (
 	// CustomResourceDefinitionKind is the Kind name of custom resource definition
 	CustomResourceDefinitionKind = "CiliumNetworkPolicy"
 
+	// EgressGatewayPolicySingularName is the singular name of the egress
+	// gateway policy custom resource definition
+	EgressGatewayPolicySingularName = "ciliumegressgatewaypolicy"
+
+	// EgressGatewayPolicyPluralName is the plural name of the egress gateway
+	// policy custom resource definition
+	EgressGatewayPolicyPluralName = "ciliumegressgatewaypolicies"
+
+	// EgressGatewayPolicyKind is the Kind name of the egress gateway policy
+	// custom resource definition
+	EgressGatewayPolicyKind = "CiliumEgressGatewayPolicy"
+
 	// CustomResourceDefinitionGroup is the name of the third party resource group
 	CustomResourceDefinitionGroup = k8sconst.GroupName
 
@@ -105,6 +117,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&CiliumNetworkPolicy{},
 		&CiliumNetworkPolicyList{},
+		&CiliumEgressGatewayPolicy{},
+		&CiliumEgressGatewayPolicyList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -136,7 +150,37 @@ func CreateCustomResourceDefinitions(clientset apiextensionsclient.Interface) er
 		},
 	}
 
-	return createUpdateCRD(clientset, "CiliumNetworkPolicy/v2", res)
+	if err := createUpdateCRD(clientset, "CiliumNetworkPolicy/v2", res); err != nil {
+		return err
+	}
+
+	return createEgressGatewayPolicyCRD(clientset)
+}
+
+// createEgressGatewayPolicyCRD creates the cluster-wide
+// CiliumEgressGatewayPolicy CRD. The policies are validated by the agents.
+func createEgressGatewayPolicyCRD(clientset apiextensionsclient.Interface) error {
+	res := &apiextensionsv1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: EgressGatewayPolicyPluralName + "." + SchemeGroupVersion.Group,
+			Labels: map[string]string{
+				CustomResourceDefinitionSchemaVersionKey: CustomResourceDefinitionSchemaVersion,
+			},
+		},
+		Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+			Group:   SchemeGroupVersion.Group,
+			Version: SchemeGroupVersion.Version,
+			Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+				Plural:     EgressGatewayPolicyPluralName,
+				Singular:   EgressGatewayPolicySingularName,
+				ShortNames: []string{"cegp"},
+				Kind:       EgressGatewayPolicyKind,
+			},
+			Scope: apiextensionsv1beta1.ClusterScoped,
+		},
+	}
+
+	return createUpdateCRD(clientset, "CiliumEgressGatewayPolicy/v2", res)
 }
 
 // createUpdateCRD ensures the CRD object is installed into the k8s cluster. It
@@ -158,3 +158,49 @@ type CiliumNetworkPolicyList struct {
 	// Items is a list of CiliumNetworkPolicy
 	Items []CiliumNetworkPolicy `json:"items"`
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CiliumEgressGatewayPolicy is a cluster-wide Kubernetes custom resource
+// redirecting the traffic of endpoints to external destinations through a
+// gateway node
+type CiliumEgressGatewayPolicy struct {
+	// +k8s:openapi-gen=false
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec is the desired egress gateway policy
+	Spec CiliumEgressGatewayPolicySpec `json:"spec"`
+}
+
+// CiliumEgressGatewayPolicySpec is the specification of an egress gateway
+// policy
+type CiliumEgressGatewayPolicySpec struct {
+	// EndpointSelector selects the endpoints whose traffic is redirected
+	EndpointSelector api.EndpointSelector `json:"endpointSelector"`
+
+	// DestinationCIDRs is the list of IPv4 prefixes of the external
+	// destinations
+	DestinationCIDRs []string `json:"destinationCIDRs"`
+
+	// GatewayNode is the name of the node through which the traffic leaves
+	// the cluster
+	GatewayNode string `json:"gatewayNode"`
+
+	// EgressIP is the source IP of the traffic leaving the gateway node. It
+	// must be assigned to an interface of the gateway node.
+	EgressIP string `json:"egressIP"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CiliumEgressGatewayPolicyList is a list of CiliumEgressGatewayPolicy objects
+// +k8s:openapi-gen=false
+type CiliumEgressGatewayPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	// Items is a list of CiliumEgressGatewayPolicy
+	Items []CiliumEgressGatewayPolicy `json:"items"`
+}
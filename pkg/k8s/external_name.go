@@ -0,0 +1,195 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"net"
+	"reflect"
+	"time"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultExternalNameRefreshInterval is the default interval in which
+	// the DNS name of an ExternalName service is resolved again
+	DefaultExternalNameRefreshInterval = 30 * time.Second
+
+	// ExternalNameRetryInterval is the interval in which resolving the DNS
+	// name of an ExternalName service is retried after a failure
+	ExternalNameRetryInterval = 10 * time.Second
+)
+
+var (
+	// ExternalNameRefreshInterval is the interval in which the DNS name of
+	// an ExternalName service is resolved again. The resolver of the Go
+	// runtime does not expose the TTL of records so names are refreshed in
+	// a fixed interval, changes of the addresses may thus take up to this
+	// long to be picked up. Must be set before NewExternalNameResolver() is
+	// called.
+	ExternalNameRefreshInterval = DefaultExternalNameRefreshInterval
+)
+
+// DNSResolver resolves the DNS names of ExternalName services
+type DNSResolver interface {
+	// LookupIP returns the addresses of the given name
+	LookupIP(name string) ([]net.IP, error)
+}
+
+// systemResolver resolves names with the resolver of the Go runtime
+type systemResolver struct{}
+
+func (systemResolver) LookupIP(name string) ([]net.IP, error) {
+	return net.LookupIP(name)
+}
+
+// externalName is the resolution state of a single ExternalName service
+type externalName struct {
+	name     string
+	endpoint *types.K8sServiceEndpoint
+	stop     chan struct{}
+}
+
+// ExternalNameResolver keeps the addresses of the DNS names of ExternalName
+// services up to date. The addresses are exposed as service endpoints so
+// that ToServices rules can be translated the same way as for headless
+// services.
+type ExternalNameResolver struct {
+	resolver DNSResolver
+	onChange func(svc types.K8sServiceNamespace)
+
+	// refreshInterval and retryInterval default to
+	// ExternalNameRefreshInterval and ExternalNameRetryInterval
+	refreshInterval time.Duration
+	retryInterval   time.Duration
+
+	mutex lock.Mutex
+	names map[types.K8sServiceNamespace]*externalName
+}
+
+// NewExternalNameResolver returns a new ExternalNameResolver. onChange is
+// called whenever the addresses of a service have changed. If resolver is
+// nil, the resolver of the Go runtime is used.
+func NewExternalNameResolver(resolver DNSResolver, onChange func(svc types.K8sServiceNamespace)) *ExternalNameResolver {
+	if resolver == nil {
+		resolver = systemResolver{}
+	}
+
+	return &ExternalNameResolver{
+		resolver:        resolver,
+		onChange:        onChange,
+		refreshInterval: ExternalNameRefreshInterval,
+		retryInterval:   ExternalNameRetryInterval,
+		names:           map[types.K8sServiceNamespace]*externalName{},
+	}
+}
+
+// Upsert starts resolving the DNS name of the given service. If the service
+// had a different DNS name before, the addresses of the previous name are
+// discarded.
+func (r *ExternalNameResolver) Upsert(svc types.K8sServiceNamespace, name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if e, ok := r.names[svc]; ok {
+		if e.name == name {
+			return
+		}
+		close(e.stop)
+	}
+
+	e := &externalName{
+		name: name,
+		stop: make(chan struct{}),
+	}
+	r.names[svc] = e
+
+	go r.run(svc, e)
+}
+
+// Delete stops resolving the DNS name of the given service
+func (r *ExternalNameResolver) Delete(svc types.K8sServiceNamespace) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if e, ok := r.names[svc]; ok {
+		close(e.stop)
+		delete(r.names, svc)
+	}
+}
+
+// Endpoint returns the addresses of the given service, nil if the DNS name
+// of the service has not been resolved yet
+func (r *ExternalNameResolver) Endpoint(svc types.K8sServiceNamespace) *types.K8sServiceEndpoint {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if e, ok := r.names[svc]; ok {
+		return e.endpoint
+	}
+	return nil
+}
+
+func (r *ExternalNameResolver) run(svc types.K8sServiceNamespace, e *externalName) {
+	scopedLog := log.WithFields(logrus.Fields{
+		logfields.K8sSvcName:   svc.ServiceName,
+		logfields.K8sNamespace: svc.Namespace,
+		fieldExternalName:      e.name,
+	})
+
+	for {
+		interval := r.retryInterval
+
+		ips, err := r.resolver.LookupIP(e.name)
+		if err != nil {
+			// Keep the previous addresses, the name may only be
+			// temporarily unresolvable
+			scopedLog.WithError(err).Warning("Unable to resolve DNS name of ExternalName service")
+		} else {
+			interval = r.refreshInterval
+
+			endpoint := types.NewK8sServiceEndpoint()
+			for _, ip := range ips {
+				endpoint.BEIPs[ip.String()] = true
+			}
+
+			r.mutex.Lock()
+			if r.names[svc] != e {
+				r.mutex.Unlock()
+				return
+			}
+			changed := e.endpoint == nil || !reflect.DeepEqual(e.endpoint.BEIPs, endpoint.BEIPs)
+			if changed {
+				e.endpoint = endpoint
+			}
+			r.mutex.Unlock()
+
+			if changed {
+				scopedLog.WithField(logfields.IPAddr, ips).Debug("Addresses of ExternalName service changed")
+				r.onChange(svc)
+			}
+		}
+
+		select {
+		case <-e.stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/lock"
+
+	. "gopkg.in/check.v1"
+)
+
+// fakeResolver resolves names from a static table
+type fakeResolver struct {
+	mutex lock.Mutex
+	ips   map[string][]net.IP
+}
+
+func (f *fakeResolver) set(name string, ips ...string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.ips[name] = nil
+	for _, ip := range ips {
+		f.ips[name] = append(f.ips[name], net.ParseIP(ip))
+	}
+}
+
+func (f *fakeResolver) LookupIP(name string) ([]net.IP, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	ips, ok := f.ips[name]
+	if !ok {
+		return nil, fmt.Errorf("no such host")
+	}
+	return ips, nil
+}
+
+func (s *K8sSuite) TestExternalNameResolver(c *C) {
+	svc := types.K8sServiceNamespace{ServiceName: "svc", Namespace: "default"}
+	resolver := &fakeResolver{ips: map[string][]net.IP{}}
+	resolver.set("example.com", "192.0.2.1")
+	resolver.set("example.org", "192.0.2.2", "2001:db8::2")
+
+	changes := make(chan types.K8sServiceNamespace, 16)
+	r := NewExternalNameResolver(resolver, func(svc types.K8sServiceNamespace) {
+		changes <- svc
+	})
+	// Refresh as quickly as possible
+	r.refreshInterval = time.Millisecond
+
+	waitForChange := func() {
+		select {
+		case changed := <-changes:
+			c.Assert(changed, Equals, svc)
+		case <-time.After(30 * time.Second):
+			c.Fatal("timeout while waiting for change")
+		}
+	}
+
+	c.Assert(r.Endpoint(svc), IsNil)
+
+	r.Upsert(svc, "example.com")
+	waitForChange()
+	c.Assert(r.Endpoint(svc).BEIPs, DeepEquals, map[string]bool{"192.0.2.1": true})
+
+	// Changing the DNS name resolves the new name
+	r.Upsert(svc, "example.org")
+	waitForChange()
+	c.Assert(r.Endpoint(svc).BEIPs, DeepEquals, map[string]bool{
+		"192.0.2.2":   true,
+		"2001:db8::2": true,
+	})
+
+	// Addresses are refreshed periodically
+	resolver.set("example.org", "192.0.2.3")
+	waitForChange()
+	c.Assert(r.Endpoint(svc).BEIPs, DeepEquals, map[string]bool{"192.0.2.3": true})
+
+	r.Delete(svc)
+	c.Assert(r.Endpoint(svc), IsNil)
+}
@@ -27,6 +27,9 @@ const (
 	// fieldMaxRetry is the maximum number of retries
 	fieldMaxRetry = "maxRetry"
 
+	// fieldExternalName is the DNS name of an ExternalName service
+	fieldExternalName = "externalName"
+
 	// subsysK8s is the value for logfields.LogSubsys
 	subsysK8s = "k8s"
 )
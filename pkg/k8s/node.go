@@ -60,6 +60,7 @@ func ParseNode(k8sNode *v1.Node) *node.Node {
 	node := &node.Node{
 		Name:        k8sNode.Name,
 		IPAddresses: addrs,
+		Zone:        k8sNode.Labels[annotation.NodeZoneLabel],
 	}
 
 	if len(k8sNode.Spec.PodCIDR) != 0 {
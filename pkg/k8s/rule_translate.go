@@ -143,25 +143,38 @@ func generateToCidrFromEndpoint(
 func deleteToCidrFromEndpoint(
 	egress *api.EgressRule, endpoint types.K8sServiceEndpoint) error {
 
-	newToCIDR := make([]api.CIDRRule, 0, len(egress.ToCIDRSet))
-
+	epIPs := make([]net.IP, 0, len(endpoint.BEIPs))
 	for ip := range endpoint.BEIPs {
 		epIP := net.ParseIP(ip)
 		if epIP == nil {
 			return fmt.Errorf("Unable to parse ip: %s", ip)
 		}
+		epIPs = append(epIPs, epIP)
+	}
 
-		for _, c := range egress.ToCIDRSet {
-			_, cidr, err := net.ParseCIDR(string(c.Cidr))
-			if err != nil {
-				return err
-			}
-			// if endpoint is not in CIDR or it's not
-			// generated it's ok to retain it
-			if !cidr.Contains(epIP) || !c.Generated {
-				newToCIDR = append(newToCIDR, c)
+	newToCIDR := make([]api.CIDRRule, 0, len(egress.ToCIDRSet))
+	for _, c := range egress.ToCIDRSet {
+		// Rules which were not generated are always retained
+		if !c.Generated {
+			newToCIDR = append(newToCIDR, c)
+			continue
+		}
+
+		_, cidr, err := net.ParseCIDR(string(c.Cidr))
+		if err != nil {
+			return err
+		}
+
+		contained := false
+		for _, epIP := range epIPs {
+			if cidr.Contains(epIP) {
+				contained = true
+				break
 			}
 		}
+		if !contained {
+			newToCIDR = append(newToCIDR, c)
+		}
 	}
 
 	egress.ToCIDRSet = newToCIDR
@@ -169,7 +182,9 @@ func deleteToCidrFromEndpoint(
 	return nil
 }
 
-// PreprocessRules translates rules that apply to headless services
+// PreprocessRules translates rules that apply to headless and ExternalName
+// services. The endpoints of ExternalName services hold the addresses their
+// DNS name resolves to.
 func PreprocessRules(
 	r api.Rules,
 	endpoints map[types.K8sServiceNamespace]*types.K8sServiceEndpoint,
@@ -178,7 +193,7 @@ func PreprocessRules(
 	for _, rule := range r {
 		for ns, ep := range endpoints {
 			svc, ok := services[ns]
-			if ok && svc.IsHeadless {
+			if ok && (svc.IsHeadless || svc.IsExternalName()) {
 				t := NewK8sTranslator(ns, *ep, false, svc.Labels)
 				err := t.Translate(rule)
 				if err != nil {
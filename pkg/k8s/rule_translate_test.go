@@ -290,3 +290,82 @@ func (s *K8sSuite) TestDontDeleteUserRules(c *C) {
 	c.Assert(len(rule.ToCIDRSet), Equals, 1)
 	c.Assert(string(rule.ToCIDRSet[0].Cidr), Equals, string(userCIDR))
 }
+
+func (s *K8sSuite) TestDeleteToCIDRMultipleEndpoints(c *C) {
+	userCIDR := api.CIDR("10.1.1.3/32")
+	rule := &api.EgressRule{
+		ToCIDRSet: []api.CIDRRule{
+			{
+				Cidr: userCIDR,
+			},
+		},
+	}
+
+	endpointInfo := types.K8sServiceEndpoint{
+		BEIPs: map[string]bool{
+			"10.1.1.1": true,
+			"10.1.1.2": true,
+		},
+	}
+
+	err := generateToCidrFromEndpoint(rule, endpointInfo)
+	c.Assert(err, IsNil)
+	c.Assert(len(rule.ToCIDRSet), Equals, 3)
+
+	// Deleting the CIDRs of an endpoint without addresses retains all rules
+	err = deleteToCidrFromEndpoint(rule, *types.NewK8sServiceEndpoint())
+	c.Assert(err, IsNil)
+	c.Assert(len(rule.ToCIDRSet), Equals, 3)
+
+	err = deleteToCidrFromEndpoint(rule, endpointInfo)
+	c.Assert(err, IsNil)
+	c.Assert(len(rule.ToCIDRSet), Equals, 1)
+	c.Assert(string(rule.ToCIDRSet[0].Cidr), Equals, string(userCIDR))
+}
+
+func (s *K8sSuite) TestPreprocessRulesExternalName(c *C) {
+	serviceInfo := types.K8sServiceNamespace{
+		ServiceName: "svc",
+		Namespace:   "default",
+	}
+
+	endpointInfo := types.K8sServiceEndpoint{
+		BEIPs: map[string]bool{
+			"192.0.2.1": true,
+		},
+	}
+
+	rule1 := api.Rule{
+		EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("bar")),
+		Egress: []api.EgressRule{{
+			ToServices: []api.Service{
+				{
+					K8sService: &api.K8sServiceNamespace{
+						ServiceName: serviceInfo.ServiceName,
+						Namespace:   serviceInfo.Namespace,
+					},
+				},
+			}},
+		},
+	}
+
+	endpoints := map[types.K8sServiceNamespace]*types.K8sServiceEndpoint{
+		serviceInfo: &endpointInfo,
+	}
+
+	// Services with a cluster IP are not translated
+	service := types.K8sServiceInfo{}
+	services := map[types.K8sServiceNamespace]*types.K8sServiceInfo{
+		serviceInfo: &service,
+	}
+
+	err := PreprocessRules(api.Rules{&rule1}, endpoints, services)
+	c.Assert(err, IsNil)
+	c.Assert(len(rule1.Egress[0].ToCIDRSet), Equals, 0)
+
+	service.ExternalName = "example.com"
+	err = PreprocessRules(api.Rules{&rule1}, endpoints, services)
+	c.Assert(err, IsNil)
+	c.Assert(len(rule1.Egress[0].ToCIDRSet), Equals, 1)
+	c.Assert(string(rule1.Egress[0].ToCIDRSet[0].Cidr), Equals, "192.0.2.1/32")
+}
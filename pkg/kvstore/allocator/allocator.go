@@ -54,8 +54,26 @@ const (
 	// attempted to be expired from the kvstore
 	gcInterval = time.Duration(10) * time.Minute
 
+	// localKeySyncInterval is the interval in which keys allocated while
+	// the kvstore was degraded are attempted to be synced to the kvstore
+	localKeySyncInterval = time.Duration(10) * time.Second
+
+	// lockOperationAllocate is the lock operation of an ID allocation
+	lockOperationAllocate = "allocator-allocate"
+
+	// lockOperationGC is the lock operation of the garbage collector
+	lockOperationGC = "allocator-gc"
+
+	// lockOperationSync is the lock operation of the synchronization of
+	// keys allocated in degraded mode
+	lockOperationSync = "allocator-sync"
+
 	// NoID is a special ID that represents "no ID available"
 	NoID ID = 0
+
+	// quarantineTimeFormat is the format used to store the expiration
+	// time of a quarantined ID in the kvstore
+	quarantineTimeFormat = time.RFC3339
 )
 
 // ID is the identified type which is being allocated. An ID maps to an
@@ -133,6 +151,16 @@ type IDMap map[ID]AllocatorKey
 //     key, the key is no longer found by Get()
 //  3. If the node goes down, all slave keys of that node are removed after
 //     the TTL expires (auto release).
+//
+// Quarantine:
+//    - basePath/quarantine/1001 => <expiration time>
+//
+//   If a quarantine period is configured, the garbage collector creates a
+//   quarantine key before it releases a master key. An ID with a quarantine
+//   key which has not expired yet is not allocated again. This prevents
+//   nodes which are lagging behind from associating a released ID with a
+//   stale key. In addition, all nodes watching the master keys quarantine
+//   deleted IDs locally for the same period.
 type Allocator struct {
 	// Events is a channel which will receive AllocatorEvent as IDs are
 	// added, modified or removed from the allocator
@@ -175,6 +203,31 @@ type Allocator struct {
 	// for ID and key changes.
 	lockPrefix string
 
+	// quarantinePrefix is the kvstore key prefix for all quarantine keys.
+	// It is being derived from the basePrefix.
+	quarantinePrefix string
+
+	// quarantinePeriodPrefix is the kvstore key prefix under which each
+	// allocator publishes its quarantine period so that tools running the
+	// garbage collector use the period configured on the nodes.
+	quarantinePeriodPrefix string
+
+	// quarantinePeriod is the duration for which a released ID is kept in
+	// quarantine before it can be allocated again
+	quarantinePeriod time.Duration
+
+	// quarantine contains all IDs known to be in quarantine along with
+	// the time at which the quarantine expires. Protected by mutex.
+	quarantine map[ID]time.Time
+
+	// gcObserver is called with the statistics of each garbage collector
+	// run if set
+	gcObserver func(*GCStats)
+
+	// reallocObserver is called if set when the ID of a key allocated in
+	// degraded mode was rejected and a new ID has been allocated
+	reallocObserver func(key AllocatorKey, oldID, newID ID)
+
 	// min is the lower limit when allocating IDs. The allocator will never
 	// allocate an ID lesser than this value.
 	min ID
@@ -225,6 +278,8 @@ type AllocatorOption func(*Allocator)
 //  - WithSuffix(string) - customize the node specifix suffix to attach to keys
 //  - WithMin(id) - minimum ID to allocate (default: 1)
 //  - WithMax(id) - maximum ID to allocate (default max(uint64))
+//  - WithQuarantine(duration) - quarantine period of released IDs (default: 0)
+//  - WithGCObserver(func) - function to call with garbage collector statistics
 //
 // After creation, IDs can be allocated with Allocate() and released with
 // Release()
@@ -233,24 +288,19 @@ func NewAllocator(basePath string, typ AllocatorKey, opts ...AllocatorOption) (*
 		return nil, fmt.Errorf("kvstore client not configured")
 	}
 
-	a := &Allocator{
-		keyType:     typ,
-		basePrefix:  basePath,
-		idPrefix:    path.Join(basePath, "id"),
-		valuePrefix: path.Join(basePath, "value"),
-		lockPrefix:  path.Join(basePath, "locks"),
-		min:         1,
-		max:         ID(^uint64(0)),
-		localKeys:   newLocalKeys(),
-		stopGC:      make(chan struct{}, 0),
-		suffix:      uuid.NewUUID().String()[:10],
-		cache:       IDMap{},
-		lockless:    locklessCapability(),
-		Events:      make(AllocatorEventChan, 1024),
-		backoffTemplate: backoff.Exponential{
-			Min:    time.Duration(20) * time.Millisecond,
-			Factor: 2.0,
-		},
+	a := newAllocator(basePath)
+	a.keyType = typ
+	a.min = 1
+	a.max = ID(^uint64(0))
+	a.localKeys = newLocalKeys()
+	a.stopGC = make(chan struct{}, 0)
+	a.suffix = uuid.NewUUID().String()[:10]
+	a.cache = IDMap{}
+	a.lockless = locklessCapability()
+	a.Events = make(AllocatorEventChan, 1024)
+	a.backoffTemplate = backoff.Exponential{
+		Min:    time.Duration(20) * time.Millisecond,
+		Factor: 2.0,
 	}
 
 	for _, fn := range opts {
@@ -269,15 +319,38 @@ func NewAllocator(basePath string, typ AllocatorKey, opts ...AllocatorOption) (*
 		return nil, errors.New("Maximum ID must be greater than minimum ID")
 	}
 
+	if a.quarantinePeriod < 0 {
+		return nil, errors.New("quarantine period must be >= 0")
+	}
+
 	if err := a.startWatchAndWait(); err != nil {
 		return nil, err
 	}
 
+	if err := a.publishQuarantinePeriod(); err != nil {
+		log.WithError(err).Warning("Unable to publish quarantine period to kvstore")
+	}
+
 	a.startGC()
+	a.startLocalKeySync()
 
 	return a, nil
 }
 
+// newAllocator returns an allocator with all kvstore prefixes derived from
+// basePath. The allocator is not connected to the kvstore.
+func newAllocator(basePath string) *Allocator {
+	return &Allocator{
+		basePrefix:             basePath,
+		idPrefix:               path.Join(basePath, "id"),
+		valuePrefix:            path.Join(basePath, "value"),
+		lockPrefix:             path.Join(basePath, "locks"),
+		quarantinePrefix:       path.Join(basePath, "quarantine"),
+		quarantinePeriodPrefix: path.Join(basePath, "config", "quarantine-period"),
+		quarantine:             map[ID]time.Time{},
+	}
+}
+
 // WithSuffix sets the suffix of the allocator to the specified value
 func WithSuffix(v string) AllocatorOption {
 	return func(a *Allocator) { a.suffix = v }
@@ -293,6 +366,26 @@ func WithMax(id ID) AllocatorOption {
 	return func(a *Allocator) { a.max = id }
 }
 
+// WithQuarantine sets the duration for which released IDs are quarantined
+// before they can be allocated again
+func WithQuarantine(period time.Duration) AllocatorOption {
+	return func(a *Allocator) { a.quarantinePeriod = period }
+}
+
+// WithGCObserver sets a function which is called with the statistics of each
+// garbage collector run
+func WithGCObserver(fn func(*GCStats)) AllocatorOption {
+	return func(a *Allocator) { a.gcObserver = fn }
+}
+
+// WithReallocationObserver sets a function which is called when the ID of a
+// key allocated in degraded mode was rejected because the ID has been
+// allocated to a different key in the meantime. All users of the old ID must
+// switch to the new ID.
+func WithReallocationObserver(fn func(key AllocatorKey, oldID, newID ID)) AllocatorOption {
+	return func(a *Allocator) { a.reallocObserver = fn }
+}
+
 // Delete deletes an allocator and stops the garbage collector
 func (a *Allocator) Delete() {
 	close(a.stopGC)
@@ -300,10 +393,10 @@ func (a *Allocator) Delete() {
 	close(a.Events)
 }
 
-// lockPath locks a key in the scope of an allocator
-func (a *Allocator) lockPath(key string) (*kvstore.Lock, error) {
+// lockPath locks a key in the scope of an allocator for the given operation
+func (a *Allocator) lockPath(key, operation string) (*kvstore.Lock, error) {
 	suffix := strings.TrimPrefix(key, a.basePrefix)
-	return kvstore.LockPath(path.Join(a.lockPrefix, suffix))
+	return kvstore.LockPathForOperation(path.Join(a.lockPrefix, suffix), operation)
 }
 
 // DeleteAllKeys will delete all keys
@@ -366,7 +459,7 @@ func (a *Allocator) selectAvailableID() (ID, string) {
 	for _, r := range idRandomizer.Perm(int(a.max - a.min + 1)) {
 		id := ID(r) + a.min
 		tried++
-		if _, ok := a.cache[id]; !ok && a.localKeys.lookupID(id) == "" {
+		if _, ok := a.cache[id]; !ok && !a.isQuarantinedLocked(id) && a.localKeys.lookupID(id) == "" {
 			return id, id.String()
 		}
 	}
@@ -450,7 +543,7 @@ func (a *Allocator) lockedAllocate(key AllocatorKey) (ID, bool, error) {
 		return 0, false, fmt.Errorf("another writer has allocated this key")
 	}
 
-	lock, err := a.lockPath(k)
+	lock, err := a.lockPath(k, lockOperationAllocate)
 	if err != nil {
 		a.localKeys.release(k)
 		return 0, false, fmt.Errorf("unable to lock key: %s", err)
@@ -469,6 +562,15 @@ func (a *Allocator) lockedAllocate(key AllocatorKey) (ID, bool, error) {
 		return 0, false, fmt.Errorf("master key already exists")
 	}
 
+	// The local quarantine cache may not know about the ID yet if the
+	// release happened before this allocator was started
+	if expires, quarantined := a.lookupQuarantine(id); quarantined {
+		a.localKeys.release(k)
+		lock.Unlock()
+		a.setQuarantine(id, expires)
+		return 0, false, fmt.Errorf("ID %s is quarantined until %s", strID, expires)
+	}
+
 	// create /id/<ID> and fail if it already exists
 	keyPath := path.Join(a.idPrefix, strID)
 	err = kvstore.CreateOnly(keyPath, []byte(k), false)
@@ -522,6 +624,13 @@ func (a *Allocator) Allocate(key AllocatorKey) (ID, bool, error) {
 		return val, false, nil
 	}
 
+	// In degraded mode the kvstore is unreachable. Keys which are known
+	// through the local cache can still be used, all other allocations
+	// fail immediately instead of running into kvstore timeouts.
+	if kvstore.IsDegraded() {
+		return a.allocateDegraded(key)
+	}
+
 	kvstore.Trace("Allocating from kvstore", nil, logrus.Fields{fieldKey: key})
 
 	// make a copy of the template and customize it
@@ -560,6 +669,180 @@ func (a *Allocator) Allocate(key AllocatorKey) (ID, bool, error) {
 	return 0, false, err
 }
 
+// allocateDegraded allocates a key while the kvstore is degraded. Only keys
+// with an ID in the local cache can be allocated. The slave key is created by
+// syncLocalKeys() once the kvstore is reachable again.
+func (a *Allocator) allocateDegraded(key AllocatorKey) (ID, bool, error) {
+	k := key.GetKey()
+
+	a.mutex.RLock()
+	value := NoID
+	for id, v := range a.cache {
+		if v.GetKey() == k {
+			value = id
+			break
+		}
+	}
+	a.mutex.RUnlock()
+
+	if value == NoID {
+		return 0, false, fmt.Errorf("kvstore is unreachable (degraded mode), unable to allocate new ID for key %s", key)
+	}
+
+	if _, err := a.localKeys.allocate(k, value); err != nil {
+		return 0, false, fmt.Errorf("unable to reserve local key '%s': %s", k, err)
+	}
+	a.localKeys.markPendingSync(k)
+
+	a.mutex.Lock()
+	a.nextCache[value] = key
+	a.mutex.Unlock()
+
+	log.WithFields(logrus.Fields{fieldKey: key, fieldID: value}).
+		Info("Allocated ID from local cache in degraded mode")
+
+	return value, false, nil
+}
+
+// syncLocalKeys creates the master and slave keys of all keys allocated
+// while the kvstore was degraded
+func (a *Allocator) syncLocalKeys() {
+	// The quarantine period key is protected by the lease and must be
+	// recreated after the lease has been lost
+	if err := a.publishQuarantinePeriod(); err != nil {
+		log.WithError(err).Warning("Unable to publish quarantine period to kvstore")
+	}
+
+	for k, id := range a.localKeys.getPendingSync() {
+		if err := a.syncLocalKey(k, id); err != nil {
+			log.WithError(err).WithFields(logrus.Fields{fieldKey: k, fieldID: id}).
+				Warning("Unable to sync local key to kvstore")
+		}
+	}
+}
+
+// syncLocalKey syncs a key allocated in degraded mode with the kvstore. The
+// ID was taken from the local cache and may have been released by the
+// garbage collector or even allocated to a different key in the meantime.
+// The master key is checked or recreated while holding the lock of the
+// garbage collector on the ID. If the ID is in use by a different key, the ID
+// is rejected and a new ID is allocated for the key.
+func (a *Allocator) syncLocalKey(k string, id ID) error {
+	masterKey := path.Join(a.idPrefix, id.String())
+
+	lock, err := a.lockPath(masterKey, lockOperationSync)
+	if err != nil {
+		return fmt.Errorf("unable to lock master key: %s", err)
+	}
+
+	if err := kvstore.CreateOnly(masterKey, []byte(k), false); err != nil {
+		v, err := kvstore.Get(masterKey)
+		if err != nil {
+			lock.Unlock()
+			return fmt.Errorf("unable to read master key '%s': %s", masterKey, err)
+		}
+
+		switch {
+		case v == nil:
+			lock.Unlock()
+			return fmt.Errorf("unable to create master key '%s'", masterKey)
+		case string(v) != k:
+			lock.Unlock()
+			log.WithFields(logrus.Fields{fieldKey: k, fieldID: id}).
+				Warning("ID allocated in degraded mode is in use by a different key, allocating new ID")
+			return a.reallocateLocalKey(k, id)
+		}
+	}
+
+	err = a.createValueNodeKey(k, id)
+	lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	a.localKeys.clearPendingSync(k)
+	a.localKeys.verify(k)
+
+	return nil
+}
+
+// reallocateLocalKey allocates a new ID for a key allocated in degraded mode
+// whose ID has been rejected. The references of the local key are moved to
+// the new ID.
+func (a *Allocator) reallocateLocalKey(k string, oldID ID) error {
+	key, err := a.keyType.PutKey(k)
+	if err != nil {
+		return fmt.Errorf("unable to decode key: %s", err)
+	}
+
+	lock, err := a.lockPath(k, lockOperationAllocate)
+	if err != nil {
+		return fmt.Errorf("unable to lock key: %s", err)
+	}
+	defer lock.Unlock()
+
+	// Another node may have allocated an ID for the key in the meantime
+	newID, err := a.GetNoCache(key)
+	if err != nil {
+		return err
+	}
+
+	if newID == NoID {
+		var strID string
+		newID, strID = a.selectAvailableID()
+		if newID == NoID {
+			return fmt.Errorf("no more available IDs in configured space")
+		}
+
+		if expires, quarantined := a.lookupQuarantine(newID); quarantined {
+			a.setQuarantine(newID, expires)
+			return fmt.Errorf("ID %s is quarantined until %s", strID, expires)
+		}
+
+		keyPath := path.Join(a.idPrefix, strID)
+		if err := kvstore.CreateOnly(keyPath, []byte(k), false); err != nil {
+			return fmt.Errorf("unable to create master key '%s': %s", keyPath, err)
+		}
+	}
+
+	if err := a.createValueNodeKey(k, newID); err != nil {
+		return err
+	}
+
+	if err := a.localKeys.replace(k, newID); err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	a.nextCache[newID] = key
+	a.mutex.Unlock()
+
+	log.WithFields(logrus.Fields{fieldKey: k, fieldID: newID, fieldOldID: oldID}).
+		Info("Reallocated key allocated in degraded mode")
+
+	if a.reallocObserver != nil {
+		a.reallocObserver(key, oldID, newID)
+	}
+
+	return nil
+}
+
+func (a *Allocator) startLocalKeySync() {
+	go func(a *Allocator) {
+		for {
+			select {
+			case <-a.stopGC:
+				return
+			case <-time.After(localKeySyncInterval):
+			}
+
+			if !kvstore.IsDegraded() {
+				a.syncLocalKeys()
+			}
+		}
+	}(a)
+}
+
 // Get returns the ID which is allocated to a key. Returns an ID of NoID if no ID
 // has been allocated to this key yet.
 func (a *Allocator) Get(key AllocatorKey) (ID, error) {
@@ -637,20 +920,99 @@ func (a *Allocator) Release(key AllocatorKey) error {
 	return nil
 }
 
-func (a *Allocator) runGC() error {
+// GCStats is the result of a garbage collector run
+type GCStats struct {
+	// Allocated is the number of IDs allocated in the kvstore
+	Allocated int
+
+	// InUse is the number of allocated IDs which are used by at least one
+	// node
+	InUse int
+
+	// Released is the map of IDs released by the garbage collector to
+	// their key. In dry-run mode, the IDs are only reported but not
+	// released.
+	Released map[ID]string
+
+	// Quarantined is the number of IDs which are in quarantine
+	Quarantined int
+
+	// QuarantinePeriod is the quarantine period used by the garbage
+	// collector run
+	QuarantinePeriod time.Duration
+}
+
+// isQuarantinedLocked returns true if the ID is in local quarantine. Must be
+// called with a.mutex held.
+func (a *Allocator) isQuarantinedLocked(id ID) bool {
+	expires, ok := a.quarantine[id]
+	return ok && time.Now().Before(expires)
+}
+
+// setQuarantine marks an ID as quarantined locally until expires
+func (a *Allocator) setQuarantine(id ID, expires time.Time) {
+	a.mutex.Lock()
+	a.quarantine[id] = expires
+	a.mutex.Unlock()
+}
+
+// expireQuarantine removes all expired IDs from the local quarantine
+func (a *Allocator) expireQuarantine() {
+	now := time.Now()
+
+	a.mutex.Lock()
+	for id, expires := range a.quarantine {
+		if !now.Before(expires) {
+			delete(a.quarantine, id)
+		}
+	}
+	a.mutex.Unlock()
+}
+
+// lookupQuarantine returns the expiration time of the quarantine key of an ID
+// in the kvstore and true if the quarantine has not expired yet
+func (a *Allocator) lookupQuarantine(id ID) (time.Time, bool) {
+	if a.quarantinePeriod == 0 {
+		return time.Time{}, false
+	}
+
+	v, err := kvstore.Get(path.Join(a.quarantinePrefix, id.String()))
+	if err != nil || v == nil {
+		return time.Time{}, false
+	}
+
+	expires, err := time.Parse(quarantineTimeFormat, string(v))
+	if err != nil {
+		log.WithError(err).WithFields(logrus.Fields{fieldID: id}).Warning("Unable to parse quarantine expiration time")
+		return time.Time{}, false
+	}
+
+	return expires, time.Now().Before(expires)
+}
+
+// RunGC performs a single garbage collector run. All master keys which are no
+// longer backed by any slave key are released. If a quarantine period is
+// configured, released IDs are put into quarantine and expired quarantine
+// keys are removed. In dry-run mode, the kvstore is not modified and the
+// returned statistics report what would have been released.
+func (a *Allocator) RunGC(dryRun bool) (*GCStats, error) {
+	stats := &GCStats{Released: map[ID]string{}}
+
 	// fetch list of all /id/ keys
 	allocated, err := kvstore.ListPrefix(a.idPrefix)
 	if err != nil {
-		return fmt.Errorf("list failed: %s", err)
+		return nil, fmt.Errorf("list failed: %s", err)
 	}
 
+	stats.Allocated = len(allocated)
+
 	// iterate over /id/
 	for key, v := range allocated {
 		// if a.lockless {
 		// FIXME: Add DeleteOnZeroCount support
 		// }
 
-		lock, err := a.lockPath(key)
+		lock, err := a.lockPath(key, lockOperationGC)
 		if err != nil {
 			continue
 		}
@@ -664,21 +1026,99 @@ func (a *Allocator) runGC() error {
 
 		// if ID has no user, delete it
 		if len(uses) == 0 {
-			kvstore.Delete(key)
+			id := a.keyToID(key, false)
+			if dryRun {
+				stats.Released[id] = string(v)
+			} else if err := a.releaseID(id, key); err != nil {
+				log.WithError(err).WithFields(logrus.Fields{fieldKey: key}).Warning("Unable to release unused ID")
+			} else {
+				stats.Released[id] = string(v)
+			}
+		} else {
+			stats.InUse++
 		}
 
 		lock.Unlock()
 	}
 
-	return nil
+	if a.quarantinePeriod > 0 {
+		quarantined, err := a.gcQuarantine(dryRun)
+		if err != nil {
+			return nil, err
+		}
+		stats.Quarantined = quarantined
+	}
+
+	return stats, nil
+}
+
+// releaseID deletes the master key of an ID. If a quarantine period is
+// configured, a quarantine key is created before the master key is deleted.
+func (a *Allocator) releaseID(id ID, key string) error {
+	if a.quarantinePeriod > 0 && id != NoID {
+		expires := time.Now().Add(a.quarantinePeriod)
+		quarantineKey := path.Join(a.quarantinePrefix, id.String())
+		if err := kvstore.Update(quarantineKey, []byte(expires.Format(quarantineTimeFormat)), false); err != nil {
+			return fmt.Errorf("unable to create quarantine key '%s': %s", quarantineKey, err)
+		}
+	}
+
+	return kvstore.Delete(key)
+}
+
+// gcQuarantine removes all expired quarantine keys from the kvstore and
+// refreshes the local quarantine. Returns the number of IDs which remain in
+// quarantine.
+func (a *Allocator) gcQuarantine(dryRun bool) (int, error) {
+	keys, err := kvstore.ListPrefix(a.quarantinePrefix)
+	if err != nil {
+		return 0, fmt.Errorf("list failed: %s", err)
+	}
+
+	now := time.Now()
+	quarantined := 0
+
+	for key, v := range keys {
+		expires, err := time.Parse(quarantineTimeFormat, string(v))
+		if err == nil && now.Before(expires) {
+			quarantined++
+			if id := a.quarantineKeyToID(key); id != NoID {
+				a.setQuarantine(id, expires)
+			}
+			continue
+		}
+
+		// expired or unparseable quarantine keys are removed
+		if !dryRun {
+			kvstore.Delete(key)
+		}
+	}
+
+	if !dryRun {
+		a.expireQuarantine()
+	}
+
+	return quarantined, nil
+}
+
+func (a *Allocator) quarantineKeyToID(key string) ID {
+	id, err := strconv.ParseUint(path.Base(key), 10, 64)
+	if err != nil {
+		return NoID
+	}
+
+	return ID(id)
 }
 
 func (a *Allocator) startGC() {
 	go func(a *Allocator) {
 		for {
-			if err := a.runGC(); err != nil {
+			stats, err := a.RunGC(false)
+			if err != nil {
 				log.WithError(err).WithFields(logrus.Fields{fieldPrefix: a.idPrefix}).
 					Debug("Unable to run garbage collector")
+			} else if a.gcObserver != nil {
+				a.gcObserver(stats)
 			}
 
 			select {
@@ -693,6 +1133,77 @@ func (a *Allocator) startGC() {
 	}(a)
 }
 
+// publishQuarantinePeriod stores the quarantine period of the allocator in
+// the kvstore. The key is protected by the lease and is removed when the
+// node disappears.
+func (a *Allocator) publishQuarantinePeriod() error {
+	key := path.Join(a.quarantinePeriodPrefix, a.suffix)
+	return kvstore.Update(key, []byte(a.quarantinePeriod.String()), true)
+}
+
+// lookupQuarantinePeriod returns the longest quarantine period published by
+// all allocators using the kvstore at basePath. Returns false if no
+// allocator has published a quarantine period.
+func (a *Allocator) lookupQuarantinePeriod() (time.Duration, bool, error) {
+	keys, err := kvstore.ListPrefix(a.quarantinePeriodPrefix)
+	if err != nil {
+		return 0, false, fmt.Errorf("list failed: %s", err)
+	}
+
+	var (
+		period time.Duration
+		found  bool
+	)
+
+	for key, v := range keys {
+		d, err := time.ParseDuration(string(v))
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{fieldKey: key}).Warning("Unable to parse quarantine period")
+			continue
+		}
+
+		if !found || d > period {
+			period = d
+			found = true
+		}
+	}
+
+	return period, found, nil
+}
+
+// RunGC performs a single garbage collector run on the allocator stored in
+// the kvstore at basePath. It does not require a running allocator and can be
+// used by tools to inspect or collect unused IDs. See Allocator.RunGC()
+//
+// The longest quarantine period published by the running allocators is used.
+// If no allocator has published a quarantine period, defaultQuarantinePeriod
+// is used.
+func RunGC(basePath string, defaultQuarantinePeriod time.Duration, dryRun bool) (*GCStats, error) {
+	if kvstore.Client() == nil {
+		return nil, fmt.Errorf("kvstore client not configured")
+	}
+
+	a := newAllocator(basePath)
+
+	period, found, err := a.lookupQuarantinePeriod()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve quarantine period: %s", err)
+	}
+	if !found {
+		period = defaultQuarantinePeriod
+	}
+	a.quarantinePeriod = period
+
+	stats, err := a.RunGC(dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.QuarantinePeriod = period
+
+	return stats, nil
+}
+
 // AllocatorEventChan is a channel to receive allocator events on
 type AllocatorEventChan chan AllocatorEvent
 
@@ -775,6 +1286,10 @@ func (a *Allocator) startWatch() waitChan {
 					case kvstore.EventTypeDelete:
 						kvstore.Trace("Removing id from cache", nil, logrus.Fields{fieldID: id})
 						delete(a.nextCache, id)
+
+						if a.quarantinePeriod > 0 {
+							a.quarantine[id] = time.Now().Add(a.quarantinePeriod)
+						}
 					}
 					a.mutex.Unlock()
 
@@ -204,7 +204,11 @@ func testAllocator(c *C, maxID ID, allocatorName string, suffix string) {
 	}
 
 	// running the GC should not evict any entries
-	allocator.runGC()
+	stats, err := allocator.RunGC(false)
+	c.Assert(err, IsNil)
+	c.Assert(stats.Allocated, Equals, int(maxID))
+	c.Assert(stats.InUse, Equals, int(maxID))
+	c.Assert(len(stats.Released), Equals, 0)
 
 	v, err := kvstore.ListPrefix(allocator.idPrefix)
 	c.Assert(err, IsNil)
@@ -215,8 +219,20 @@ func testAllocator(c *C, maxID ID, allocatorName string, suffix string) {
 		allocator.Release(TestType(fmt.Sprintf("key%04d", i)))
 	}
 
+	// a dry-run of the GC should report but not evict any entries
+	stats, err = allocator.RunGC(true)
+	c.Assert(err, IsNil)
+	c.Assert(stats.InUse, Equals, 0)
+	c.Assert(len(stats.Released), Equals, int(maxID))
+
+	v, err = kvstore.ListPrefix(allocator.idPrefix)
+	c.Assert(err, IsNil)
+	c.Assert(len(v), Equals, int(maxID))
+
 	// running the GC should evict all entries
-	allocator.runGC()
+	stats, err = allocator.RunGC(false)
+	c.Assert(err, IsNil)
+	c.Assert(len(stats.Released), Equals, int(maxID))
 
 	v, err = kvstore.ListPrefix(allocator.idPrefix)
 	c.Assert(err, IsNil)
@@ -231,6 +247,72 @@ func (s *AllocatorSuite) TestAllocateCached(c *C) {
 	testAllocator(c, ID(256), randStringRunes(12), "a") // enable use of local cache
 }
 
+func (s *AllocatorSuite) TestQuarantine(c *C) {
+	allocatorName := randStringRunes(12)
+	minID, maxID := ID(1), ID(2)
+	a, err := NewAllocator(allocatorName, TestType(""), WithMin(minID), WithMax(maxID),
+		WithSuffix("a"), WithQuarantine(time.Hour))
+	c.Assert(err, IsNil)
+	c.Assert(a, Not(IsNil))
+	defer a.DeleteAllKeys()
+	defer a.Delete()
+
+	key := TestType("key1")
+	id, isNew, err := a.Allocate(key)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, true)
+
+	c.Assert(a.Release(key), IsNil)
+
+	stats, err := a.RunGC(false)
+	c.Assert(err, IsNil)
+	c.Assert(stats.Released[id], Equals, key.GetKey())
+	c.Assert(stats.Quarantined, Equals, 1)
+
+	v, err := kvstore.Get(path.Join(a.quarantinePrefix, id.String()))
+	c.Assert(err, IsNil)
+	c.Assert(v, Not(IsNil))
+
+	// the released ID must not be selected while in quarantine
+	for i := 0; i < 10; i++ {
+		selected, _ := a.selectAvailableID()
+		c.Assert(selected, Not(Equals), id)
+	}
+
+	// a new allocator without a local quarantine must honour the
+	// quarantine key in the kvstore
+	a2, err := NewAllocator(allocatorName, TestType(""), WithMin(minID), WithMax(maxID),
+		WithSuffix("b"), WithQuarantine(time.Hour))
+	c.Assert(err, IsNil)
+	defer a2.Delete()
+
+	_, quarantined := a2.lookupQuarantine(id)
+	c.Assert(quarantined, Equals, true)
+}
+
+func (s *AllocatorSuite) TestRunGCQuarantinePeriod(c *C) {
+	allocatorName := randStringRunes(12)
+
+	// without a running allocator, the default period is used
+	stats, err := RunGC(allocatorName, time.Minute, true)
+	c.Assert(err, IsNil)
+	c.Assert(stats.QuarantinePeriod, Equals, time.Minute)
+
+	a, err := NewAllocator(allocatorName, TestType(""), WithSuffix("a"), WithQuarantine(time.Hour))
+	c.Assert(err, IsNil)
+	defer a.DeleteAllKeys()
+	defer a.Delete()
+
+	a2, err := NewAllocator(allocatorName, TestType(""), WithSuffix("b"), WithQuarantine(2*time.Hour))
+	c.Assert(err, IsNil)
+	defer a2.Delete()
+
+	// the longest period published by the allocators is used
+	stats, err = RunGC(allocatorName, time.Minute, true)
+	c.Assert(err, IsNil)
+	c.Assert(stats.QuarantinePeriod, Equals, 2*time.Hour)
+}
+
 func (s *AllocatorSuite) TestKeyToID(c *C) {
 	allocatorName := randStringRunes(12)
 	a, err := NewAllocator(allocatorName, TestType(""), WithSuffix("a"))
@@ -29,6 +29,10 @@ type localKey struct {
 
 	// verified is true when the key has been synced with the kvstore
 	verified bool
+
+	// pendingSync is true when the key has been allocated while the
+	// kvstore was degraded and the slave key has not been created yet
+	pendingSync bool
 }
 
 // localKeys is a map of keys in use locally. Keys can be used multiple times.
@@ -77,6 +81,60 @@ func (lk *localKeys) verify(key string) error {
 	return fmt.Errorf("key %s not found", key)
 }
 
+// markPendingSync marks an unverified key to require creation of its slave
+// key in the kvstore
+func (lk *localKeys) markPendingSync(key string) {
+	lk.Lock()
+	defer lk.Unlock()
+
+	if k, ok := lk.keys[key]; ok && !k.verified {
+		k.pendingSync = true
+	}
+}
+
+// clearPendingSync clears the pending sync mark of a key
+func (lk *localKeys) clearPendingSync(key string) {
+	lk.Lock()
+	defer lk.Unlock()
+
+	if k, ok := lk.keys[key]; ok {
+		k.pendingSync = false
+	}
+}
+
+// replace replaces the value of a key while keeping all references. The key
+// is marked as verified.
+func (lk *localKeys) replace(key string, val ID) error {
+	lk.Lock()
+	defer lk.Unlock()
+
+	if k, ok := lk.keys[key]; ok {
+		k.val = val
+		k.verified = true
+		k.pendingSync = false
+		kvstore.Trace("Replaced local key value", nil, logrus.Fields{fieldKey: key, fieldID: val, fieldRefCnt: k.refcnt})
+		return nil
+	}
+
+	return fmt.Errorf("key %s not found", key)
+}
+
+// getPendingSync returns all keys which are pending to be synced with the
+// kvstore
+func (lk *localKeys) getPendingSync() map[string]ID {
+	lk.RLock()
+	defer lk.RUnlock()
+
+	pending := map[string]ID{}
+	for k, v := range lk.keys {
+		if v.pendingSync {
+			pending[k] = v.val
+		}
+	}
+
+	return pending
+}
+
 // lookupID returns the key for a given ID or an empty string
 func (lk *localKeys) lookupID(id ID) string {
 	lk.RLock()
@@ -61,3 +61,43 @@ func (s *AllocatorSuite) TestLocalKeys(c *C) {
 	v = k.use(key2)
 	c.Assert(v, Equals, NoID)
 }
+
+func (s *AllocatorSuite) TestLocalKeysPendingSync(c *C) {
+	k := newLocalKeys()
+	key, val := "foo", ID(200)
+	key2, val2 := "bar", ID(300)
+
+	_, err := k.allocate(key, val)
+	c.Assert(err, IsNil)
+	k.markPendingSync(key)
+
+	// verified keys are never pending
+	_, err = k.allocate(key2, val2)
+	c.Assert(err, IsNil)
+	c.Assert(k.verify(key2), IsNil)
+	k.markPendingSync(key2)
+
+	c.Assert(k.getPendingSync(), DeepEquals, map[string]ID{key: val})
+
+	k.clearPendingSync(key)
+	c.Assert(k.getPendingSync(), DeepEquals, map[string]ID{})
+}
+
+func (s *AllocatorSuite) TestLocalKeysReplace(c *C) {
+	k := newLocalKeys()
+	key, val, newVal := "foo", ID(200), ID(201)
+
+	c.Assert(k.replace(key, newVal), Not(IsNil))
+
+	_, err := k.allocate(key, val)
+	c.Assert(err, IsNil)
+	_, err = k.allocate(key, val)
+	c.Assert(err, IsNil)
+	k.markPendingSync(key)
+
+	// the references are kept and the key is no longer pending
+	c.Assert(k.replace(key, newVal), IsNil)
+	c.Assert(k.getPendingSync(), DeepEquals, map[string]ID{})
+	c.Assert(k.use(key), Equals, newVal)
+	c.Assert(k.keys[key].refcnt, Equals, uint64(3))
+}
@@ -20,4 +20,5 @@ const (
 	fieldPrefix = "prefix"
 	fieldValue  = "value"
 	fieldRefCnt = "refcnt"
+	fieldOldID  = "oldID"
 )
@@ -58,6 +58,12 @@ type backendModule interface {
 	// newClient must initializes the backend and create a new kvstore
 	// client which implements the BackendOperations interface
 	newClient() (BackendOperations, error)
+
+	// newReadOnlyClient must create a new kvstore client configured with
+	// the specified options. The client is independent of the default
+	// client, does not maintain a lease and is not required to support
+	// locking or any write operations.
+	newReadOnlyClient(opts map[string]string) (BackendOperations, error)
 }
 
 var (
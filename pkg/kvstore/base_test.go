@@ -41,6 +41,90 @@ func (s *BaseTests) TestLock(c *C) {
 	}
 }
 
+func (s *BaseTests) TestLockInfo(c *C) {
+	prefix := "locktest/"
+	lockPath := prefix + "info"
+
+	DeletePrefix(prefix)
+	defer DeletePrefix(prefix)
+	defer DeletePrefix(LocksAuditPath)
+
+	lock, err := LockPathForOperation(lockPath, "test")
+	c.Assert(err, IsNil)
+
+	findLock := func() *LockInfo {
+		locks, err := ListLocks()
+		c.Assert(err, IsNil)
+		for _, l := range locks {
+			if l.Path == lockPath {
+				return l
+			}
+		}
+		return nil
+	}
+
+	info := findLock()
+	c.Assert(info, Not(IsNil))
+	c.Assert(info.Operation, Equals, "test")
+	c.Assert(info.Node, Equals, LockOwnerNode)
+	c.Assert(info.Key, Not(Equals), "")
+
+	// Unlocking removes the owner information
+	c.Assert(lock.Unlock(), IsNil)
+	c.Assert(findLock(), IsNil)
+
+	_, err = ForceReleaseLock(lockPath)
+	c.Assert(err, Equals, ErrLockNotHeld)
+
+	// A forcefully released lock can be acquired again
+	lock, err = LockPath(lockPath)
+	c.Assert(err, IsNil)
+
+	released, err := ForceReleaseLock(lockPath)
+	c.Assert(err, IsNil)
+	c.Assert(released.Path, Equals, lockPath)
+	c.Assert(findLock(), IsNil)
+
+	audit, err := ListLockAuditEntries()
+	c.Assert(err, IsNil)
+	c.Assert(len(audit), Equals, 1)
+	c.Assert(audit[0].Lock.Path, Equals, lockPath)
+	c.Assert(audit[0].ReleasedBy, Equals, LockOwnerNode)
+
+	// Unlocking the forcefully released lock must not remove the owner
+	// information of a new owner
+	c.Assert(writeLockInfo(newLockInfo(lockPath, "other", "other")), IsNil)
+	lock.Unlock()
+	c.Assert(findLock(), Not(IsNil))
+}
+
+func (s *BaseTests) TestLockAuditRetention(c *C) {
+	oldMax := MaxLockAuditEntries
+	defer func() { MaxLockAuditEntries = oldMax }()
+	MaxLockAuditEntries = 2
+
+	DeletePrefix(LocksAuditPath)
+	defer DeletePrefix(LocksAuditPath)
+
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		entry := &LockAuditEntry{
+			Lock:          &LockInfo{Path: fmt.Sprintf("locktest/%d", i)},
+			ReleasedBy:    LockOwnerNode,
+			ReleasedByPID: i,
+			ReleasedAt:    now.Add(time.Duration(i) * time.Second),
+		}
+		c.Assert(writeLockAuditEntry(entry), IsNil)
+	}
+
+	// only the most recent entries are kept
+	audit, err := ListLockAuditEntries()
+	c.Assert(err, IsNil)
+	c.Assert(len(audit), Equals, 2)
+	c.Assert(audit[0].Lock.Path, Equals, "locktest/2")
+	c.Assert(audit[1].Lock.Path, Equals, "locktest/3")
+}
+
 func testKey(prefix string, i int) string {
 	return fmt.Sprintf("%s%s/%010d", prefix, "foo", i)
 }
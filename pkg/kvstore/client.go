@@ -38,6 +38,7 @@ func initClient(module backendModule) error {
 	}
 
 	defaultClient = c
+	selectedModule = module.getName()
 
 	deleteLegacyPrefixes()
 	if err := renewDefaultLease(); err != nil {
@@ -45,6 +46,8 @@ func initClient(module backendModule) error {
 		return err
 	}
 
+	startStatusCheck()
+
 	return nil
 }
 
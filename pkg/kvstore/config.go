@@ -89,8 +89,6 @@ func setup(selectedBackend string, opts map[string]string) error {
 		return err
 	}
 
-	selectedModule = module.getName()
-
 	return initClient(module)
 }
 
@@ -105,3 +103,22 @@ func Setup(selectedBackend string, opts map[string]string) error {
 
 	return err
 }
+
+// NewReadOnlyClient creates a new kvstore client for the specified backend
+// which is independent of the default client set up with Setup(). The client
+// is intended to read and watch keys of a kvstore other than the default
+// kvstore, e.g. the kvstore of a remote cluster. It must be closed with
+// CloseClient().
+func NewReadOnlyClient(selectedBackend string, opts map[string]string) (BackendOperations, error) {
+	module := getBackend(selectedBackend)
+	if module == nil {
+		return nil, fmt.Errorf("unknown key-value store type %q. See cilium.link/err-kvstore for details", selectedBackend)
+	}
+
+	return module.newReadOnlyClient(opts)
+}
+
+// CloseClient closes a client created with NewReadOnlyClient()
+func CloseClient(c BackendOperations) {
+	c.closeClient()
+}
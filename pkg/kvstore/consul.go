@@ -111,12 +111,19 @@ func (c *consulModule) newClient() (BackendOperations, error) {
 	return client, nil
 }
 
+func (c *consulModule) newReadOnlyClient(opts map[string]string) (BackendOperations, error) {
+	return nil, fmt.Errorf("read-only clients are not supported by the %s backend", consulName)
+}
+
 var (
 	maxRetries = 30
 )
 
 type consulClient struct {
 	*consulAPI.Client
+
+	// revisions tracks the latest known raft index of the consul cluster
+	revisions revisionTracker
 }
 
 func newConsulClient(config *consulAPI.Config) (BackendOperations, error) {
@@ -156,7 +163,26 @@ func newConsulClient(config *consulAPI.Config) (BackendOperations, error) {
 		log.WithError(err).Fatal("Unable to contact consul server")
 	}
 
-	return &consulClient{c}, nil
+	return &consulClient{Client: c}, nil
+}
+
+type consulMutex struct {
+	*consulAPI.Lock
+	client *consulAPI.Client
+	key    string
+}
+
+func (c *consulMutex) Key() string {
+	return c.key
+}
+
+// UnlockAndDelete deletes key and releases the lock. The consul API does not
+// support transactions so this requires two operations.
+func (c *consulMutex) UnlockAndDelete(key string) error {
+	if _, err := c.client.KV().Delete(key, nil); err != nil {
+		log.WithError(err).WithField(fieldKey, key).Debug("Unable to delete key while unlocking")
+	}
+	return c.Unlock()
 }
 
 func (c *consulClient) LockPath(path string) (kvLocker, error) {
@@ -173,7 +199,7 @@ func (c *consulClient) LockPath(path string) (kvLocker, error) {
 		case ch == nil && err == nil:
 			Trace("Acquiring lock timed out, retrying", nil, logrus.Fields{fieldKey: path, logfields.Attempt: retries})
 		default:
-			return lockKey, err
+			return &consulMutex{Lock: lockKey, client: c.Client, key: getLockPath(path)}, err
 		}
 	}
 
@@ -388,6 +414,7 @@ func (c *consulClient) Watch(w *Watcher) {
 
 		qo.WaitIndex = nextIndex
 		pairs, q, err := c.KV().List(w.prefix, &qo)
+		received := time.Now()
 		if err != nil {
 			sleepTime = 5 * time.Second
 			Trace("List of Watch failed", err, logrus.Fields{fieldPrefix: w.prefix, fieldWatcher: w.name})
@@ -395,6 +422,7 @@ func (c *consulClient) Watch(w *Watcher) {
 
 		if q != nil {
 			nextIndex = q.LastIndex
+			c.revisions.observe(int64(q.LastIndex))
 		}
 
 		// timeout while watching for changes, re-schedule
@@ -408,32 +436,32 @@ func (c *consulClient) Watch(w *Watcher) {
 			// Keys reported for the first time must be new
 			if !ok {
 				if newPair.CreateIndex == newPair.ModifyIndex {
-					w.Events <- KeyValueEvent{
+					w.emit(consulName, received, &c.revisions, int64(newPair.ModifyIndex), KeyValueEvent{
 						Typ:   EventTypeCreate,
 						Key:   newPair.Key,
 						Value: newPair.Value,
-					}
+					})
 				} else {
 					log.Warning("consul: Previously unknown key %s received with CreateIndex(%d) != ModifyIndex(%d)",
 						newPair.Key, newPair.CreateIndex, newPair.ModifyIndex)
 				}
 			} else if oldPair.ModifyIndex != newPair.ModifyIndex {
-				w.Events <- KeyValueEvent{
+				w.emit(consulName, received, &c.revisions, int64(newPair.ModifyIndex), KeyValueEvent{
 					Typ:   EventTypeModify,
 					Key:   newPair.Key,
 					Value: newPair.Value,
-				}
+				})
 			}
 
 			delete(localState, newPair.Key)
 		}
 
 		for k, deletedPair := range localState {
-			w.Events <- KeyValueEvent{
+			w.emit(consulName, received, &c.revisions, int64(nextIndex), KeyValueEvent{
 				Typ:   EventTypeDelete,
 				Key:   deletedPair.Key,
 				Value: deletedPair.Value,
-			}
+			})
 			delete(localState, k)
 		}
 
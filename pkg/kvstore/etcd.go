@@ -37,10 +37,24 @@ import (
 )
 
 const (
-	etcdName = "etcd"
+	// EtcdBackendName is the backend name for etcd
+	EtcdBackendName = "etcd"
 
-	addrOption = "etcd.address"
-	cfgOption  = "etcd.config"
+	// EtcdAddrOption is the option to specify the address of etcd
+	EtcdAddrOption = "etcd.address"
+
+	// EtcdOptionConfig is the option to specify the path to the etcd
+	// configuration file
+	EtcdOptionConfig = "etcd.config"
+
+	etcdName = EtcdBackendName
+
+	// statusCheckTimeout is the timeout when performing status checks
+	// with each etcd endpoint
+	statusCheckTimeout = 10 * time.Second
+
+	addrOption = EtcdAddrOption
+	cfgOption  = EtcdOptionConfig
 )
 
 type etcdModule struct {
@@ -107,6 +121,36 @@ func (e *etcdModule) newClient() (BackendOperations, error) {
 	return newEtcdClient(e.config, configPath)
 }
 
+func (e *etcdModule) newReadOnlyClient(opts map[string]string) (BackendOperations, error) {
+	config := &client.Config{}
+
+	if addr, ok := opts[addrOption]; ok {
+		config.Endpoints = []string{addr}
+	}
+
+	configPath := opts[cfgOption]
+	if len(config.Endpoints) == 0 && configPath == "" {
+		return nil, fmt.Errorf("invalid etcd configuration, %s or %s must be specified", cfgOption, addrOption)
+	}
+
+	c, err := connectEtcdClient(config, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ec := &etcdClient{
+		client:    c,
+		lockPaths: map[string]*lock.Mutex{},
+	}
+
+	if err := ec.checkMinVersion(15 * time.Second); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	return ec, nil
+}
+
 func init() {
 	// register etcd module for use
 	registerBackend(etcdName, etcdInstance)
@@ -120,16 +164,31 @@ type etcdClient struct {
 	session     *concurrency.Session
 	lockPathsMU lock.Mutex
 	lockPaths   map[string]*lock.Mutex
+
+	// revisions tracks the latest known revision of the etcd cluster
+	revisions revisionTracker
 }
 
 type etcdMutex struct {
-	mutex *concurrency.Mutex
+	client *client.Client
+	mutex  *concurrency.Mutex
 }
 
 func (e *etcdMutex) Unlock() error {
 	return e.mutex.Unlock(ctx.Background())
 }
 
+func (e *etcdMutex) UnlockAndDelete(key string) error {
+	_, err := e.client.Txn(ctx.Background()).
+		Then(client.OpDelete(e.mutex.Key()), client.OpDelete(key)).
+		Commit()
+	return err
+}
+
+func (e *etcdMutex) Key() string {
+	return e.mutex.Key()
+}
+
 func (e *etcdClient) renewSession() error {
 	<-e.session.Done()
 
@@ -156,7 +215,7 @@ func (e *etcdClient) renewSession() error {
 	return nil
 }
 
-func newEtcdClient(config *client.Config, cfgPath string) (BackendOperations, error) {
+func connectEtcdClient(config *client.Config, cfgPath string) (*client.Client, error) {
 	var (
 		c   *client.Client
 		err error
@@ -178,6 +237,15 @@ func newEtcdClient(config *client.Config, cfgPath string) (BackendOperations, er
 	if err != nil {
 		return nil, err
 	}
+
+	return c, nil
+}
+
+func newEtcdClient(config *client.Config, cfgPath string) (BackendOperations, error) {
+	c, err := connectEtcdClient(config, cfgPath)
+	if err != nil {
+		return nil, err
+	}
 	log.Info("Waiting for etcd client to be ready")
 	s, err := concurrency.NewSession(c)
 	if err != nil {
@@ -256,6 +324,10 @@ func (e *etcdClient) checkMinVersion(timeout time.Duration) error {
 
 func (e *etcdClient) LockPath(path string) (kvLocker, error) {
 	e.RLock()
+	if e.session == nil {
+		e.RUnlock()
+		return nil, fmt.Errorf("locking not supported by read-only client")
+	}
 	mu := concurrency.NewMutex(e.session, path)
 	e.RUnlock()
 
@@ -264,7 +336,7 @@ func (e *etcdClient) LockPath(path string) (kvLocker, error) {
 		return nil, err
 	}
 
-	return &etcdMutex{mutex: mu}, nil
+	return &etcdMutex{client: e.client, mutex: mu}, nil
 }
 
 // FIXME: Obsolete, remove
@@ -458,6 +530,7 @@ func (e *etcdClient) Watch(w *Watcher) {
 		}
 
 		lastRev := res.Header.Revision
+		e.revisions.observe(lastRev)
 
 		log.WithFields(logrus.Fields{
 			fieldRev:     lastRev,
@@ -505,7 +578,10 @@ func (e *etcdClient) Watch(w *Watcher) {
 					goto recreateWatcher
 				}
 
+				received := time.Now()
+
 				lastRev = r.Header.Revision
+				e.revisions.observe(lastRev)
 
 				if err := r.Err(); err != nil {
 					log.WithFields(logrus.Fields{
@@ -538,7 +614,7 @@ func (e *etcdClient) Watch(w *Watcher) {
 						fieldWatcher: w,
 					}).Debugf("Emiting %v event for %s=%v", event.Typ, event.Key, event.Value)
 
-					w.Events <- event
+					w.emit(etcdName, received, &e.revisions, ev.Kv.ModRevision, event)
 				}
 			}
 		}
@@ -549,9 +625,16 @@ func (e *etcdClient) Status() (string, error) {
 	eps := e.client.Endpoints()
 	var err1 error
 	for i, ep := range eps {
-		if sr, err := e.client.Status(ctx.Background(), ep); err != nil {
+		ctxTimeout, cancel := ctx.WithTimeout(ctx.Background(), statusCheckTimeout)
+		sr, err := e.client.Status(ctxTimeout, ep)
+		cancel()
+		if err != nil {
 			err1 = err
-		} else if sr.Header.MemberId == sr.Leader {
+			continue
+		}
+
+		e.revisions.observe(sr.Header.Revision)
+		if sr.Header.MemberId == sr.Leader {
 			eps[i] = fmt.Sprintf("%s - (Leader) %s", ep, sr.Version)
 		} else {
 			eps[i] = fmt.Sprintf("%s - %s", ep, sr.Version)
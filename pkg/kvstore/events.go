@@ -14,6 +14,13 @@
 
 package kvstore
 
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/cilium/cilium/pkg/metrics"
+)
+
 // EventType defines the type of watch event that occured
 type EventType int
 
@@ -93,6 +100,12 @@ func (w *Watcher) String() string {
 // Returns a watcher structure plus a channel that is closed when the initial
 // list operation has been completed
 func ListAndWatch(name, prefix string, chanSize int) *Watcher {
+	return ListAndWatchWithClient(Client(), name, prefix, chanSize)
+}
+
+// ListAndWatchWithClient is identical to ListAndWatch() but uses the
+// specified client instead of the default client
+func ListAndWatchWithClient(c BackendOperations, name, prefix string, chanSize int) *Watcher {
 	w := &Watcher{
 		name:      name,
 		prefix:    prefix,
@@ -102,7 +115,7 @@ func ListAndWatch(name, prefix string, chanSize int) *Watcher {
 
 	log.WithField(fieldWatcher, w).Debug("Starting watcher...")
 
-	go Client().Watch(w)
+	go c.Watch(w)
 
 	return w
 }
@@ -117,3 +130,39 @@ func (w *Watcher) Stop() {
 	log.WithField(fieldWatcher, w).Debug("Stopped watcher...")
 	w.stopped = true
 }
+
+// emit sends the event to the watcher and records the time the event waited
+// since it has been received from the backend. The number of revisions the
+// event lags behind the latest known revision of the kvstore at the time of
+// delivery is recorded as well.
+func (w *Watcher) emit(backend string, received time.Time, revisions *revisionTracker, revision int64, event KeyValueEvent) {
+	w.Events <- event
+	metrics.KVStoreEventsQueueDuration.
+		WithLabelValues(backend, event.Typ.String()).
+		Observe(time.Since(received).Seconds())
+
+	if lag := revisions.latest() - revision; lag >= 0 {
+		metrics.KVStoreEventsLag.WithLabelValues(backend).Observe(float64(lag))
+	}
+}
+
+// revisionTracker tracks the latest revision of the kvstore known to a
+// client. The revision is learned from the responses of the kvstore.
+type revisionTracker struct {
+	revision int64
+}
+
+// observe records a revision reported by the kvstore
+func (r *revisionTracker) observe(revision int64) {
+	for {
+		cur := atomic.LoadInt64(&r.revision)
+		if revision <= cur || atomic.CompareAndSwapInt64(&r.revision, cur, revision) {
+			return
+		}
+	}
+}
+
+// latest returns the latest known revision of the kvstore
+func (r *revisionTracker) latest() int64 {
+	return atomic.LoadInt64(&r.revision)
+}
@@ -0,0 +1,38 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type EventsSuite struct{}
+
+var _ = Suite(&EventsSuite{})
+
+func (s *EventsSuite) TestRevisionTracker(c *C) {
+	r := &revisionTracker{}
+	c.Assert(r.latest(), Equals, int64(0))
+
+	r.observe(10)
+	c.Assert(r.latest(), Equals, int64(10))
+
+	// older revisions never move the latest revision backwards
+	r.observe(5)
+	c.Assert(r.latest(), Equals, int64(10))
+
+	r.observe(12)
+	c.Assert(r.latest(), Equals, int64(12))
+}
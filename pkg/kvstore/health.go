@@ -0,0 +1,154 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultDegradedThreshold is the default duration the kvstore must
+	// be unreachable before degraded mode is entered
+	DefaultDegradedThreshold = 30 * time.Second
+
+	// statusCheckInterval is the interval in which the connectivity to
+	// the kvstore is checked
+	statusCheckInterval = 5 * time.Second
+)
+
+var (
+	// DegradedThreshold is the duration the kvstore must be unreachable
+	// before degraded mode is entered. In degraded mode, users of the
+	// kvstore are expected to rely on cached state and to fail operations
+	// which require the kvstore early. Must be set before Setup().
+	DegradedThreshold = DefaultDegradedThreshold
+
+	health = healthState{}
+)
+
+// HealthStatus is the health of the connection to the kvstore
+type HealthStatus struct {
+	// Degraded is true if the kvstore has been unreachable for longer
+	// than DegradedThreshold
+	Degraded bool
+
+	// LastSuccess is the time of the last successful kvstore interaction
+	LastSuccess time.Time
+
+	// LastError is the error of the last failed status check, nil if the
+	// last status check succeeded
+	LastError error
+}
+
+type healthState struct {
+	mutex       lock.RWMutex
+	lastSuccess time.Time
+	lastError   error
+	degraded    bool
+}
+
+func (h *healthState) success() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.lastSuccess = time.Now()
+	h.lastError = nil
+
+	if h.degraded {
+		h.degraded = false
+		metrics.KVStoreDegraded.Set(0)
+		log.Info("Connectivity to kvstore has been restored, leaving degraded mode")
+	}
+}
+
+func (h *healthState) failure(err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.lastError = err
+
+	if !h.degraded && time.Since(h.lastSuccess) > DegradedThreshold {
+		h.degraded = true
+		metrics.KVStoreDegraded.Set(1)
+		log.WithError(err).WithFields(logrus.Fields{
+			"lastSuccess": h.lastSuccess,
+			"threshold":   DegradedThreshold,
+		}).Warning("Kvstore is unreachable, entering degraded mode")
+	}
+}
+
+// IsDegraded returns true if the kvstore has been unreachable for longer than
+// DegradedThreshold
+func IsDegraded() bool {
+	health.mutex.RLock()
+	defer health.mutex.RUnlock()
+	return health.degraded
+}
+
+// GetHealthStatus returns the health of the connection to the kvstore
+func GetHealthStatus() HealthStatus {
+	health.mutex.RLock()
+	defer health.mutex.RUnlock()
+
+	return HealthStatus{
+		Degraded:    health.degraded,
+		LastSuccess: health.lastSuccess,
+		LastError:   health.lastError,
+	}
+}
+
+// observeOperation records the duration and outcome of a kvstore operation on
+// the default client. A successful operation proves connectivity to the
+// kvstore.
+func observeOperation(operation string, start time.Time, err error) {
+	outcome := metrics.LabelValueOutcomeSuccess
+	if err != nil {
+		outcome = metrics.LabelValueOutcomeFail
+	} else {
+		health.success()
+	}
+
+	metrics.KVStoreOperationsDuration.
+		WithLabelValues(operation, selectedModule, outcome).
+		Observe(time.Since(start).Seconds())
+}
+
+// startStatusCheck starts periodically checking the connectivity to the
+// kvstore of the default client
+func startStatusCheck() {
+	health.success()
+
+	kvstoreControllers.UpdateController("kvstore-status-check",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				start := time.Now()
+				_, err := Client().Status()
+				observeOperation("Status", start, err)
+				if err != nil {
+					health.failure(err)
+				}
+				return err
+			},
+			RunInterval:  statusCheckInterval,
+			NoErrorRetry: true,
+		},
+	)
+}
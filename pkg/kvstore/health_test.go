@@ -0,0 +1,49 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"fmt"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type HealthSuite struct{}
+
+var _ = Suite(&HealthSuite{})
+
+func (s *HealthSuite) TestDegradedMode(c *C) {
+	oldThreshold := DegradedThreshold
+	defer func() { DegradedThreshold = oldThreshold }()
+	DegradedThreshold = 50 * time.Millisecond
+
+	h := &healthState{}
+	h.success()
+	c.Assert(h.degraded, Equals, false)
+
+	// failures within the threshold do not enter degraded mode
+	h.failure(fmt.Errorf("unreachable"))
+	c.Assert(h.degraded, Equals, false)
+	c.Assert(h.lastError, Not(IsNil))
+
+	time.Sleep(2 * DegradedThreshold)
+	h.failure(fmt.Errorf("unreachable"))
+	c.Assert(h.degraded, Equals, true)
+
+	h.success()
+	c.Assert(h.degraded, Equals, false)
+	c.Assert(h.lastError, IsNil)
+}
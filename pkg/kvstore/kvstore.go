@@ -15,6 +15,8 @@
 package kvstore
 
 import (
+	"time"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -37,63 +39,81 @@ const (
 
 // Get returns value of key
 func Get(key string) ([]byte, error) {
+	start := time.Now()
 	v, err := Client().Get(key)
+	observeOperation("Get", start, err)
 	Trace("Get", err, logrus.Fields{fieldKey: key, fieldValue: string(v)})
 	return v, err
 }
 
 // GetPrefix returns the first key which matches the prefix
 func GetPrefix(prefix string) ([]byte, error) {
+	start := time.Now()
 	v, err := Client().GetPrefix(prefix)
+	observeOperation("GetPrefix", start, err)
 	Trace("GetPrefix", err, logrus.Fields{fieldPrefix: prefix, fieldValue: string(v)})
 	return v, err
 }
 
 // ListPrefix returns the list of keys matching the prefix
 func ListPrefix(prefix string) (KeyValuePairs, error) {
+	start := time.Now()
 	v, err := Client().ListPrefix(prefix)
+	observeOperation("ListPrefix", start, err)
 	Trace("ListPrefix", err, logrus.Fields{fieldPrefix: prefix, fieldNumEntries: len(v)})
 	return v, err
 }
 
 // CreateOnly atomically creates a key or fails if it already exists
 func CreateOnly(key string, value []byte, lease bool) error {
+	start := time.Now()
 	err := Client().CreateOnly(key, value, lease)
+	observeOperation("CreateOnly", start, err)
 	Trace("CreateOnly", err, logrus.Fields{fieldKey: key, fieldValue: string(value), fieldAttachLease: lease})
 	return err
 }
 
 // Update creates or updates a key value pair
 func Update(key string, value []byte, lease bool) error {
+	start := time.Now()
 	err := Client().Update(key, value, lease)
+	observeOperation("Update", start, err)
 	Trace("Update", err, logrus.Fields{fieldKey: key, fieldValue: string(value), fieldAttachLease: lease})
 	return err
 }
 
 // CreateIfExists creates a key with the value only if key condKey exists
 func CreateIfExists(condKey, key string, value []byte, lease bool) error {
+	start := time.Now()
 	err := Client().CreateIfExists(condKey, key, value, lease)
+	observeOperation("CreateIfExists", start, err)
 	Trace("CreateIfExists", err, logrus.Fields{fieldKey: key, fieldValue: string(value), fieldCondition: condKey, fieldAttachLease: lease})
 	return err
 }
 
 // Set sets the value of a key
 func Set(key string, value []byte) error {
+	start := time.Now()
 	err := Client().Set(key, value)
+	observeOperation("Set", start, err)
 	Trace("Set", err, logrus.Fields{fieldKey: key, fieldValue: string(value)})
 	return err
 }
 
 // Delete deletes a key
 func Delete(key string) error {
+	start := time.Now()
 	err := Client().Delete(key)
+	observeOperation("Delete", start, err)
 	Trace("Delete", err, logrus.Fields{fieldKey: key})
 	return err
 }
 
 // DeletePrefix deletes all keys matching a prefix
 func DeletePrefix(prefix string) error {
+	start := time.Now()
 	err := Client().DeletePrefix(prefix)
+	observeOperation("DeletePrefix", start, err)
 	Trace("DeletePrefix", err, logrus.Fields{fieldPrefix: prefix})
 	return err
 }
@@ -33,6 +33,15 @@ const (
 
 type kvLocker interface {
 	Unlock() error
+
+	// UnlockAndDelete releases the lock and deletes the given key.
+	// Backends supporting transactions perform both in a single
+	// transaction.
+	UnlockAndDelete(key string) error
+
+	// Key returns the backend specific key which represents the held
+	// lock. Deleting the key releases the lock.
+	Key() string
 }
 
 // getLockPath returns the lock path representation of the given path.
@@ -82,6 +91,7 @@ func (pl *pathLocks) unlock(path string) {
 type Lock struct {
 	path   string
 	kvLock kvLocker
+	info   *LockInfo
 }
 
 // LockPath locks the specified path. The key for the lock is not the path
@@ -90,9 +100,18 @@ type Lock struct {
 //
 // It is required to call Unlock() on the returned Lock to unlock
 func LockPath(path string) (l *Lock, err error) {
+	return LockPathForOperation(path, "")
+}
+
+// LockPathForOperation is identical to LockPath() but records the operation
+// for which the lock is held in the lock owner information. The owner
+// information is listed by ListLocks().
+func LockPathForOperation(path, operation string) (l *Lock, err error) {
 	kvstoreLocks.lock(path)
 
+	start := time.Now()
 	lock, err := Client().LockPath(path)
+	observeOperation("LockPath", start, err)
 	if err != nil {
 		kvstoreLocks.unlock(path)
 		Trace("Failed to lock", err, logrus.Fields{fieldKey: path})
@@ -101,7 +120,18 @@ func LockPath(path string) (l *Lock, err error) {
 	}
 
 	Trace("Successful lock", err, logrus.Fields{fieldKey: path})
-	return &Lock{kvLock: lock, path: path}, err
+
+	// Writing the owner information costs a single put per acquired
+	// lock. Locks are only taken to allocate new IDs so this is not on a
+	// fast path. The key is attached to the lease and is removed in the
+	// same operation which releases the lock.
+	info := newLockInfo(path, lock.Key(), operation)
+	if err := writeLockInfo(info); err != nil {
+		// The owner information is for diagnostics only
+		log.WithError(err).WithField(fieldKey, path).Warning("Unable to write lock owner information")
+	}
+
+	return &Lock{kvLock: lock, path: path, info: info}, nil
 }
 
 // Unlock unlocks a lock
@@ -110,8 +140,8 @@ func (l *Lock) Unlock() error {
 		return nil
 	}
 
-	// Unlock kvstore mutex first
-	err := l.kvLock.Unlock()
+	// Unlock kvstore mutex first and remove the owner information
+	err := l.kvLock.UnlockAndDelete(l.info.key())
 
 	// unlock local lock even if kvstore cannot be unlocked
 	kvstoreLocks.unlock(l.path)
@@ -0,0 +1,305 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// LocksPath is the path to where the owner information of all held
+	// locks is stored. The owner information of a lock on path P is
+	// stored in LocksPath/P/<owner>, the owner key is unique for each
+	// acquisition of the lock.
+	LocksPath = path.Join(BaseKeyPrefix, "state", "locks", "v1")
+
+	// LocksAuditPath is the path to where an audit entry is stored for
+	// each forcefully released lock
+	LocksAuditPath = path.Join(BaseKeyPrefix, "state", "locks-audit", "v1")
+
+	// MaxLockAuditEntries is the maximum number of audit entries kept in
+	// LocksAuditPath. The oldest entries are removed when the limit is
+	// exceeded.
+	MaxLockAuditEntries = 128
+
+	// LockOwnerNode is the name of the node recorded as owner of all
+	// locks acquired by this process. Defaults to the hostname.
+	LockOwnerNode = "localhost"
+
+	// ErrLockNotHeld is returned by ForceReleaseLock if no lock is held on
+	// the specified path
+	ErrLockNotHeld = errors.New("no lock is held")
+)
+
+// LockInfo is the owner information of a held lock
+type LockInfo struct {
+	// Path is the path which has been locked
+	Path string `json:"path"`
+
+	// Key is the backend specific key representing the lock
+	Key string `json:"key"`
+
+	// Node is the name of the node holding the lock
+	Node string `json:"node"`
+
+	// PID is the process ID of the process holding the lock
+	PID int `json:"pid"`
+
+	// AcquiredAt is the time the lock has been acquired
+	AcquiredAt time.Time `json:"acquired-at"`
+
+	// Operation is the operation for which the lock is held
+	Operation string `json:"operation,omitempty"`
+}
+
+// Age returns the duration the lock has been held for
+func (li *LockInfo) Age() time.Duration {
+	return time.Since(li.AcquiredAt)
+}
+
+// LockAuditEntry is the audit entry written when a lock has been released
+// forcefully
+type LockAuditEntry struct {
+	// Lock is the owner information of the released lock
+	Lock *LockInfo `json:"lock"`
+
+	// ReleasedBy is the node which released the lock
+	ReleasedBy string `json:"released-by"`
+
+	// ReleasedByPID is the process ID of the process which released the
+	// lock
+	ReleasedByPID int `json:"released-by-pid"`
+
+	// ReleasedAt is the time the lock has been released
+	ReleasedAt time.Time `json:"released-at"`
+}
+
+// key returns the key under which the audit entry is stored. The key starts
+// with the release time so that keys sort chronologically and contains the
+// releasing node and process so that keys written by different nodes at the
+// same time do not collide.
+func (e *LockAuditEntry) key() string {
+	name := fmt.Sprintf("%s-%s-%d", e.ReleasedAt.UTC().Format(time.RFC3339Nano), e.ReleasedBy, e.ReleasedByPID)
+	return path.Join(LocksAuditPath, name)
+}
+
+// ListLockAuditEntries returns all audit entries of forcefully released
+// locks, the oldest entry first
+func ListLockAuditEntries() ([]*LockAuditEntry, error) {
+	keys, entries, err := listLockAuditEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*LockAuditEntry, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, entries[key])
+	}
+
+	return result, nil
+}
+
+// listLockAuditEntries returns all audit entries by key along with the keys
+// sorted by release time, the oldest entry first
+func listLockAuditEntries() ([]string, map[string]*LockAuditEntry, error) {
+	pairs, err := ListPrefix(LocksAuditPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := make([]string, 0, len(pairs))
+	entries := make(map[string]*LockAuditEntry, len(pairs))
+	for key, value := range pairs {
+		entry := &LockAuditEntry{}
+		if err := json.Unmarshal(value, entry); err != nil {
+			log.WithError(err).WithField(fieldKey, key).Warning("Unable to decode lock audit entry")
+			continue
+		}
+		keys = append(keys, key)
+		entries[key] = entry
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return entries[keys[i]].ReleasedAt.Before(entries[keys[j]].ReleasedAt)
+	})
+
+	return keys, entries, nil
+}
+
+// writeLockAuditEntry stores an audit entry and removes the oldest entries
+// exceeding MaxLockAuditEntries
+func writeLockAuditEntry(entry *LockAuditEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := Set(entry.key(), b); err != nil {
+		return err
+	}
+
+	keys, _, err := listLockAuditEntries()
+	if err != nil {
+		log.WithError(err).Warning("Unable to list lock audit entries")
+		return nil
+	}
+
+	for i := 0; i < len(keys)-MaxLockAuditEntries; i++ {
+		if err := Delete(keys[i]); err != nil {
+			log.WithError(err).WithField(fieldKey, keys[i]).Warning("Unable to remove lock audit entry")
+		}
+	}
+
+	return nil
+}
+
+// key returns the key under which the owner information is stored. The key
+// is unique for each acquisition so that the owner information can be
+// removed without checking whether the lock has been acquired by another
+// owner in the meantime, e.g. after the lock has been released forcefully.
+func (li *LockInfo) key() string {
+	owner := fmt.Sprintf("%s-%d-%d", li.Node, li.PID, li.AcquiredAt.UnixNano())
+	return path.Join(LocksPath, li.Path, owner)
+}
+
+func newLockInfo(lockPath, key, operation string) *LockInfo {
+	return &LockInfo{
+		Path:       lockPath,
+		Key:        key,
+		Node:       LockOwnerNode,
+		PID:        os.Getpid(),
+		AcquiredAt: time.Now(),
+		Operation:  operation,
+	}
+}
+
+// writeLockInfo stores the owner information of a lock. The key is attached
+// to the lease so the information disappears together with the owner.
+func writeLockInfo(info *LockInfo) error {
+	b, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	return Update(info.key(), b, true)
+}
+
+// ListLocks returns the owner information of all locks currently held
+func ListLocks() ([]*LockInfo, error) {
+	return listLocks(LocksPath)
+}
+
+// listLocks returns the owner information of all locks stored below prefix
+func listLocks(prefix string) ([]*LockInfo, error) {
+	pairs, err := ListPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	locks := make([]*LockInfo, 0, len(pairs))
+	for key, value := range pairs {
+		info := &LockInfo{}
+		if err := json.Unmarshal(value, info); err != nil {
+			log.WithError(err).WithField(fieldKey, key).Warning("Unable to decode lock owner information")
+			continue
+		}
+		locks = append(locks, info)
+	}
+
+	return locks, nil
+}
+
+// getLockInfo returns the owner information of the lock on lockPath. If
+// multiple owners are recorded, the most recent one is returned.
+func getLockInfo(lockPath string) (*LockInfo, error) {
+	locks, err := listLocks(path.Join(LocksPath, lockPath) + "/")
+	if err != nil {
+		return nil, err
+	}
+
+	var info *LockInfo
+	for _, l := range locks {
+		// The prefix also matches locks on paths below lockPath
+		if l.Path != lockPath {
+			continue
+		}
+
+		if info == nil || l.AcquiredAt.After(info.AcquiredAt) {
+			info = l
+		}
+	}
+
+	return info, nil
+}
+
+// ForceReleaseLock releases the lock on the specified path regardless of its
+// owner. This is intended to recover from owners which hang while holding a
+// lock. An audit entry is written to LocksAuditPath. The owner information of
+// the released lock is returned.
+func ForceReleaseLock(lockPath string) (*LockInfo, error) {
+	info, err := getLockInfo(lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve lock owner information: %s", err)
+	}
+
+	if info == nil {
+		return nil, ErrLockNotHeld
+	}
+
+	if err := Delete(info.Key); err != nil {
+		return nil, fmt.Errorf("unable to delete lock key %s: %s", info.Key, err)
+	}
+
+	if err := Delete(info.key()); err != nil {
+		log.WithError(err).WithField(fieldKey, lockPath).Warning("Unable to delete lock owner information")
+	}
+
+	entry := &LockAuditEntry{
+		Lock:          info,
+		ReleasedBy:    LockOwnerNode,
+		ReleasedByPID: os.Getpid(),
+		ReleasedAt:    time.Now(),
+	}
+
+	log.WithFields(logrus.Fields{
+		fieldKey:        lockPath,
+		"owner":         info.Node,
+		"ownerPID":      info.PID,
+		"operation":     info.Operation,
+		"age":           info.Age(),
+		"releasedBy":    entry.ReleasedBy,
+		"releasedByPID": entry.ReleasedByPID,
+	}).Warning("Forcefully released lock")
+
+	if err := writeLockAuditEntry(entry); err != nil {
+		return info, fmt.Errorf("lock released but unable to write audit entry: %s", err)
+	}
+
+	return info, nil
+}
+
+func init() {
+	if h, err := os.Hostname(); err == nil {
+		LockOwnerNode = h
+	}
+}
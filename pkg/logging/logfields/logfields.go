@@ -26,6 +26,9 @@ const (
 	// NodeName is a human readable name for the node
 	NodeName = "nodeName"
 
+	// ClusterName is the name of a cluster
+	ClusterName = "clusterName"
+
 	// EndpointID is the numeric endpoint identifier
 	EndpointID = "endpointID"
 
@@ -51,6 +54,9 @@ const (
 	// the object in question
 	PolicyRevision = "policyRevision"
 
+	// EgressGatewayPolicy is the name of an egress gateway policy
+	EgressGatewayPolicy = "egressGatewayPolicy"
+
 	// PolicyID is the identifier of a L3, L4 or L7 Policy. Ideally the .NumericIdentity
 	PolicyID = "policyID"
 
@@ -63,6 +69,12 @@ const (
 	// IPAddr is an IPV4 or IPv6 address
 	IPAddr = "ipAddr"
 
+	// IPSecKey is an IPsec key used to encrypt the traffic between nodes
+	IPSecKey = "ipsecKey"
+
+	// SPI is the security parameter index of an IPsec key
+	SPI = "spi"
+
 	// V4HealthIP is an address used to contact the cilium-health endpoint
 	V4HealthIP = "v4healthIP.IPv4"
 
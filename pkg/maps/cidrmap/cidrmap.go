@@ -32,6 +32,14 @@ const (
 	MapName = "cilium_cidr_"
 )
 
+func init() {
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        MapName,
+		Prefix:      true,
+		Description: "CIDR prefixes of the L3 policy of an endpoint or of the prefilter",
+	})
+}
+
 // CIDRMap refers to an LPM trie map at 'path'.
 type CIDRMap struct {
 	path      string
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"time"
 	"unsafe"
 
 	"github.com/cilium/cilium/pkg/bpf"
@@ -87,12 +88,96 @@ type CtEntry struct {
 // GetValuePtr returns the unsafe.Pointer for s.
 func (c *CtEntry) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(c) }
 
+// String returns the attributes of the entry in the format of
+// "cilium bpf ct list"
+func (c *CtEntry) String() string {
+	return fmt.Sprintf("expires=%d rx_packets=%d rx_bytes=%d tx_packets=%d tx_bytes=%d flags=%x revnat=%d proxyport=%d src_sec_id=%d",
+		c.lifetime,
+		c.rx_packets,
+		c.rx_bytes,
+		c.tx_packets,
+		c.tx_bytes,
+		c.flags,
+		byteorder.NetworkToHost(c.revnat),
+		byteorder.NetworkToHost(c.proxy_port),
+		c.src_sec_id,
+	)
+}
+
 // CtEntryDump represents the key and value contained in the conntrack map.
 type CtEntryDump struct {
 	Key   CtKey
 	Value CtEntry
 }
 
+func init() {
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        MapName4,
+		Prefix:      true,
+		Description: "IPv4 connection tracking table of an endpoint",
+		Parser:      dumpParser4,
+	})
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        MapName6,
+		Prefix:      true,
+		Description: "IPv6 connection tracking table of an endpoint",
+		Parser:      dumpParser6,
+	})
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        MapName4Global,
+		Description: "IPv4 connection tracking table shared by all endpoints",
+		Parser:      dumpParser4,
+	})
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        MapName6Global,
+		Description: "IPv6 connection tracking table shared by all endpoints",
+		Parser:      dumpParser6,
+	})
+}
+
+// copyRaw copies the raw representation of a key or value as read from a
+// map into the structure at ptr of the given size. The fields of the CT
+// structures are unexported and thus cannot be decoded with encoding/binary.
+func copyRaw(ptr unsafe.Pointer, size uintptr, raw []byte) error {
+	if uintptr(len(raw)) != size {
+		return fmt.Errorf("size %d does not match expected size %d", len(raw), size)
+	}
+	copy((*[1 << 16]byte)(ptr)[:size:size], raw)
+	return nil
+}
+
+// dumpParser4 decodes the raw entries of the local and global IPv4 CT maps.
+// Both use the same key layout.
+func dumpParser4(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v := CtKey4Global{}, CtEntry{}
+
+	if err := copyRaw(unsafe.Pointer(&k), unsafe.Sizeof(k), key); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := copyRaw(unsafe.Pointer(&v), unsafe.Sizeof(v), value); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return k.ToHost(), &v, nil
+}
+
+// dumpParser6 decodes the raw entries of the local and global IPv6 CT maps.
+// Both use the same key layout.
+func dumpParser6(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v := CtKey6Global{}, CtEntry{}
+
+	if err := copyRaw(unsafe.Pointer(&k), unsafe.Sizeof(k), key); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := copyRaw(unsafe.Pointer(&v), unsafe.Sizeof(v), value); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return k.ToHost(), &v, nil
+}
+
 const (
 	// GCFilterByTime filters CT entries by time
 	GCFilterByTime = 1 << iota
@@ -102,6 +187,9 @@ const (
 	// GCFilterByIDsToKeep removes all CT entries that do not match by the
 	// filter.
 	GCFilterByIDsToKeep
+	// GCFilterByMatch removes all CT entries selected by the ListFilter in
+	// GCFilter.Match.
+	GCFilterByMatch
 )
 
 // GCFilterFlags is the type for the different filter flags
@@ -113,6 +201,7 @@ type GCFilter struct {
 	IP        net.IP
 	IDsToMod  policy.SecurityIDContexts
 	IDsToKeep policy.SecurityIDContexts
+	Match     *ListFilter
 	Type      GCFilterFlags
 }
 
@@ -134,6 +223,8 @@ func (f *GCFilter) TypeString() string {
 		return "security ID"
 	case GCFilterByIDsToKeep:
 		return "security ID to keep"
+	case GCFilterByMatch:
+		return "match"
 	default:
 		return "(unknown)"
 	}
@@ -143,30 +234,13 @@ func (f *GCFilter) TypeString() string {
 // to a string.
 func ToString(m *bpf.Map, mapName string) (string, error) {
 	var buffer bytes.Buffer
-	entries, err := dumpToSlice(m, mapName)
+	entries, err := ListEntries(m, mapName, &ListFilter{})
 	if err != nil {
 		return "", err
 	}
 	for _, entry := range entries {
-		if !entry.Key.ToHost().Dump(&buffer) {
-			continue
-		}
-
-		value := entry.Value
-		buffer.WriteString(
-			fmt.Sprintf(" expires=%d rx_packets=%d rx_bytes=%d tx_packets=%d tx_bytes=%d flags=%x revnat=%d proxyport=%d src_sec_id=%d\n",
-				value.lifetime,
-				value.rx_packets,
-				value.rx_bytes,
-				value.tx_packets,
-				value.tx_bytes,
-				value.flags,
-				byteorder.NetworkToHost(value.revnat),
-				byteorder.NetworkToHost(value.proxy_port),
-				value.src_sec_id,
-			),
-		)
-
+		buffer.WriteString(entry.String())
+		buffer.WriteString("\n")
 	}
 	return buffer.String(), nil
 }
@@ -224,21 +298,22 @@ func dumpToSlice(m *bpf.Map, mapType string) ([]CtEntryDump, error) {
 
 // doGC6 iterates through a CTv6 map and drops entries based on the given
 // filter.
-func doGC6(m *bpf.Map, filter *GCFilter) int {
+func doGC6(m *bpf.Map, filter *GCFilter) GCStats {
 	var (
-		action, deleted int
+		action          int
+		stats           GCStats
 		nextKey, tmpKey CtKey6Global
 	)
 
 	err := m.GetNextKey(&tmpKey, &nextKey)
 	if err != nil {
-		return 0
+		return stats
 	}
 
 	// If the filter is by ID and the IDsToMod is empty then skip GC.
 	if filter.Type&GCFilterByIDToMod != 0 {
 		if len(filter.IDsToMod) == 0 {
-			return 0
+			return stats
 		}
 	}
 
@@ -251,11 +326,15 @@ func doGC6(m *bpf.Map, filter *GCFilter) int {
 		}
 
 		entry := entryMap.(*CtEntry)
+		stats.Scanned++
 
 		// In CT entries, the source address of the conntrack entry (`saddr`) is
 		// the destination of the packet received, therefore it's the packet's
 		// destination IP
 		action = filter.doFiltering(nextKey.saddr.IP(), nextKey.sport, uint8(nextKey.nexthdr), nextKey.flags, entry)
+		if filter.Type&GCFilterByMatch != 0 && filter.Match.Matches(newEntry(&nextKey, entry, filter.Time)) {
+			action = deleteEntry
+		}
 
 		switch action {
 		case modifyEntry:
@@ -268,7 +347,7 @@ func doGC6(m *bpf.Map, filter *GCFilter) int {
 			if err != nil {
 				log.WithError(err).Errorf("Unable to delete CT entry %s", nextKey.String())
 			} else {
-				deleted++
+				stats.Deleted++
 			}
 		}
 
@@ -277,26 +356,27 @@ func doGC6(m *bpf.Map, filter *GCFilter) int {
 		}
 		nextKey = tmpKey
 	}
-	return deleted
+	return stats
 }
 
 // doGC4 iterates through a CTv4 map and drops entries based on the given
 // filter.
-func doGC4(m *bpf.Map, filter *GCFilter) int {
+func doGC4(m *bpf.Map, filter *GCFilter) GCStats {
 	var (
-		action, deleted int
+		action          int
+		stats           GCStats
 		nextKey, tmpKey CtKey4Global
 	)
 
 	err := m.GetNextKey(&tmpKey, &nextKey)
 	if err != nil {
-		return 0
+		return stats
 	}
 
 	// If the filter is by ID and the IDsToMod is empty then skip GC.
 	if filter.Type&GCFilterByIDToMod != 0 {
 		if len(filter.IDsToMod) == 0 {
-			return 0
+			return stats
 		}
 	}
 
@@ -309,11 +389,15 @@ func doGC4(m *bpf.Map, filter *GCFilter) int {
 		}
 
 		entry := entryMap.(*CtEntry)
+		stats.Scanned++
 
 		// In CT entries, the source address of the conntrack entry (`saddr`) is
 		// the destination of the packet received, therefore it's the packet's
 		// destination IP
 		action = filter.doFiltering(nextKey.saddr.IP(), nextKey.sport, uint8(nextKey.nexthdr), nextKey.flags, entry)
+		if filter.Type&GCFilterByMatch != 0 && filter.Match.Matches(newEntry(&nextKey, entry, filter.Time)) {
+			action = deleteEntry
+		}
 
 		switch action {
 		case modifyEntry:
@@ -326,7 +410,7 @@ func doGC4(m *bpf.Map, filter *GCFilter) int {
 			if err != nil {
 				log.WithError(err).Errorf("Unable to delete CT entry %s", nextKey.String())
 			} else {
-				deleted++
+				stats.Deleted++
 			}
 		}
 
@@ -335,7 +419,7 @@ func doGC4(m *bpf.Map, filter *GCFilter) int {
 		}
 		nextKey = tmpKey
 	}
-	return deleted
+	return stats
 }
 
 func (f *GCFilter) doFiltering(dstIP net.IP, dstPort uint16, nextHdr, flags uint8, entry *CtEntry) (action int) {
@@ -439,27 +523,59 @@ func (f *GCFilter) doFiltering(dstIP net.IP, dstPort uint16, nextHdr, flags uint
 	return
 }
 
+// GCStats is the result of a garbage collection run on a CT map.
+type GCStats struct {
+	// Scanned is the number of entries visited
+	Scanned int
+
+	// Deleted is the number of entries removed
+	Deleted int
+
+	// Duration is the time the garbage collection run took
+	Duration time.Duration
+}
+
+// Alive returns the number of entries remaining in the map after the garbage
+// collection run.
+func (s GCStats) Alive() int {
+	return s.Scanned - s.Deleted
+}
+
+// DeleteRatio returns the ratio of removed entries to all scanned entries, or 0
+// if no entries were scanned.
+func (s GCStats) DeleteRatio() float64 {
+	if s.Scanned == 0 {
+		return 0
+	}
+	return float64(s.Deleted) / float64(s.Scanned)
+}
+
 // GC runs garbage collection for map m with name mapName with the given filter.
-// It returns how many items were deleted from m.
-func GC(m *bpf.Map, mapName string, filter *GCFilter) int {
+// It returns the statistics of the run, including how many items were deleted
+// from m.
+func GC(m *bpf.Map, mapName string, filter *GCFilter) GCStats {
 	if filter.Type&GCFilterByTime != 0 {
 		// If LRUHashtable, no need to garbage collect as LRUHashtable cleans itself up.
 		if m.MapInfo.MapType == bpf.MapTypeLRUHash {
-			return 0
+			return GCStats{}
 		}
 		t, _ := bpf.GetMtime()
 		tsec := t / 1000000000
 		filter.Time = uint32(tsec)
 	}
 
+	var stats GCStats
+	start := time.Now()
+
 	switch mapName {
 	case MapName6, MapName6Global:
-		return doGC6(m, filter)
+		stats = doGC6(m, filter)
 	case MapName4, MapName4Global:
-		return doGC4(m, filter)
-	default:
-		return 0
+		stats = doGC4(m, filter)
 	}
+
+	stats.Duration = time.Since(start)
+	return stats
 }
 
 // Flush runs garbage collection for map m with the name mapName, deleting all
@@ -470,9 +586,9 @@ func Flush(m *bpf.Map, mapName string) int {
 
 	switch mapName {
 	case MapName6, MapName6Global:
-		return doGC6(m, filter)
+		return doGC6(m, filter).Deleted
 	case MapName4, MapName4Global:
-		return doGC4(m, filter)
+		return doGC4(m, filter).Deleted
 	default:
 		return 0
 	}
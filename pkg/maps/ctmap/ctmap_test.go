@@ -0,0 +1,99 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctmap
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/cilium/cilium/pkg/bpf"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type CTMapSuite struct {
+	prev bpf.MapBackend
+	m    *bpf.Map
+}
+
+var _ = Suite(&CTMapSuite{})
+
+func (s *CTMapSuite) SetUpTest(c *C) {
+	s.prev = bpf.SetMapBackend(bpf.NewMemoryBackend())
+	s.m = bpf.NewMap(MapName4Global,
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(CtKey4Global{})),
+		int(unsafe.Sizeof(CtEntry{})),
+		16, 0)
+	_, err := s.m.OpenOrCreate()
+	c.Assert(err, IsNil)
+}
+
+func (s *CTMapSuite) TearDownTest(c *C) {
+	c.Assert(s.m.Close(), IsNil)
+	bpf.SetMapBackend(s.prev)
+}
+
+func (s *CTMapSuite) TestGCStats(c *C) {
+	c.Assert(GCStats{}.DeleteRatio(), Equals, 0.0)
+
+	stats := GCStats{Scanned: 4, Deleted: 1}
+	c.Assert(stats.Alive(), Equals, 3)
+	c.Assert(stats.DeleteRatio(), Equals, 0.25)
+}
+
+func (s *CTMapSuite) TestGCByTime(c *C) {
+	for i := uint16(1); i <= 5; i++ {
+		key := &CtKey4Global{sport: i, nexthdr: 6}
+		entry := &CtEntry{lifetime: MaxTime - 1}
+		if i%2 == 1 {
+			// Expired
+			entry.lifetime = 0
+		}
+		c.Assert(s.m.Update(key, entry), IsNil)
+	}
+
+	stats := GC(s.m, MapName4Global, NewGCFilterBy(GCFilterByTime))
+	c.Assert(stats.Scanned, Equals, 5)
+	c.Assert(stats.Deleted, Equals, 3)
+	c.Assert(stats.Alive(), Equals, 2)
+
+	stats = GC(s.m, MapName4Global, NewGCFilterBy(GCFilterByTime))
+	c.Assert(stats.Scanned, Equals, 2)
+	c.Assert(stats.Deleted, Equals, 0)
+
+	c.Assert(Flush(s.m, MapName4Global), Equals, 2)
+	c.Assert(GC(s.m, MapName4Global, NewGCFilterBy(GCFilterByTime)).Scanned, Equals, 0)
+}
+
+func (s *CTMapSuite) TestDumpParser(c *C) {
+	key := CtKey4Global{sport: 1, dport: 2, nexthdr: 6}
+	c.Assert(s.m.Update(key.ToNetwork(), &CtEntry{lifetime: 100, rx_packets: 3}), IsNil)
+
+	model, err := bpf.GetPinnedMapModel(MapName4Global, true)
+	c.Assert(err, IsNil)
+	c.Assert(model.Description, Equals, "IPv4 connection tracking table shared by all endpoints")
+	c.Assert(model.Entries, HasLen, 1)
+	c.Assert(model.Entries[0].Key, Equals, key.String())
+	c.Assert(model.Entries[0].Value, Matches, "expires=100 rx_packets=3 .*")
+
+	_, _, err = dumpParser4(make([]byte, 4), make([]byte, unsafe.Sizeof(CtEntry{})))
+	c.Assert(err, Not(IsNil))
+}
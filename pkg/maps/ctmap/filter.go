@@ -0,0 +1,267 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctmap
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+const (
+	// DefaultLifetime is the lifetime of a CT entry after it has seen a
+	// packet. Must match CT_DEFAULT_LIFETIME in "bpf/lib/conntrack.h".
+	DefaultLifetime = 360 * time.Second
+)
+
+// Names of the flags of a CT entry
+const (
+	FlagIn         = "in"
+	FlagOut        = "out"
+	FlagRelated    = "related"
+	FlagRxClosing  = "rx-closing"
+	FlagTxClosing  = "tx-closing"
+	FlagNat46      = "nat46"
+	FlagLBLoopback = "lb-loopback"
+)
+
+// entryFlags are the bits of CtEntry.flags, see 'struct ct_entry' in
+// "bpf/lib/common.h".
+var entryFlags = []struct {
+	name string
+	bit  uint16
+}{
+	{FlagRxClosing, 1 << 0},
+	{FlagTxClosing, 1 << 1},
+	{FlagNat46, 1 << 2},
+	{FlagLBLoopback, 1 << 3},
+}
+
+// Entry is the human readable representation of a CT entry
+type Entry struct {
+	Proto            string   `json:"proto"`
+	SrcIP            net.IP   `json:"src-ip"`
+	SrcPort          uint16   `json:"src-port"`
+	DstIP            net.IP   `json:"dst-ip"`
+	DstPort          uint16   `json:"dst-port"`
+	Flags            []string `json:"flags"`
+	Expires          uint32   `json:"expires"`
+	ExpiresIn        int64    `json:"expires-in"`
+	RxPackets        uint64   `json:"rx-packets"`
+	RxBytes          uint64   `json:"rx-bytes"`
+	TxPackets        uint64   `json:"tx-packets"`
+	TxBytes          uint64   `json:"tx-bytes"`
+	RevNAT           uint16   `json:"rev-nat"`
+	ProxyPort        uint16   `json:"proxy-port"`
+	SourceSecurityID uint32   `json:"src-sec-id"`
+
+	nexthdr u8proto.U8proto
+	dump    CtEntryDump
+}
+
+// String returns the entry in the format of "cilium bpf ct list"
+func (e *Entry) String() string {
+	var buffer bytes.Buffer
+	e.dump.Key.ToHost().Dump(&buffer)
+
+	buffer.WriteString(" ")
+	buffer.WriteString(e.dump.Value.String())
+
+	return buffer.String()
+}
+
+// HasFlag returns true if the entry has the flag with the given name
+func (e *Entry) HasFlag(flag string) bool {
+	for _, f := range e.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Idle returns the time since the entry last saw a packet, derived from its
+// remaining lifetime. Entries of closing connections have a shorter lifetime
+// and thus appear to be idle for longer.
+func (e *Entry) Idle() time.Duration {
+	idle := DefaultLifetime - time.Duration(e.ExpiresIn)*time.Second
+	if idle < 0 {
+		return 0
+	}
+	return idle
+}
+
+// newEntry returns the human readable representation of the CT entry with key
+// and value. now is the current time in seconds as returned by bpf.GetMtime().
+func newEntry(key CtKey, value *CtEntry, now uint32) *Entry {
+	e := &Entry{
+		Expires:          value.lifetime,
+		ExpiresIn:        int64(value.lifetime) - int64(now),
+		RxPackets:        value.rx_packets,
+		RxBytes:          value.rx_bytes,
+		TxPackets:        value.tx_packets,
+		TxBytes:          value.tx_bytes,
+		RevNAT:           byteorder.NetworkToHost(value.revnat).(uint16),
+		ProxyPort:        byteorder.NetworkToHost(value.proxy_port).(uint16),
+		SourceSecurityID: value.src_sec_id,
+		Flags:            []string{},
+		dump:             CtEntryDump{Key: key, Value: *value},
+	}
+
+	var flags uint8
+	switch k := key.ToHost().(type) {
+	case *CtKey4Global:
+		e.nexthdr, flags = k.nexthdr, k.flags
+		e.SrcIP, e.SrcPort = k.saddr.IP(), k.sport
+		e.DstIP, e.DstPort = k.daddr.IP(), k.dport
+	case *CtKey6Global:
+		e.nexthdr, flags = k.nexthdr, k.flags
+		e.SrcIP, e.SrcPort = k.saddr.IP(), k.sport
+		e.DstIP, e.DstPort = k.daddr.IP(), k.dport
+	}
+	e.Proto = e.nexthdr.String()
+
+	if flags&TUPLE_F_IN != 0 {
+		e.Flags = append(e.Flags, FlagIn)
+	} else {
+		e.Flags = append(e.Flags, FlagOut)
+	}
+	if flags&TUPLE_F_RELATED != 0 {
+		e.Flags = append(e.Flags, FlagRelated)
+	}
+	for _, f := range entryFlags {
+		if value.flags&f.bit != 0 {
+			e.Flags = append(e.Flags, f.name)
+		}
+	}
+
+	return e
+}
+
+// ListFilter selects CT entries by their tuple, flags and idle time. Fields
+// left at their zero value match all entries.
+type ListFilter struct {
+	SrcIP   net.IP
+	DstIP   net.IP
+	SrcPort uint16
+	DstPort uint16
+	Proto   u8proto.U8proto
+
+	// Flags lists flags which must all be set on the entry, see FlagIn
+	// and following.
+	Flags []string
+
+	// MinIdle and MaxIdle bound the time since the entry last saw a
+	// packet, see Entry.Idle().
+	MinIdle time.Duration
+	MaxIdle time.Duration
+}
+
+// IsEmpty returns true if the filter matches all entries
+func (f *ListFilter) IsEmpty() bool {
+	return f.SrcIP == nil && f.DstIP == nil && f.SrcPort == 0 && f.DstPort == 0 &&
+		f.Proto == 0 && len(f.Flags) == 0 && f.MinIdle == 0 && f.MaxIdle == 0
+}
+
+// ValidateFlags returns an error if any of the flags of the filter is unknown
+func (f *ListFilter) ValidateFlags() error {
+	for _, flag := range f.Flags {
+		switch flag {
+		case FlagIn, FlagOut, FlagRelated, FlagRxClosing, FlagTxClosing, FlagNat46, FlagLBLoopback:
+		default:
+			return fmt.Errorf("unknown flag '%s'", flag)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the entry is selected by the filter
+func (f *ListFilter) Matches(e *Entry) bool {
+	switch {
+	case e.nexthdr == 0:
+		return false
+	case f.SrcIP != nil && !f.SrcIP.Equal(e.SrcIP):
+		return false
+	case f.DstIP != nil && !f.DstIP.Equal(e.DstIP):
+		return false
+	case f.SrcPort != 0 && f.SrcPort != e.SrcPort:
+		return false
+	case f.DstPort != 0 && f.DstPort != e.DstPort:
+		return false
+	case f.Proto != 0 && f.Proto != e.nexthdr:
+		return false
+	case f.MinIdle != 0 && e.Idle() < f.MinIdle:
+		return false
+	case f.MaxIdle != 0 && e.Idle() > f.MaxIdle:
+		return false
+	}
+
+	for _, flag := range f.Flags {
+		if !e.HasFlag(flag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// now returns the current time in seconds in the clock of CT entry lifetimes
+func now() uint32 {
+	t, _ := bpf.GetMtime()
+	return uint32(t / 1000000000)
+}
+
+// ListEntries returns all entries of map m with name mapName which are
+// selected by filter.
+func ListEntries(m *bpf.Map, mapName string, filter *ListFilter) ([]*Entry, error) {
+	dumps, err := dumpToSlice(m, mapName)
+	if err != nil {
+		return nil, err
+	}
+
+	t := now()
+	entries := []*Entry{}
+	for i := range dumps {
+		e := newEntry(dumps[i].Key, &dumps[i].Value, t)
+		if filter.Matches(e) {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries, nil
+}
+
+// FlushFiltered runs garbage collection for map m with the name mapName,
+// deleting all entries selected by filter. It returns how many entries were
+// deleted from m.
+func FlushFiltered(m *bpf.Map, mapName string, filter *ListFilter) int {
+	gcFilter := NewGCFilterBy(GCFilterByMatch)
+	gcFilter.Time = now()
+	gcFilter.Match = filter
+
+	switch mapName {
+	case MapName6, MapName6Global:
+		return doGC6(m, gcFilter).Deleted
+	case MapName4, MapName4Global:
+		return doGC4(m, gcFilter).Deleted
+	default:
+		return 0
+	}
+}
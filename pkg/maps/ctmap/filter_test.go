@@ -0,0 +1,104 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctmap
+
+import (
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	. "gopkg.in/check.v1"
+)
+
+// newTestKey4 returns a CT key with the ports in network byte order
+func newTestKey4(src string, sport uint16, dst string, dport uint16, proto u8proto.U8proto, flags uint8) *CtKey4Global {
+	k := &CtKey4Global{
+		sport:   byteorder.HostToNetwork(sport).(uint16),
+		dport:   byteorder.HostToNetwork(dport).(uint16),
+		nexthdr: proto,
+		flags:   flags,
+	}
+	copy(k.saddr[:], net.ParseIP(src).To4())
+	copy(k.daddr[:], net.ParseIP(dst).To4())
+	return k
+}
+
+func (s *CTMapSuite) TestEntry(c *C) {
+	k := newTestKey4("10.0.0.1", 1234, "10.0.0.2", 80, u8proto.TCP, TUPLE_F_IN)
+	e := newEntry(k, &CtEntry{lifetime: 1300, flags: 1 << 1, src_sec_id: 42}, 1000)
+
+	c.Assert(e.Proto, Equals, "TCP")
+	c.Assert(e.SrcIP.Equal(net.ParseIP("10.0.0.1")), Equals, true)
+	c.Assert(e.SrcPort, Equals, uint16(1234))
+	c.Assert(e.DstIP.Equal(net.ParseIP("10.0.0.2")), Equals, true)
+	c.Assert(e.DstPort, Equals, uint16(80))
+	c.Assert(e.Flags, DeepEquals, []string{FlagIn, FlagTxClosing})
+	c.Assert(e.ExpiresIn, Equals, int64(300))
+	c.Assert(e.Idle(), Equals, 60*time.Second)
+	c.Assert(e.String(), Equals,
+		"TCP IN 10.0.0.1:1234 -> 10.0.0.2:80  expires=1300 rx_packets=0 rx_bytes=0 tx_packets=0 tx_bytes=0 flags=2 revnat=0 proxyport=0 src_sec_id=42")
+}
+
+func (s *CTMapSuite) TestListFilter(c *C) {
+	e := newEntry(newTestKey4("10.0.0.1", 1234, "10.0.0.2", 80, u8proto.TCP, TUPLE_F_OUT),
+		&CtEntry{lifetime: 1300}, 1000)
+
+	c.Assert((&ListFilter{}).IsEmpty(), Equals, true)
+	c.Assert((&ListFilter{}).Matches(e), Equals, true)
+	c.Assert((&ListFilter{SrcIP: net.ParseIP("10.0.0.1"), DstPort: 80}).Matches(e), Equals, true)
+	c.Assert((&ListFilter{SrcIP: net.ParseIP("10.0.0.2")}).Matches(e), Equals, false)
+	c.Assert((&ListFilter{DstIP: net.ParseIP("10.0.0.2"), SrcPort: 1234}).Matches(e), Equals, true)
+	c.Assert((&ListFilter{SrcPort: 80}).Matches(e), Equals, false)
+	c.Assert((&ListFilter{Proto: u8proto.TCP}).Matches(e), Equals, true)
+	c.Assert((&ListFilter{Proto: u8proto.UDP}).Matches(e), Equals, false)
+	c.Assert((&ListFilter{Flags: []string{FlagOut}}).Matches(e), Equals, true)
+	c.Assert((&ListFilter{Flags: []string{FlagOut, FlagRelated}}).Matches(e), Equals, false)
+	c.Assert((&ListFilter{MinIdle: time.Minute}).Matches(e), Equals, true)
+	c.Assert((&ListFilter{MinIdle: 2 * time.Minute}).Matches(e), Equals, false)
+	c.Assert((&ListFilter{MaxIdle: 30 * time.Second}).Matches(e), Equals, false)
+
+	c.Assert((&ListFilter{Flags: []string{FlagIn, FlagRxClosing}}).ValidateFlags(), IsNil)
+	c.Assert((&ListFilter{Flags: []string{"established"}}).ValidateFlags(), Not(IsNil))
+}
+
+func (s *CTMapSuite) TestFlushFiltered(c *C) {
+	for i := uint16(1); i <= 4; i++ {
+		proto := u8proto.TCP
+		if i%2 == 0 {
+			proto = u8proto.UDP
+		}
+		key := newTestKey4("10.0.0.1", 1000+i, "10.0.0.2", 53, proto, TUPLE_F_OUT)
+		c.Assert(s.m.Update(key, &CtEntry{lifetime: MaxTime - 1}), IsNil)
+	}
+
+	entries, err := ListEntries(s.m, MapName4Global, &ListFilter{Proto: u8proto.UDP})
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 2)
+
+	c.Assert(FlushFiltered(s.m, MapName4Global, &ListFilter{Proto: u8proto.UDP}), Equals, 2)
+	entries, err = ListEntries(s.m, MapName4Global, &ListFilter{})
+	c.Assert(err, IsNil)
+	c.Assert(len(entries), Equals, 2)
+	for _, e := range entries {
+		c.Assert(e.Proto, Equals, "TCP")
+	}
+
+	c.Assert(FlushFiltered(s.m, MapName4Global, &ListFilter{SrcPort: 1001}), Equals, 1)
+	out, err := ToString(s.m, MapName4Global)
+	c.Assert(err, IsNil)
+	c.Assert(out, Matches, "TCP OUT 10.0.0.1:1003 -> 10.0.0.2:53  expires=.*\n")
+}
@@ -0,0 +1,262 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egressmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+)
+
+const (
+	// MapName4 is the name of the map holding the egress gateway
+	// policies of the local endpoints
+	MapName4 = "cilium_egress_v4"
+
+	// GatewayMapName4 is the name of the map holding the destinations of
+	// the egress gateway policies for which the local node is the gateway
+	GatewayMapName4 = "cilium_egress_gw_v4"
+
+	// MaxEntries is the maximum number of entries in each egress gateway
+	// map, see EGRESS_GW_MAP_SIZE in "bpf/lib/egress_gw.h"
+	MaxEntries = 16384
+
+	// addrBits is the length of an IPv4 address in bits
+	addrBits = 32
+)
+
+var (
+	// Map4 maps the IP of a local endpoint and a destination prefix to
+	// the egress IP and the gateway node of the matching policy
+	Map4 = bpf.NewMap(MapName4,
+		bpf.MapTypeLPMTrie,
+		int(unsafe.Sizeof(Key4{})),
+		int(unsafe.Sizeof(Info4{})),
+		MaxEntries, bpf.BPF_F_NO_PREALLOC).WithCache().WithNonPersistent()
+
+	// GatewayMap4 maps the destination prefixes of the policies for which
+	// the local node is the gateway to their egress IP
+	GatewayMap4 = bpf.NewMap(GatewayMapName4,
+		bpf.MapTypeLPMTrie,
+		int(unsafe.Sizeof(GatewayKey4{})),
+		int(unsafe.Sizeof(GatewayInfo4{})),
+		MaxEntries, bpf.BPF_F_NO_PREALLOC).WithCache().WithNonPersistent()
+)
+
+func init() {
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        MapName4,
+		Description: "Egress gateway policies of local endpoints indexed by endpoint IP and destination prefix",
+		Parser:      dumpParser,
+	})
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        GatewayMapName4,
+		Description: "Destination prefixes of the egress gateway policies with the local node as gateway",
+		Parser:      gatewayDumpParser,
+	})
+}
+
+// OpenMaps creates the egress gateway maps. Unlike most maps, they are
+// created by the agent as the datapath only reads them.
+func OpenMaps() error {
+	for _, m := range []*bpf.Map{Map4, GatewayMap4} {
+		if _, err := m.OpenOrCreate(); err != nil {
+			return fmt.Errorf("unable to create map %s: %s", m.Name(), err)
+		}
+	}
+	return nil
+}
+
+// prefixToIPv4 returns the address and prefix length of the IPv4 prefix
+func prefixToIPv4(prefix *net.IPNet) (addr types.IPv4, ones int) {
+	ones, _ = prefix.Mask.Size()
+	copy(addr[:], prefix.IP.Mask(prefix.Mask).To4())
+	return
+}
+
+// Key4 must match 'struct egress_key4' in "bpf/lib/egress_gw.h".
+type Key4 struct {
+	// Prefixlen covers the full source address and the prefix of the
+	// destination address
+	Prefixlen uint32
+	SAddr     types.IPv4
+	DAddr     types.IPv4
+}
+
+// NewKey4 returns the key of the policy entry of the endpoint with the IP
+// source for the destination prefix dest.
+func NewKey4(source net.IP, dest *net.IPNet) Key4 {
+	key := Key4{}
+	daddr, ones := prefixToIPv4(dest)
+	key.Prefixlen = uint32(addrBits + ones)
+	copy(key.SAddr[:], source.To4())
+	key.DAddr = daddr
+	return key
+}
+
+// GetKeyPtr returns the unsafe.Pointer for k.
+func (k *Key4) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+
+// NewValue creates a new bpf.MapValue.
+func (k *Key4) NewValue() bpf.MapValue { return &Info4{} }
+
+// GetDestination returns the destination prefix of the key
+func (k *Key4) GetDestination() *net.IPNet {
+	ones := int(k.Prefixlen) - addrBits
+	if ones < 0 {
+		ones = 0
+	}
+	return &net.IPNet{
+		IP:   k.DAddr.IP(),
+		Mask: net.CIDRMask(ones, addrBits),
+	}
+}
+
+func (k *Key4) String() string {
+	return fmt.Sprintf("%s -> %s", k.SAddr, k.GetDestination())
+}
+
+// Info4 must match 'struct egress_info4' in "bpf/lib/egress_gw.h".
+type Info4 struct {
+	EgressIP types.IPv4
+	// GatewayIP is the node IP of the gateway node or all zeroes if the
+	// local node is the gateway
+	GatewayIP types.IPv4
+}
+
+// NewInfo4 returns the policy entry translating to egressIP on the node with
+// the IP gatewayIP. gatewayIP is nil if the local node is the gateway.
+func NewInfo4(egressIP, gatewayIP net.IP) Info4 {
+	info := Info4{}
+	copy(info.EgressIP[:], egressIP.To4())
+	if gatewayIP != nil {
+		copy(info.GatewayIP[:], gatewayIP.To4())
+	}
+	return info
+}
+
+// GetValuePtr returns the unsafe.Pointer for i.
+func (i *Info4) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(i) }
+
+// IsLocalGateway returns true if the local node is the gateway
+func (i *Info4) IsLocalGateway() bool {
+	return i.GatewayIP == types.IPv4{}
+}
+
+func (i *Info4) String() string {
+	gateway := "local"
+	if !i.IsLocalGateway() {
+		gateway = i.GatewayIP.String()
+	}
+	return fmt.Sprintf("egress=%s gateway=%s", i.EgressIP, gateway)
+}
+
+// GatewayKey4 must match 'struct egress_gw_key4' in "bpf/lib/egress_gw.h".
+type GatewayKey4 struct {
+	Prefixlen uint32
+	DAddr     types.IPv4
+}
+
+// NewGatewayKey4 returns the gateway entry key of the destination prefix dest.
+func NewGatewayKey4(dest *net.IPNet) GatewayKey4 {
+	daddr, ones := prefixToIPv4(dest)
+	return GatewayKey4{
+		Prefixlen: uint32(ones),
+		DAddr:     daddr,
+	}
+}
+
+// GetKeyPtr returns the unsafe.Pointer for k.
+func (k *GatewayKey4) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+
+// NewValue creates a new bpf.MapValue.
+func (k *GatewayKey4) NewValue() bpf.MapValue { return &GatewayInfo4{} }
+
+// GetDestination returns the destination prefix of the key
+func (k *GatewayKey4) GetDestination() *net.IPNet {
+	return &net.IPNet{
+		IP:   k.DAddr.IP(),
+		Mask: net.CIDRMask(int(k.Prefixlen), addrBits),
+	}
+}
+
+func (k *GatewayKey4) String() string {
+	return k.GetDestination().String()
+}
+
+// GatewayInfo4 must match 'struct egress_gw_info4' in "bpf/lib/egress_gw.h".
+type GatewayInfo4 struct {
+	EgressIP types.IPv4
+}
+
+// GetValuePtr returns the unsafe.Pointer for i.
+func (i *GatewayInfo4) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(i) }
+
+func (i *GatewayInfo4) String() string {
+	return fmt.Sprintf("egress=%s", i.EgressIP)
+}
+
+func dumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v := Key4{}, Info4{}
+
+	if err := binary.Read(bytes.NewBuffer(key), byteorder.Native, &k); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(bytes.NewBuffer(value), byteorder.Native, &v); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return &k, &v, nil
+}
+
+func gatewayDumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v := GatewayKey4{}, GatewayInfo4{}
+
+	if err := binary.Read(bytes.NewBuffer(key), byteorder.Native, &k); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(bytes.NewBuffer(value), byteorder.Native, &v); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return &k, &v, nil
+}
+
+// Dump returns the desired entries of the map of local endpoint policies
+func Dump() (map[Key4]Info4, error) {
+	entries := map[Key4]Info4{}
+	err := Map4.DumpCache(dumpParser, func(key bpf.MapKey, value bpf.MapValue) {
+		entries[*key.(*Key4)] = *value.(*Info4)
+	})
+	return entries, err
+}
+
+// DumpGateway returns the desired entries of the map of the destinations
+// for which the local node is the gateway
+func DumpGateway() (map[GatewayKey4]GatewayInfo4, error) {
+	entries := map[GatewayKey4]GatewayInfo4{}
+	err := GatewayMap4.DumpCache(gatewayDumpParser, func(key bpf.MapKey, value bpf.MapValue) {
+		entries[*key.(*GatewayKey4)] = *value.(*GatewayInfo4)
+	})
+	return entries, err
+}
@@ -0,0 +1,403 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// affinityGCInterval is the interval in which expired session
+	// affinity entries are removed
+	affinityGCInterval = time.Minute
+)
+
+var (
+	Affinity4Map = bpf.NewMap("cilium_lb4_affinity",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(Affinity4Key{})),
+		int(unsafe.Sizeof(AffinityValue{})),
+		maxEntries, 0)
+	Affinity6Map = bpf.NewMap("cilium_lb6_affinity",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(Affinity6Key{})),
+		int(unsafe.Sizeof(AffinityValue{})),
+		maxEntries, 0)
+	AffinityMatchMap = bpf.NewMap("cilium_lb_affinity_match",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(AffinityMatchKey{})),
+		int(unsafe.Sizeof(AffinityMatchValue{})),
+		maxEntries, 0)
+
+	// affinityControllers is the controller manager for the session
+	// affinity garbage collector
+	affinityControllers = controller.NewManager()
+)
+
+// AffinityKey is the interface describing protocol independent key for the
+// session affinity maps.
+type AffinityKey interface {
+	bpf.MapKey
+
+	// Returns human readable string representation
+	String() string
+
+	// Returns the BPF map matching the key type
+	Map() *bpf.Map
+
+	// Returns the IP address of the client
+	GetClientIP() net.IP
+
+	// Returns the reverse NAT identifier of the service
+	GetRevNat() uint16
+
+	// ToNetwork converts fields to network byte order.
+	ToNetwork() AffinityKey
+}
+
+// Affinity4Key must match 'struct lb4_affinity_key' in "bpf/lib/common.h".
+type Affinity4Key struct {
+	ClientIP types.IPv4
+	RevNat   uint16
+	Pad      uint16
+}
+
+// NewAffinity4Key returns the session affinity key of the given client of
+// the service with the given reverse NAT identifier.
+func NewAffinity4Key(clientIP net.IP, revNat uint16) *Affinity4Key {
+	key := Affinity4Key{RevNat: revNat}
+	copy(key.ClientIP[:], clientIP.To4())
+	return &key
+}
+
+func (k *Affinity4Key) Map() *bpf.Map             { return Affinity4Map }
+func (k *Affinity4Key) NewValue() bpf.MapValue    { return &AffinityValue{} }
+func (k *Affinity4Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+func (k *Affinity4Key) GetClientIP() net.IP       { return k.ClientIP.IP() }
+func (k *Affinity4Key) GetRevNat() uint16         { return k.RevNat }
+
+func (k *Affinity4Key) String() string {
+	return fmt.Sprintf("%s (%d)", k.ClientIP, k.RevNat)
+}
+
+// ToNetwork converts Affinity4Key reverse NAT identifier to network byte order.
+func (k *Affinity4Key) ToNetwork() AffinityKey {
+	n := *k
+	n.RevNat = byteorder.HostToNetwork(n.RevNat).(uint16)
+	return &n
+}
+
+// Affinity6Key must match 'struct lb6_affinity_key' in "bpf/lib/common.h".
+type Affinity6Key struct {
+	ClientIP types.IPv6
+	RevNat   uint16
+	Pad      uint16
+}
+
+// NewAffinity6Key returns the session affinity key of the given client of
+// the service with the given reverse NAT identifier.
+func NewAffinity6Key(clientIP net.IP, revNat uint16) *Affinity6Key {
+	key := Affinity6Key{RevNat: revNat}
+	copy(key.ClientIP[:], clientIP.To16())
+	return &key
+}
+
+func (k *Affinity6Key) Map() *bpf.Map             { return Affinity6Map }
+func (k *Affinity6Key) NewValue() bpf.MapValue    { return &AffinityValue{} }
+func (k *Affinity6Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+func (k *Affinity6Key) GetClientIP() net.IP       { return k.ClientIP.IP() }
+func (k *Affinity6Key) GetRevNat() uint16         { return k.RevNat }
+
+func (k *Affinity6Key) String() string {
+	return fmt.Sprintf("%s (%d)", k.ClientIP, k.RevNat)
+}
+
+// ToNetwork converts Affinity6Key reverse NAT identifier to network byte order.
+func (k *Affinity6Key) ToNetwork() AffinityKey {
+	n := *k
+	n.RevNat = byteorder.HostToNetwork(n.RevNat).(uint16)
+	return &n
+}
+
+// AffinityValue must match 'struct lb_affinity_val' in "bpf/lib/common.h".
+type AffinityValue struct {
+	// Time of the last packet of the client in seconds, as returned by
+	// bpf_ktime_get_sec()
+	LastUsed uint32
+
+	// Backend index the client is sent to
+	Slave uint16
+	Pad   uint16
+}
+
+func (v *AffinityValue) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(v) }
+
+func (v *AffinityValue) String() string {
+	return fmt.Sprintf("backend %d, last used %d", v.Slave, v.LastUsed)
+}
+
+// AffinityMatchKey must match 'struct lb_affinity_match_key' in
+// "bpf/lib/common.h".
+type AffinityMatchKey struct {
+	RevNat uint16
+	Pad    uint16
+}
+
+func (k *AffinityMatchKey) NewValue() bpf.MapValue    { return &AffinityMatchValue{} }
+func (k *AffinityMatchKey) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+
+// ToNetwork converts AffinityMatchKey reverse NAT identifier to network byte
+// order.
+func (k *AffinityMatchKey) ToNetwork() *AffinityMatchKey {
+	n := *k
+	n.RevNat = byteorder.HostToNetwork(n.RevNat).(uint16)
+	return &n
+}
+
+// AffinityMatchValue must match 'struct lb_affinity_match' in
+// "bpf/lib/common.h".
+type AffinityMatchValue struct {
+	// Session affinity timeout in seconds
+	Timeout uint32
+}
+
+func (v *AffinityMatchValue) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(v) }
+
+// AffinityEntry is a session affinity entry of a client to a backend of a
+// service.
+type AffinityEntry struct {
+	Key   AffinityKey
+	Value AffinityValue
+}
+
+func Affinity4DumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	keyBuf := bytes.NewBuffer(key)
+	valueBuf := bytes.NewBuffer(value)
+	affKey := Affinity4Key{}
+	affVal := AffinityValue{}
+
+	if err := binary.Read(keyBuf, byteorder.Native, &affKey); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(valueBuf, byteorder.Native, &affVal); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return affKey.ToNetwork(), &affVal, nil
+}
+
+func Affinity6DumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	keyBuf := bytes.NewBuffer(key)
+	valueBuf := bytes.NewBuffer(value)
+	affKey := Affinity6Key{}
+	affVal := AffinityValue{}
+
+	if err := binary.Read(keyBuf, byteorder.Native, &affKey); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(valueBuf, byteorder.Native, &affVal); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return affKey.ToNetwork(), &affVal, nil
+}
+
+func AffinityMatchDumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	keyBuf := bytes.NewBuffer(key)
+	valueBuf := bytes.NewBuffer(value)
+	matchKey := AffinityMatchKey{}
+	matchVal := AffinityMatchValue{}
+
+	if err := binary.Read(keyBuf, byteorder.Native, &matchKey); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(valueBuf, byteorder.Native, &matchVal); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return matchKey.ToNetwork(), &matchVal, nil
+}
+
+// UpdateAffinityMatch enables ClientIP session affinity with the given
+// timeout in seconds for the service with the given reverse NAT identifier.
+func UpdateAffinityMatch(revNat uint16, timeout uint32) error {
+	log.WithFields(logrus.Fields{
+		logfields.ServiceID: revNat,
+		"timeout":           timeout,
+	}).Debug("enabling session affinity for service")
+
+	if _, err := AffinityMatchMap.OpenOrCreate(); err != nil {
+		return err
+	}
+
+	key := AffinityMatchKey{RevNat: revNat}
+	return AffinityMatchMap.Update(key.ToNetwork(), &AffinityMatchValue{Timeout: timeout})
+}
+
+// DeleteAffinityMatch disables ClientIP session affinity for the service with
+// the given reverse NAT identifier. The affinity entries of the service are
+// removed by the next garbage collection.
+func DeleteAffinityMatch(revNat uint16) {
+	key := AffinityMatchKey{RevNat: revNat}
+	// Ignore if entry is not found, only services with session affinity
+	// have one.
+	AffinityMatchMap.Delete(key.ToNetwork())
+}
+
+// DumpAffinityMatch returns the session affinity timeout of all services
+// with ClientIP session affinity, indexed by reverse NAT identifier.
+func DumpAffinityMatch() (map[uint16]uint32, error) {
+	timeouts := map[uint16]uint32{}
+	err := AffinityMatchMap.Dump(AffinityMatchDumpParser, func(key bpf.MapKey, value bpf.MapValue) {
+		timeouts[key.(*AffinityMatchKey).RevNat] = value.(*AffinityMatchValue).Timeout
+	})
+	return timeouts, err
+}
+
+// DumpAffinity returns all session affinity entries of the given session
+// affinity map.
+func DumpAffinity(m *bpf.Map) ([]AffinityEntry, error) {
+	parser := Affinity4DumpParser
+	if m == Affinity6Map {
+		parser = Affinity6DumpParser
+	}
+
+	entries := []AffinityEntry{}
+	err := m.Dump(parser, func(key bpf.MapKey, value bpf.MapValue) {
+		entries = append(entries, AffinityEntry{
+			Key:   key.(AffinityKey),
+			Value: *value.(*AffinityValue),
+		})
+	})
+	return entries, err
+}
+
+// RemapAffinity updates the backend index of the session affinity entries of
+// the service with the given reverse NAT identifier after its backends have
+// changed. slaves maps the previous backend index to the new one. Entries of
+// backends which were removed are deleted.
+func RemapAffinity(m *bpf.Map, revNat uint16, slaves map[uint16]uint16) error {
+	entries, err := DumpAffinity(m)
+	if err != nil {
+		return err
+	}
+
+	// The map is modified outside of Dump() as Dump() holds the map lock.
+	for _, entry := range entries {
+		if entry.Key.GetRevNat() != revNat {
+			continue
+		}
+
+		slave, ok := slaves[entry.Value.Slave]
+		switch {
+		case !ok:
+			m.Delete(entry.Key.ToNetwork())
+		case slave != entry.Value.Slave:
+			entry.Value.Slave = slave
+			if err := m.Update(entry.Key.ToNetwork(), &entry.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// gcAffinity removes the entries of the given session affinity map which
+// have expired or belong to a service without session affinity. now is the
+// current time in seconds as returned by bpf_ktime_get_sec(). It returns the
+// number of deleted entries.
+func gcAffinity(m *bpf.Map, timeouts map[uint16]uint32, now uint32) int {
+	entries, err := DumpAffinity(m)
+	if err != nil {
+		return 0
+	}
+
+	deleted := 0
+	for _, entry := range entries {
+		timeout, ok := timeouts[entry.Key.GetRevNat()]
+		if ok && !affinityExpired(&entry.Value, timeout, now) {
+			continue
+		}
+
+		if err := m.Delete(entry.Key.ToNetwork()); err != nil {
+			log.WithError(err).WithField(logfields.BPFMapKey, entry.Key).Warn("Unable to delete session affinity entry")
+		} else {
+			deleted++
+		}
+	}
+
+	return deleted
+}
+
+// affinityExpired returns true if the given session affinity entry has not
+// been used within the timeout.
+func affinityExpired(v *AffinityValue, timeout, now uint32) bool {
+	return v.LastUsed+timeout < now
+}
+
+// GCAffinity removes all session affinity entries which have expired or
+// belong to a service without session affinity. It returns the number of
+// deleted entries.
+func GCAffinity() (int, error) {
+	t, err := bpf.GetMtime()
+	if err != nil {
+		return 0, err
+	}
+	now := uint32(t / 1000000000)
+
+	timeouts, err := DumpAffinityMatch()
+	if err != nil {
+		return 0, err
+	}
+
+	return gcAffinity(Affinity4Map, timeouts, now) + gcAffinity(Affinity6Map, timeouts, now), nil
+}
+
+// StartAffinityGC starts a controller which periodically removes expired
+// session affinity entries.
+func StartAffinityGC() {
+	affinityControllers.UpdateController("lb-affinity-gc",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				deleted, err := GCAffinity()
+				if err != nil {
+					return err
+				}
+				if deleted > 0 {
+					log.WithField("deleted", deleted).Debug("Removed expired session affinity entries")
+				}
+				return nil
+			},
+			RunInterval: affinityGCInterval,
+		},
+	)
+}
@@ -0,0 +1,51 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"net"
+	"unsafe"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *LBMapSuite) TestAffinityKeySize(c *C) {
+	// Must match the size of the structs in bpf/lib/common.h
+	c.Assert(unsafe.Sizeof(Affinity4Key{}), Equals, uintptr(8))
+	c.Assert(unsafe.Sizeof(Affinity6Key{}), Equals, uintptr(20))
+	c.Assert(unsafe.Sizeof(AffinityValue{}), Equals, uintptr(8))
+	c.Assert(unsafe.Sizeof(AffinityMatchKey{}), Equals, uintptr(4))
+}
+
+func (s *LBMapSuite) TestAffinityKey(c *C) {
+	k4 := NewAffinity4Key(net.ParseIP("10.0.0.1"), 5)
+	c.Assert(k4.GetClientIP().Equal(net.ParseIP("10.0.0.1")), Equals, true)
+	c.Assert(k4.GetRevNat(), Equals, uint16(5))
+	c.Assert(k4.ToNetwork().GetRevNat(), Equals, byteorder.HostToNetwork(uint16(5)).(uint16))
+	c.Assert(k4.String(), Equals, "10.0.0.1 (5)")
+
+	k6 := NewAffinity6Key(net.ParseIP("f00d::1"), 5)
+	c.Assert(k6.GetClientIP().Equal(net.ParseIP("f00d::1")), Equals, true)
+	c.Assert(k6.ToNetwork().GetRevNat(), Equals, byteorder.HostToNetwork(uint16(5)).(uint16))
+}
+
+func (s *LBMapSuite) TestAffinityExpired(c *C) {
+	v := &AffinityValue{LastUsed: 100, Slave: 1}
+	c.Assert(affinityExpired(v, 60, 150), Equals, false)
+	c.Assert(affinityExpired(v, 60, 160), Equals, false)
+	c.Assert(affinityExpired(v, 60, 161), Equals, true)
+}
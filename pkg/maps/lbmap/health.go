@@ -0,0 +1,137 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/common/types"
+)
+
+// healthyWeights returns a copy of weights with the weight of all backends
+// which are not healthy set to 0. If none of the healthy backends is
+// weighted, they get a weight of 1 so that the unhealthy backends are still
+// excluded. If no backend is healthy, the weights are returned unchanged as
+// selecting an unhealthy backend is preferred over dropping all new flows.
+func healthyWeights(weights []uint16, healthy []bool) []uint16 {
+	result := make([]uint16, len(weights))
+	copy(result, weights)
+
+	nHealthy := 0
+	nWeighted := 0
+	for i := range weights {
+		if healthy[i] {
+			nHealthy++
+			if weights[i] != 0 {
+				nWeighted++
+			}
+		}
+	}
+
+	if nHealthy == 0 || nHealthy == len(weights) {
+		return result
+	}
+
+	for i := range result {
+		switch {
+		case !healthy[i]:
+			result[i] = 0
+		case nWeighted == 0:
+			result[i] = 1
+		}
+	}
+	return result
+}
+
+// excludedBackends returns whether each backend must be excluded from the
+// selection for new flows because it is not healthy. As in healthyWeights,
+// no backend is excluded if none of them is healthy.
+func excludedBackends(healthy []bool) []bool {
+	excluded := make([]bool, len(healthy))
+	for _, h := range healthy {
+		if h {
+			for i := range healthy {
+				excluded[i] = !healthy[i]
+			}
+			return excluded
+		}
+	}
+	return excluded
+}
+
+// UpdateBackendHealth marks the backend entries of the service fe which are
+// not healthy, or otherwise not selectable such as remote backends of a
+// topology aware service, with ServiceFlagExcluded so that the datapath does
+// not select them for new flows. The weighted round robin sequence and, if the
+// algorithm in opts is LBAlgorithmMaglev, the Maglev lookup table are
+// updated accordingly. healthy must be in the same order as besValues. The
+// flags of besValues are updated in place. The backend entries keep their
+// weights, so a service re-added with AddSVC2BPFMap selects all backends
+// again.
+func UpdateBackendHealth(fe ServiceKey, besValues []ServiceValue, healthy []bool, opts types.LBSVCOptions) error {
+	if len(besValues) != len(healthy) {
+		return fmt.Errorf("number of health states does not match number of backends")
+	}
+
+	excluded := excludedBackends(healthy)
+	for i, be := range besValues {
+		flags := be.GetFlags() &^ ServiceFlagExcluded
+		if excluded[i] {
+			flags |= ServiceFlagExcluded
+		}
+		be.SetFlags(flags)
+
+		fe.SetBackend(i + 1)
+		if err := UpdateService(fe, be); err != nil {
+			return fmt.Errorf("unable to update service %+v with the value %+v: %s", fe, be, err)
+		}
+	}
+
+	weights := make([]uint16, 0, len(besValues))
+	for _, be := range besValues {
+		weights = append(weights, be.GetWeight())
+	}
+	weights = healthyWeights(weights, healthy)
+
+	nNonZeroWeights := 0
+	for _, w := range weights {
+		if w != 0 {
+			nNonZeroWeights++
+		}
+	}
+
+	fe.SetBackend(0)
+	master, err := LookupService(fe)
+	if err != nil {
+		return fmt.Errorf("unable to lookup service %s: %s", fe.String(), err)
+	}
+	master.SetWeight(uint16(nNonZeroWeights))
+	if err := UpdateService(fe, master); err != nil {
+		return fmt.Errorf("unable to update service %+v with the value %+v: %s", fe, master, err)
+	}
+
+	if opts.Algorithm == types.LBAlgorithmMaglev && len(besValues) > 0 {
+		if err := updateMaglevTable(fe, besValues, weights); err != nil {
+			return err
+		}
+	}
+
+	// UpdateWrrSeq normalizes the weights in place so it must come last.
+	if err := UpdateWrrSeq(fe, weights); err != nil {
+		return fmt.Errorf("unable to update service weights for %s with value %+v: %s", fe.String(), weights, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"net"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *LBMapSuite) TestHealthyWeights(c *C) {
+	// Weighted backends keep their weight unless unhealthy
+	weights := []uint16{2, 3, 4}
+	c.Assert(healthyWeights(weights, []bool{true, false, true}), DeepEquals, []uint16{2, 0, 4})
+	c.Assert(weights, DeepEquals, []uint16{2, 3, 4})
+
+	// Unweighted backends must be weighted to exclude the unhealthy ones
+	c.Assert(healthyWeights([]uint16{0, 0, 0}, []bool{true, false, true}), DeepEquals, []uint16{1, 0, 1})
+
+	// A healthy backend with weight 0 stays excluded if others are weighted
+	c.Assert(healthyWeights([]uint16{0, 0, 5}, []bool{true, false, true}), DeepEquals, []uint16{0, 0, 5})
+
+	// All healthy or all unhealthy leaves the weights unchanged
+	c.Assert(healthyWeights([]uint16{0, 0}, []bool{true, true}), DeepEquals, []uint16{0, 0})
+	c.Assert(healthyWeights([]uint16{1, 2}, []bool{false, false}), DeepEquals, []uint16{1, 2})
+}
+
+func (s *LBMapSuite) TestExcludedBackends(c *C) {
+	c.Assert(excludedBackends([]bool{true, false, true}), DeepEquals, []bool{false, true, false})
+	c.Assert(excludedBackends([]bool{true, true}), DeepEquals, []bool{false, false})
+	c.Assert(excludedBackends([]bool{false, false}), DeepEquals, []bool{false, false})
+}
+
+// withServiceMaps runs fn with the IPv4 service maps backed by an in-memory
+// map backend.
+func withServiceMaps(c *C, fn func()) {
+	prev := bpf.SetMapBackend(bpf.NewMemoryBackend())
+	defer bpf.SetMapBackend(prev)
+
+	for _, m := range []*bpf.Map{Service4Map, RRSeq4Map, Maglev4Map, AffinityMatchMap} {
+		_, err := m.OpenOrCreate()
+		c.Assert(err, IsNil)
+		defer m.Close()
+	}
+
+	fn()
+}
+
+// backendFlags returns the flags of the backend entries of the service fe in
+// the service map.
+func backendFlags(c *C, fe ServiceKey, n int) []uint16 {
+	flags := make([]uint16, n)
+	for i := range flags {
+		fe.SetBackend(i + 1)
+		be, err := LookupService(fe)
+		c.Assert(err, IsNil)
+		flags[i] = be.GetFlags()
+	}
+	fe.SetBackend(0)
+	return flags
+}
+
+func (s *LBMapSuite) TestUpdateBackendHealth(c *C) {
+	withServiceMaps(c, func() {
+		fe := NewService4Key(net.ParseIP("10.96.0.1"), 80, 0)
+		besValues := []ServiceValue{
+			NewService4Value(0, net.ParseIP("10.0.0.1"), 8080, 1, 0),
+			NewService4Value(0, net.ParseIP("10.0.0.2"), 8080, 1, 0),
+			NewService4Value(0, net.ParseIP("10.0.0.3"), 8080, 1, 0),
+		}
+		besValues[2].SetFlags(ServiceFlagDraining)
+		opts := types.LBSVCOptions{Algorithm: types.LBAlgorithmHash}
+
+		c.Assert(AddSVC2BPFMap(fe, besValues, false, 1, opts), IsNil)
+		c.Assert(backendFlags(c, fe, 3), DeepEquals, []uint16{0, 0, ServiceFlagDraining})
+
+		// The unhealthy backend is excluded in the service map read by
+		// the datapath, the draining backend keeps its flag
+		c.Assert(UpdateBackendHealth(fe, besValues, []bool{true, false, false}, opts), IsNil)
+		c.Assert(backendFlags(c, fe, 3), DeepEquals,
+			[]uint16{0, ServiceFlagExcluded, ServiceFlagDraining | ServiceFlagExcluded})
+
+		master, err := LookupService(fe)
+		c.Assert(err, IsNil)
+		c.Assert(master.GetCount(), Equals, 3)
+
+		// Recovered backends are selectable again
+		c.Assert(UpdateBackendHealth(fe, besValues, []bool{true, true, false}, opts), IsNil)
+		c.Assert(backendFlags(c, fe, 3), DeepEquals,
+			[]uint16{0, 0, ServiceFlagDraining | ServiceFlagExcluded})
+
+		// If no backend is healthy, none of them is excluded
+		c.Assert(UpdateBackendHealth(fe, besValues, []bool{false, false, false}, opts), IsNil)
+		c.Assert(backendFlags(c, fe, 3), DeepEquals, []uint16{0, 0, ServiceFlagDraining})
+	})
+}
@@ -31,16 +31,21 @@ var (
 		bpf.MapTypeHash,
 		int(unsafe.Sizeof(Service4Key{})),
 		int(unsafe.Sizeof(Service4Value{})),
-		maxEntries, 0)
+		maxEntries, 0).WithCache()
 	RevNat4Map = bpf.NewMap("cilium_lb4_reverse_nat",
 		bpf.MapTypeHash,
 		int(unsafe.Sizeof(RevNat4Key{})),
 		int(unsafe.Sizeof(RevNat4Value{})),
-		maxEntries, 0)
+		maxEntries, 0).WithCache()
 	RRSeq4Map = bpf.NewMap("cilium_lb4_rr_seq",
 		bpf.MapTypeHash,
 		int(unsafe.Sizeof(Service4Key{})),
 		int(unsafe.Sizeof(RRSeqValue{})),
+		maxFrontEnds, 0).WithCache()
+	Maglev4Map = bpf.NewMap("cilium_lb4_maglev",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(Service4Key{})),
+		int(unsafe.Sizeof(MaglevValue{})),
 		maxFrontEnds, 0)
 )
 
@@ -54,6 +59,8 @@ type Service4Key struct {
 func (k Service4Key) IsIPv6() bool               { return false }
 func (k Service4Key) Map() *bpf.Map              { return Service4Map }
 func (k Service4Key) RRMap() *bpf.Map            { return RRSeq4Map }
+func (k Service4Key) MaglevMap() *bpf.Map        { return Maglev4Map }
+func (k Service4Key) AffinityMap() *bpf.Map      { return Affinity4Map }
 func (k Service4Key) NewValue() bpf.MapValue     { return &Service4Value{} }
 func (k *Service4Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
 func (k *Service4Key) GetPort() uint16           { return k.Port }
@@ -127,6 +134,8 @@ func (s *Service4Value) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(s)
 func (s *Service4Value) SetPort(port uint16)         { s.Port = port }
 func (s *Service4Value) SetCount(count int)          { s.Count = uint16(count) }
 func (s *Service4Value) GetCount() int               { return int(s.Count) }
+func (s *Service4Value) SetFlags(flags uint16)       { s.Count = flags }
+func (s *Service4Value) GetFlags() uint16            { return s.Count }
 func (s *Service4Value) SetRevNat(id int)            { s.RevNat = uint16(id) }
 func (s *Service4Value) SetWeight(weight uint16)     { s.Weight = weight }
 func (s *Service4Value) GetWeight() uint16           { return s.Weight }
@@ -200,6 +209,23 @@ func Service4RRSeqDumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue
 	return svcKey.ToNetwork(), &svcVal, nil
 }
 
+func Service4MaglevDumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	keyBuf := bytes.NewBuffer(key)
+	valueBuf := bytes.NewBuffer(value)
+	svcKey := Service4Key{}
+	svcVal := MaglevValue{}
+
+	if err := binary.Read(keyBuf, byteorder.Native, &svcKey); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(valueBuf, byteorder.Native, &svcVal); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return svcKey.ToNetwork(), &svcVal, nil
+}
+
 type RevNat4Key struct {
 	Key uint16
 }
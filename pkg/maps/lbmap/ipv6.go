@@ -31,16 +31,21 @@ var (
 		bpf.MapTypeHash,
 		int(unsafe.Sizeof(Service6Key{})),
 		int(unsafe.Sizeof(Service6Value{})),
-		maxEntries, 0)
+		maxEntries, 0).WithCache()
 	RevNat6Map = bpf.NewMap("cilium_lb6_reverse_nat",
 		bpf.MapTypeHash,
 		int(unsafe.Sizeof(RevNat6Key{})),
 		int(unsafe.Sizeof(RevNat6Value{})),
-		maxEntries, 0)
+		maxEntries, 0).WithCache()
 	RRSeq6Map = bpf.NewMap("cilium_lb6_rr_seq",
 		bpf.MapTypeHash,
 		int(unsafe.Sizeof(Service6Key{})),
 		int(unsafe.Sizeof(RRSeqValue{})),
+		maxFrontEnds, 0).WithCache()
+	Maglev6Map = bpf.NewMap("cilium_lb6_maglev",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(Service6Key{})),
+		int(unsafe.Sizeof(MaglevValue{})),
 		maxFrontEnds, 0)
 )
 
@@ -65,6 +70,8 @@ func NewService6Key(ip net.IP, port uint16, slave uint16) *Service6Key {
 func (k Service6Key) IsIPv6() bool               { return true }
 func (k Service6Key) Map() *bpf.Map              { return Service6Map }
 func (k Service6Key) RRMap() *bpf.Map            { return RRSeq6Map }
+func (k Service6Key) MaglevMap() *bpf.Map        { return Maglev6Map }
+func (k Service6Key) AffinityMap() *bpf.Map      { return Affinity6Map }
 func (k Service6Key) NewValue() bpf.MapValue     { return &Service6Value{} }
 func (k *Service6Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
 func (k *Service6Key) GetPort() uint16           { return k.Port }
@@ -123,6 +130,8 @@ func (s *Service6Value) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(s)
 func (s *Service6Value) SetPort(port uint16)         { s.Port = port }
 func (s *Service6Value) SetCount(count int)          { s.Count = uint16(count) }
 func (s *Service6Value) GetCount() int               { return int(s.Count) }
+func (s *Service6Value) SetFlags(flags uint16)       { s.Count = flags }
+func (s *Service6Value) GetFlags() uint16            { return s.Count }
 func (s *Service6Value) SetRevNat(id int)            { s.RevNat = uint16(id) }
 func (s *Service6Value) RevNatKey() RevNatKey        { return &RevNat6Key{s.RevNat} }
 func (s *Service6Value) SetWeight(weight uint16)     { s.Weight = weight }
@@ -193,6 +202,23 @@ func Service6RRSeqDumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue
 	return svcKey.ToNetwork(), svcVal, nil
 }
 
+func Service6MaglevDumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	keyBuf := bytes.NewBuffer(key)
+	valueBuf := bytes.NewBuffer(value)
+	svcKey := Service6Key{}
+	svcVal := MaglevValue{}
+
+	if err := binary.Read(keyBuf, byteorder.Native, &svcKey); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(valueBuf, byteorder.Native, &svcVal); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return svcKey.ToNetwork(), &svcVal, nil
+}
+
 type RevNat6Key struct {
 	Key uint16
 }
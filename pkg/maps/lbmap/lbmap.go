@@ -35,8 +35,45 @@ const (
 	maxFrontEnds = 256
 	// MaxSeq is used by daemon for generating bpf define LB_RR_MAX_SEQ.
 	MaxSeq = 31
+
+	// ServiceFlagDraining marks a backend which is not selected for new
+	// connections, must match LB_SLAVE_DRAINING in "bpf/lib/common.h".
+	ServiceFlagDraining = 1
+
+	// ServiceFlagExcluded marks a backend which is excluded from the
+	// selection for new connections, e.g. because it is failing its health
+	// check, must match LB_SLAVE_EXCLUDED in "bpf/lib/common.h".
+	ServiceFlagExcluded = 2
 )
 
+func init() {
+	for _, m := range []struct {
+		m           *bpf.Map
+		description string
+		parser      bpf.DumpParser
+	}{
+		{Service4Map, "IPv4 service frontends and their backends", Service4DumpParser},
+		{Service6Map, "IPv6 service frontends and their backends", Service6DumpParser},
+		{RevNat4Map, "IPv4 reverse NAT of service replies", RevNat4DumpParser},
+		{RevNat6Map, "IPv6 reverse NAT of service replies", RevNat6DumpParser},
+		{RRSeq4Map, "Weighted round robin sequences of IPv4 services", Service4RRSeqDumpParser},
+		{RRSeq6Map, "Weighted round robin sequences of IPv6 services", Service6RRSeqDumpParser},
+		{Maglev4Map, "Maglev lookup tables of IPv4 services", Service4MaglevDumpParser},
+		{Maglev6Map, "Maglev lookup tables of IPv6 services", Service6MaglevDumpParser},
+		{Affinity4Map, "Session affinity of IPv4 clients to backends", Affinity4DumpParser},
+		{Affinity6Map, "Session affinity of IPv6 clients to backends", Affinity6DumpParser},
+		{AffinityMatchMap, "Session affinity timeout per service", AffinityMatchDumpParser},
+		{Stats4Map, "Traffic statistics of IPv4 service backends", Stats4DumpParser},
+		{Stats6Map, "Traffic statistics of IPv6 service backends", Stats6DumpParser},
+	} {
+		bpf.RegisterMap(bpf.MapDescription{
+			Name:        m.m.Name(),
+			Description: m.description,
+			Parser:      m.parser,
+		})
+	}
+}
+
 // ServiceKey is the interface describing protocol independent key for services map.
 type ServiceKey interface {
 	bpf.MapKey
@@ -53,6 +90,12 @@ type ServiceKey interface {
 	// Returns the BPF Weighted Round Robin map matching the key type
 	RRMap() *bpf.Map
 
+	// Returns the BPF Maglev lookup table map matching the key type
+	MaglevMap() *bpf.Map
+
+	// Returns the BPF session affinity map matching the key type
+	AffinityMap() *bpf.Map
+
 	// Returns a RevNatValue matching a ServiceKey
 	RevNatValue() RevNatValue
 
@@ -88,6 +131,12 @@ type ServiceValue interface {
 	// Get the number of backends
 	GetCount() int
 
+	// Set the flags of a backend, stored in place of the count
+	SetFlags(uint16)
+
+	// Get the flags of a backend
+	GetFlags() uint16
+
 	// Set address to map to (left blank for master)
 	SetAddress(net.IP) error
 
@@ -141,6 +190,7 @@ func DeleteService(key ServiceKey) error {
 	if err != nil {
 		return err
 	}
+	DeleteMaglevTable(key)
 	return LookupAndDeleteServiceWeights(key)
 }
 
@@ -324,8 +374,11 @@ func UpdateWrrSeq(fe ServiceKey, weights []uint16) error {
 	return UpdateServiceWeights(fe, svcRRSeq)
 }
 
-// AddSVC2BPFMap adds the given bpf service to the bpf maps.
-func AddSVC2BPFMap(fe ServiceKey, besValues []ServiceValue, addRevNAT bool, revNATID int) error {
+// AddSVC2BPFMap adds the given bpf service to the bpf maps. If the algorithm
+// in opts is LBAlgorithmMaglev, a Maglev lookup table is generated for the
+// backends. If opts enable session affinity, it is enabled for revNATID.
+func AddSVC2BPFMap(fe ServiceKey, besValues []ServiceValue, addRevNAT bool, revNATID int,
+	opts types.LBSVCOptions) error {
 	var err error
 	var weights []uint16
 	// Put all the backend services first
@@ -375,6 +428,24 @@ func AddSVC2BPFMap(fe ServiceKey, besValues []ServiceValue, addRevNAT bool, revN
 		return fmt.Errorf("unable to update service weights for %s with value %+v: %s", fe.String(), weights, err)
 	}
 
+	if opts.Algorithm == types.LBAlgorithmMaglev && len(besValues) > 0 {
+		err = UpdateMaglevTable(fe, besValues)
+		if err != nil {
+			return err
+		}
+	} else {
+		DeleteMaglevTable(fe)
+	}
+
+	if opts.SessionAffinity() {
+		err = UpdateAffinityMatch(uint16(revNATID), opts.SessionAffinityTimeout)
+		if err != nil {
+			return fmt.Errorf("unable to enable session affinity for %s: %s", fe.String(), err)
+		}
+	} else {
+		DeleteAffinityMatch(uint16(revNATID))
+	}
+
 	return nil
 }
 
@@ -407,6 +478,9 @@ func LBSVC2ServiceKeynValue(svc types.LBSVC) (ServiceKey, []ServiceValue, error)
 		beValue.SetPort(be.Port)
 		beValue.SetRevNat(int(svc.FE.ID))
 		beValue.SetWeight(be.Weight)
+		if be.Draining {
+			beValue.SetFlags(ServiceFlagDraining)
+		}
 
 		besValues = append(besValues, beValue)
 		log.WithFields(logrus.Fields{
@@ -458,6 +532,7 @@ func ServiceKeynValue2FEnBE(svcKey ServiceKey, svcValue ServiceValue) (*types.L3
 		svcID    types.ServiceID
 		bePort   uint16
 		beWeight uint16
+		beFlags  uint16
 	)
 
 	log.WithFields(logrus.Fields{
@@ -471,12 +546,14 @@ func ServiceKeynValue2FEnBE(svcKey ServiceKey, svcValue ServiceValue) (*types.L3
 		beIP = svc6Val.Address.IP()
 		bePort = svc6Val.Port
 		beWeight = svc6Val.Weight
+		beFlags = svc6Val.Count
 	} else {
 		svc4Val := svcValue.(*Service4Value)
 		svcID = types.ServiceID(svc4Val.RevNat)
 		beIP = svc4Val.Address.IP()
 		bePort = svc4Val.Port
 		beWeight = svc4Val.Weight
+		beFlags = svc4Val.Count
 	}
 
 	feL3n4Addr, err := ServiceKey2L3n4Addr(svcKey)
@@ -488,6 +565,7 @@ func ServiceKeynValue2FEnBE(svcKey ServiceKey, svcValue ServiceValue) (*types.L3
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to create a new backend for %s:%d: %s", beIP, bePort, err)
 	}
+	beLBBackEnd.Draining = beFlags&ServiceFlagDraining != 0
 
 	feL3n4AddrID := &types.L3n4AddrID{
 		L3n4Addr: *feL3n4Addr,
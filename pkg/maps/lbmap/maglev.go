@@ -0,0 +1,185 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"strconv"
+	"unsafe"
+)
+
+const (
+	// MaglevTableSize is the number of entries of the Maglev lookup table
+	// of a service. It must be a prime number and considerably larger than
+	// the number of backends of a service for the flows to be distributed
+	// evenly. It is used by daemon for generating bpf define
+	// LB_MAGLEV_TABLE_SIZE.
+	MaglevTableSize = 1021
+)
+
+// MaglevValue is the Maglev lookup table of a service. It must match
+// 'struct lb_maglev' in "bpf/lib/common.h".
+type MaglevValue struct {
+	// Number of backends referenced by the table
+	Count uint16
+
+	// Backend index for each entry of the table
+	Idx [MaglevTableSize]uint16
+}
+
+func (m *MaglevValue) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(m) }
+
+func (m *MaglevValue) String() string {
+	return fmt.Sprintf("maglev table with %d backends", m.Count)
+}
+
+// maglevPermutation returns the offset and skip of the preference list of
+// the backend with the given name as described in the Maglev paper.
+func maglevPermutation(name string) (offset, skip uint64) {
+	h1 := fnv.New64a()
+	h1.Write([]byte(name))
+	h2 := fnv.New64()
+	h2.Write([]byte(name))
+
+	offset = h1.Sum64() % MaglevTableSize
+	skip = h2.Sum64()%(MaglevTableSize-1) + 1
+	return
+}
+
+// generateMaglevTable generates a Maglev lookup table for the given backends.
+// Each backend is identified by a name which must be stable across calls as
+// it determines the backend's preference list. If any weight is non-zero,
+// the backends are assigned table entries proportionally to their weight and
+// backends with a weight of 0 are not assigned any entry, matching
+// generateWrrSeq. Otherwise all backends are weighted equally.
+func generateMaglevTable(backends []string, weights []uint16) (*MaglevValue, error) {
+	n := len(backends)
+	if n == 0 {
+		return nil, fmt.Errorf("needs at least 1 backend")
+	}
+	if n > MaglevTableSize {
+		return nil, fmt.Errorf("number of backends exceeds %d", MaglevTableSize)
+	}
+	if len(weights) != n {
+		return nil, fmt.Errorf("number of weights does not match number of backends")
+	}
+
+	g := uint16(0)
+	for _, w := range weights {
+		if w != 0 {
+			g = gcd(g, w)
+		}
+	}
+
+	// Normalize the weights. If all of them are 0, the weights are not
+	// specified and each backend gets the same share.
+	turns := make([]uint16, n)
+	for i := range turns {
+		if g == 0 {
+			turns[i] = 1
+		} else {
+			turns[i] = weights[i] / g
+		}
+	}
+
+	offsets := make([]uint64, n)
+	skips := make([]uint64, n)
+	for i, name := range backends {
+		offsets[i], skips[i] = maglevPermutation(name)
+	}
+
+	var (
+		next    = make([]uint64, n)
+		entries [MaglevTableSize]int
+		filled  = 0
+	)
+	for i := range entries {
+		entries[i] = -1
+	}
+
+	// Let each backend claim the next free entry of its preference list,
+	// once per unit of weight, until all entries are taken.
+	for filled < MaglevTableSize {
+		for i := 0; i < n && filled < MaglevTableSize; i++ {
+			for t := uint16(0); t < turns[i] && filled < MaglevTableSize; t++ {
+				c := (offsets[i] + next[i]*skips[i]) % MaglevTableSize
+				for entries[c] >= 0 {
+					next[i]++
+					c = (offsets[i] + next[i]*skips[i]) % MaglevTableSize
+				}
+				entries[c] = i
+				next[i]++
+				filled++
+			}
+		}
+	}
+
+	table := MaglevValue{Count: uint16(n)}
+	for i, be := range entries {
+		table.Idx[i] = uint16(be)
+	}
+	return &table, nil
+}
+
+// maglevBackendName returns the name identifying the given backend in the
+// Maglev lookup table.
+func maglevBackendName(be ServiceValue) string {
+	switch v := be.(type) {
+	case *Service4Value:
+		return net.JoinHostPort(v.Address.IP().String(), strconv.Itoa(int(v.Port)))
+	case *Service6Value:
+		return net.JoinHostPort(v.Address.IP().String(), strconv.Itoa(int(v.Port)))
+	}
+	return be.String()
+}
+
+// UpdateMaglevTable generates the Maglev lookup table for the given backends
+// and stores it in cilium_lb6_maglev or cilium_lb4_maglev bpf maps.
+func UpdateMaglevTable(fe ServiceKey, besValues []ServiceValue) error {
+	weights := make([]uint16, 0, len(besValues))
+	for _, be := range besValues {
+		weights = append(weights, be.GetWeight())
+	}
+
+	return updateMaglevTable(fe, besValues, weights)
+}
+
+// updateMaglevTable is like UpdateMaglevTable but takes the weights of the
+// backends from weights instead of the backend values.
+func updateMaglevTable(fe ServiceKey, besValues []ServiceValue, weights []uint16) error {
+	names := make([]string, 0, len(besValues))
+	for _, be := range besValues {
+		names = append(names, maglevBackendName(be))
+	}
+
+	table, err := generateMaglevTable(names, weights)
+	if err != nil {
+		return fmt.Errorf("unable to generate maglev table for %s: %s", fe.String(), err)
+	}
+
+	if _, err := fe.MaglevMap().OpenOrCreate(); err != nil {
+		return err
+	}
+
+	return fe.MaglevMap().Update(fe.ToNetwork(), table)
+}
+
+// DeleteMaglevTable deletes entry from cilium_lb6_maglev or cilium_lb4_maglev
+func DeleteMaglevTable(key ServiceKey) {
+	// Ignore if entry is not found, only services using Maglev have one.
+	key.MaglevMap().Delete(key.ToNetwork())
+}
@@ -0,0 +1,160 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type LBMapSuite struct{}
+
+var _ = Suite(&LBMapSuite{})
+
+func maglevBackends(n int) []string {
+	backends := make([]string, n)
+	for i := range backends {
+		backends[i] = fmt.Sprintf("10.0.%d.%d:80", i/256, i%256)
+	}
+	return backends
+}
+
+// maglevLookup resolves each entry of the table to the name of the backend.
+func maglevLookup(table *MaglevValue, backends []string) []string {
+	names := make([]string, len(table.Idx))
+	for i, idx := range table.Idx {
+		names[i] = backends[idx]
+	}
+	return names
+}
+
+func (s *LBMapSuite) TestGenerateMaglevTableErrors(c *C) {
+	_, err := generateMaglevTable(nil, nil)
+	c.Assert(err, Not(IsNil))
+
+	_, err = generateMaglevTable(maglevBackends(2), []uint16{0})
+	c.Assert(err, Not(IsNil))
+
+	_, err = generateMaglevTable(maglevBackends(MaglevTableSize+1), make([]uint16, MaglevTableSize+1))
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *LBMapSuite) TestGenerateMaglevTableEven(c *C) {
+	for _, n := range []int{1, 2, 3, 10, 33} {
+		backends := maglevBackends(n)
+		table, err := generateMaglevTable(backends, make([]uint16, n))
+		c.Assert(err, IsNil)
+		c.Assert(table.Count, Equals, uint16(n))
+
+		counts := make([]int, n)
+		for _, idx := range table.Idx {
+			c.Assert(int(idx) < n, Equals, true)
+			counts[idx]++
+		}
+
+		// Backends take turns in claiming entries so their share of
+		// the table differs by at most one entry.
+		for _, count := range counts {
+			c.Assert(count >= MaglevTableSize/n, Equals, true, Commentf("%d backends: %v", n, counts))
+			c.Assert(count <= MaglevTableSize/n+1, Equals, true, Commentf("%d backends: %v", n, counts))
+		}
+	}
+}
+
+func (s *LBMapSuite) TestGenerateMaglevTableStable(c *C) {
+	backends := maglevBackends(5)
+	table1, err := generateMaglevTable(backends, make([]uint16, 5))
+	c.Assert(err, IsNil)
+	table2, err := generateMaglevTable(backends, make([]uint16, 5))
+	c.Assert(err, IsNil)
+	c.Assert(*table1, Equals, *table2)
+}
+
+func (s *LBMapSuite) TestGenerateMaglevTableWeights(c *C) {
+	backends := maglevBackends(3)
+	table, err := generateMaglevTable(backends, []uint16{2, 0, 6})
+	c.Assert(err, IsNil)
+
+	counts := make([]int, 3)
+	for _, idx := range table.Idx {
+		counts[idx]++
+	}
+
+	// A weight of 0 excludes the backend like in generateWrrSeq.
+	c.Assert(counts[1], Equals, 0)
+	c.Assert(counts[0]+counts[2], Equals, MaglevTableSize)
+	c.Assert(counts[2] >= 3*counts[0]-3, Equals, true, Commentf("%v", counts))
+	c.Assert(counts[2] <= 3*counts[0]+3, Equals, true, Commentf("%v", counts))
+}
+
+func (s *LBMapSuite) TestGenerateMaglevTableDisruption(c *C) {
+	const n = 10
+
+	backends := maglevBackends(n)
+	table, err := generateMaglevTable(backends, make([]uint16, n))
+	c.Assert(err, IsNil)
+	before := maglevLookup(table, backends)
+
+	// Removing a backend must only move the flows of that backend plus a
+	// small amount of other flows.
+	removed := append(append([]string{}, backends[:3]...), backends[4:]...)
+	table, err = generateMaglevTable(removed, make([]uint16, n-1))
+	c.Assert(err, IsNil)
+	after := maglevLookup(table, removed)
+
+	moved := 0
+	for i := range before {
+		if before[i] != after[i] && before[i] != backends[3] {
+			moved++
+		}
+	}
+	c.Assert(moved < MaglevTableSize/n, Equals, true, Commentf("%d entries moved", moved))
+
+	// Adding a backend must move about 1/N of the flows, all of them to the
+	// new backend.
+	added := append(append([]string{}, backends...), "10.0.1.0:80")
+	table, err = generateMaglevTable(added, make([]uint16, n+1))
+	c.Assert(err, IsNil)
+	after = maglevLookup(table, added)
+
+	moved = 0
+	movedElsewhere := 0
+	for i := range before {
+		if before[i] != after[i] {
+			moved++
+			if after[i] != added[n] {
+				movedElsewhere++
+			}
+		}
+	}
+	c.Assert(moved <= 2*MaglevTableSize/(n+1), Equals, true, Commentf("%d entries moved", moved))
+	c.Assert(movedElsewhere < MaglevTableSize/(n+1), Equals, true, Commentf("%d entries moved elsewhere", movedElsewhere))
+}
+
+func (s *LBMapSuite) TestMaglevBackendName(c *C) {
+	be4 := NewService4Value(0, net.ParseIP("10.0.0.1"), 80, 1, 0)
+	c.Assert(maglevBackendName(be4), Equals, "10.0.0.1:80")
+
+	be6 := NewService6Value(0, net.ParseIP("f00d::1"), 80, 1, 0)
+	c.Assert(maglevBackendName(be6), Equals, "[f00d::1]:80")
+}
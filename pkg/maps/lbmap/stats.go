@@ -0,0 +1,222 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+)
+
+var (
+	Stats4Map = bpf.NewMap("cilium_lb4_stats",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(Stats4Key{})),
+		int(unsafe.Sizeof(StatsValue{})),
+		maxEntries, 0)
+	Stats6Map = bpf.NewMap("cilium_lb6_stats",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(Stats6Key{})),
+		int(unsafe.Sizeof(StatsValue{})),
+		maxEntries, 0)
+)
+
+// StatsKey is the interface describing protocol independent key for the
+// load-balancing statistics maps.
+type StatsKey interface {
+	bpf.MapKey
+
+	// Returns human readable string representation
+	String() string
+
+	// Returns the BPF map matching the key type
+	Map() *bpf.Map
+
+	// Returns the address of the backend
+	GetBackend() types.L3n4Addr
+
+	// Returns the reverse NAT identifier of the service
+	GetRevNat() uint16
+
+	// ToNetwork converts fields to network byte order.
+	ToNetwork() StatsKey
+}
+
+// Stats4Key must match 'struct lb4_stats_key' in "bpf/lib/common.h".
+type Stats4Key struct {
+	Address types.IPv4
+	Port    uint16
+	RevNat  uint16
+}
+
+// NewStats4Key returns the statistics key of the given backend of the service
+// with the given reverse NAT identifier.
+func NewStats4Key(ip net.IP, port uint16, revNat uint16) *Stats4Key {
+	key := Stats4Key{Port: port, RevNat: revNat}
+	copy(key.Address[:], ip.To4())
+	return &key
+}
+
+func (k *Stats4Key) Map() *bpf.Map             { return Stats4Map }
+func (k *Stats4Key) NewValue() bpf.MapValue    { return &StatsValue{} }
+func (k *Stats4Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+func (k *Stats4Key) GetRevNat() uint16         { return k.RevNat }
+
+func (k *Stats4Key) GetBackend() types.L3n4Addr {
+	return types.L3n4Addr{IP: k.Address.IP(), L4Addr: types.L4Addr{Port: k.Port}}
+}
+
+func (k *Stats4Key) String() string {
+	return fmt.Sprintf("%s:%d (%d)", k.Address, k.Port, k.RevNat)
+}
+
+// ToNetwork converts Stats4Key port and reverse NAT identifier to network
+// byte order.
+func (k *Stats4Key) ToNetwork() StatsKey {
+	n := *k
+	n.Port = byteorder.HostToNetwork(n.Port).(uint16)
+	n.RevNat = byteorder.HostToNetwork(n.RevNat).(uint16)
+	return &n
+}
+
+// Stats6Key must match 'struct lb6_stats_key' in "bpf/lib/common.h".
+type Stats6Key struct {
+	Address types.IPv6
+	Port    uint16
+	RevNat  uint16
+}
+
+// NewStats6Key returns the statistics key of the given backend of the service
+// with the given reverse NAT identifier.
+func NewStats6Key(ip net.IP, port uint16, revNat uint16) *Stats6Key {
+	key := Stats6Key{Port: port, RevNat: revNat}
+	copy(key.Address[:], ip.To16())
+	return &key
+}
+
+func (k *Stats6Key) Map() *bpf.Map             { return Stats6Map }
+func (k *Stats6Key) NewValue() bpf.MapValue    { return &StatsValue{} }
+func (k *Stats6Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+func (k *Stats6Key) GetRevNat() uint16         { return k.RevNat }
+
+func (k *Stats6Key) GetBackend() types.L3n4Addr {
+	return types.L3n4Addr{IP: k.Address.IP(), L4Addr: types.L4Addr{Port: k.Port}}
+}
+
+func (k *Stats6Key) String() string {
+	return fmt.Sprintf("[%s]:%d (%d)", k.Address, k.Port, k.RevNat)
+}
+
+// ToNetwork converts Stats6Key port and reverse NAT identifier to network
+// byte order.
+func (k *Stats6Key) ToNetwork() StatsKey {
+	n := *k
+	n.Port = byteorder.HostToNetwork(n.Port).(uint16)
+	n.RevNat = byteorder.HostToNetwork(n.RevNat).(uint16)
+	return &n
+}
+
+// StatsValue must match 'struct lb_stats' in "bpf/lib/common.h".
+type StatsValue struct {
+	// Number of packets sent to the backend
+	Packets uint64
+
+	// Number of bytes sent to the backend
+	Bytes uint64
+
+	// Number of connections opened to the backend
+	Connections uint64
+}
+
+func (v *StatsValue) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(v) }
+
+func (v *StatsValue) String() string {
+	return fmt.Sprintf("packets %d, bytes %d, connections %d", v.Packets, v.Bytes, v.Connections)
+}
+
+// Add adds the counters of other to v.
+func (v *StatsValue) Add(other StatsValue) {
+	v.Packets += other.Packets
+	v.Bytes += other.Bytes
+	v.Connections += other.Connections
+}
+
+// StatsEntry holds the load-balancing statistics of a backend of a service.
+type StatsEntry struct {
+	Key   StatsKey
+	Value StatsValue
+}
+
+func Stats4DumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	keyBuf := bytes.NewBuffer(key)
+	valueBuf := bytes.NewBuffer(value)
+	statsKey := Stats4Key{}
+	statsVal := StatsValue{}
+
+	if err := binary.Read(keyBuf, byteorder.Native, &statsKey); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(valueBuf, byteorder.Native, &statsVal); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return statsKey.ToNetwork(), &statsVal, nil
+}
+
+func Stats6DumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	keyBuf := bytes.NewBuffer(key)
+	valueBuf := bytes.NewBuffer(value)
+	statsKey := Stats6Key{}
+	statsVal := StatsValue{}
+
+	if err := binary.Read(keyBuf, byteorder.Native, &statsKey); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(valueBuf, byteorder.Native, &statsVal); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return statsKey.ToNetwork(), &statsVal, nil
+}
+
+// DumpStats returns all entries of the given load-balancing statistics map.
+func DumpStats(m *bpf.Map) ([]StatsEntry, error) {
+	parser := Stats4DumpParser
+	if m == Stats6Map {
+		parser = Stats6DumpParser
+	}
+
+	entries := []StatsEntry{}
+	err := m.Dump(parser, func(key bpf.MapKey, value bpf.MapValue) {
+		entries = append(entries, StatsEntry{
+			Key:   key.(StatsKey),
+			Value: *value.(*StatsValue),
+		})
+	})
+	return entries, err
+}
+
+// DeleteStats removes the statistics entry with the given key.
+func DeleteStats(key StatsKey) error {
+	return key.Map().Delete(key.ToNetwork())
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"net"
+	"unsafe"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *LBMapSuite) TestStatsKeySize(c *C) {
+	// Must match the size of the structs in bpf/lib/common.h
+	c.Assert(unsafe.Sizeof(Stats4Key{}), Equals, uintptr(8))
+	c.Assert(unsafe.Sizeof(Stats6Key{}), Equals, uintptr(20))
+	c.Assert(unsafe.Sizeof(StatsValue{}), Equals, uintptr(24))
+}
+
+func (s *LBMapSuite) TestStatsKey(c *C) {
+	k4 := NewStats4Key(net.ParseIP("10.0.0.1"), 80, 5)
+	be := k4.GetBackend()
+	c.Assert(be.IP.Equal(net.ParseIP("10.0.0.1")), Equals, true)
+	c.Assert(be.Port, Equals, uint16(80))
+	c.Assert(k4.GetRevNat(), Equals, uint16(5))
+	c.Assert(k4.String(), Equals, "10.0.0.1:80 (5)")
+
+	n4 := k4.ToNetwork()
+	c.Assert(n4.GetRevNat(), Equals, byteorder.HostToNetwork(uint16(5)).(uint16))
+	c.Assert(n4.GetBackend().Port, Equals, byteorder.HostToNetwork(uint16(80)).(uint16))
+	c.Assert(n4.ToNetwork(), DeepEquals, StatsKey(k4))
+
+	k6 := NewStats6Key(net.ParseIP("f00d::1"), 80, 5)
+	c.Assert(k6.GetBackend().IP.Equal(net.ParseIP("f00d::1")), Equals, true)
+	c.Assert(k6.String(), Equals, "[f00d::1]:80 (5)")
+	c.Assert(k6.ToNetwork().GetRevNat(), Equals, byteorder.HostToNetwork(uint16(5)).(uint16))
+}
+
+func (s *LBMapSuite) TestStatsValueAdd(c *C) {
+	v := StatsValue{Packets: 1, Bytes: 100, Connections: 1}
+	v.Add(StatsValue{Packets: 2, Bytes: 50})
+	c.Assert(v, Equals, StatsValue{Packets: 3, Bytes: 150, Connections: 1})
+}
+
+func (s *LBMapSuite) TestDumpStats(c *C) {
+	prev := bpf.SetMapBackend(bpf.NewMemoryBackend())
+	defer bpf.SetMapBackend(prev)
+
+	_, err := Stats4Map.OpenOrCreate()
+	c.Assert(err, IsNil)
+	defer Stats4Map.Close()
+
+	k1 := NewStats4Key(net.ParseIP("10.0.0.1"), 80, 5)
+	k2 := NewStats4Key(net.ParseIP("10.0.0.2"), 80, 5)
+	c.Assert(Stats4Map.Update(k1.ToNetwork(), &StatsValue{Packets: 1, Bytes: 100}), IsNil)
+	c.Assert(Stats4Map.Update(k2.ToNetwork(), &StatsValue{Packets: 2, Bytes: 200}), IsNil)
+
+	entries, err := DumpStats(Stats4Map)
+	c.Assert(err, IsNil)
+	c.Assert(entries, DeepEquals, []StatsEntry{
+		{Key: k1, Value: StatsValue{Packets: 1, Bytes: 100}},
+		{Key: k2, Value: StatsValue{Packets: 2, Bytes: 200}},
+	})
+
+	c.Assert(DeleteStats(k1), IsNil)
+	c.Assert(DeleteStats(k1), Not(IsNil))
+	entries, err = DumpStats(Stats4Map)
+	c.Assert(err, IsNil)
+	c.Assert(entries, DeepEquals, []StatsEntry{
+		{Key: k2, Value: StatsValue{Packets: 2, Bytes: 200}},
+	})
+}
@@ -45,11 +45,16 @@ var (
 		bpf.MapTypeHash,
 		int(unsafe.Sizeof(EndpointKey{})),
 		int(unsafe.Sizeof(EndpointInfo{})),
-		MaxKeys, 0)
+		MaxKeys, 0).WithCache()
 )
 
 func init() {
 	bpf.OpenAfterMount(mapInstance)
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        MapName,
+		Description: "Local endpoints indexed by IP address",
+		Parser:      dumpParser,
+	})
 }
 
 // MAC is the __u64 representation of a MAC address.
@@ -109,12 +114,13 @@ type EndpointFrontend interface {
 // Must be in sync with struct endpoint_info in <bpf/lib/common.h>
 type EndpointInfo struct {
 	IfIndex    uint32
-	SecLabelID uint16
+	Unused     uint16
 	LxcID      uint16
 	Flags      uint32
 	MAC        MAC
 	NodeMAC    MAC
-	Pad        [4]uint32
+	SecLabelID uint32
+	Pad        [3]uint32
 	PortMap    [PortMapMax]PortMap
 }
 
@@ -152,7 +158,7 @@ func (v EndpointInfo) String() string {
 	if len(portMaps) == 0 {
 		portMaps = append(portMaps, "(empty)")
 	}
-	return fmt.Sprintf("id=%-5d ifindex=%-3d mac=%s nodemac=%s seclabel=%#-4x portMaps=%s",
+	return fmt.Sprintf("id=%-5d ifindex=%-3d mac=%s nodemac=%s seclabel=%#-6x portMaps=%s",
 		v.LxcID,
 		v.IfIndex,
 		v.MAC,
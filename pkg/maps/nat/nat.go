@@ -0,0 +1,418 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+const (
+	// MapName4 is the name of the IPv4 masquerading table
+	MapName4 = "cilium_snat_v4_external"
+
+	// MaxEntries is the maximum number of entries in the masquerading
+	// table. Every masqueraded flow uses two entries.
+	MaxEntries = 65536
+
+	// MinPort and MaxPort bound the range of the translated source ports.
+	// The range must not overlap with the ephemeral port range of the
+	// node, see SNAT_MAPPING_MIN_PORT in "bpf/lib/nat.h".
+	MinPort = 1024
+	MaxPort = 32767
+
+	// DefaultLifetime is the lifetime of a mapping after it has seen a
+	// packet. Must match SNAT_LIFETIME in "bpf/lib/nat.h".
+	DefaultLifetime = 360 * time.Second
+
+	// DirEgress marks the mappings of packets leaving an endpoint
+	DirEgress = 0
+	// DirIngress marks the mappings of the replies to the node IP
+	DirIngress = 1
+
+	// gcInterval is the interval in which expired mappings are removed
+	gcInterval = time.Minute
+)
+
+var (
+	log = logging.DefaultLogger.WithField(logfields.LogSubsys, "map-nat")
+
+	// Map4 is the IPv4 masquerading table. It is created by the datapath
+	// when masquerading is enabled.
+	Map4 = bpf.NewMap(MapName4,
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(NatKey4{})),
+		int(unsafe.Sizeof(NatEntry4{})),
+		MaxEntries, 0)
+
+	// gcControllers is the controller manager for the garbage collector
+	// of the masquerading table
+	gcControllers = controller.NewManager()
+)
+
+func init() {
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        MapName4,
+		Description: "IPv4 masquerading mappings of egress flows and their replies",
+		Parser:      dumpParser,
+	})
+}
+
+// NatKey4 must match 'struct ipv4_nat_tuple' in "bpf/lib/nat.h". Egress
+// mappings are indexed by the tuple of the packet leaving the endpoint,
+// ingress mappings by the tuple of the reply to the node IP.
+type NatKey4 struct {
+	DAddr types.IPv4
+	SAddr types.IPv4
+	// DPort is in network byte order
+	DPort uint16
+	// SPort is in network byte order
+	SPort   uint16
+	Nexthdr u8proto.U8proto
+	Flags   uint8
+	Pad     uint16
+}
+
+// GetKeyPtr returns the unsafe.Pointer for k.
+func (k *NatKey4) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+
+// NewValue creates a new bpf.MapValue.
+func (k *NatKey4) NewValue() bpf.MapValue { return &NatEntry4{} }
+
+// ToHost converts NatKey4 ports to host byte order.
+func (k *NatKey4) ToHost() *NatKey4 {
+	n := *k
+	n.DPort = byteorder.NetworkToHost(n.DPort).(uint16)
+	n.SPort = byteorder.NetworkToHost(n.SPort).(uint16)
+	return &n
+}
+
+// Dir returns the direction of the mapping, DirEgress or DirIngress.
+func (k *NatKey4) Dir() uint8 {
+	return k.Flags & DirIngress
+}
+
+func (k *NatKey4) String() string {
+	dir := "OUT"
+	if k.Dir() == DirIngress {
+		dir = "IN"
+	}
+	return fmt.Sprintf("%s %s %s:%d -> %s:%d", k.Nexthdr, dir, k.SAddr, k.SPort, k.DAddr, k.DPort)
+}
+
+// NatEntry4 must match 'struct ipv4_nat_entry' in "bpf/lib/nat.h".
+type NatEntry4 struct {
+	Lifetime uint32
+	// Addr and Port are the source of egress packets and the destination
+	// of ingress packets after the translation
+	Addr types.IPv4
+	// Port is in network byte order
+	Port uint16
+	Pad  uint16
+}
+
+// GetValuePtr returns the unsafe.Pointer for e.
+func (e *NatEntry4) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(e) }
+
+// ToHost converts the NatEntry4 port to host byte order.
+func (e *NatEntry4) ToHost() *NatEntry4 {
+	n := *e
+	n.Port = byteorder.NetworkToHost(n.Port).(uint16)
+	return &n
+}
+
+func (e *NatEntry4) String() string {
+	return fmt.Sprintf("%s:%d expires=%d", e.Addr, e.Port, e.Lifetime)
+}
+
+// parseEntry decodes a raw entry of the masquerading table without
+// converting the byte order of the ports.
+func parseEntry(key []byte, value []byte) (*NatKey4, *NatEntry4, error) {
+	k, v := NatKey4{}, NatEntry4{}
+
+	if err := binary.Read(bytes.NewBuffer(key), byteorder.Native, &k); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(bytes.NewBuffer(value), byteorder.Native, &v); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return &k, &v, nil
+}
+
+func dumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v, err := parseEntry(key, value)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k.ToHost(), v.ToHost(), nil
+}
+
+func rawParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	return parseEntry(key, value)
+}
+
+// entryDump is a raw entry of the masquerading table
+type entryDump struct {
+	Key   NatKey4
+	Value NatEntry4
+}
+
+// dump returns all entries of m as stored in the map
+func dump(m *bpf.Map) ([]entryDump, error) {
+	entries := []entryDump{}
+	err := m.Dump(rawParser, func(key bpf.MapKey, value bpf.MapValue) {
+		entries = append(entries, entryDump{
+			Key:   *key.(*NatKey4),
+			Value: *value.(*NatEntry4),
+		})
+	})
+	return entries, err
+}
+
+// Entry is the human readable representation of a masquerading mapping
+type Entry struct {
+	Proto     string `json:"proto"`
+	Direction string `json:"direction"`
+	SrcIP     net.IP `json:"src-ip"`
+	SrcPort   uint16 `json:"src-port"`
+	DstIP     net.IP `json:"dst-ip"`
+	DstPort   uint16 `json:"dst-port"`
+	ToIP      net.IP `json:"to-ip"`
+	ToPort    uint16 `json:"to-port"`
+	Expires   uint32 `json:"expires"`
+	ExpiresIn int64  `json:"expires-in"`
+}
+
+// String returns the entry in the format of "cilium bpf nat list"
+func (e *Entry) String() string {
+	to := "src"
+	if e.Direction == "in" {
+		to = "dst"
+	}
+	return fmt.Sprintf("%s %s %s:%d -> %s:%d %s=%s:%d expires=%d",
+		e.Proto, strings.ToUpper(e.Direction), e.SrcIP, e.SrcPort,
+		e.DstIP, e.DstPort, to, e.ToIP, e.ToPort, e.Expires)
+}
+
+func newEntry(d *entryDump, now uint32) *Entry {
+	k, v := d.Key.ToHost(), d.Value.ToHost()
+	e := &Entry{
+		Proto:     k.Nexthdr.String(),
+		Direction: "out",
+		SrcIP:     k.SAddr.IP(),
+		SrcPort:   k.SPort,
+		DstIP:     k.DAddr.IP(),
+		DstPort:   k.DPort,
+		ToIP:      v.Addr.IP(),
+		ToPort:    v.Port,
+		Expires:   v.Lifetime,
+		ExpiresIn: int64(v.Lifetime) - int64(now),
+	}
+	if k.Dir() == DirIngress {
+		e.Direction = "in"
+	}
+	return e
+}
+
+// currentTime returns the current time in seconds as returned by
+// bpf_ktime_get_sec() in the datapath
+func currentTime() (uint32, error) {
+	t, err := bpf.GetMtime()
+	if err != nil {
+		return 0, err
+	}
+	return uint32(t / 1000000000), nil
+}
+
+// ListEntries returns the human readable representation of all mappings of
+// the masquerading table m.
+func ListEntries(m *bpf.Map) ([]*Entry, error) {
+	t, err := currentTime()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := dump(m)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Entry, 0, len(entries))
+	for i := range entries {
+		list = append(list, newEntry(&entries[i], t))
+	}
+	return list, nil
+}
+
+// reverseKey returns the key of the ingress mapping of the egress mapping
+// with key k and value v, see snat_v4_new_mapping() in "bpf/lib/nat.h".
+func reverseKey(k *NatKey4, v *NatEntry4) NatKey4 {
+	r := NatKey4{
+		DAddr:   v.Addr,
+		SAddr:   k.DAddr,
+		DPort:   v.Port,
+		SPort:   k.DPort,
+		Nexthdr: k.Nexthdr,
+		Flags:   DirIngress,
+	}
+	// ICMP echo mappings carry the identifier as both ports
+	if k.Nexthdr == u8proto.ICMP {
+		r.SPort = v.Port
+	}
+	return r
+}
+
+// originalKey returns the key of the egress mapping of the ingress mapping
+// with key k and value v.
+func originalKey(k *NatKey4, v *NatEntry4) NatKey4 {
+	o := NatKey4{
+		DAddr:   k.SAddr,
+		SAddr:   v.Addr,
+		DPort:   k.SPort,
+		SPort:   v.Port,
+		Nexthdr: k.Nexthdr,
+		Flags:   DirEgress,
+	}
+	if k.Nexthdr == u8proto.ICMP {
+		o.DPort = v.Port
+	}
+	return o
+}
+
+// GCStats is the result of a garbage collection run on a masquerading table.
+type GCStats struct {
+	// Scanned is the number of entries visited
+	Scanned int
+
+	// Deleted is the number of entries removed
+	Deleted int
+}
+
+// Alive returns the number of entries remaining in the map after the garbage
+// collection run.
+func (s GCStats) Alive() int {
+	return s.Scanned - s.Deleted
+}
+
+// GC removes the expired mappings of the masquerading table m. now is the
+// current time in seconds as returned by bpf_ktime_get_sec(). Both mappings
+// of a flow are removed together once both have expired, so that the
+// translated port is only reused after the flow has ended. Ingress mappings
+// left without their egress mapping are removed once they have expired.
+func GC(m *bpf.Map, now uint32) GCStats {
+	var stats GCStats
+
+	entries, err := dump(m)
+	if err != nil {
+		log.WithError(err).WithField(logfields.Path, m.Path()).Warn("Unable to dump masquerading table")
+		return stats
+	}
+
+	index := make(map[NatKey4]NatEntry4, len(entries))
+	for _, e := range entries {
+		index[e.Key] = e.Value
+	}
+	stats.Scanned = len(entries)
+
+	// The map is modified outside of Dump() as Dump() holds the map lock.
+	del := func(k NatKey4) {
+		if err := m.Delete(&k); err != nil {
+			log.WithError(err).WithField(logfields.BPFMapKey, k.ToHost()).Warn("Unable to delete masquerading entry")
+		} else {
+			stats.Deleted++
+		}
+	}
+
+	for _, e := range entries {
+		if e.Value.Lifetime >= now {
+			continue
+		}
+
+		if e.Key.Dir() == DirEgress {
+			rk := reverseKey(&e.Key, &e.Value)
+			if rv, ok := index[rk]; ok {
+				if rv.Lifetime >= now {
+					continue
+				}
+				del(rk)
+			}
+			del(e.Key)
+			continue
+		}
+
+		// Ingress mappings of active flows are removed together with
+		// their egress mapping above.
+		ok := originalKey(&e.Key, &e.Value)
+		if ov, found := index[ok]; found && ov.Addr == e.Key.DAddr && ov.Port == e.Key.DPort {
+			continue
+		}
+		del(e.Key)
+	}
+
+	return stats
+}
+
+// StartGC starts a controller which periodically removes expired mappings
+// from the masquerading table.
+func StartGC() {
+	gcControllers.UpdateController("nat-gc",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				t, err := currentTime()
+				if err != nil {
+					return err
+				}
+
+				stats := GC(Map4, t)
+				metrics.NATEntries.WithLabelValues(MapName4).Set(float64(stats.Alive()))
+				metrics.NATGCEntriesDeleted.WithLabelValues(MapName4).Add(float64(stats.Deleted))
+				if stats.Deleted > 0 {
+					log.WithField("deleted", stats.Deleted).Debug("Removed expired masquerading entries")
+				}
+				return nil
+			},
+			RunInterval: gcInterval,
+		},
+	)
+}
+
+// FmtExcludeMappings returns the value of SNAT_IPV4_EXCLUDE_MAPPINGS in
+// "bpf/lib/nat.h" for the given IPv4 prefixes which are not masqueraded.
+func FmtExcludeMappings(cidrs []*net.IPNet) string {
+	mappings := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		mappings = append(mappings, fmt.Sprintf("{ .net = %#x, .mask = %#x }",
+			byteorder.HostSliceToNetwork(cidr.IP.To4().Mask(cidr.Mask), reflect.Uint32).(uint32),
+			byteorder.HostSliceToNetwork(cidr.Mask, reflect.Uint32).(uint32)))
+	}
+	return strings.Join(mappings, ", ")
+}
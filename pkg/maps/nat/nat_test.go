@@ -0,0 +1,183 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type NATMapSuite struct {
+	prev bpf.MapBackend
+	m    *bpf.Map
+}
+
+var _ = Suite(&NATMapSuite{})
+
+func (s *NATMapSuite) SetUpTest(c *C) {
+	s.prev = bpf.SetMapBackend(bpf.NewMemoryBackend())
+	s.m = bpf.NewMap(MapName4,
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(NatKey4{})),
+		int(unsafe.Sizeof(NatEntry4{})),
+		16, 0)
+	_, err := s.m.OpenOrCreate()
+	c.Assert(err, IsNil)
+}
+
+func (s *NATMapSuite) TearDownTest(c *C) {
+	c.Assert(s.m.Close(), IsNil)
+	bpf.SetMapBackend(s.prev)
+}
+
+func ip4(s string) (ip types.IPv4) {
+	copy(ip[:], net.ParseIP(s).To4())
+	return
+}
+
+func port(p uint16) uint16 {
+	return byteorder.HostToNetwork(p).(uint16)
+}
+
+// addFlow inserts the egress and ingress mapping of a flow from endpoint
+// 10.1.0.5:port to 1.1.1.1:80 translated to 192.168.0.1:toPort, as created
+// by snat_v4_new_mapping().
+func (s *NATMapSuite) addFlow(c *C, proto u8proto.U8proto, sport, toPort uint16, egressLifetime, ingressLifetime uint32) (NatKey4, NatKey4) {
+	egress := NatKey4{
+		DAddr:   ip4("1.1.1.1"),
+		SAddr:   ip4("10.1.0.5"),
+		DPort:   port(80),
+		SPort:   port(sport),
+		Nexthdr: proto,
+		Flags:   DirEgress,
+	}
+	ingress := NatKey4{
+		DAddr:   ip4("192.168.0.1"),
+		SAddr:   ip4("1.1.1.1"),
+		DPort:   port(toPort),
+		SPort:   port(80),
+		Nexthdr: proto,
+		Flags:   DirIngress,
+	}
+	if proto == u8proto.ICMP {
+		egress.DPort = egress.SPort
+		ingress.SPort = ingress.DPort
+	}
+
+	c.Assert(s.m.Update(&egress, &NatEntry4{Lifetime: egressLifetime, Addr: ip4("192.168.0.1"), Port: port(toPort)}), IsNil)
+	c.Assert(s.m.Update(&ingress, &NatEntry4{Lifetime: ingressLifetime, Addr: ip4("10.1.0.5"), Port: port(sport)}), IsNil)
+	return egress, ingress
+}
+
+func (s *NATMapSuite) exists(key NatKey4) bool {
+	_, err := s.m.Lookup(&key)
+	return err == nil
+}
+
+func (s *NATMapSuite) TestStructSize(c *C) {
+	// Must match the size of the structs in bpf/lib/nat.h
+	c.Assert(unsafe.Sizeof(NatKey4{}), Equals, uintptr(16))
+	c.Assert(unsafe.Sizeof(NatEntry4{}), Equals, uintptr(12))
+}
+
+func (s *NATMapSuite) TestReverseKey(c *C) {
+	for _, proto := range []u8proto.U8proto{u8proto.TCP, u8proto.ICMP} {
+		egress, ingress := s.addFlow(c, proto, 40000, 2000, 100, 100)
+
+		v, err := s.m.Lookup(&egress)
+		c.Assert(err, IsNil)
+		c.Assert(reverseKey(&egress, v.(*NatEntry4)), Equals, ingress)
+
+		v, err = s.m.Lookup(&ingress)
+		c.Assert(err, IsNil)
+		c.Assert(originalKey(&ingress, v.(*NatEntry4)), Equals, egress)
+	}
+}
+
+func (s *NATMapSuite) TestGC(c *C) {
+	// Both mappings expired
+	e1, i1 := s.addFlow(c, u8proto.TCP, 1000, 1001, 50, 50)
+	// Egress mapping expired, replies still active
+	e2, i2 := s.addFlow(c, u8proto.TCP, 2000, 2001, 50, 150)
+	// Both mappings active
+	e3, i3 := s.addFlow(c, u8proto.ICMP, 3000, 3001, 150, 150)
+	// Ingress mapping expired, egress still active
+	e4, i4 := s.addFlow(c, u8proto.UDP, 4000, 4001, 150, 50)
+	// Expired ingress mapping without egress mapping
+	e5, i5 := s.addFlow(c, u8proto.UDP, 5000, 5001, 50, 50)
+	c.Assert(s.m.Delete(&e5), IsNil)
+
+	stats := GC(s.m, 100)
+	c.Assert(stats.Scanned, Equals, 9)
+	c.Assert(stats.Deleted, Equals, 3)
+	c.Assert(stats.Alive(), Equals, 6)
+
+	c.Assert(s.exists(e1), Equals, false)
+	c.Assert(s.exists(i1), Equals, false)
+	c.Assert(s.exists(e2), Equals, true)
+	c.Assert(s.exists(i2), Equals, true)
+	c.Assert(s.exists(e3), Equals, true)
+	c.Assert(s.exists(i3), Equals, true)
+	c.Assert(s.exists(e4), Equals, true)
+	c.Assert(s.exists(i4), Equals, true)
+	c.Assert(s.exists(i5), Equals, false)
+
+	// All remaining mappings have expired
+	stats = GC(s.m, 200)
+	c.Assert(stats.Deleted, Equals, 6)
+	c.Assert(stats.Alive(), Equals, 0)
+}
+
+func (s *NATMapSuite) TestListEntries(c *C) {
+	s.addFlow(c, u8proto.TCP, 40000, 2000, 100, 100)
+
+	entries, err := ListEntries(s.m)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 2)
+	for _, e := range entries {
+		if e.Direction == "out" {
+			c.Assert(e.String(), Equals, "TCP OUT 10.1.0.5:40000 -> 1.1.1.1:80 src=192.168.0.1:2000 expires=100")
+		} else {
+			c.Assert(e.String(), Equals, "TCP IN 1.1.1.1:80 -> 192.168.0.1:2000 dst=10.1.0.5:40000 expires=100")
+		}
+	}
+}
+
+func (s *NATMapSuite) TestFmtExcludeMappings(c *C) {
+	_, cidr1, _ := net.ParseCIDR("192.168.0.0/16")
+	_, cidr2, _ := net.ParseCIDR("10.0.0.0/8")
+
+	c.Assert(FmtExcludeMappings(nil), Equals, "")
+	mappings := FmtExcludeMappings([]*net.IPNet{cidr1, cidr2})
+	if byteorder.Native == binary.BigEndian {
+		c.Assert(mappings, Equals, "{ .net = 0xc0a80000, .mask = 0xffff0000 }, { .net = 0xa000000, .mask = 0xff000000 }")
+	} else {
+		c.Assert(mappings, Equals, "{ .net = 0xa8c0, .mask = 0xffff }, { .net = 0xa, .mask = 0xff }")
+	}
+}
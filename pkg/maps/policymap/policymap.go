@@ -16,6 +16,7 @@ package policymap
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"unsafe"
 
@@ -34,17 +35,30 @@ var (
 	log = logging.DefaultLogger.WithField(logfields.LogSubsys, "policy-map")
 )
 
+func init() {
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        MapName,
+		Prefix:      true,
+		Description: "Identities and L4 ports allowed to reach an endpoint",
+		Parser:      dumpParser,
+	})
+}
+
 type PolicyMap struct {
-	path string
-	Fd   int
+	*bpf.Map
 }
 
 const (
+	// MAX_KEYS is the default maximum number of entries of a policy map
 	MAX_KEYS = 1024
 )
 
+// MaxEntries is the maximum number of entries of the policy maps created or
+// migrated by OpenMap.
+var MaxEntries = MAX_KEYS
+
 func (pe *PolicyEntry) String() string {
-	return string(pe.Action)
+	return fmt.Sprintf("%d", pe.Action)
 }
 
 type policyKey struct {
@@ -61,6 +75,10 @@ type PolicyEntry struct {
 	Bytes   uint64
 }
 
+func (key *policyKey) GetKeyPtr() unsafe.Pointer    { return unsafe.Pointer(key) }
+func (key *policyKey) NewValue() bpf.MapValue       { return &PolicyEntry{} }
+func (pe *PolicyEntry) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(pe) }
+
 func (pe *PolicyEntry) Add(oPe PolicyEntry) {
 	pe.Packets += oPe.Packets
 	pe.Bytes += oPe.Bytes
@@ -71,17 +89,36 @@ type PolicyEntryDump struct {
 	Key policyKey
 }
 
+// GetDestPort returns the destination port of the key in host byte order
+func (key *policyKey) GetDestPort() uint16 {
+	return byteorder.NetworkToHost(key.DestPort).(uint16)
+}
+
 func (key *policyKey) String() string {
 	if key.DestPort != 0 {
-		return fmt.Sprintf("%d %d/%d", key.Identity, byteorder.NetworkToHost(key.DestPort), key.Nexthdr)
+		return fmt.Sprintf("%d %d/%d", key.Identity, key.GetDestPort(), key.Nexthdr)
 	}
 	return fmt.Sprintf("%d", key.Identity)
 }
 
+func dumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v := policyKey{}, PolicyEntry{}
+
+	if err := binary.Read(bytes.NewBuffer(key), byteorder.Native, &k); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert key: %s", err)
+	}
+
+	if err := binary.Read(bytes.NewBuffer(value), byteorder.Native, &v); err != nil {
+		return nil, nil, fmt.Errorf("Unable to convert value: %s", err)
+	}
+
+	return &k, &v, nil
+}
+
 func (pm *PolicyMap) AllowConsumer(id uint32) error {
 	key := policyKey{Identity: id}
 	entry := PolicyEntry{Action: 1}
-	return bpf.UpdateElement(pm.Fd, unsafe.Pointer(&key), unsafe.Pointer(&entry), 0)
+	return pm.Update(&key, &entry)
 }
 
 // AllowL4 pushes an entry into the PolicyMap to allow source identity `id`
@@ -89,13 +126,13 @@ func (pm *PolicyMap) AllowConsumer(id uint32) error {
 func (pm *PolicyMap) AllowL4(id uint32, dport uint16, proto uint8) error {
 	key := policyKey{Identity: id, DestPort: byteorder.HostToNetwork(dport).(uint16), Nexthdr: proto}
 	entry := PolicyEntry{Action: 1}
-	return bpf.UpdateElement(pm.Fd, unsafe.Pointer(&key), unsafe.Pointer(&entry), 0)
+	return pm.Update(&key, &entry)
 }
 
 func (pm *PolicyMap) ConsumerExists(id uint32) bool {
 	key := policyKey{Identity: id}
-	var entry PolicyEntry
-	return bpf.LookupElement(pm.Fd, unsafe.Pointer(&key), unsafe.Pointer(&entry)) == nil
+	_, err := pm.Lookup(&key)
+	return err == nil
 }
 
 // L4Exists determines whether PolicyMap currently contains an entry that
@@ -103,24 +140,24 @@ func (pm *PolicyMap) ConsumerExists(id uint32) bool {
 // protocol `proto`.
 func (pm *PolicyMap) L4Exists(id uint32, dport uint16, proto uint8) bool {
 	key := policyKey{Identity: id, DestPort: byteorder.HostToNetwork(dport).(uint16), Nexthdr: proto}
-	var entry PolicyEntry
-	return bpf.LookupElement(pm.Fd, unsafe.Pointer(&key), unsafe.Pointer(&entry)) == nil
+	_, err := pm.Lookup(&key)
+	return err == nil
 }
 
 func (pm *PolicyMap) DeleteConsumer(id uint32) error {
 	key := policyKey{Identity: id}
-	return bpf.DeleteElement(pm.Fd, unsafe.Pointer(&key))
+	return pm.Delete(&key)
 }
 
 // DeleteL4 removes an entry from the PolicyMap for source identity `id`
 // sending traffic with destination port `dport` over protocol `proto`.
 func (pm *PolicyMap) DeleteL4(id uint32, dport uint16, proto uint8) error {
 	key := policyKey{Identity: id, DestPort: byteorder.HostToNetwork(dport).(uint16), Nexthdr: proto}
-	return bpf.DeleteElement(pm.Fd, unsafe.Pointer(&key))
+	return pm.Delete(&key)
 }
 
 func (pm *PolicyMap) String() string {
-	return pm.path
+	return pm.Path()
 }
 
 func (pm *PolicyMap) Dump() (string, error) {
@@ -142,7 +179,7 @@ func (pm *PolicyMap) DumpToSlice() ([]PolicyEntryDump, error) {
 	for {
 		var entry PolicyEntry
 		err := bpf.GetNextKey(
-			pm.Fd,
+			pm.GetFd(),
 			unsafe.Pointer(&key),
 			unsafe.Pointer(&nextKey),
 		)
@@ -152,7 +189,7 @@ func (pm *PolicyMap) DumpToSlice() ([]PolicyEntryDump, error) {
 		}
 
 		err = bpf.LookupElement(
-			pm.Fd,
+			pm.GetFd(),
 			unsafe.Pointer(&nextKey),
 			unsafe.Pointer(&entry),
 		)
@@ -171,47 +208,27 @@ func (pm *PolicyMap) DumpToSlice() ([]PolicyEntryDump, error) {
 
 // Flush deletes all entries from the given policy map
 func (pm *PolicyMap) Flush() error {
-	var key, nextKey policyKey
-	for {
-		err := bpf.GetNextKey(
-			pm.Fd,
-			unsafe.Pointer(&key),
-			unsafe.Pointer(&nextKey),
-		)
-
-		// FIXME: Ignore delete errors?
-		bpf.DeleteElement(
-			pm.Fd,
-			unsafe.Pointer(&key),
-		)
-
-		if err != nil {
-			break
-		}
-
-		key = nextKey
-	}
-	return nil
-}
-
-// Close closes the FD of the given PolicyMap
-func (pm *PolicyMap) Close() error {
-	return bpf.ObjClose(pm.Fd)
+	return pm.DeleteAll()
 }
 
 // Validate checks the map pinned to the specified path to ensure that the map
 // attributes such as type, key length, value length are the same as for the
-// current version of Cilium.
+// current version of Cilium. The maximum number of entries is not compared as
+// OpenMap migrates the entries of maps with a different size.
 func Validate(path string) (bool, error) {
 	dummy := bpf.NewMap(path, bpf.BPF_MAP_TYPE_HASH,
 		int(unsafe.Sizeof(policyKey{})),
-		int(unsafe.Sizeof(PolicyEntry{})), MAX_KEYS, 0)
+		int(unsafe.Sizeof(PolicyEntry{})), MaxEntries, 0)
 
 	existing, err := bpf.OpenMap(path)
 	if err != nil {
 		return true, err
 	}
 
+	if existing != nil {
+		dummy.MaxEntries = existing.MaxEntries
+	}
+
 	logging.MultiLine(log.Debug, comparator.Compare(existing, dummy))
 
 	if existing != nil && !existing.DeepEquals(dummy) {
@@ -221,31 +238,33 @@ func Validate(path string) (bool, error) {
 	return true, nil
 }
 
+// OpenMap opens or creates the policy map at path. 'bool' returns 'true' if
+// the map was created. The desired state of the map is cached and
+// reconciled in the background. A pinned map of a size other than MaxEntries
+// is replaced by a map of MaxEntries entries with its entries migrated.
 func OpenMap(path string) (*PolicyMap, bool, error) {
-	fd, isNewMap, err := bpf.OpenOrCreateMap(
-		path,
-		bpf.BPF_MAP_TYPE_HASH,
-		uint32(unsafe.Sizeof(policyKey{})),
-		uint32(unsafe.Sizeof(PolicyEntry{})),
-		MAX_KEYS,
-		0,
-	)
+	m := bpf.NewMap(path, bpf.BPF_MAP_TYPE_HASH,
+		int(unsafe.Sizeof(policyKey{})),
+		int(unsafe.Sizeof(PolicyEntry{})), MaxEntries, 0).WithCache()
 
+	// The datapath maintains the packet and byte counters of the entries
+	m.VolatileValues = true
+	m.MigrateEntries = true
+
+	isNewMap, err := m.OpenOrCreate()
 	if err != nil {
 		return nil, false, err
 	}
 
-	m := &PolicyMap{path: path, Fd: fd}
-
-	return m, isNewMap, nil
+	return &PolicyMap{Map: m}, isNewMap, nil
 }
 
+// OpenGlobalMap opens the existing policy map at path.
 func OpenGlobalMap(path string) (*PolicyMap, error) {
-	fd, err := bpf.ObjGet(path)
+	m, err := bpf.OpenMap(path)
 	if err != nil {
 		return nil, err
 	}
 
-	m := &PolicyMap{path: path, Fd: fd}
-	return m, nil
+	return &PolicyMap{Map: m}, nil
 }
@@ -37,12 +37,17 @@ var (
 		bpf.MapTypeHash,
 		int(unsafe.Sizeof(tunnelEndpoint{})),
 		int(unsafe.Sizeof(tunnelEndpoint{})),
-		MaxEntries, 0)
+		MaxEntries, 0).WithCache()
 )
 
 func init() {
 	mapInstance.NonPersistent = true
 	bpf.OpenAfterMount(mapInstance)
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        mapName,
+		Description: "Tunnel endpoints indexed by prefix",
+		Parser:      dumpParser,
+	})
 }
 
 type tunnelEndpoint struct {
@@ -0,0 +1,72 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnel
+
+import (
+	"net"
+	"testing"
+
+	"github.com/cilium/cilium/pkg/bpf"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type TunnelSuite struct {
+	prev bpf.MapBackend
+}
+
+var _ = Suite(&TunnelSuite{})
+
+func (s *TunnelSuite) SetUpTest(c *C) {
+	s.prev = bpf.SetMapBackend(bpf.NewMemoryBackend())
+	_, err := mapInstance.OpenOrCreate()
+	c.Assert(err, IsNil)
+}
+
+func (s *TunnelSuite) TearDownTest(c *C) {
+	c.Assert(mapInstance.Close(), IsNil)
+	bpf.SetMapBackend(s.prev)
+}
+
+func (s *TunnelSuite) dump(c *C) map[string]string {
+	entries := map[string]string{}
+	err := DumpMap(func(key bpf.MapKey, value bpf.MapValue) {
+		entries[key.(tunnelEndpoint).String()] = value.(tunnelEndpoint).String()
+	})
+	c.Assert(err, IsNil)
+	return entries
+}
+
+func (s *TunnelSuite) TestTunnelEndpoint(c *C) {
+	c.Assert(SetTunnelEndpoint(net.ParseIP("10.1.0.0"), net.ParseIP("192.168.0.1")), IsNil)
+	c.Assert(SetTunnelEndpoint(net.ParseIP("f00d:1::"), net.ParseIP("192.168.0.2")), IsNil)
+	c.Assert(s.dump(c), DeepEquals, map[string]string{
+		"10.1.0.0": "192.168.0.1",
+		"f00d:1::": "192.168.0.2",
+	})
+
+	c.Assert(SetTunnelEndpoint(net.ParseIP("10.1.0.0"), net.ParseIP("192.168.0.3")), IsNil)
+	c.Assert(DeleteTunnelEndpoint(net.ParseIP("f00d:1::")), IsNil)
+	c.Assert(s.dump(c), DeepEquals, map[string]string{
+		"10.1.0.0": "192.168.0.3",
+	})
+
+	c.Assert(DeleteTunnelEndpoint(net.ParseIP("f00d:1::")), Not(IsNil))
+}
@@ -0,0 +1,90 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// LabelService is the label for the name of a service, or its
+	// frontend address if it has no name
+	LabelService = "service"
+
+	// LabelFrontend is the label for the frontend address of a service
+	LabelFrontend = "frontend"
+
+	// LabelBackend is the label for the address of a service backend
+	LabelBackend = "backend"
+)
+
+var (
+	lbStatsLabels = []string{LabelService, LabelFrontend, LabelBackend}
+
+	lbBackendPackets = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "", "lb_backend_packets"),
+		"Number of packets sent to a service backend",
+		lbStatsLabels, nil)
+
+	lbBackendBytes = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "", "lb_backend_bytes"),
+		"Number of bytes sent to a service backend",
+		lbStatsLabels, nil)
+
+	lbBackendConnections = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "", "lb_backend_connections"),
+		"Number of connections opened to a service backend",
+		lbStatsLabels, nil)
+)
+
+// LBBackendStats are the load-balancing statistics of a backend of a service
+// frontend.
+type LBBackendStats struct {
+	Service     string
+	Frontend    string
+	Backend     string
+	Packets     uint64
+	Bytes       uint64
+	Connections uint64
+}
+
+// lbStatsCollector exports the load-balancing statistics maintained by the
+// datapath. The counters are read on each scrape.
+type lbStatsCollector struct {
+	dump func() []LBBackendStats
+}
+
+// NewLBStatsCollector returns a collector exporting the load-balancing
+// statistics returned by dump.
+func NewLBStatsCollector(dump func() []LBBackendStats) prometheus.Collector {
+	return &lbStatsCollector{dump: dump}
+}
+
+func (c *lbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- lbBackendPackets
+	ch <- lbBackendBytes
+	ch <- lbBackendConnections
+}
+
+func (c *lbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.dump() {
+		ch <- prometheus.MustNewConstMetric(lbBackendPackets, prometheus.CounterValue,
+			float64(s.Packets), s.Service, s.Frontend, s.Backend)
+		ch <- prometheus.MustNewConstMetric(lbBackendBytes, prometheus.CounterValue,
+			float64(s.Bytes), s.Service, s.Frontend, s.Backend)
+		ch <- prometheus.MustNewConstMetric(lbBackendConnections, prometheus.CounterValue,
+			float64(s.Connections), s.Service, s.Frontend, s.Backend)
+	}
+}
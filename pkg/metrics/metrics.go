@@ -55,6 +55,32 @@ var (
 	// LabelEventSourceContainerd marks event-related metrics that come from containerd
 	LabelEventSourceContainerd = "containerd"
 
+	// LabelValueIdentityAllocated marks identities allocated in the kvstore
+	LabelValueIdentityAllocated = "allocated"
+
+	// LabelValueIdentityInUse marks allocated identities in use by at least
+	// one node
+	LabelValueIdentityInUse = "in_use"
+
+	// LabelValueIdentityQuarantined marks released identities which are
+	// quarantined before they can be allocated again
+	LabelValueIdentityQuarantined = "quarantined"
+
+	// LabelKVStoreOperation is the label for the kvstore operation
+	LabelKVStoreOperation = "operation"
+
+	// LabelKVStoreBackend is the label for the kvstore backend
+	LabelKVStoreBackend = "backend"
+
+	// LabelOutcome is the label for the outcome of an operation
+	LabelOutcome = "outcome"
+
+	// LabelEventType is the label for the type of an event
+	LabelEventType = "type"
+
+	// LabelMapName is the label for the name of a BPF map
+	LabelMapName = "map"
+
 	// Endpoint
 
 	// EndpointCount is a function used to collect this metric.
@@ -100,6 +126,167 @@ var (
 		Help:      "Number of times a policy import has failed",
 	})
 
+	// Identity
+
+	// IdentityCount is the number of identities seen by the last run of the
+	// identity garbage collector, tagged by state
+	IdentityCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "identity_count",
+		Help:      "Number of identities seen by the last garbage collector run, tagged by state",
+	},
+		[]string{"state"})
+
+	// IdentityReleased is the number of identities released by the identity
+	// garbage collector
+	IdentityReleased = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "identity_released",
+		Help:      "Number of identities released by the garbage collector",
+	})
+
+	// KVStore
+
+	// KVStoreOperationsDuration is the duration of kvstore operations,
+	// tagged by operation, backend and outcome
+	KVStoreOperationsDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "kvstore_operations_duration_seconds",
+		Help:      "Duration in seconds of kvstore operations",
+	},
+		[]string{LabelKVStoreOperation, LabelKVStoreBackend, LabelOutcome})
+
+	// KVStoreEventsQueueDuration is the duration a watch event received
+	// from the kvstore waited until it was delivered to the watcher
+	KVStoreEventsQueueDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "kvstore_events_queue_seconds",
+		Help:      "Duration in seconds a watch event waited to be delivered to the watcher",
+	},
+		[]string{LabelKVStoreBackend, LabelEventType})
+
+	// KVStoreEventsLag is the number of revisions a watch event lagged
+	// behind the latest known revision of the kvstore when it was
+	// delivered to the watcher
+	KVStoreEventsLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "kvstore_events_lag_revisions",
+		Help:      "Number of revisions a watch event lagged behind the kvstore when it was delivered to the watcher",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+	},
+		[]string{LabelKVStoreBackend})
+
+	// KVStoreDegraded is 1 if the kvstore has been unreachable for longer
+	// than the degraded threshold and the agent is running in degraded
+	// mode
+	KVStoreDegraded = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "kvstore_degraded",
+		Help:      "Whether the agent is running in degraded mode because the kvstore is unreachable",
+	})
+
+	// Load balancing
+
+	// LBHealthChecks is the number of health checks of service backends,
+	// tagged by outcome
+	LBHealthChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "lb_health_checks",
+		Help:      "Number of health checks of service backends",
+	},
+		[]string{LabelOutcome})
+
+	// LBBackendsUnhealthy is the number of service backends currently
+	// considered unhealthy by their health check
+	LBBackendsUnhealthy = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "lb_backends_unhealthy",
+		Help:      "Number of service backends failing their health check",
+	})
+
+	// LBBackendsDraining is the number of service backends removed from
+	// their service which still serve their established connections
+	LBBackendsDraining = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "lb_backends_draining",
+		Help:      "Number of service backends draining their established connections",
+	})
+
+	// Conntrack
+
+	// ConntrackEntries is the number of entries of the connection tracking
+	// maps after the last garbage collection run, tagged by map
+	ConntrackEntries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "conntrack_entries",
+		Help:      "Number of connection tracking entries after the last garbage collection run",
+	},
+		[]string{LabelMapName})
+
+	// ConntrackOccupancy is the highest fill level of the connection
+	// tracking maps after the last garbage collection run, tagged by map
+	ConntrackOccupancy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "conntrack_occupancy_ratio",
+		Help:      "Ratio of used to maximum connection tracking entries after the last garbage collection run",
+	},
+		[]string{LabelMapName})
+
+	// ConntrackGCEntriesScanned is the number of connection tracking
+	// entries visited by the garbage collector, tagged by map
+	ConntrackGCEntriesScanned = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "conntrack_gc_entries_scanned",
+		Help:      "Number of connection tracking entries visited by the garbage collector",
+	},
+		[]string{LabelMapName})
+
+	// ConntrackGCEntriesDeleted is the number of connection tracking
+	// entries removed by the garbage collector, tagged by map
+	ConntrackGCEntriesDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "conntrack_gc_entries_deleted",
+		Help:      "Number of connection tracking entries removed by the garbage collector",
+	},
+		[]string{LabelMapName})
+
+	// ConntrackGCDuration is the duration of garbage collection runs,
+	// tagged by map
+	ConntrackGCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "conntrack_gc_duration_seconds",
+		Help:      "Duration in seconds of connection tracking garbage collection runs",
+	},
+		[]string{LabelMapName})
+
+	// ConntrackGCInterval is the current interval between garbage
+	// collection runs
+	ConntrackGCInterval = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "conntrack_gc_interval_seconds",
+		Help:      "Interval in seconds between connection tracking garbage collection runs",
+	})
+
+	// NAT
+
+	// NATEntries is the number of entries of the masquerading tables after
+	// the last garbage collection run, tagged by map
+	NATEntries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "nat_entries",
+		Help:      "Number of masquerading entries after the last garbage collection run",
+	},
+		[]string{LabelMapName})
+
+	// NATGCEntriesDeleted is the number of masquerading entries removed by
+	// the garbage collector, tagged by map
+	NATGCEntriesDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "nat_gc_entries_deleted",
+		Help:      "Number of masquerading entries removed by the garbage collector",
+	},
+		[]string{LabelMapName})
+
 	// Events
 
 	// EventTS*is the time in seconds since epoch that we last recieved an
@@ -143,6 +330,28 @@ func init() {
 	MustRegister(PolicyRevision)
 	MustRegister(PolicyImportErrors)
 
+	MustRegister(IdentityCount)
+	MustRegister(IdentityReleased)
+
+	MustRegister(KVStoreOperationsDuration)
+	MustRegister(KVStoreEventsQueueDuration)
+	MustRegister(KVStoreEventsLag)
+	MustRegister(KVStoreDegraded)
+
+	MustRegister(LBHealthChecks)
+	MustRegister(LBBackendsUnhealthy)
+	MustRegister(LBBackendsDraining)
+
+	MustRegister(ConntrackEntries)
+	MustRegister(ConntrackOccupancy)
+	MustRegister(ConntrackGCEntriesScanned)
+	MustRegister(ConntrackGCEntriesDeleted)
+	MustRegister(ConntrackGCDuration)
+	MustRegister(ConntrackGCInterval)
+
+	MustRegister(NATEntries)
+	MustRegister(NATGCEntriesDeleted)
+
 	MustRegister(EventTSK8s)
 	MustRegister(EventTSContainerd)
 	MustRegister(EventTSAPI)
@@ -0,0 +1,155 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// LabelEndpoint is the label for the ID of an endpoint
+	LabelEndpoint = "endpoint"
+
+	// LabelIdentity is the label for a numeric security identity
+	LabelIdentity = "identity"
+
+	// LabelPort is the label for a destination port and its protocol
+	LabelPort = "port"
+
+	// LabelValueOther is the value of all labels of the series aggregating
+	// the policy entries exceeding the series limit
+	LabelValueOther = "other"
+)
+
+var (
+	policyStatsLabels = []string{LabelEndpoint, LabelIdentity, LabelPort}
+
+	policyEntryPackets = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "", "policy_entry_packets"),
+		"Number of packets allowed by an entry of the policy of an endpoint",
+		policyStatsLabels, nil)
+
+	policyEntryBytes = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "", "policy_entry_bytes"),
+		"Number of bytes allowed by an entry of the policy of an endpoint",
+		policyStatsLabels, nil)
+)
+
+// PolicyEntryStats are the hit counters of an entry of the policy of an
+// endpoint.
+type PolicyEntryStats struct {
+	Endpoint string
+	Identity string
+	Port     string
+	Packets  uint64
+	Bytes    uint64
+}
+
+type policyEntryKey struct {
+	endpoint, identity, port string
+}
+
+// policyStatsCollector exports the hit counters of the policy entries of all
+// endpoints. The counters are read on each scrape.
+//
+// The number of exported series is limited. Entries keep their series for as
+// long as they exist, new entries are assigned a series in the order of their
+// packet count while series are available. The counters of all remaining
+// entries are aggregated into a single series labeled LabelValueOther.
+type policyStatsCollector struct {
+	dump  func() []PolicyEntryStats
+	limit int
+
+	mutex    sync.Mutex
+	exported map[policyEntryKey]struct{}
+}
+
+// NewPolicyStatsCollector returns a collector exporting the policy hit
+// counters returned by dump in at most limit series per metric, in addition
+// to the series aggregating the remaining entries.
+func NewPolicyStatsCollector(dump func() []PolicyEntryStats, limit int) prometheus.Collector {
+	return &policyStatsCollector{
+		dump:     dump,
+		limit:    limit,
+		exported: map[policyEntryKey]struct{}{},
+	}
+}
+
+func (c *policyStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- policyEntryPackets
+	ch <- policyEntryBytes
+}
+
+// selectEntries returns the entries exported in their own series and the
+// aggregate of all other entries. Returns nil if there are no other entries.
+func (c *policyStatsCollector) selectEntries(entries []PolicyEntryStats) ([]PolicyEntryStats, *PolicyEntryStats) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	present := make(map[policyEntryKey]struct{}, len(entries))
+	for _, e := range entries {
+		present[policyEntryKey{e.Endpoint, e.Identity, e.Port}] = struct{}{}
+	}
+	for key := range c.exported {
+		if _, ok := present[key]; !ok {
+			delete(c.exported, key)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Packets > entries[j].Packets
+	})
+
+	var (
+		selected []PolicyEntryStats
+		other    *PolicyEntryStats
+	)
+	for _, e := range entries {
+		key := policyEntryKey{e.Endpoint, e.Identity, e.Port}
+		if _, ok := c.exported[key]; !ok && len(c.exported) >= c.limit {
+			if other == nil {
+				other = &PolicyEntryStats{
+					Endpoint: LabelValueOther,
+					Identity: LabelValueOther,
+					Port:     LabelValueOther,
+				}
+			}
+			other.Packets += e.Packets
+			other.Bytes += e.Bytes
+			continue
+		}
+		c.exported[key] = struct{}{}
+		selected = append(selected, e)
+	}
+
+	return selected, other
+}
+
+func (c *policyStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	selected, other := c.selectEntries(c.dump())
+	if other != nil {
+		selected = append(selected, *other)
+	}
+
+	for _, s := range selected {
+		ch <- prometheus.MustNewConstMetric(policyEntryPackets, prometheus.CounterValue,
+			float64(s.Packets), s.Endpoint, s.Identity, s.Port)
+		ch <- prometheus.MustNewConstMetric(policyEntryBytes, prometheus.CounterValue,
+			float64(s.Bytes), s.Endpoint, s.Identity, s.Port)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type MetricsSuite struct{}
+
+var _ = Suite(&MetricsSuite{})
+
+func (s *MetricsSuite) TestPolicyStatsLimit(c *C) {
+	collector := NewPolicyStatsCollector(nil, 2).(*policyStatsCollector)
+
+	entry := func(identity string, packets uint64) PolicyEntryStats {
+		return PolicyEntryStats{
+			Endpoint: "1",
+			Identity: identity,
+			Port:     "80/TCP",
+			Packets:  packets,
+			Bytes:    packets * 100,
+		}
+	}
+
+	// Series are assigned in the order of the packet count
+	selected, other := collector.selectEntries([]PolicyEntryStats{
+		entry("100", 1), entry("101", 3), entry("102", 2),
+	})
+	c.Assert(selected, DeepEquals, []PolicyEntryStats{entry("101", 3), entry("102", 2)})
+	c.Assert(other, DeepEquals, &PolicyEntryStats{
+		Endpoint: LabelValueOther,
+		Identity: LabelValueOther,
+		Port:     LabelValueOther,
+		Packets:  1,
+		Bytes:    100,
+	})
+
+	// Entries keep their series when overtaken by other entries
+	selected, other = collector.selectEntries([]PolicyEntryStats{
+		entry("100", 10), entry("101", 3), entry("102", 2),
+	})
+	c.Assert(selected, DeepEquals, []PolicyEntryStats{entry("101", 3), entry("102", 2)})
+	c.Assert(other.Packets, Equals, uint64(10))
+
+	// Series of removed entries are released
+	selected, other = collector.selectEntries([]PolicyEntryStats{
+		entry("100", 10), entry("102", 2),
+	})
+	c.Assert(selected, DeepEquals, []PolicyEntryStats{entry("100", 10), entry("102", 2)})
+	c.Assert(other, IsNil)
+
+	selected, other = collector.selectEntries(nil)
+	c.Assert(selected, IsNil)
+	c.Assert(other, IsNil)
+	c.Assert(collector.exported, HasLen, 0)
+}
@@ -89,6 +89,7 @@ const (
 	DbgCTCreated6
 	DbgSkipProxy
 	DbgL4Create
+	DbgEgressGateway
 )
 
 // must be in sync with <bpf/lib/conntrack.h>
@@ -230,7 +231,7 @@ func (n *DebugMsg) Dump(data []byte, prefix string) {
 	case DbgEncap:
 		fmt.Printf("Encapsulating to node %d (%#x) from seclabel %d\n", n.Arg1, n.Arg1, n.Arg2)
 	case DbgLxcFound:
-		fmt.Printf("Local container found ifindex %s seclabel %d\n", ifname(int(n.Arg1)), byteorder.NetworkToHost(uint16(n.Arg2)))
+		fmt.Printf("Local container found ifindex %s seclabel %d\n", ifname(int(n.Arg1)), byteorder.NetworkToHost(n.Arg2))
 	case DbgPolicyDenied:
 		fmt.Printf("Policy evaluation would deny packet from %d to %d\n", n.Arg1, n.Arg2)
 	case DbgCtLookup:
@@ -327,6 +328,8 @@ func (n *DebugMsg) Dump(data []byte, prefix string) {
 		fmt.Printf("Skipping proxy, tc_index is set=%x", n.Arg1)
 	case DbgL4Create:
 		fmt.Printf("Matched L4 policy; creating conntrack %s\n", l4CreateInfo(n))
+	case DbgEgressGateway:
+		fmt.Printf("Redirecting to egress gateway node %s, egress IP %s\n", ip4Str(n.Arg1), ip4Str(n.Arg2))
 	default:
 		fmt.Printf("Unknown message type=%d arg1=%d arg2=%d\n", n.SubType, n.Arg1, n.Arg2)
 	}
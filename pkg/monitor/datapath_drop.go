@@ -68,10 +68,11 @@ var errors = map[uint8]string{
 	154: "Error while correcting L4 checksum",
 	155: "CT: Map insertion failed",
 	156: "Invalid IPv6 extension header",
-	157: "IPv6 fragmentation not supported",
+	157: "Fragmentation not supported",
 	158: "Service backend not found",
 	159: "Policy denied (L4)",
 	160: "No tunnel/encapsulation endpoint (datapath BUG!)",
+	161: "No port available for NAT masquerading",
 }
 
 func dropReason(reason uint8) string {
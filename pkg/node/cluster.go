@@ -0,0 +1,71 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultClusterName is the name of the local cluster if no cluster
+	// name has been configured
+	DefaultClusterName = "default"
+
+	// MaxClusterID is the largest cluster ID which can be configured
+	MaxClusterID = 255
+)
+
+var (
+	clusterName = DefaultClusterName
+	clusterID   = 0
+)
+
+// SetClusterName sets the name of the cluster the local node is part of.
+//
+// Note: This function is designed to only be called during the bootstrapping
+// procedure of the agent.
+func SetClusterName(name string) error {
+	if name == "" {
+		return fmt.Errorf("cluster name must not be empty")
+	}
+
+	clusterName = name
+	return nil
+}
+
+// GetClusterName returns the name of the cluster the local node is part of
+func GetClusterName() string {
+	return clusterName
+}
+
+// SetClusterID sets the numeric ID of the cluster the local node is part of.
+// The ID must be unique across all clusters connected to each other.
+//
+// Note: This function is designed to only be called during the bootstrapping
+// procedure of the agent.
+func SetClusterID(id int) error {
+	if id < 0 || id > MaxClusterID {
+		return fmt.Errorf("invalid cluster ID %d, must be in range 0..%d", id, MaxClusterID)
+	}
+
+	clusterID = id
+	return nil
+}
+
+// GetClusterID returns the numeric ID of the cluster the local node is part
+// of
+func GetClusterID() int {
+	return clusterID
+}
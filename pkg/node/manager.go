@@ -50,6 +50,18 @@ type clusterConfiguation struct {
 	ciliumHostInitialized bool
 	usePerNodeRoutes      bool
 	auxPrefixes           []*net.IPNet
+	observers             []Observer
+}
+
+// Observer is notified about nodes being added to, updated in and removed
+// from the list of known nodes. Observers are called with the list of nodes
+// locked and must not call back into this package.
+type Observer interface {
+	// NodeUpdated is called when a node is added or updated
+	NodeUpdated(ni Identity, n Node)
+
+	// NodeDeleted is called when a node is removed
+	NodeDeleted(ni Identity, n Node)
 }
 
 var clusterConf = newClusterConfiguration()
@@ -74,6 +86,19 @@ func (cc *clusterConfiguation) addAuxPrefix(prefix *net.IPNet) {
 	cc.Unlock()
 }
 
+// RegisterObserver registers an observer to be notified about node changes.
+// The observer is notified about all nodes already known before this function
+// returns.
+func RegisterObserver(o Observer) {
+	clusterConf.Lock()
+	defer clusterConf.Unlock()
+
+	clusterConf.observers = append(clusterConf.observers, o)
+	for ni, n := range clusterConf.nodes {
+		o.NodeUpdated(ni, *n)
+	}
+}
+
 // GetNode returns the node with the given identity, if exists, from the nodes
 // map.
 func GetNode(ni Identity) *Node {
@@ -300,6 +325,10 @@ func UpdateNode(ni Identity, n *Node, routesTypes RouteType, ownAddr net.IP) {
 
 	clusterConf.nodes[ni] = n
 	clusterConf.replaceHostRoutes()
+
+	for _, o := range clusterConf.observers {
+		o.NodeUpdated(ni, *n)
+	}
 }
 
 // DeleteNode remove the node from the nodes' maps and / or the L3 routes to
@@ -324,6 +353,10 @@ func DeleteNode(ni Identity, routesTypes RouteType) {
 		}
 		delete(clusterConf.nodes, ni)
 		clusterConf.replaceHostRoutes()
+
+		for _, o := range clusterConf.observers {
+			o.NodeDeleted(ni, *n)
+		}
 	}
 }
 
@@ -0,0 +1,60 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type recordingObserver struct {
+	updated []Identity
+	deleted []Identity
+}
+
+func (o *recordingObserver) NodeUpdated(ni Identity, n Node) {
+	o.updated = append(o.updated, ni)
+}
+
+func (o *recordingObserver) NodeDeleted(ni Identity, n Node) {
+	o.deleted = append(o.deleted, ni)
+}
+
+func (s *NodeSuite) TestObserver(c *C) {
+	clusterConf.Lock()
+	prevNodes, prevObservers := clusterConf.nodes, clusterConf.observers
+	clusterConf.nodes, clusterConf.observers = map[Identity]*Node{}, nil
+	clusterConf.Unlock()
+	defer func() {
+		clusterConf.Lock()
+		clusterConf.nodes, clusterConf.observers = prevNodes, prevObservers
+		clusterConf.Unlock()
+	}()
+
+	node1 := Identity{Name: "node1"}
+	node2 := Identity{Name: "node2"}
+
+	UpdateNode(node1, &Node{Name: "node1"}, 0, nil)
+
+	o := &recordingObserver{}
+	RegisterObserver(o)
+	c.Assert(o.updated, DeepEquals, []Identity{node1})
+
+	UpdateNode(node2, &Node{Name: "node2"}, 0, nil)
+	c.Assert(o.updated, DeepEquals, []Identity{node1, node2})
+
+	DeleteNode(node1, 0)
+	DeleteNode(Identity{Name: "unknown"}, 0)
+	c.Assert(o.deleted, DeepEquals, []Identity{node1})
+}
@@ -25,10 +25,17 @@ import (
 // Identity represents the node identity of a node.
 type Identity struct {
 	Name string
+
+	// Cluster is the name of the cluster the node is part of. It is empty
+	// for nodes of the local cluster.
+	Cluster string
 }
 
 // String returns the string representation on NodeIdentity.
 func (nn Identity) String() string {
+	if nn.Cluster != "" {
+		return nn.Cluster + "/" + nn.Name
+	}
 	return nn.Name
 }
 
@@ -37,6 +44,12 @@ type Node struct {
 	Name        string
 	IPAddresses []Address
 
+	// Cluster is the name of the cluster the node is part of
+	Cluster string
+
+	// Zone is the failure domain the node is running in, empty if unknown
+	Zone string
+
 	// IPv4AllocCIDR if set, is the IPv4 address pool out of which the node
 	// allocates IPs for local endpoints from
 	IPv4AllocCIDR *net.IPNet
@@ -179,7 +192,8 @@ func (n *Node) GetModel(ipv4 bool) *models.NodeElement {
 // GetLocalNode returns the identity and node spec for the local node
 func GetLocalNode() (Identity, *Node) {
 	return Identity{Name: nodeName}, &Node{
-		Name: nodeName,
+		Name:    nodeName,
+		Cluster: GetClusterName(),
 		IPAddresses: []Address{
 			{
 				AddressType: v1.NodeInternalIP,
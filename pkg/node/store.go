@@ -0,0 +1,143 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// NodesPath is the path to where nodes are stored in the kvstore. Each
+	// node is stored as NodesPath/<cluster>/<node name>.
+	NodesPath = path.Join(kvstore.BaseKeyPrefix, "state", "nodes", "v1")
+
+	// nodeStoreControllers is the controller manager for all controllers
+	// of the node store
+	nodeStoreControllers = controller.NewManager()
+)
+
+const (
+	// nodePublishInterval is the interval in which the local node is
+	// written to the kvstore
+	nodePublishInterval = time.Minute
+)
+
+// nodeKey returns the kvstore key of a node
+func nodeKey(cluster, name string) string {
+	return path.Join(NodesPath, cluster, name)
+}
+
+// PublishLocalNode writes the local node to the kvstore so that it can be
+// discovered by nodes of other clusters. The key is attached to the kvstore
+// lease and rewritten periodically. The kvstore must be set up before calling
+// this function.
+func PublishLocalNode() {
+	nodeStoreControllers.UpdateController("node-store-publish-local",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				_, n := GetLocalNode()
+				b, err := json.Marshal(n)
+				if err != nil {
+					return err
+				}
+
+				return kvstore.Update(nodeKey(n.Cluster, n.Name), b, true)
+			},
+			RunInterval: nodePublishInterval,
+		},
+	)
+}
+
+// RemoteNodeWatcher watches the nodes of a remote cluster and installs the
+// routes to reach them
+type RemoteNodeWatcher struct {
+	cluster string
+	watcher *kvstore.Watcher
+	nodes   map[Identity]struct{}
+	done    chan struct{}
+}
+
+// WatchRemoteNodes starts watching the nodes stored by the remote cluster
+// with the given name in the kvstore reachable via the specified client. All
+// nodes are added to the local node manager with tunnel routes.
+func WatchRemoteNodes(cluster string, c kvstore.BackendOperations) *RemoteNodeWatcher {
+	r := &RemoteNodeWatcher{
+		cluster: cluster,
+		watcher: kvstore.ListAndWatchWithClient(c, "remote-nodes-"+cluster, path.Join(NodesPath, cluster), 128),
+		nodes:   map[Identity]struct{}{},
+		done:    make(chan struct{}),
+	}
+
+	go r.run()
+
+	return r
+}
+
+func (r *RemoteNodeWatcher) run() {
+	defer close(r.done)
+	scopedLog := log.WithField(logfields.ClusterName, r.cluster)
+
+	for event := range r.watcher.Events {
+		switch event.Typ {
+		case kvstore.EventTypeCreate, kvstore.EventTypeModify:
+			n := &Node{}
+			if err := json.Unmarshal(event.Value, n); err != nil {
+				scopedLog.WithError(err).WithField("key", event.Key).Warning("Unable to decode remote node")
+				continue
+			}
+
+			if n.Cluster != r.cluster {
+				scopedLog.WithFields(logrus.Fields{
+					logfields.NodeName: n.Name,
+					"nodeCluster":      n.Cluster,
+				}).Warning("Ignoring remote node of different cluster")
+				continue
+			}
+
+			ni := Identity{Name: n.Name, Cluster: n.Cluster}
+			r.nodes[ni] = struct{}{}
+			UpdateNode(ni, n, TunnelRoute, nil)
+
+			scopedLog.WithField(logfields.NodeName, n.Name).Debug("Updated remote node")
+
+		case kvstore.EventTypeDelete:
+			ni := Identity{Name: path.Base(event.Key), Cluster: r.cluster}
+			delete(r.nodes, ni)
+			DeleteNode(ni, TunnelRoute)
+
+			scopedLog.WithField(logfields.NodeName, ni.Name).Debug("Removed remote node")
+		}
+	}
+
+	// The watcher has been stopped, remove all nodes of the cluster
+	for ni := range r.nodes {
+		DeleteNode(ni, TunnelRoute)
+	}
+}
+
+// Stop stops watching the remote nodes. All nodes of the remote cluster are
+// removed from the local node manager before Stop returns.
+func (r *RemoteNodeWatcher) Stop() {
+	r.watcher.Stop()
+	<-r.done
+}
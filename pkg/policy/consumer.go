@@ -107,7 +107,7 @@ func (c *Consumable) AddMap(m *policymap.PolicyMap) {
 	}
 
 	// Check if map is already associated with this consumable
-	if _, ok := c.Maps[m.Fd]; ok {
+	if _, ok := c.Maps[m.GetFd()]; ok {
 		return
 	}
 
@@ -115,7 +115,7 @@ func (c *Consumable) AddMap(m *policymap.PolicyMap) {
 		"policymap":  m,
 		"consumable": c,
 	}).Debug("Adding policy map to consumable")
-	c.Maps[m.Fd] = m
+	c.Maps[m.GetFd()] = m
 
 	// Populate the new map with the already established consumers of
 	// this consumable
@@ -163,7 +163,7 @@ func (c *Consumable) delete() {
 func (c *Consumable) RemoveMap(m *policymap.PolicyMap) {
 	if m != nil {
 		c.Mutex.Lock()
-		delete(c.Maps, m.Fd)
+		delete(c.Maps, m.GetFd())
 		log.WithFields(logrus.Fields{
 			"policymap":  m,
 			"consumable": c,
@@ -26,6 +26,8 @@ import (
 	"github.com/cilium/cilium/pkg/kvstore/allocator"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/node"
 	"github.com/cilium/cilium/pkg/u8proto"
 
 	"github.com/sirupsen/logrus"
@@ -41,11 +43,26 @@ const (
 	// InvalidIdentity is the identity assigned if the identity is invalid
 	// or not determined yet
 	InvalidIdentity = NumericIdentity(0)
+
+	// DefaultIdentityQuarantinePeriod is the default duration for which a
+	// released identity is quarantined before it can be allocated again
+	DefaultIdentityQuarantinePeriod = 15 * time.Minute
+
+	// ClusterIDShift is the number of bits the cluster ID is shifted by to
+	// derive the range of identities allocated by a cluster. The identity
+	// range of a cluster is ClusterID<<16 | [256..65535] which allows
+	// identities of up to 256 clusters to fit into 24 bits.
+	ClusterIDShift = 16
 )
 
 var (
 	// IdentitiesPath is the to where identities are stored
 	IdentitiesPath = path.Join(kvstore.BaseKeyPrefix, "state", "identities", "v1")
+
+	// IdentityQuarantinePeriod is the duration for which a released
+	// identity is quarantined before it can be allocated again. Must be
+	// set before InitIdentityAllocator() is called.
+	IdentityQuarantinePeriod = DefaultIdentityQuarantinePeriod
 )
 
 // NumericIdentity represents an identity of an entity to which consumer policy
@@ -77,6 +94,11 @@ func (id NumericIdentity) Uint32() uint32 {
 	return uint32(id)
 }
 
+// ClusterLocal returns the identity without the cluster ID bits
+func (id NumericIdentity) ClusterLocal() NumericIdentity {
+	return id & (1<<ClusterIDShift - 1)
+}
+
 // SecurityIDContexts maps a security identity to a L4RuleContexts
 type SecurityIDContexts map[NumericIdentity]L4RuleContexts
 
@@ -287,6 +309,7 @@ func (gi globalIdentity) PutKey(v string) (allocator.AllocatorKey, error) {
 var (
 	setupOnce         sync.Once
 	identityAllocator *allocator.Allocator
+	identityOwner     IdentityAllocatorOwner
 )
 
 // IdentityAllocatorOwner is the interface the owner of an identity allocator
@@ -298,36 +321,73 @@ type IdentityAllocatorOwner interface {
 
 	// GetSuffix must return the node specific suffix to use
 	GetNodeSuffix() string
+
+	// IdentityReallocated will be called when an identity allocated while
+	// the kvstore was unreachable has been rejected and replaced with a
+	// new identity. All users of the old identity must resolve their
+	// identity again.
+	IdentityReallocated(oldID, newID NumericIdentity)
 }
 
 // InitIdentityAllocator creates the the identity allocator. Only the first
 // invocation of this function will have an effect.
 func InitIdentityAllocator(owner IdentityAllocatorOwner) {
 	setupOnce.Do(func() {
-		minID := allocator.ID(MinimalNumericIdentity)
-		maxID := allocator.ID(^uint16(0))
+		clusterID := allocator.ID(node.GetClusterID()) << ClusterIDShift
+		minID := clusterID | allocator.ID(MinimalNumericIdentity)
+		maxID := clusterID | allocator.ID(^uint16(0))
 		a, err := allocator.NewAllocator(IdentitiesPath, globalIdentity{},
 			allocator.WithMax(maxID), allocator.WithMin(minID),
-			allocator.WithSuffix(owner.GetNodeSuffix()))
+			allocator.WithSuffix(owner.GetNodeSuffix()),
+			allocator.WithQuarantine(IdentityQuarantinePeriod),
+			allocator.WithGCObserver(updateIdentityMetrics),
+			allocator.WithReallocationObserver(func(key allocator.AllocatorKey, oldID, newID allocator.ID) {
+				owner.IdentityReallocated(NumericIdentity(oldID), NumericIdentity(newID))
+			}))
 		if err != nil {
 			log.WithError(err).Fatal("Unable to initialize identity allocator")
 		}
 
 		identityAllocator = a
+		identityOwner = owner
+
+		watchWellKnownConflicts(a)
 
 		go identityWatcher(owner)
 	})
 }
 
+// LocalClusterIdentityBits returns the bits of the local cluster ID which are
+// part of all identities allocated by the local cluster
+func LocalClusterIdentityBits() uint32 {
+	return uint32(node.GetClusterID()) << ClusterIDShift
+}
+
+// IsLocalClusterIdentity returns true if the numeric identity is within the
+// range of identities allocated by the local cluster
+func IsLocalClusterIdentity(id NumericIdentity) bool {
+	return int(id>>ClusterIDShift) == node.GetClusterID()
+}
+
 // AllocateIdentity allocates an identity described by the specified labels. If
-// an identity for the specified set of labels already exist, the identity is
-// re-used and reference counting is performed, otherwise a new identity is
-// allocated via the kvstore.
+// the labels are configured as well-known identity, the well-known identity is
+// returned. If an identity for the specified set of labels already exist, the
+// identity is re-used and reference counting is performed, otherwise a new
+// identity is allocated via the kvstore.
 func AllocateIdentity(lbls labels.Labels) (*Identity, bool, error) {
 	log.WithFields(logrus.Fields{
 		logfields.IdentityLabels: lbls.String(),
 	}).Debug("Resolving identity")
 
+	if identity := LookupWellKnownIdentity(lbls); identity != nil {
+		log.WithFields(logrus.Fields{
+			logfields.Identity:       identity.ID,
+			logfields.IdentityLabels: lbls.String(),
+		}).Debug("Resolved well-known identity")
+
+		return NewIdentity(identity.ID, lbls), false, nil
+	}
+
 	id, isNew, err := identityAllocator.Allocate(globalIdentity{lbls})
 	if err != nil {
 		return nil, false, err
@@ -346,6 +406,10 @@ func AllocateIdentity(lbls labels.Labels) (*Identity, bool, error) {
 // This function will first search through the local cache and fall back to
 // querying the kvstore.
 func LookupIdentity(lbls labels.Labels) *Identity {
+	if identity := LookupWellKnownIdentity(lbls); identity != nil {
+		return identity
+	}
+
 	if identityAllocator == nil {
 		return nil
 	}
@@ -364,7 +428,22 @@ func LookupIdentity(lbls labels.Labels) *Identity {
 
 // LookupIdentityByID returns the identity by ID. This function will first
 // search through the local cache and fall back to querying the kvstore.
+// Identities of remote clusters are resolved via the remote identity cache
+// first.
 func LookupIdentityByID(id NumericIdentity) *Identity {
+	if identity := LookupWellKnownIdentityByID(id); identity != nil {
+		return identity
+	}
+
+	if !IsLocalClusterIdentity(id) {
+		if identity := lookupRemoteIdentityByID(id); identity != nil {
+			return identity
+		}
+
+		// Identities allocated before the cluster ID was changed remain
+		// in the local kvstore outside of the local identity range
+	}
+
 	if identityAllocator == nil {
 		return nil
 	}
@@ -384,6 +463,11 @@ func LookupIdentityByID(id NumericIdentity) *Identity {
 // Release is the reverse operation of AllocateIdentity() and releases the
 // identity again. This function may result in kvstore operations.
 func (id *Identity) Release() error {
+	// Well-known identities are never allocated in the kvstore
+	if LookupWellKnownIdentityByID(id.ID) != nil {
+		return nil
+	}
+
 	return identityAllocator.Release(globalIdentity{id.Labels})
 }
 
@@ -399,6 +483,14 @@ func GetIdentityCache() IdentityCache {
 		cache[NumericIdentity(id)] = gi.LabelArray()
 	})
 
+	wellKnown.forEach(func(identity *Identity) {
+		cache[identity.ID] = identity.Labels.LabelArray()
+	})
+
+	forEachRemoteIdentity(func(id NumericIdentity, lbls labels.Labels) {
+		cache[id] = lbls.LabelArray()
+	})
+
 	return cache
 }
 
@@ -414,9 +506,76 @@ func GetIdentities() []*models.Identity {
 
 	})
 
+	wellKnown.forEach(func(identity *Identity) {
+		identities = append(identities, identity.GetModel())
+	})
+
+	forEachRemoteIdentity(func(id NumericIdentity, lbls labels.Labels) {
+		identities = append(identities, NewIdentity(id, lbls).GetModel())
+	})
+
 	return identities
 }
 
+func updateIdentityMetrics(stats *allocator.GCStats) {
+	metrics.IdentityCount.WithLabelValues(metrics.LabelValueIdentityAllocated).Set(float64(stats.Allocated))
+	metrics.IdentityCount.WithLabelValues(metrics.LabelValueIdentityInUse).Set(float64(stats.InUse))
+	metrics.IdentityCount.WithLabelValues(metrics.LabelValueIdentityQuarantined).Set(float64(stats.Quarantined))
+	metrics.IdentityReleased.Add(float64(len(stats.Released)))
+}
+
+// IdentityGCResult is the result of a garbage collector run on the identity
+// allocator
+type IdentityGCResult struct {
+	// Allocated is the number of identities allocated in the kvstore
+	Allocated int `json:"allocated"`
+
+	// InUse is the number of identities used by at least one node
+	InUse int `json:"in-use"`
+
+	// Released is the list of identities released by the garbage
+	// collector
+	Released []*Identity `json:"released"`
+
+	// Quarantined is the number of identities in quarantine
+	Quarantined int `json:"quarantined"`
+
+	// QuarantinePeriod is the quarantine period of released identities
+	// as configured on the agents
+	QuarantinePeriod string `json:"quarantine-period"`
+}
+
+// RunIdentityGC performs a single garbage collector run on the identities
+// stored in the kvstore. It does not require the identity allocator to be
+// initialized. In dry-run mode, identities are reported but not released.
+// The quarantine period is the one configured on the agents, or
+// DefaultIdentityQuarantinePeriod if no agent is running.
+func RunIdentityGC(dryRun bool) (*IdentityGCResult, error) {
+	stats, err := allocator.RunGC(IdentitiesPath, DefaultIdentityQuarantinePeriod, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IdentityGCResult{
+		Allocated:        stats.Allocated,
+		InUse:            stats.InUse,
+		Released:         []*Identity{},
+		Quarantined:      stats.Quarantined,
+		QuarantinePeriod: stats.QuarantinePeriod.String(),
+	}
+
+	for id, key := range stats.Released {
+		gi, err := globalIdentity{}.PutKey(key)
+		if err != nil {
+			log.WithError(err).WithField(logfields.Identity, id).Warning("Unable to decode identity labels")
+			continue
+		}
+		result.Released = append(result.Released, NewIdentity(NumericIdentity(id), gi.(globalIdentity).Labels))
+	}
+
+	return result, nil
+}
+
 func identityWatcher(owner IdentityAllocatorOwner) {
 	for {
 		event := <-identityAllocator.Events
@@ -41,6 +41,12 @@ func (s *PolicyTestSuite) TestReservedID(c *C) {
 	c.Assert(unknown.String(), Equals, "700")
 }
 
+func (s *PolicyTestSuite) TestClusterLocal(c *C) {
+	c.Assert(NumericIdentity(700).ClusterLocal(), Equals, NumericIdentity(700))
+	c.Assert(NumericIdentity(5<<ClusterIDShift|700).ClusterLocal(), Equals, NumericIdentity(700))
+	c.Assert(NumericIdentity(255<<ClusterIDShift|0xffff).ClusterLocal(), Equals, NumericIdentity(0xffff))
+}
+
 type IdentityAllocatorSuite struct{}
 
 type IdentityAllocatorEtcdSuite struct {
@@ -73,6 +79,8 @@ func (d dummyOwner) GetNodeSuffix() string {
 	return "foo"
 }
 
+func (d dummyOwner) IdentityReallocated(oldID, newID NumericIdentity) {}
+
 func (ias *IdentityAllocatorSuite) TestAllocator(c *C) {
 	lbls1 := labels.NewLabelsFromSortedList("id=foo;user=anna;blah=%%//!!")
 	lbls2 := labels.NewLabelsFromSortedList("id=bar;user=anna")
@@ -0,0 +1,249 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"path"
+	"time"
+
+	"github.com/cilium/cilium/pkg/k8s/apis/cilium.io"
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RemoteIdentityWatcher watches the identities allocated in the kvstore of a
+// remote cluster. All identities of the remote cluster are labeled with the
+// cluster label so they can be selected by policy.
+type RemoteIdentityWatcher struct {
+	cluster string
+	client  kvstore.BackendOperations
+	watcher *kvstore.Watcher
+	done    chan struct{}
+
+	mutex      lock.RWMutex
+	identities map[NumericIdentity]labels.Labels
+}
+
+// remotePolicyUpdateDelay is the delay used to coalesce changes to remote
+// identities into a single policy recalculation
+const remotePolicyUpdateDelay = time.Second
+
+type remoteIdentities struct {
+	mutex    lock.RWMutex
+	clusters map[string]*RemoteIdentityWatcher
+
+	// updateMutex protects updatePending
+	updateMutex   lock.Mutex
+	updatePending bool
+}
+
+var remote = remoteIdentities{
+	clusters: map[string]*RemoteIdentityWatcher{},
+}
+
+// WatchRemoteIdentities starts watching the identities of the remote cluster
+// with the given name in the kvstore reachable via the specified client. Any
+// previous watcher for the same cluster is stopped.
+func WatchRemoteIdentities(cluster string, c kvstore.BackendOperations) *RemoteIdentityWatcher {
+	r := &RemoteIdentityWatcher{
+		cluster:    cluster,
+		client:     c,
+		watcher:    kvstore.ListAndWatchWithClient(c, "remote-identities-"+cluster, path.Join(IdentitiesPath, "id"), 512),
+		identities: map[NumericIdentity]labels.Labels{},
+		done:       make(chan struct{}),
+	}
+
+	remote.mutex.Lock()
+	old := remote.clusters[cluster]
+	remote.clusters[cluster] = r
+	remote.mutex.Unlock()
+
+	if old != nil {
+		old.stopWatcher()
+	}
+
+	go r.run()
+
+	return r
+}
+
+// Stop stops watching the remote identities. All identities of the remote
+// cluster are removed before Stop returns.
+func (r *RemoteIdentityWatcher) Stop() {
+	remote.mutex.Lock()
+	if remote.clusters[r.cluster] == r {
+		delete(remote.clusters, r.cluster)
+	}
+	remote.mutex.Unlock()
+
+	r.stopWatcher()
+}
+
+func (r *RemoteIdentityWatcher) stopWatcher() {
+	r.watcher.Stop()
+	<-r.done
+}
+
+// NumIdentities returns the number of identities known of the remote cluster
+func (r *RemoteIdentityWatcher) NumIdentities() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return len(r.identities)
+}
+
+// decodeLabels decodes the value of a master key of the identity allocator
+// and adds the cluster label
+func (r *RemoteIdentityWatcher) decodeLabels(value []byte) (labels.Labels, error) {
+	b, err := r.client.Decode(string(value))
+	if err != nil {
+		return nil, err
+	}
+
+	lbls := labels.NewLabelsFromSortedList(string(b))
+	lbl := labels.NewLabel(ciliumio.PolicyLabelCluster, r.cluster, labels.LabelSourceK8s)
+	lbls[lbl.Key] = lbl
+
+	return lbls, nil
+}
+
+func (r *RemoteIdentityWatcher) run() {
+	defer close(r.done)
+	scopedLog := log.WithField(logfields.ClusterName, r.cluster)
+	listDone := false
+
+	for event := range r.watcher.Events {
+		if event.Typ == kvstore.EventTypeListDone {
+			listDone = true
+			triggerRemotePolicyUpdates()
+			continue
+		}
+
+		id, err := ParseNumericIdentity(path.Base(event.Key))
+		if err != nil {
+			scopedLog.WithError(err).WithField("key", event.Key).Warning("Invalid remote identity key")
+			continue
+		}
+
+		if IsLocalClusterIdentity(id) {
+			scopedLog.WithField(logfields.Identity, id).
+				Warning("Ignoring remote identity overlapping with local identity range. Is the cluster ID unique?")
+			continue
+		}
+
+		switch event.Typ {
+		case kvstore.EventTypeCreate, kvstore.EventTypeModify:
+			lbls, err := r.decodeLabels(event.Value)
+			if err != nil {
+				scopedLog.WithError(err).WithField(logfields.Identity, id).Warning("Unable to decode remote identity")
+				continue
+			}
+
+			r.mutex.Lock()
+			r.identities[id] = lbls
+			r.mutex.Unlock()
+
+			scopedLog.WithFields(logrus.Fields{
+				logfields.Identity:       id,
+				logfields.IdentityLabels: lbls.String(),
+			}).Debug("Updated remote identity")
+
+		case kvstore.EventTypeDelete:
+			r.mutex.Lock()
+			delete(r.identities, id)
+			r.mutex.Unlock()
+
+			scopedLog.WithField(logfields.Identity, id).Debug("Removed remote identity")
+		}
+
+		if listDone {
+			triggerRemotePolicyUpdates()
+		}
+	}
+
+	// The watcher has been stopped, all identities of the remote cluster
+	// are no longer known
+	r.mutex.Lock()
+	r.identities = map[NumericIdentity]labels.Labels{}
+	r.mutex.Unlock()
+
+	triggerRemotePolicyUpdates()
+}
+
+// triggerRemotePolicyUpdates triggers a policy recalculation via the owner of
+// the identity allocator after remote identities have changed. All changes
+// occurring within remotePolicyUpdateDelay are coalesced into a single
+// recalculation.
+func triggerRemotePolicyUpdates() {
+	remote.updateMutex.Lock()
+	defer remote.updateMutex.Unlock()
+
+	if remote.updatePending {
+		return
+	}
+	remote.updatePending = true
+
+	time.AfterFunc(remotePolicyUpdateDelay, func() {
+		remote.updateMutex.Lock()
+		remote.updatePending = false
+		remote.updateMutex.Unlock()
+
+		if identityOwner != nil {
+			identityOwner.TriggerPolicyUpdates(true)
+		}
+	})
+}
+
+func (r *RemoteIdentityWatcher) lookupByID(id NumericIdentity) labels.Labels {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.identities[id]
+}
+
+func (r *RemoteIdentityWatcher) forEach(cb func(NumericIdentity, labels.Labels)) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for id, lbls := range r.identities {
+		cb(id, lbls)
+	}
+}
+
+// lookupRemoteIdentityByID returns the identity of any remote cluster with the
+// specified numeric identity or nil
+func lookupRemoteIdentityByID(id NumericIdentity) *Identity {
+	remote.mutex.RLock()
+	defer remote.mutex.RUnlock()
+
+	for _, r := range remote.clusters {
+		if lbls := r.lookupByID(id); lbls != nil {
+			return NewIdentity(id, lbls)
+		}
+	}
+
+	return nil
+}
+
+// forEachRemoteIdentity calls cb for all identities of all remote clusters
+func forEachRemoteIdentity(cb func(NumericIdentity, labels.Labels)) {
+	remote.mutex.RLock()
+	defer remote.mutex.RUnlock()
+
+	for _, r := range remote.clusters {
+		r.forEach(cb)
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/kvstore/allocator"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+)
+
+const (
+	// WellKnownCfgFileVersion is the version of the well-known identities
+	// configuration file format
+	WellKnownCfgFileVersion = 1
+
+	// MinimalWellKnownIdentity is the lowest numeric identity which can be
+	// assigned to a well-known identity
+	MinimalWellKnownIdentity = NumericIdentity(128)
+
+	// MaximalWellKnownIdentity is the highest numeric identity which can
+	// be assigned to a well-known identity
+	MaximalWellKnownIdentity = MinimalNumericIdentity - 1
+)
+
+// WellKnownIdentity is a set of labels with a fixed numeric identity
+type WellKnownIdentity struct {
+	// ID is the numeric identity assigned to the labels
+	ID NumericIdentity `json:"id"`
+
+	// Labels is the list of labels in string representation
+	Labels []string `json:"labels"`
+}
+
+// wellKnownCfg is the well-known identities configuration file, e.g.:
+//
+// {
+//   "version": 1,
+//   "identities": [
+//     {
+//       "id": 128,
+//       "labels": [
+//         "k8s:k8s-app=kube-dns",
+//         "k8s:io.kubernetes.pod.namespace=kube-system"
+//       ]
+//     }
+//   ]
+// }
+type wellKnownCfg struct {
+	Version    int                  `json:"version"`
+	Identities []*WellKnownIdentity `json:"identities"`
+}
+
+type wellKnownIdentities struct {
+	mutex lock.RWMutex
+
+	// byID maps the numeric identity to the identity
+	byID map[NumericIdentity]*Identity
+
+	// byKey maps the sorted label list to the identity
+	byKey map[string]*Identity
+}
+
+var wellKnown = newWellKnownIdentities()
+
+func newWellKnownIdentities() *wellKnownIdentities {
+	return &wellKnownIdentities{
+		byID:  map[NumericIdentity]*Identity{},
+		byKey: map[string]*Identity{},
+	}
+}
+
+func (w *wellKnownIdentities) add(wki *WellKnownIdentity) error {
+	if wki.ID < MinimalWellKnownIdentity || wki.ID > MaximalWellKnownIdentity {
+		return fmt.Errorf("identity %d is outside of the well-known identity range %d-%d",
+			wki.ID, MinimalWellKnownIdentity, MaximalWellKnownIdentity)
+	}
+
+	if len(wki.Labels) == 0 {
+		return fmt.Errorf("identity %d has no labels", wki.ID)
+	}
+
+	lbls := labels.NewLabelsFromModel(wki.Labels)
+	for _, lbl := range lbls {
+		if lbl.Source == labels.LabelSourceReserved {
+			return fmt.Errorf("identity %d: reserved label %s cannot be used", wki.ID, lbl)
+		}
+	}
+
+	if existing, ok := w.byID[wki.ID]; ok {
+		return fmt.Errorf("identity %d is assigned to multiple label sets: %s and %s",
+			wki.ID, existing.Labels, lbls)
+	}
+
+	key := string(lbls.SortedList())
+	if existing, ok := w.byKey[key]; ok {
+		return fmt.Errorf("labels %s are assigned to multiple identities: %d and %d",
+			lbls, existing.ID, wki.ID)
+	}
+
+	identity := NewIdentity(wki.ID, lbls)
+	w.byID[wki.ID] = identity
+	w.byKey[key] = identity
+
+	return nil
+}
+
+func (w *wellKnownIdentities) lookup(lbls labels.Labels) *Identity {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.byKey[string(lbls.SortedList())]
+}
+
+func (w *wellKnownIdentities) lookupByID(id NumericIdentity) *Identity {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.byID[id]
+}
+
+func (w *wellKnownIdentities) isEmpty() bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return len(w.byID) == 0
+}
+
+func (w *wellKnownIdentities) forEach(cb func(*Identity)) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	for _, identity := range w.byID {
+		cb(identity)
+	}
+}
+
+func readWellKnownCfgFrom(fileName string) (*wellKnownIdentities, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cfg := wellKnownCfg{}
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Version != WellKnownCfgFileVersion {
+		return nil, fmt.Errorf("unsupported version %d", cfg.Version)
+	}
+
+	w := newWellKnownIdentities()
+	for _, wki := range cfg.Identities {
+		if err := w.add(wki); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+// ParseWellKnownIdentityCfg reads the well-known identities from the specified
+// configuration file. The configuration is rejected if identities are outside
+// of the well-known identity range or if an identity or label set is assigned
+// more than once. Must be called before InitIdentityAllocator().
+func ParseWellKnownIdentityCfg(file string) error {
+	if file == "" {
+		return nil
+	}
+
+	w, err := readWellKnownCfgFrom(file)
+	if err != nil {
+		return fmt.Errorf("Unable to read well-known identities file: %s", err)
+	}
+
+	wellKnown = w
+
+	log.Info("Well-known identities:")
+	wellKnown.forEach(func(identity *Identity) {
+		log.Infof(" - %d: %s", identity.ID, identity.Labels)
+	})
+
+	return nil
+}
+
+// LookupWellKnownIdentity returns the well-known identity for the specified
+// labels or nil if the labels have no well-known identity
+func LookupWellKnownIdentity(lbls labels.Labels) *Identity {
+	return wellKnown.lookup(lbls)
+}
+
+// LookupWellKnownIdentityByID returns the well-known identity with the
+// specified numeric identity or nil
+func LookupWellKnownIdentityByID(id NumericIdentity) *Identity {
+	return wellKnown.lookupByID(id)
+}
+
+// wellKnownConflictsController is the name of the controller reporting
+// conflicts between well-known identities and identities in the kvstore
+const wellKnownConflictsController = "well-known-identity-conflicts"
+
+var wellKnownControllers = controller.NewManager()
+
+// checkWellKnownConflicts returns an error listing the label sets of
+// well-known identities which have been allocated a different identity in
+// the kvstore. forEach iterates over the identities of the kvstore.
+// Endpoints using such an identity are moved to the well-known identity
+// when they resolve their identity the next time.
+func checkWellKnownConflicts(forEach func(allocator.RangeFunc)) error {
+	conflicts := []string{}
+	forEach(func(id allocator.ID, val allocator.AllocatorKey) {
+		gi, ok := val.(globalIdentity)
+		if !ok {
+			return
+		}
+
+		if identity := LookupWellKnownIdentity(gi.Labels); identity != nil {
+			conflicts = append(conflicts, fmt.Sprintf("%s is allocated to identity %d instead of %d",
+				gi.Labels.String(), id, identity.ID))
+		}
+	})
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	sort.Strings(conflicts)
+	return fmt.Errorf("labels of well-known identities are allocated to different identities in the kvstore: %s",
+		strings.Join(conflicts, "; "))
+}
+
+// watchWellKnownConflicts periodically checks the identities of the
+// allocator for conflicts with well-known identities. Conflicts are
+// reported as failure of the controller so that they show up in
+// "cilium status".
+func watchWellKnownConflicts(a *allocator.Allocator) {
+	if wellKnown.isEmpty() {
+		return
+	}
+
+	wellKnownControllers.UpdateController(wellKnownConflictsController,
+		controller.ControllerParams{
+			DoFunc: func() error {
+				return checkWellKnownConflicts(a.ForeachCache)
+			},
+			RunInterval: time.Minute,
+		})
+}
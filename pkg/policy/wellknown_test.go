@@ -0,0 +1,158 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/cilium/cilium/pkg/k8s/apis/cilium.io"
+	"github.com/cilium/cilium/pkg/kvstore/allocator"
+	"github.com/cilium/cilium/pkg/labels"
+
+	. "gopkg.in/check.v1"
+)
+
+func writeWellKnownCfg(c *C, content string) string {
+	f, err := ioutil.TempFile("", "wellknown")
+	c.Assert(err, IsNil)
+	_, err = f.WriteString(content)
+	c.Assert(err, IsNil)
+	c.Assert(f.Close(), IsNil)
+	return f.Name()
+}
+
+func (s *PolicyTestSuite) TestWellKnownIdentityCfg(c *C) {
+	file := writeWellKnownCfg(c, `{
+  "version": 1,
+  "identities": [
+    {"id": 128, "labels": ["k8s:k8s-app=kube-dns", "k8s:io.kubernetes.pod.namespace=kube-system"]},
+    {"id": 129, "labels": ["k8s:app=ingress"]}
+  ]
+}`)
+	defer os.Remove(file)
+
+	oldWellKnown := wellKnown
+	defer func() { wellKnown = oldWellKnown }()
+
+	c.Assert(ParseWellKnownIdentityCfg(file), IsNil)
+
+	lbls := labels.NewLabelsFromModel([]string{"k8s:io.kubernetes.pod.namespace=kube-system", "k8s:k8s-app=kube-dns"})
+	identity := LookupWellKnownIdentity(lbls)
+	c.Assert(identity, Not(IsNil))
+	c.Assert(identity.ID, Equals, NumericIdentity(128))
+
+	identity = LookupWellKnownIdentityByID(NumericIdentity(129))
+	c.Assert(identity, Not(IsNil))
+	c.Assert(identity.Labels, DeepEquals, labels.NewLabelsFromModel([]string{"k8s:app=ingress"}))
+
+	c.Assert(LookupWellKnownIdentity(labels.NewLabelsFromModel([]string{"k8s:k8s-app=kube-dns"})), IsNil)
+	c.Assert(LookupWellKnownIdentityByID(NumericIdentity(130)), IsNil)
+
+	// well-known identities are resolved without an identity allocator
+	identity, isNew, err := AllocateIdentity(lbls)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	c.Assert(identity.ID, Equals, NumericIdentity(128))
+	c.Assert(identity.Release(), IsNil)
+}
+
+func (s *PolicyTestSuite) TestWellKnownIdentityLocalPod(c *C) {
+	file := writeWellKnownCfg(c, `{
+		"version": 1,
+		"identities": [{"id": 128, "labels": ["k8s:k8s-app=kube-dns", "k8s:io.kubernetes.pod.namespace=kube-system"]}]}`)
+	defer os.Remove(file)
+
+	oldWellKnown := wellKnown
+	defer func() { wellKnown = oldWellKnown }()
+	c.Assert(ParseWellKnownIdentityCfg(file), IsNil)
+
+	// Labels of a local kube-dns pod as derived by the workload runtimes
+	podLabels := map[string]string{
+		"k8s-app":                  "kube-dns",
+		ciliumio.PodNamespaceLabel: "kube-system",
+	}
+	lbls := labels.Map2Labels(podLabels, labels.LabelSourceK8s)
+
+	identity, isNew, err := AllocateIdentity(lbls)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	c.Assert(identity.ID, Equals, NumericIdentity(128))
+
+	// kube-dns of a remote cluster is qualified with the cluster label and
+	// does not resolve to the well-known identity of the local cluster
+	lbl := labels.NewLabel(ciliumio.PolicyLabelCluster, "cluster1", labels.LabelSourceK8s)
+	lbls[lbl.Key] = lbl
+	c.Assert(LookupWellKnownIdentity(lbls), IsNil)
+}
+
+func (s *PolicyTestSuite) TestWellKnownIdentityCfgConflicts(c *C) {
+	oldWellKnown := wellKnown
+	defer func() { wellKnown = oldWellKnown }()
+
+	invalid := []string{
+		// unsupported version
+		`{"version": 2, "identities": []}`,
+		// outside of the well-known range
+		`{"version": 1, "identities": [{"id": 1, "labels": ["k8s:app=foo"]}]}`,
+		`{"version": 1, "identities": [{"id": 256, "labels": ["k8s:app=foo"]}]}`,
+		// no labels
+		`{"version": 1, "identities": [{"id": 128, "labels": []}]}`,
+		// reserved labels
+		`{"version": 1, "identities": [{"id": 128, "labels": ["reserved:host"]}]}`,
+		// duplicate identity
+		`{"version": 1, "identities": [
+			{"id": 128, "labels": ["k8s:app=foo"]},
+			{"id": 128, "labels": ["k8s:app=bar"]}]}`,
+		// duplicate label set
+		`{"version": 1, "identities": [
+			{"id": 128, "labels": ["k8s:app=foo", "k8s:env=prod"]},
+			{"id": 129, "labels": ["k8s:env=prod", "k8s:app=foo"]}]}`,
+	}
+
+	for _, content := range invalid {
+		file := writeWellKnownCfg(c, content)
+		c.Assert(ParseWellKnownIdentityCfg(file), Not(IsNil), Commentf("%s", content))
+		os.Remove(file)
+	}
+
+	c.Assert(ParseWellKnownIdentityCfg("/does/not/exist"), Not(IsNil))
+	c.Assert(ParseWellKnownIdentityCfg(""), IsNil)
+}
+
+func (s *PolicyTestSuite) TestWellKnownIdentityKVStoreConflicts(c *C) {
+	file := writeWellKnownCfg(c, `{
+		"version": 1,
+		"identities": [{"id": 128, "labels": ["k8s:k8s-app=kube-dns"]}]}`)
+	defer os.Remove(file)
+
+	oldWellKnown := wellKnown
+	defer func() { wellKnown = oldWellKnown }()
+	c.Assert(ParseWellKnownIdentityCfg(file), IsNil)
+
+	kvstoreIdentities := map[allocator.ID]allocator.AllocatorKey{
+		300: globalIdentity{labels.NewLabelsFromModel([]string{"k8s:app=foo"})},
+	}
+	forEach := func(cb allocator.RangeFunc) {
+		for id, key := range kvstoreIdentities {
+			cb(id, key)
+		}
+	}
+	c.Assert(checkWellKnownConflicts(forEach), IsNil)
+
+	kvstoreIdentities[301] = globalIdentity{labels.NewLabelsFromModel([]string{"k8s:k8s-app=kube-dns"})}
+	c.Assert(checkWellKnownConflicts(forEach), ErrorMatches,
+		"labels of well-known identities are allocated to different identities in the kvstore: .* is allocated to identity 301 instead of 128")
+}
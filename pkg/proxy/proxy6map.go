@@ -64,6 +64,11 @@ var (
 
 func init() {
 	bpf.OpenAfterMount(proxy6Map)
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        proxy6Map.Name(),
+		Description: "Original destinations of IPv6 connections redirected to a proxy",
+		Parser:      proxy6DumpParser,
+	})
 }
 
 func (k Proxy6Key) NewValue() bpf.MapValue {
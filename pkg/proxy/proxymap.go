@@ -64,6 +64,11 @@ var (
 
 func init() {
 	bpf.OpenAfterMount(proxy4Map)
+	bpf.RegisterMap(bpf.MapDescription{
+		Name:        proxy4Map.Name(),
+		Description: "Original destinations of IPv4 connections redirected to a proxy",
+		Parser:      proxy4DumpParser,
+	})
 }
 
 func (k Proxy4Key) NewValue() bpf.MapValue {
@@ -0,0 +1,20 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package servicefile synchronizes services declared in a directory of YAML
+// or JSON files. Services added to, modified in or removed from the files are
+// reported to the agent so that service definitions can be managed without
+// calling the API. Files should be replaced atomically, e.g. by renaming a
+// temporary file, so that partially written files are never read.
+package servicefile
@@ -0,0 +1,427 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicefile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/ghodss/yaml"
+	"github.com/go-openapi/strfmt"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "servicefile")
+
+// Configuration is the configuration that must be provided to NewWatcher()
+type Configuration struct {
+	// Directory is the path to the directory containing the service
+	// files. Only files with a .yaml, .yml or .json extension are read.
+	Directory string
+
+	// Add is called for each service which was added to or modified in a
+	// service file
+	Add func(svc *models.Service) error
+
+	// Delete is called for each service which was removed from a service
+	// file, including all services of a removed file
+	Delete func(svc *models.Service) error
+}
+
+// Watcher synchronizes the services defined in the files of a directory
+type Watcher struct {
+	conf    Configuration
+	watcher *fsnotify.Watcher
+	stop    chan struct{}
+	done    chan struct{}
+
+	mutex lock.Mutex
+
+	// services maps the name of each service file to the services applied
+	// from it, keyed by frontend address
+	services map[string]map[string]*models.Service
+
+	// invalid maps the name of each service file which could not be
+	// synchronized to the reason why
+	invalid map[string]error
+}
+
+// NewWatcher creates a new service file watcher. All services defined in the
+// directory are added before NewWatcher returns. The directory is watched for
+// changes so that services can be added, changed and removed at runtime.
+func NewWatcher(c Configuration) (*Watcher, error) {
+	if c.Directory == "" {
+		return nil, fmt.Errorf("service file directory must be specified")
+	}
+
+	if c.Add == nil || c.Delete == nil {
+		return nil, fmt.Errorf("service add and delete functions must be specified")
+	}
+
+	if _, err := os.Stat(c.Directory); err != nil {
+		return nil, fmt.Errorf("unable to access service file directory: %s", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create fsnotify watcher: %s", err)
+	}
+
+	if err := watcher.Add(c.Directory); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("unable to watch %s: %s", c.Directory, err)
+	}
+
+	w := &Watcher{
+		conf:     c,
+		watcher:  watcher,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		services: map[string]map[string]*models.Service{},
+		invalid:  map[string]error{},
+	}
+
+	w.resync()
+
+	go w.watch()
+
+	return w, nil
+}
+
+// isServiceFile returns true if the file in the directory is a service file.
+// Hidden files are ignored, this includes editor swap files and the "..data"
+// indirection of Kubernetes ConfigMap mounts.
+func (w *Watcher) isServiceFile(name string) bool {
+	if name == "" || strings.HasPrefix(name, ".") {
+		return false
+	}
+
+	switch path.Ext(name) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return false
+	}
+
+	info, err := os.Stat(path.Join(w.conf.Directory, name))
+	if err != nil {
+		return false
+	}
+
+	return info.Mode().IsRegular()
+}
+
+// parse decodes the content of a service file. A file contains either a
+// single service or a list of services. The services are returned keyed by
+// frontend address.
+func parse(data []byte) (map[string]*models.Service, error) {
+	result := map[string]*models.Service{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return result, nil
+	}
+
+	j, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var svcs []*models.Service
+	j = bytes.TrimSpace(j)
+	switch {
+	case bytes.Equal(j, []byte("null")):
+		// A file without any document defines no services
+	case j[0] == '[':
+		if err := json.Unmarshal(j, &svcs); err != nil {
+			return nil, err
+		}
+	default:
+		svc := &models.Service{}
+		if err := json.Unmarshal(j, svc); err != nil {
+			return nil, err
+		}
+		svcs = append(svcs, svc)
+	}
+
+	for i, svc := range svcs {
+		if svc == nil {
+			return nil, fmt.Errorf("service %d: empty definition", i)
+		}
+
+		if err := svc.Validate(strfmt.Default); err != nil {
+			return nil, fmt.Errorf("service %d: %s", i, err)
+		}
+
+		fe, err := types.NewL3n4AddrFromModel(svc.FrontendAddress)
+		if err != nil {
+			return nil, fmt.Errorf("service %d: invalid frontend: %s", i, err)
+		}
+
+		key := fe.String()
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("frontend %s is defined more than once", key)
+		}
+		result[key] = svc
+	}
+
+	return result, nil
+}
+
+// resync synchronizes the services with the content of the directory
+func (w *Watcher) resync() {
+	files, err := ioutil.ReadDir(w.conf.Directory)
+	if err != nil {
+		log.WithError(err).Warning("Unable to read service file directory")
+		return
+	}
+
+	found := map[string]struct{}{}
+	for _, f := range files {
+		if w.isServiceFile(f.Name()) {
+			found[f.Name()] = struct{}{}
+		}
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for name := range w.services {
+		if _, ok := found[name]; !ok {
+			w.removeLocked(name)
+		}
+	}
+
+	for name := range w.invalid {
+		if _, ok := found[name]; !ok {
+			w.removeLocked(name)
+		}
+	}
+
+	for name := range found {
+		w.updateLocked(name)
+	}
+}
+
+// updateLocked synchronizes the services of the given file. If the file
+// cannot be parsed, the services last applied from it are kept.
+func (w *Watcher) updateLocked(name string) {
+	scopedLog := log.WithField(logfields.Path, path.Join(w.conf.Directory, name))
+
+	data, err := ioutil.ReadFile(path.Join(w.conf.Directory, name))
+	var svcs map[string]*models.Service
+	if err == nil {
+		svcs, err = parse(data)
+	}
+	if err == nil {
+		err = w.checkConflictsLocked(name, svcs)
+	}
+	if err != nil {
+		scopedLog.WithError(err).Warning("Invalid service file, keeping previously applied services")
+		w.invalid[name] = err
+		return
+	}
+
+	if err := w.applyLocked(name, svcs); err != nil {
+		scopedLog.WithError(err).Warning("Unable to apply all services of service file")
+		w.invalid[name] = err
+		return
+	}
+
+	delete(w.invalid, name)
+}
+
+// checkConflictsLocked returns an error if any of the services is already
+// defined in another service file
+func (w *Watcher) checkConflictsLocked(name string, svcs map[string]*models.Service) error {
+	for other, otherSvcs := range w.services {
+		if other == name {
+			continue
+		}
+		for key := range svcs {
+			if _, ok := otherSvcs[key]; ok {
+				return fmt.Errorf("frontend %s is already defined in %s", key, other)
+			}
+		}
+	}
+	return nil
+}
+
+// applyLocked deletes the services which were removed from the given file
+// and adds all services which were added or modified
+func (w *Watcher) applyLocked(name string, svcs map[string]*models.Service) error {
+	old := w.services[name]
+	applied := map[string]*models.Service{}
+	failed := []string{}
+
+	for key, svc := range old {
+		if _, ok := svcs[key]; ok {
+			continue
+		}
+		log.WithField(logfields.ServiceName, key).Info("Deleting service removed from service file")
+		if err := w.conf.Delete(svc); err != nil {
+			log.WithError(err).WithField(logfields.ServiceName, key).Warning("Unable to delete service")
+		}
+	}
+
+	for key, svc := range svcs {
+		oldSvc, exists := old[key]
+		if exists && reflect.DeepEqual(oldSvc, svc) {
+			applied[key] = svc
+			continue
+		}
+
+		log.WithField(logfields.ServiceName, key).Info("Adding service from service file")
+		if err := w.conf.Add(svc); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", key, err))
+			// Keep track of the previous definition so that it is
+			// removed together with the file. As it differs from
+			// the file content, adding is retried on the next change.
+			if exists {
+				applied[key] = oldSvc
+			}
+			continue
+		}
+		applied[key] = svc
+	}
+
+	if len(applied) > 0 {
+		w.services[name] = applied
+	} else {
+		delete(w.services, name)
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("unable to add services: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// removeLocked deletes all services of the given file
+func (w *Watcher) removeLocked(name string) {
+	if svcs, ok := w.services[name]; ok {
+		log.WithField(logfields.Path, path.Join(w.conf.Directory, name)).Info("Removing services of deleted service file")
+
+		for key, svc := range svcs {
+			if err := w.conf.Delete(svc); err != nil {
+				log.WithError(err).WithField(logfields.ServiceName, key).Warning("Unable to delete service")
+			}
+		}
+		delete(w.services, name)
+	}
+	delete(w.invalid, name)
+}
+
+// changed handles a change of a file in the directory
+func (w *Watcher) changed(name string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !w.isServiceFile(name) {
+		w.removeLocked(name)
+		return
+	}
+
+	w.updateLocked(name)
+}
+
+func (w *Watcher) watch() {
+	defer close(w.done)
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			log.WithField("event", event).Debug("Received fsnotify event")
+
+			name := path.Base(event.Name)
+			if strings.HasPrefix(name, ".") {
+				// Kubernetes ConfigMap mounts are updated by
+				// swapping the ..data symlink, resync all files
+				w.resync()
+				continue
+			}
+
+			w.changed(name)
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.WithError(err).Warning("Error while watching service file directory")
+
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// Close stops watching the directory. Services which were added from service
+// files are not deleted.
+func (w *Watcher) Close() {
+	close(w.stop)
+	<-w.done
+	w.watcher.Close()
+}
+
+// Status returns the status of the service file synchronization
+func (w *Watcher) Status() *models.ServiceFilesStatus {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	status := &models.ServiceFilesStatus{
+		State:     models.ServiceFilesStatusStateOk,
+		Directory: w.conf.Directory,
+	}
+
+	for _, svcs := range w.services {
+		status.Services += int64(len(svcs))
+	}
+
+	names := make([]string, 0, len(w.invalid))
+	for name := range w.invalid {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		status.InvalidFiles = append(status.InvalidFiles, &models.ServiceFileError{
+			File:  name,
+			Error: w.invalid[name].Error(),
+		})
+	}
+
+	if len(names) > 0 {
+		status.State = models.ServiceFilesStatusStateWarning
+		status.Msg = fmt.Sprintf("%d of the service files could not be synchronized", len(names))
+	}
+
+	return status
+}
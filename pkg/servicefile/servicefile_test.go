@@ -0,0 +1,251 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicefile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/lock"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type ServiceFileSuite struct{}
+
+var _ = Suite(&ServiceFileSuite{})
+
+const (
+	webYAML = `
+frontend-address:
+  ip: 10.0.0.1
+  port: 80
+  protocol: tcp
+name: web
+backend-addresses:
+- ip: 10.0.1.1
+  port: 8080
+`
+
+	dnsJSON = `[
+  {"frontend-address": {"ip": "10.0.0.2", "port": 53, "protocol": "udp"},
+   "backend-addresses": [{"ip": "10.0.1.2", "port": 53}]},
+  {"frontend-address": {"ip": "f00d::1", "port": 53, "protocol": "udp"},
+   "backend-addresses": [{"ip": "f00d::2", "port": 53}]}
+]`
+)
+
+// fakeServices records the services added and deleted by a watcher
+type fakeServices struct {
+	mutex    lock.Mutex
+	services map[string]*models.Service
+	fail     bool
+}
+
+func key(svc *models.Service) string {
+	fe := svc.FrontendAddress
+	return fmt.Sprintf("%s:%d/%s", fe.IP, fe.Port, fe.Protocol)
+}
+
+func (f *fakeServices) add(svc *models.Service) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.fail {
+		return fmt.Errorf("failure")
+	}
+	f.services[key(svc)] = svc
+	return nil
+}
+
+func (f *fakeServices) delete(svc *models.Service) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.services, key(svc))
+	return nil
+}
+
+func (f *fakeServices) get(k string) *models.Service {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.services[k]
+}
+
+func (f *fakeServices) len() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.services)
+}
+
+func waitFor(c *C, cond func() bool) {
+	timeout := time.After(30 * time.Second)
+	for !cond() {
+		select {
+		case <-timeout:
+			c.Fatal("timeout while waiting for condition")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// writeFile replaces the file atomically so that the watcher never reads a
+// partially written file
+func writeFile(c *C, name, content string) {
+	tmp := path.Join(path.Dir(name), "."+path.Base(name)+".tmp")
+	c.Assert(ioutil.WriteFile(tmp, []byte(content), 0644), IsNil)
+	c.Assert(os.Rename(tmp, name), IsNil)
+}
+
+func (s *ServiceFileSuite) TestParse(c *C) {
+	svcs, err := parse([]byte(webYAML))
+	c.Assert(err, IsNil)
+	c.Assert(len(svcs), Equals, 1)
+	c.Assert(svcs["10.0.0.1:80"], Not(IsNil))
+	c.Assert(svcs["10.0.0.1:80"].Name, Equals, "web")
+
+	svcs, err = parse([]byte(dnsJSON))
+	c.Assert(err, IsNil)
+	c.Assert(len(svcs), Equals, 2)
+
+	svcs, err = parse([]byte{})
+	c.Assert(err, IsNil)
+	c.Assert(len(svcs), Equals, 0)
+
+	// Frontend address is required
+	_, err = parse([]byte("backend-addresses: []\n"))
+	c.Assert(err, Not(IsNil))
+
+	_, err = parse([]byte("frontend-address:\n  ip: foo\n  port: 80\n  protocol: tcp\n"))
+	c.Assert(err, Not(IsNil))
+
+	_, err = parse([]byte("- " + `{"frontend-address": {"ip": "10.0.0.1", "port": 80, "protocol": "tcp"}}` +
+		"\n- " + `{"frontend-address": {"ip": "10.0.0.1", "port": 80, "protocol": "tcp"}}`))
+	c.Assert(err, Not(IsNil))
+
+	_, err = parse([]byte("frontend-address: [\n"))
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *ServiceFileSuite) TestWatcher(c *C) {
+	dir, err := ioutil.TempDir("", "servicefile")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	writeFile(c, path.Join(dir, "web.yaml"), webYAML)
+	// Hidden files and files without a known extension are ignored
+	writeFile(c, path.Join(dir, ".web.yaml.swp"), "invalid")
+	writeFile(c, path.Join(dir, "README"), "invalid")
+
+	f := &fakeServices{services: map[string]*models.Service{}}
+	w, err := NewWatcher(Configuration{
+		Directory: dir,
+		Add:       f.add,
+		Delete:    f.delete,
+	})
+	c.Assert(err, IsNil)
+	defer w.Close()
+
+	// Services are added before NewWatcher returns
+	c.Assert(f.len(), Equals, 1)
+	status := w.Status()
+	c.Assert(status.State, Equals, models.ServiceFilesStatusStateOk)
+	c.Assert(status.Services, Equals, int64(1))
+	c.Assert(len(status.InvalidFiles), Equals, 0)
+
+	writeFile(c, path.Join(dir, "dns.json"), dnsJSON)
+	waitFor(c, func() bool { return f.len() == 3 })
+
+	// Modifying a service re-adds it
+	writeFile(c, path.Join(dir, "web.yaml"), webYAML+"labels:\n  app: web\n")
+	waitFor(c, func() bool {
+		svc := f.get("10.0.0.1:80/tcp")
+		return svc != nil && svc.Labels["app"] == "web"
+	})
+
+	// An invalid file is reported and its services are kept
+	writeFile(c, path.Join(dir, "web.yaml"), "frontend-address: [\n")
+	waitFor(c, func() bool { return w.Status().State == models.ServiceFilesStatusStateWarning })
+	status = w.Status()
+	c.Assert(len(status.InvalidFiles), Equals, 1)
+	c.Assert(status.InvalidFiles[0].File, Equals, "web.yaml")
+	c.Assert(status.Services, Equals, int64(3))
+	c.Assert(f.len(), Equals, 3)
+
+	// Services already defined in another file are rejected
+	writeFile(c, path.Join(dir, "web2.yaml"), webYAML)
+	waitFor(c, func() bool { return len(w.Status().InvalidFiles) == 2 })
+
+	// Removing services from a file deletes them
+	c.Assert(os.Remove(path.Join(dir, "web2.yaml")), IsNil)
+	writeFile(c, path.Join(dir, "web.yaml"), "")
+	waitFor(c, func() bool { return f.len() == 2 })
+	waitFor(c, func() bool { return len(w.Status().InvalidFiles) == 0 })
+
+	// Removing a file deletes all of its services
+	c.Assert(os.Remove(path.Join(dir, "dns.json")), IsNil)
+	waitFor(c, func() bool { return f.len() == 0 })
+	c.Assert(w.Status().Services, Equals, int64(0))
+}
+
+func (s *ServiceFileSuite) TestWatcherAddFailure(c *C) {
+	dir, err := ioutil.TempDir("", "servicefile")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	writeFile(c, path.Join(dir, "web.yaml"), webYAML)
+
+	f := &fakeServices{services: map[string]*models.Service{}, fail: true}
+	w, err := NewWatcher(Configuration{
+		Directory: dir,
+		Add:       f.add,
+		Delete:    f.delete,
+	})
+	c.Assert(err, IsNil)
+	defer w.Close()
+
+	status := w.Status()
+	c.Assert(status.State, Equals, models.ServiceFilesStatusStateWarning)
+	c.Assert(len(status.InvalidFiles), Equals, 1)
+	c.Assert(status.Services, Equals, int64(0))
+
+	// Adding is retried on the next change of the file
+	f.mutex.Lock()
+	f.fail = false
+	f.mutex.Unlock()
+	writeFile(c, path.Join(dir, "web.yaml"), webYAML)
+	waitFor(c, func() bool { return f.len() == 1 })
+	waitFor(c, func() bool { return w.Status().State == models.ServiceFilesStatusStateOk })
+}
+
+func (s *ServiceFileSuite) TestNewWatcherInvalidConfig(c *C) {
+	f := &fakeServices{services: map[string]*models.Service{}}
+
+	_, err := NewWatcher(Configuration{Add: f.add, Delete: f.delete})
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewWatcher(Configuration{Directory: "/does/not/exist", Add: f.add, Delete: f.delete})
+	c.Assert(err, Not(IsNil))
+
+	_, err = NewWatcher(Configuration{Directory: os.TempDir()})
+	c.Assert(err, Not(IsNil))
+}
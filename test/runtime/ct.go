@@ -33,7 +33,9 @@ const (
 	serverImage = "httpd"
 	ctCleanUpNC = "ct-clean-up-nc.py"
 
-	// Change to "<=" if the pkg/endpointmanager/conntrack.go:GcInterval is 10
+	// The agent runs with --conntrack-gc-max-interval set to
+	// pkg/endpointmanager/conntrack.go:ConntrackGCMinInterval (10s) so that
+	// the garbage collection interval does not grow during the tests.
 	// Change to "==" if it is set to a large number
 	comparator = "<="
 )